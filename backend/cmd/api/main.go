@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -22,17 +24,66 @@ import (
 	architecturepg "github.com/dark/idea-forge/internal/architecture/adapter/pg"
 	architecturehttp "github.com/dark/idea-forge/internal/architecture/adapter/http"
 	architectureuc "github.com/dark/idea-forge/internal/architecture/usecase"
+	architecturescaffold "github.com/dark/idea-forge/internal/architecture/adapter/scaffold"
 
 	devmodulepg "github.com/dark/idea-forge/internal/devmodule/adapter/pg"
 	devmodulehttp "github.com/dark/idea-forge/internal/devmodule/adapter/http"
 	devmoduleuc "github.com/dark/idea-forge/internal/devmodule/usecase"
 	devmoduledomain "github.com/dark/idea-forge/internal/devmodule/domain"
 
+	authdomain "github.com/dark/idea-forge/internal/auth/domain"
+	authport "github.com/dark/idea-forge/internal/auth/port"
 	authpg "github.com/dark/idea-forge/internal/auth/adapter/pg"
 	authhttp "github.com/dark/idea-forge/internal/auth/adapter/http"
-	authsmtp "github.com/dark/idea-forge/internal/auth/adapter/smtp"
+	authoidc "github.com/dark/idea-forge/internal/auth/adapter/oidc"
 	authuc "github.com/dark/idea-forge/internal/auth/usecase"
 	"github.com/dark/idea-forge/internal/middleware"
+
+	mailport "github.com/dark/idea-forge/internal/mail/port"
+	mailpg "github.com/dark/idea-forge/internal/mail/adapter/pg"
+	mailsmtp "github.com/dark/idea-forge/internal/mail/adapter/smtp"
+	"github.com/dark/idea-forge/internal/mail/adapter/mailbox"
+	mailtemplates "github.com/dark/idea-forge/internal/mail/templates"
+	mailuc "github.com/dark/idea-forge/internal/mail/usecase"
+
+	projectpg "github.com/dark/idea-forge/internal/project/adapter/pg"
+	projecthttp "github.com/dark/idea-forge/internal/project/adapter/http"
+	projectpdf "github.com/dark/idea-forge/internal/project/adapter/pdf"
+	projectdomain "github.com/dark/idea-forge/internal/project/domain"
+	projectuc "github.com/dark/idea-forge/internal/project/usecase"
+
+	adminpg "github.com/dark/idea-forge/internal/admin/adapter/pg"
+	adminhttp "github.com/dark/idea-forge/internal/admin/adapter/http"
+	adminuc "github.com/dark/idea-forge/internal/admin/usecase"
+
+	workspacedomain "github.com/dark/idea-forge/internal/workspace/domain"
+	workspacepg "github.com/dark/idea-forge/internal/workspace/adapter/pg"
+	workspacehttp "github.com/dark/idea-forge/internal/workspace/adapter/http"
+	workspaceuc "github.com/dark/idea-forge/internal/workspace/usecase"
+
+	commentdomain "github.com/dark/idea-forge/internal/comment/domain"
+	commentpg "github.com/dark/idea-forge/internal/comment/adapter/pg"
+	commenthttp "github.com/dark/idea-forge/internal/comment/adapter/http"
+	commentuc "github.com/dark/idea-forge/internal/comment/usecase"
+
+	activitypg "github.com/dark/idea-forge/internal/activity/adapter/pg"
+	activityhttp "github.com/dark/idea-forge/internal/activity/adapter/http"
+	activityuc "github.com/dark/idea-forge/internal/activity/usecase"
+
+	notificationdomain "github.com/dark/idea-forge/internal/notification/domain"
+	notificationpg "github.com/dark/idea-forge/internal/notification/adapter/pg"
+	notificationhttp "github.com/dark/idea-forge/internal/notification/adapter/http"
+	notificationuc "github.com/dark/idea-forge/internal/notification/usecase"
+
+	webhookdomain "github.com/dark/idea-forge/internal/webhook/domain"
+	webhookpg "github.com/dark/idea-forge/internal/webhook/adapter/pg"
+	webhookhttp "github.com/dark/idea-forge/internal/webhook/adapter/http"
+	webhooksender "github.com/dark/idea-forge/internal/webhook/adapter/sender"
+	webhookuc "github.com/dark/idea-forge/internal/webhook/usecase"
+
+	"github.com/dark/idea-forge/internal/access"
+	"github.com/dark/idea-forge/internal/events"
+	"github.com/dark/idea-forge/internal/realtime"
 )
 
 func main() {
@@ -49,26 +100,86 @@ func main() {
 	}
 	defer sqlDB.Close()
 
+	// Bus de eventos: los repositorios registran los eventos de dominio en el outbox
+	// dentro de la misma transacción que el cambio, y el dispatcher los reparte
+	dispatcher := events.NewDispatcher(sqlDB)
+
+	// Permisos sobre proyectos según membresía de workspace y colaboradores
+	accessChecker := access.NewChecker(access.NewPGStore(sqlDB))
+
+	// Eventos en tiempo real: el dispatcher los publica con NOTIFY y cada instancia
+	// los reenvía a sus clientes conectados
+	realtimePublisher := realtime.NewPublisher(sqlDB)
+	dispatcher.Subscribe("realtime", func(ctx context.Context, r events.Record) error {
+		return realtimePublisher.Publish(ctx, realtime.Event{ID: r.ID, Type: r.Type, IdeaID: r.IdeaID, Data: r.Data, At: r.OccurredAt})
+	})
+	eventHub := realtime.NewHub(accessChecker)
+	go realtime.Listen(context.Background(), dsn, eventHub)
+
 	repo := ideationpg.NewRepo(sqlDB)
-	create := ideationuc.NewCreateIdea(repo)
+	create := ideationuc.NewCreateIdea(repo, accessChecker)
 	get := ideationuc.NewGetIdea(repo)
 	list := ideationuc.NewListIdeas(repo)
 	update := ideationuc.NewUpdateIdea(repo)
 	deleteIdea := ideationuc.NewDeleteIdea(repo)
-	appendMsg := ideationuc.NewAppendMessage(repo)
+	appendMsg := ideationuc.NewAppendMessage(repo, accessChecker)
+	importIdeas := ideationuc.NewImportIdeas(repo)
+
+	// Admin repo (también registra el uso de IA por usuario)
+	adminRepo := adminpg.NewRepo(sqlDB)
 
 	// Configurar HTTP client con timeout para llamadas a servicios externos
 	httpClient := &http.Client{
 		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:        100,
-			MaxIdleConnsPerHost: 10,
-			IdleConnTimeout:     90 * time.Second,
+		Transport: &middleware.AIUsageTransport{
+			Base: &http.Transport{
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			Recorder: adminRepo,
 		},
 	}
 
+	// Auth setup
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		jwtSecret = "dev-secret-key-change-in-production"
+		log.Println("WARNING: Using default JWT secret. Set JWT_SECRET env var in production!")
+	}
+	authRepo := authpg.NewUserRepository(sqlDB)
+
+	// Valida el JWT y además el estado actual del usuario (suspensiones aplican de inmediato)
+	jwtAuth := middleware.AuthMiddleware(jwtSecret)
+	loadAccess := middleware.LoadUserAccess(&userAccessAdapter{repo: authRepo})
+	authMiddleware := func(next http.Handler) http.Handler {
+		return jwtAuth(loadAccess(next))
+	}
+
 	mux := http.NewServeMux()
 
+	// Las rutas de proyectos (ideación, plan, arquitectura, módulos) requieren autenticación
+	// y se autorizan según el rol del usuario en el workspace de la idea
+	projectMux := http.NewServeMux()
+	projectRoutes := authMiddleware(accessChecker.Guard(projectMux))
+	// Se registran solo los prefijos de proyecto: cualquier otra ruta responde 404
+	for _, prefix := range []string{
+		"/ideation/",
+		"/action-plan", "/action-plan/",
+		"/architecture", "/architecture/",
+		"/dev-modules", "/dev-modules/",
+		"/global-chat", "/global-chat/",
+		"/projects/",
+		"/workspaces", "/workspaces/",
+		"/notifications/",
+		"/webhooks", "/webhooks/",
+	} {
+		mux.Handle(prefix, projectRoutes)
+	}
+
+	// Stream SSE de eventos por proyecto
+	projectMux.HandleFunc("GET /projects/{ideaID}/events", eventHub.ServeEvents)
+
 	// Ideation handlers
 	ideationHandlers := &ideationhttp.Handlers{
 		Create:     create,
@@ -77,35 +188,41 @@ func main() {
 		Update:     update,
 		Delete:     deleteIdea,
 		Append:     appendMsg,
+		Import:     importIdeas,
 		HTTPClient: httpClient,
 	}
-	ideationHandlers.Register(mux)
+	ideationHandlers.Register(projectMux)
 
 	// Action Plan handlers
 	actionPlanRepo := actionplanpg.NewRepo(sqlDB)
-	actionPlanUsecase := actionplanuc.NewActionPlanUsecase(actionPlanRepo)
+	actionPlanUsecase := actionplanuc.NewActionPlanUsecase(actionPlanRepo, accessChecker)
 	actionPlanHandlers := &actionplanhttp.Handlers{
 		Usecase:     actionPlanUsecase,
 		HTTPClient:  httpClient,
 		IdeaUsecase: get, // Para obtener la idea al crear el plan
 	}
-	actionPlanHandlers.Register(mux)
+	actionPlanHandlers.Register(projectMux)
 
 	// Development Modules repo and usecase (needed by both architecture and devmodule handlers)
 	devModuleRepo := devmodulepg.NewRepo(sqlDB)
-	devModuleUsecase := devmoduleuc.NewDevModuleUsecase(devModuleRepo)
+	devModuleUsecase := devmoduleuc.NewDevModuleUsecase(devModuleRepo, accessChecker)
 
 	// Architecture handlers
 	architectureRepo := architecturepg.NewRepo(sqlDB)
-	architectureUsecase := architectureuc.NewArchitectureUsecase(architectureRepo)
+	architectureUsecase := architectureuc.NewArchitectureUsecase(architectureRepo, accessChecker)
+	// Plantillas de scaffold por stack; la primera es la predeterminada
+	scaffoldUsecase := architectureuc.NewScaffoldUsecase(architecturescaffold.NewGoTemplate())
 	architectureHandlers := &architecturehttp.Handlers{
 		Usecase:           architectureUsecase,
+		Scaffold:          scaffoldUsecase,
+		Diagrams:          architectureuc.NewDiagramUsecase(),
+		C4:                architectureuc.NewC4Usecase(),
 		HTTPClient:        httpClient,
 		ActionPlanUsecase: actionPlanUsecase,
 		IdeaUsecase:       get,
 		DevModuleUsecase:  &devModuleAdapter{uc: devModuleUsecase},
 	}
-	architectureHandlers.Register(mux)
+	architectureHandlers.Register(projectMux)
 
 	// Development Modules & Global Chat handlers
 	devModuleHandlers := &devmodulehttp.Handlers{
@@ -116,21 +233,24 @@ func main() {
 		ActionPlanUsecase:   actionPlanUsecase,
 		ArchitectureUsecase: architectureUsecase,
 	}
-	devModuleHandlers.Register(mux)
+	devModuleHandlers.Register(projectMux)
 
-	// Auth setup
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "dev-secret-key-change-in-production"
-		log.Println("WARNING: Using default JWT secret. Set JWT_SECRET env var in production!")
-	}
+	// Project aggregation (used by data export)
+	projectUsecase := projectuc.NewProjectUsecase(repo, actionPlanUsecase, architectureUsecase, devModuleUsecase, projectpdf.NewRenderer())
 
 	frontendURL := os.Getenv("FRONTEND_URL")
 	if frontendURL == "" {
 		frontendURL = "http://localhost:3000"
 	}
 
-	// Email service config
+	// URL pública del API (links de descarga enviados por email)
+	apiURL := os.Getenv("API_URL")
+	if apiURL == "" {
+		apiURL = "http://localhost:8080"
+	}
+
+	// Emails: se renderizan con las plantillas de internal/mail/templates en el idioma del
+	// destinatario, se encolan en mail_outbox y un worker los envía con reintentos
 	smtpHost := os.Getenv("SMTP_HOST")
 	smtpPort := os.Getenv("SMTP_PORT")
 	smtpUser := os.Getenv("SMTP_USER")
@@ -140,8 +260,43 @@ func main() {
 		smtpFrom = "Idea Forge <noreply@ideaforge.com>"
 	}
 
-	emailService := authsmtp.NewEmailService(smtpHost, smtpPort, smtpUser, smtpPass, smtpFrom)
-	authRepo := authpg.NewUserRepository(sqlDB)
+	// MAIL_TRANSPORT: smtp, file (archivos .eml en MAIL_DIR) o memory.
+	// Sin SMTP configurado se usa file para desarrollo local
+	mailTransport := os.Getenv("MAIL_TRANSPORT")
+	if mailTransport == "" {
+		mailTransport = "smtp"
+		if smtpHost == "" {
+			mailTransport = "file"
+		}
+	}
+	var transport mailport.Transport
+	switch mailTransport {
+	case "smtp":
+		if smtpPort == "" {
+			smtpPort = "587"
+		}
+		transport = mailsmtp.NewTransport(smtpHost, smtpPort, smtpUser, smtpPass, smtpFrom, 30*time.Second)
+	case "file":
+		mailDir := os.Getenv("MAIL_DIR")
+		if mailDir == "" {
+			mailDir = "mailbox"
+		}
+		fileMailbox, err := mailbox.NewFile(mailDir, smtpFrom)
+		if err != nil {
+			log.Fatal(err)
+		}
+		transport = fileMailbox
+	case "memory":
+		transport = mailbox.NewMemory()
+	default:
+		log.Fatalf("unknown MAIL_TRANSPORT %q", mailTransport)
+	}
+	mailTemplates, err := mailtemplates.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	emailService := mailuc.NewMailUsecase(mailpg.NewRepo(sqlDB), mailTemplates, transport, &mailLocaleAdapter{repo: authRepo})
+	go emailService.Run(context.Background())
 
 	// Auth use cases
 	registerUC := authuc.NewRegisterUseCase(authRepo, emailService)
@@ -150,6 +305,27 @@ func main() {
 	forgotPasswordUC := authuc.NewForgotPasswordUseCase(authRepo, emailService, frontendURL)
 	resetPasswordUC := authuc.NewResetPasswordUseCase(authRepo)
 
+	// Account self-service use cases
+	changePasswordUC := authuc.NewChangePasswordUseCase(authRepo)
+	updateProfileUC := authuc.NewUpdateProfileUseCase(authRepo)
+	changeEmailUC := authuc.NewChangeEmailUseCase(authRepo, emailService)
+	deleteAccountUC := authuc.NewDeleteAccountUseCase(authRepo, authdomain.DeletionPolicy(os.Getenv("ACCOUNT_DELETION_POLICY")))
+
+	// Proveedores OIDC (login con identidad corporativa / social)
+	oidcProviders, err := authoidc.ProvidersFromEnv(frontendURL, nil)
+	if err != nil {
+		log.Fatalf("error configurando OIDC: %v", err)
+	}
+	identityProviders := make([]authport.IdentityProvider, len(oidcProviders))
+	for i, p := range oidcProviders {
+		identityProviders[i] = p
+	}
+	oidcLoginUC := authuc.NewOIDCLoginUseCase(authRepo, identityProviders, jwtSecret)
+	magicLinkUC := authuc.NewMagicLinkUseCase(authRepo, emailService, frontendURL, jwtSecret)
+	exportDataUC := authuc.NewExportDataUseCase(authRepo, emailService, &userDataAdapter{uc: projectUsecase}, apiURL)
+	// Elimina cada hora las exportaciones vencidas (el archivo de las descargadas se borra al descargarlo)
+	go exportDataUC.Run(context.Background())
+
 	// Auth handlers
 	authHandlers := authhttp.NewAuthHandler(
 		registerUC,
@@ -169,10 +345,114 @@ func main() {
 	mux.HandleFunc("POST /auth/forgot-password", authHandlers.ForgotPassword)
 	mux.HandleFunc("POST /auth/reset-password", authHandlers.ResetPassword)
 
+	// OIDC login
+	oidcHandlers := authhttp.NewOIDCHandler(oidcLoginUC)
+	mux.HandleFunc("GET /auth/oidc/providers", oidcHandlers.ListProviders)
+	mux.HandleFunc("GET /auth/oidc/{provider}/authorize", oidcHandlers.Authorize)
+	mux.HandleFunc("POST /auth/oidc/callback", oidcHandlers.Callback)
+
+	// Magic link login (opcional)
+	if os.Getenv("MAGIC_LINK_ENABLED") == "true" {
+		magicLinkHandlers := authhttp.NewMagicLinkHandler(magicLinkUC)
+		mux.HandleFunc("POST /auth/magic-link", magicLinkHandlers.RequestMagicLink)
+		mux.HandleFunc("POST /auth/magic-link/consume", magicLinkHandlers.ConsumeMagicLink)
+		log.Println("Magic link login enabled")
+	}
+
 	// Auth routes (protected)
-	authMiddleware := middleware.AuthMiddleware(jwtSecret)
 	mux.Handle("GET /auth/me", authMiddleware(http.HandlerFunc(authHandlers.GetMe)))
 
+	// Account self-service routes (protected)
+	accountHandlers := authhttp.NewAccountHandler(changePasswordUC, updateProfileUC, changeEmailUC, deleteAccountUC)
+	mux.Handle("PUT /auth/me", authMiddleware(http.HandlerFunc(accountHandlers.UpdateProfile)))
+	mux.Handle("DELETE /auth/me", authMiddleware(http.HandlerFunc(accountHandlers.DeleteAccount)))
+	mux.Handle("POST /auth/change-password", authMiddleware(http.HandlerFunc(accountHandlers.ChangePassword)))
+	mux.Handle("POST /auth/change-email", authMiddleware(http.HandlerFunc(accountHandlers.RequestEmailChange)))
+	mux.Handle("POST /auth/change-email/confirm", authMiddleware(http.HandlerFunc(accountHandlers.ConfirmEmailChange)))
+
+	// Personal data export (el link de descarga es público y de tiempo limitado)
+	exportHandlers := authhttp.NewExportHandler(exportDataUC)
+	mux.Handle("POST /auth/export", authMiddleware(http.HandlerFunc(exportHandlers.RequestExport)))
+	mux.HandleFunc("GET /auth/export/{token}", exportHandlers.DownloadExport)
+
+	// Workspaces: miembros, roles e invitaciones por email
+	workspaceRepo := workspacepg.NewRepo(sqlDB)
+	workspaceUsecase := workspaceuc.NewWorkspaceUsecase(workspaceRepo, &workspaceUserAdapter{repo: authRepo}, emailService, frontendURL)
+	workspaceHandlers := &workspacehttp.Handlers{Usecase: workspaceUsecase}
+	workspaceHandlers.Register(projectMux)
+	ideationHandlers.Workspaces = workspaceUsecase
+
+	// Notificaciones por email (menciones, proyectos compartidos, etapas y módulos estancados)
+	// según las preferencias de cada usuario: al momento o en un resumen diario/semanal
+	notificationUsecase := notificationuc.NewNotificationUsecase(notificationpg.NewRepo(sqlDB), &notificationMailAdapter{uc: emailService, frontendURL: frontendURL}, frontendURL)
+	go notificationUsecase.Run(context.Background())
+	notificationHandlers := &notificationhttp.Handlers{Usecase: notificationUsecase}
+	notificationHandlers.Register(projectMux)
+
+	// Colaboradores y links públicos por proyecto (el guard de acceso exige owner para gestionarlos)
+	shareLinkSecret := os.Getenv("SHARE_LINK_SECRET")
+	if shareLinkSecret == "" {
+		shareLinkSecret = jwtSecret
+	}
+	collaboratorUsecase := projectuc.NewCollaboratorUsecase(projectpg.NewCollaboratorRepo(sqlDB), &projectUserAdapter{repo: authRepo}, &collaboratorNotifierAdapter{uc: notificationUsecase})
+	shareLinkUsecase := projectuc.NewShareLinkUsecase(projectpg.NewShareLinkRepo(sqlDB), projectUsecase, shareLinkSecret, apiURL)
+	importUsecase := projectuc.NewImportUsecase(projectpg.NewImportRepo(sqlDB))
+	projectHandlers := &projecthttp.Handlers{
+		Projects:      projectUsecase,
+		Imports:       importUsecase,
+		Collaborators: collaboratorUsecase,
+		ShareLinks:    shareLinkUsecase,
+		Workspaces:    workspaceUsecase,
+	}
+	projectHandlers.Register(projectMux)
+	projectHandlers.RegisterPublic(mux)
+
+	// Hilos de comentarios anclados a secciones (con menciones notificadas por email)
+	commentUsecase := commentuc.NewCommentUsecase(
+		commentpg.NewRepo(sqlDB),
+		&commentSectionAdapter{uc: projectUsecase},
+		&commentUserAdapter{repo: authRepo},
+		accessChecker,
+		&mentionNotifierAdapter{uc: notificationUsecase},
+		frontendURL,
+	)
+	commentHandlers := &commenthttp.Handlers{Usecase: commentUsecase}
+	commentHandlers.Register(projectMux)
+
+	// Historial de actividad por proyecto: se construye a partir de los eventos de dominio
+	activityUsecase := activityuc.NewActivityUsecase(activitypg.NewRepo(sqlDB))
+	dispatcher.Subscribe("activity", activityUsecase.Record)
+	activityHandlers := &activityhttp.Handlers{Usecase: activityUsecase}
+	activityHandlers.Register(projectMux)
+
+	// Webhooks salientes: eventos del ciclo de vida de los proyectos firmados con HMAC,
+	// con reintentos persistentes (cualquier instancia puede enviar las entregas pendientes).
+	// Las URLs a direcciones locales o privadas se rechazan salvo WEBHOOK_ALLOW_PRIVATE_TARGETS=true
+	allowPrivateTargets := os.Getenv("WEBHOOK_ALLOW_PRIVATE_TARGETS") == "true"
+	webhookUsecase := webhookuc.NewWebhookUsecase(
+		webhookpg.NewRepo(sqlDB),
+		&webhookProjectAdapter{uc: get},
+		accessChecker,
+		webhooksender.NewHTTPSender(10*time.Second, allowPrivateTargets),
+		allowPrivateTargets,
+	)
+	dispatcher.Subscribe("webhooks", func(ctx context.Context, r events.Record) error {
+		return webhookUsecase.Enqueue(ctx, r.ID, r.IdeaID, r.Type, r.OccurredAt, r.Data)
+	})
+	go webhookUsecase.Run(context.Background())
+
+	// Con todos los suscriptores registrados, empezar a repartir el outbox
+	go dispatcher.Run(context.Background())
+	webhookHandlers := &webhookhttp.Handlers{Usecase: webhookUsecase}
+	webhookHandlers.Register(projectMux)
+
+	// Admin API (solo rol admin, todas las acciones quedan auditadas)
+	adminUsecase := adminuc.NewAdminUsecase(adminRepo, &passwordResetAdapter{uc: forgotPasswordUC})
+	adminHandlers := &adminhttp.Handlers{Usecase: adminUsecase}
+	adminMux := http.NewServeMux()
+	adminHandlers.Register(adminMux)
+	mux.Handle("/admin/", authMiddleware(middleware.RequireRole(string(authdomain.RoleAdmin))(adminMux)))
+
 	srv := &http.Server{
 		Addr:              ":8080",
 		Handler:           cors(security(mux)),
@@ -273,3 +553,219 @@ func (a *devModuleAdapter) GetModulesByArchitectureID(ctx context.Context, archi
 	}
 	return modules, nil
 }
+
+// userDataAdapter adapts the project usecase to the data source expected by the auth export
+type userDataAdapter struct {
+	uc *projectuc.ProjectUsecase
+}
+
+func (a *userDataAdapter) CollectUserFiles(ctx context.Context, userID uuid.UUID) ([]authdomain.ExportFile, error) {
+	projectFiles, err := a.uc.UserArchiveFiles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]authdomain.ExportFile, len(projectFiles))
+	for i, f := range projectFiles {
+		files[i] = authdomain.ExportFile{Path: f.Path, Content: f.Content}
+	}
+	return files, nil
+}
+
+// userAccessAdapter exposes the current role and status of a user to the auth middleware
+type userAccessAdapter struct {
+	repo authport.UserRepository
+}
+
+func (a *userAccessAdapter) GetUserAccess(ctx context.Context, userID uuid.UUID) (*middleware.UserAccess, error) {
+	user, err := a.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &middleware.UserAccess{Role: string(user.Role), Status: string(user.Status)}, nil
+}
+
+// passwordResetAdapter lets the admin module trigger the regular forgot-password email
+type passwordResetAdapter struct {
+	uc *authuc.ForgotPasswordUseCase
+}
+
+func (a *passwordResetAdapter) SendPasswordReset(ctx context.Context, email string) error {
+	return a.uc.Execute(ctx, authuc.ForgotPasswordInput{Email: email})
+}
+
+// workspaceUserAdapter exposes user accounts to the workspace module
+type workspaceUserAdapter struct {
+	repo authport.UserRepository
+}
+
+func (a *workspaceUserAdapter) GetUser(ctx context.Context, userID uuid.UUID) (*workspacedomain.User, error) {
+	user, err := a.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &workspacedomain.User{ID: user.ID, Username: user.Username, Email: user.Email}, nil
+}
+
+// projectUserAdapter exposes user accounts to the project collaborators
+type projectUserAdapter struct {
+	repo authport.UserRepository
+}
+
+func (a *projectUserAdapter) GetUserByID(ctx context.Context, userID uuid.UUID) (*projectdomain.User, error) {
+	user, err := a.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &projectdomain.User{ID: user.ID, Username: user.Username, Email: user.Email}, nil
+}
+
+func (a *projectUserAdapter) GetUserByEmail(ctx context.Context, email string) (*projectdomain.User, error) {
+	user, err := a.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return &projectdomain.User{ID: user.ID, Username: user.Username, Email: user.Email}, nil
+}
+
+// commentSectionAdapter exposes the current section contents of a project to the comments
+type commentSectionAdapter struct {
+	uc *projectuc.ProjectUsecase
+}
+
+func (a *commentSectionAdapter) Sections(ctx context.Context, ideaID uuid.UUID) (string, map[string]string, error) {
+	project, err := a.uc.GetProject(ctx, ideaID, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return project.Idea.Title, projectuc.Sections(project), nil
+}
+
+// commentUserAdapter exposes user accounts to the comments
+type commentUserAdapter struct {
+	repo authport.UserRepository
+}
+
+func (a *commentUserAdapter) GetUser(ctx context.Context, userID uuid.UUID) (*commentdomain.User, error) {
+	user, err := a.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &commentdomain.User{ID: user.ID, Username: user.Username, Email: user.Email}, nil
+}
+
+func (a *commentUserAdapter) GetUserByUsername(ctx context.Context, username string) (*commentdomain.User, error) {
+	user, err := a.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return &commentdomain.User{ID: user.ID, Username: user.Username, Email: user.Email}, nil
+}
+
+// mailLocaleAdapter tells the mail module the language of a recipient
+type mailLocaleAdapter struct {
+	repo authport.UserRepository
+}
+
+func (a *mailLocaleAdapter) LocaleFor(ctx context.Context, email string) string {
+	user, err := a.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return ""
+	}
+	return user.Locale
+}
+
+// notificationMailAdapter sends notification emails through the mail outbox
+type notificationMailAdapter struct {
+	uc          *mailuc.MailUsecase
+	frontendURL string
+}
+
+func (a *notificationMailAdapter) SendCommentMention(ctx context.Context, email, username, authorName, projectTitle, anchor, excerpt, link string) error {
+	return a.uc.SendCommentMention(ctx, email, username, authorName, projectTitle, anchor, excerpt, link)
+}
+
+func (a *notificationMailAdapter) SendCollaboratorAdded(ctx context.Context, email, username, inviterName, projectTitle, role, link string) error {
+	return a.uc.SendCollaboratorAdded(ctx, email, username, inviterName, projectTitle, role, link)
+}
+
+func (a *notificationMailAdapter) SendDigest(ctx context.Context, email, username string, d *notificationdomain.Digest) error {
+	items := func(list []notificationdomain.Notification) []map[string]any {
+		out := make([]map[string]any, 0, len(list))
+		for _, n := range list {
+			item := map[string]any{"Link": n.Link}
+			for k, v := range n.Data {
+				item[k] = v
+			}
+			out = append(out, item)
+		}
+		return out
+	}
+	stages := make([]map[string]any, 0, len(d.StaleStages))
+	for _, s := range d.StaleStages {
+		stages = append(stages, map[string]any{"Project": s.ProjectTitle, "Stage": s.Stage, "Days": s.Days, "Link": s.Link})
+	}
+	modules := make([]map[string]any, 0, len(d.StaleModules))
+	for _, m := range d.StaleModules {
+		modules = append(modules, map[string]any{"Module": m.ModuleName, "Project": m.ProjectTitle, "Days": m.Days})
+	}
+	return a.uc.Queue(ctx, email, "notification_digest", map[string]any{
+		"Username":     username,
+		"Frequency":    d.Frequency,
+		"Mentions":     items(d.Mentions),
+		"Shared":       items(d.Shared),
+		"StaleStages":  stages,
+		"StaleModules": modules,
+		"SettingsLink": a.frontendURL,
+	})
+}
+
+// collaboratorNotifierAdapter notifies users given access to a project
+type collaboratorNotifierAdapter struct {
+	uc *notificationuc.NotificationUsecase
+}
+
+func (a *collaboratorNotifierAdapter) NotifyCollaboratorAdded(ctx context.Context, c *projectdomain.Collaborator, inviterName string) error {
+	return a.uc.NotifyCollaboratorAdded(ctx, notificationdomain.CollaboratorGrant{
+		UserID:      c.UserID,
+		Email:       c.Email,
+		Username:    c.Username,
+		IdeaID:      c.IdeaID,
+		InviterName: inviterName,
+		Role:        c.Role,
+	})
+}
+
+// mentionNotifierAdapter notifies users mentioned in a comment
+type mentionNotifierAdapter struct {
+	uc *notificationuc.NotificationUsecase
+}
+
+func (a *mentionNotifierAdapter) NotifyMention(ctx context.Context, user *commentdomain.User, ideaID uuid.UUID, authorName, projectTitle, anchor, excerpt, link string) error {
+	return a.uc.NotifyMention(ctx, notificationdomain.Mention{
+		UserID:       user.ID,
+		Email:        user.Email,
+		Username:     user.Username,
+		IdeaID:       ideaID,
+		AuthorName:   authorName,
+		ProjectTitle: projectTitle,
+		Anchor:       anchor,
+		Excerpt:      excerpt,
+		Link:         link,
+	})
+}
+
+// webhookProjectAdapter tells the webhooks who created a project and its workspace
+type webhookProjectAdapter struct {
+	uc *ideationuc.GetIdea
+}
+
+func (a *webhookProjectAdapter) Scope(ctx context.Context, ideaID uuid.UUID) (*uuid.UUID, *uuid.UUID, error) {
+	idea, err := a.uc.Execute(ctx, ideaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, webhookdomain.ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return idea.UserID, idea.WorkspaceID, nil
+}
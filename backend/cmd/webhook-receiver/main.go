@@ -0,0 +1,61 @@
+// Command webhook-receiver is a local endpoint for testing Idea Forge webhooks.
+// It verifies the signature of every delivery and prints it:
+//
+//	go run ./cmd/webhook-receiver -secret whsec_... -addr :9000
+//
+// Register http://localhost:9000/ as the webhook URL. Use -fail to answer 500
+// and watch the API retry with backoff.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dark/idea-forge/internal/webhook/domain"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "listen address")
+	secret := flag.String("secret", "", "webhook secret returned when the webhook was created")
+	fail := flag.Bool("fail", false, "answer every delivery with 500")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "cannot read body", http.StatusBadRequest)
+			return
+		}
+
+		verified := "not checked (no -secret)"
+		if *secret != "" {
+			if err := domain.Verify(*secret, r.Header.Get(domain.HeaderSignature), body, 5*time.Minute); err != nil {
+				log.Printf("rejected delivery %s: %v", r.Header.Get(domain.HeaderDelivery), err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			verified = "ok"
+		}
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") != nil {
+			pretty.Write(body)
+		}
+		log.Printf("%s delivery=%s signature=%s\n%s",
+			r.Header.Get(domain.HeaderEvent), r.Header.Get(domain.HeaderDelivery), verified, pretty.String())
+
+		if *fail {
+			http.Error(w, "failing on purpose", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("webhook receiver listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
@@ -0,0 +1,182 @@
+// Package access decides what a user may do with a project (an idea and every
+// stage derived from it: action plan, architecture and development modules).
+//
+// Access is granted through workspace membership and per-project
+// collaborator grants; the strongest role wins.
+package access
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Role is the effective role of a user on a workspace or project
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+// Permission is what an operation needs
+type Permission int
+
+const (
+	PermRead Permission = iota
+	PermWrite
+	PermManage
+)
+
+var (
+	ErrForbidden = errors.New("forbidden")
+	ErrNotFound  = errors.New("not found")
+)
+
+// IsValid reports whether r is a known role
+func (r Role) IsValid() bool {
+	return r == RoleOwner || r == RoleEditor || r == RoleViewer
+}
+
+// Allows reports whether the role grants the permission
+func (r Role) Allows(p Permission) bool {
+	switch p {
+	case PermRead:
+		return r.rank() >= 1
+	case PermWrite:
+		return r.rank() >= 2
+	case PermManage:
+		return r.rank() >= 3
+	default:
+		return false
+	}
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleOwner:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Highest returns the strongest of the given roles ("" if none)
+func Highest(roles ...Role) Role {
+	var best Role
+	for _, r := range roles {
+		if r.rank() > best.rank() {
+			best = r
+		}
+	}
+	return best
+}
+
+// Store resolves resources to their idea and roles from membership
+type Store interface {
+	// IdeaRoles returns every role the user holds on the idea; ErrNotFound if the idea does not exist
+	IdeaRoles(ctx context.Context, userID, ideaID uuid.UUID) ([]Role, error)
+	// WorkspaceRole returns the user's role in the workspace ("" if not a member); ErrNotFound if it does not exist
+	WorkspaceRole(ctx context.Context, userID, workspaceID uuid.UUID) (Role, error)
+
+	IdeaForActionPlan(ctx context.Context, actionPlanID uuid.UUID) (uuid.UUID, error)
+	IdeaForArchitecture(ctx context.Context, architectureID uuid.UUID) (uuid.UUID, error)
+	IdeaForModule(ctx context.Context, moduleID uuid.UUID) (uuid.UUID, error)
+}
+
+// Checker answers permission questions for a user
+type Checker struct {
+	store Store
+}
+
+// NewChecker creates a new access checker
+func NewChecker(store Store) *Checker {
+	return &Checker{store: store}
+}
+
+// IdeaRole returns the effective role of the user on the idea ("" if none)
+func (c *Checker) IdeaRole(ctx context.Context, userID, ideaID uuid.UUID) (Role, error) {
+	roles, err := c.store.IdeaRoles(ctx, userID, ideaID)
+	if err != nil {
+		return "", err
+	}
+	return Highest(roles...), nil
+}
+
+// CanRead reports whether the user can read the idea (false if it does not exist)
+func (c *Checker) CanRead(ctx context.Context, userID, ideaID uuid.UUID) (bool, error) {
+	role, err := c.IdeaRole(ctx, userID, ideaID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return role.Allows(PermRead), nil
+}
+
+// CanWrite reports whether the user can change the idea (false if it does not exist)
+func (c *Checker) CanWrite(ctx context.Context, userID, ideaID uuid.UUID) (bool, error) {
+	role, err := c.IdeaRole(ctx, userID, ideaID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return role.Allows(PermWrite), nil
+}
+
+// CanWriteWorkspace reports whether the user can create projects in the workspace (false if it does not exist)
+func (c *Checker) CanWriteWorkspace(ctx context.Context, userID, workspaceID uuid.UUID) (bool, error) {
+	role, err := c.store.WorkspaceRole(ctx, userID, workspaceID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return role.Allows(PermWrite), nil
+}
+
+// CanManageWorkspace reports whether the user owns the workspace (false if it does not exist)
+func (c *Checker) CanManageWorkspace(ctx context.Context, userID, workspaceID uuid.UUID) (bool, error) {
+	role, err := c.store.WorkspaceRole(ctx, userID, workspaceID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return role.Allows(PermManage), nil
+}
+
+// CheckIdea returns ErrForbidden unless the user holds the permission on the idea
+func (c *Checker) CheckIdea(ctx context.Context, userID, ideaID uuid.UUID, perm Permission) error {
+	role, err := c.IdeaRole(ctx, userID, ideaID)
+	if err != nil {
+		return err
+	}
+	if !role.Allows(perm) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CheckWorkspace returns ErrForbidden unless the user holds the permission on the workspace
+func (c *Checker) CheckWorkspace(ctx context.Context, userID, workspaceID uuid.UUID, perm Permission) error {
+	role, err := c.store.WorkspaceRole(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !role.Allows(perm) {
+		return ErrForbidden
+	}
+	return nil
+}
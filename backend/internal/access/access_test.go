@@ -0,0 +1,142 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeStore holds the roles of a single user; ideas and workspaces missing
+// from the maps do not exist
+type fakeStore struct {
+	ideaRoles      map[uuid.UUID][]Role
+	workspaceRoles map[uuid.UUID]Role
+	actionPlans    map[uuid.UUID]uuid.UUID // action plan -> idea
+	architectures  map[uuid.UUID]uuid.UUID // architecture -> idea
+	modules        map[uuid.UUID]uuid.UUID // module -> idea
+}
+
+func (s *fakeStore) IdeaRoles(ctx context.Context, userID, ideaID uuid.UUID) ([]Role, error) {
+	roles, ok := s.ideaRoles[ideaID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return roles, nil
+}
+
+func (s *fakeStore) WorkspaceRole(ctx context.Context, userID, workspaceID uuid.UUID) (Role, error) {
+	role, ok := s.workspaceRoles[workspaceID]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return role, nil
+}
+
+func (s *fakeStore) IdeaForActionPlan(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return lookupIdea(s.actionPlans, id)
+}
+
+func (s *fakeStore) IdeaForArchitecture(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return lookupIdea(s.architectures, id)
+}
+
+func (s *fakeStore) IdeaForModule(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return lookupIdea(s.modules, id)
+}
+
+func lookupIdea(m map[uuid.UUID]uuid.UUID, id uuid.UUID) (uuid.UUID, error) {
+	ideaID, ok := m[id]
+	if !ok {
+		return uuid.Nil, ErrNotFound
+	}
+	return ideaID, nil
+}
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role                Role
+		read, write, manage bool
+	}{
+		{RoleOwner, true, true, true},
+		{RoleEditor, true, true, false},
+		{RoleViewer, true, false, false},
+		{"", false, false, false},
+		{"admin", false, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.role.Allows(PermRead); got != tt.read {
+			t.Errorf("%q read = %v, want %v", tt.role, got, tt.read)
+		}
+		if got := tt.role.Allows(PermWrite); got != tt.write {
+			t.Errorf("%q write = %v, want %v", tt.role, got, tt.write)
+		}
+		if got := tt.role.Allows(PermManage); got != tt.manage {
+			t.Errorf("%q manage = %v, want %v", tt.role, got, tt.manage)
+		}
+	}
+}
+
+func TestIdeaRoleResolution(t *testing.T) {
+	idea := uuid.New()
+	tests := []struct {
+		name      string
+		roles     []Role // workspace membership and collaborator grants, in any order
+		want      Role
+		canRead   bool
+		canWrite  bool
+		manageErr error
+	}{
+		{name: "no access", want: "", manageErr: ErrForbidden},
+		{name: "workspace viewer", roles: []Role{RoleViewer}, want: RoleViewer, canRead: true, manageErr: ErrForbidden},
+		{name: "viewer member granted editor on the project", roles: []Role{RoleViewer, RoleEditor}, want: RoleEditor, canRead: true, canWrite: true, manageErr: ErrForbidden},
+		{name: "collaborator grant below workspace role", roles: []Role{RoleOwner, RoleViewer}, want: RoleOwner, canRead: true, canWrite: true},
+		{name: "collaborator only", roles: []Role{RoleEditor}, want: RoleEditor, canRead: true, canWrite: true, manageErr: ErrForbidden},
+		{name: "unknown role is ignored", roles: []Role{"admin", RoleViewer}, want: RoleViewer, canRead: true, manageErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewChecker(&fakeStore{ideaRoles: map[uuid.UUID][]Role{idea: tt.roles}})
+			user := uuid.New()
+
+			role, err := c.IdeaRole(ctx, user, idea)
+			if err != nil || role != tt.want {
+				t.Fatalf("IdeaRole = %q, %v; want %q", role, err, tt.want)
+			}
+			if ok, _ := c.CanRead(ctx, user, idea); ok != tt.canRead {
+				t.Errorf("CanRead = %v, want %v", ok, tt.canRead)
+			}
+			if ok, _ := c.CanWrite(ctx, user, idea); ok != tt.canWrite {
+				t.Errorf("CanWrite = %v, want %v", ok, tt.canWrite)
+			}
+			if err := c.CheckIdea(ctx, user, idea, PermManage); !errors.Is(err, tt.manageErr) {
+				t.Errorf("CheckIdea(manage) = %v, want %v", err, tt.manageErr)
+			}
+		})
+	}
+}
+
+func TestMissingResources(t *testing.T) {
+	ctx := context.Background()
+	c := NewChecker(&fakeStore{})
+	user, missing := uuid.New(), uuid.New()
+
+	if err := c.CheckIdea(ctx, user, missing, PermRead); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CheckIdea = %v, want ErrNotFound", err)
+	}
+	if ok, err := c.CanRead(ctx, user, missing); ok || err != nil {
+		t.Errorf("CanRead = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := c.CanWrite(ctx, user, missing); ok || err != nil {
+		t.Errorf("CanWrite = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := c.CanWriteWorkspace(ctx, user, missing); ok || err != nil {
+		t.Errorf("CanWriteWorkspace = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := c.CanManageWorkspace(ctx, user, missing); ok || err != nil {
+		t.Errorf("CanManageWorkspace = %v, %v; want false, nil", ok, err)
+	}
+}
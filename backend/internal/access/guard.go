@@ -0,0 +1,231 @@
+package access
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/middleware"
+)
+
+type resourceKind int
+
+const (
+	// kindSelf routes authorize in their use cases: the resource is in the
+	// body, or the route only reaches the user's own data
+	kindSelf resourceKind = iota
+	kindIdea
+	kindActionPlan
+	kindArchitecture
+	kindModule
+)
+
+// rule maps a project route to the resource it acts on. Paths ending in "/"
+// match as a prefix and take the ID from the first segment after it; other
+// paths match exactly. Rules are checked in order, so more specific paths go
+// first (as in http.ServeMux).
+type rule struct {
+	path string
+	kind resourceKind
+	// perm returns the permission the request needs; rest is the path after the ID.
+	// nil means reads need viewer and anything else needs editor.
+	perm func(r *http.Request, rest string) Permission
+}
+
+var rules = []rule{
+	{path: "/ideation/agent/chat", kind: kindSelf},
+	{path: "/ideation/ideas", kind: kindSelf},
+	{path: "/ideation/ideas/", kind: kindIdea, perm: ideaPermission},
+
+	{path: "/action-plan/agent/chat", kind: kindSelf},
+	{path: "/action-plan/by-idea/", kind: kindIdea},
+	{path: "/action-plan/", kind: kindActionPlan},
+	{path: "/action-plan", kind: kindSelf},
+
+	{path: "/architecture/agent/chat", kind: kindSelf},
+	{path: "/architecture/by-action-plan/", kind: kindActionPlan},
+	{path: "/architecture/", kind: kindArchitecture, perm: architecturePermission},
+	{path: "/architecture", kind: kindSelf},
+
+	{path: "/dev-modules/by-architecture/", kind: kindArchitecture},
+	{path: "/dev-modules/", kind: kindModule},
+	{path: "/dev-modules", kind: kindSelf},
+
+	{path: "/global-chat/messages/", kind: kindIdea},
+	{path: "/global-chat", kind: kindSelf},
+
+	{path: "/projects/", kind: kindIdea, perm: projectPermission},
+
+	// Workspaces and webhooks check membership and ownership in their use
+	// cases; notification preferences belong to the user
+	{path: "/workspaces", kind: kindSelf},
+	{path: "/workspaces/", kind: kindSelf},
+	{path: "/webhooks", kind: kindSelf},
+	{path: "/webhooks/", kind: kindSelf},
+	{path: "/notifications/", kind: kindSelf},
+}
+
+// Guard authorizes every project route (ideation, action plan, architecture
+// and development modules) against the user's role on the owning idea.
+// Reads need viewer, changes need editor; deleting an idea and managing its
+// collaborators and share links need owner. Any reader can comment.
+// Importing a project needs write access to the target workspace.
+// Routes whose resource travels in the body are authorized by their use cases.
+// Routes without a rule are rejected, so new routes must be listed here.
+// Must run after the auth middleware.
+func (c *Checker) Guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Importar o partir de un documento también crea ideas; el workspace va en la query porque el cuerpo es el archivo
+		switch r.URL.Path {
+		case "/projects/import", "/ideation/ideas/from-document", "/ideation/ideas/import-csv":
+			if !c.allowWorkspaceWrite(w, r, userID, r.URL.Query().Get("workspace_id")) {
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rl, ok := matchRule(r.URL.Path)
+		if !ok {
+			log.Printf("no access rule for %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if rl.kind == kindSelf {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ideaID, err := c.resolveIdea(r, rl)
+		if err != nil {
+			writeAccessError(w, err)
+			return
+		}
+
+		if err := c.CheckIdea(r.Context(), userID, ideaID, requiredPermission(r, rl)); err != nil {
+			writeAccessError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowWorkspaceWrite checks the user can create ideas in the workspace given
+// by raw (empty means the personal one) and writes the error otherwise
+func (c *Checker) allowWorkspaceWrite(w http.ResponseWriter, r *http.Request, userID uuid.UUID, raw string) bool {
+	if raw == "" {
+		return true
+	}
+	workspaceID, err := uuid.Parse(raw)
+	if err != nil {
+		http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+		return false
+	}
+	if err := c.CheckWorkspace(r.Context(), userID, workspaceID, PermWrite); err != nil {
+		writeAccessError(w, err)
+		return false
+	}
+	return true
+}
+
+func matchRule(path string) (rule, bool) {
+	for _, rl := range rules {
+		if strings.HasSuffix(rl.path, "/") {
+			if strings.HasPrefix(path, rl.path) {
+				return rl, true
+			}
+		} else if path == rl.path {
+			return rl, true
+		}
+	}
+	return rule{}, false
+}
+
+func requiredPermission(r *http.Request, rl rule) Permission {
+	if rl.perm != nil {
+		_, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, rl.path), "/")
+		return rl.perm(r, rest)
+	}
+	return defaultPermission(r)
+}
+
+func defaultPermission(r *http.Request) Permission {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return PermRead
+	}
+	return PermWrite
+}
+
+// ideaPermission: deleting the idea itself needs owner
+func ideaPermission(r *http.Request, rest string) Permission {
+	if r.Method == http.MethodDelete && rest == "" {
+		return PermManage
+	}
+	return defaultPermission(r)
+}
+
+// architecturePermission: generating the scaffold only reads the architecture;
+// the C4 export in agent mode calls the AI, which only editors may spend
+func architecturePermission(r *http.Request, rest string) Permission {
+	switch {
+	case r.Method == http.MethodPost && rest == "scaffold":
+		return PermRead
+	case rest == "c4" && r.URL.Query().Get("mode") == "agent":
+		return PermWrite
+	}
+	return defaultPermission(r)
+}
+
+// projectPermission: comments are open to every reader; collaborators and share links need owner
+func projectPermission(r *http.Request, rest string) Permission {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead || strings.HasPrefix(rest, "comments") {
+		return PermRead
+	}
+	return PermManage
+}
+
+var errBadID = errors.New("invalid id")
+
+// resolveIdea finds the idea that owns the resource addressed by the request
+func (c *Checker) resolveIdea(r *http.Request, rl rule) (uuid.UUID, error) {
+	raw, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, rl.path), "/")
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, errBadID
+	}
+
+	switch rl.kind {
+	case kindActionPlan:
+		return c.store.IdeaForActionPlan(r.Context(), id)
+	case kindArchitecture:
+		return c.store.IdeaForArchitecture(r.Context(), id)
+	case kindModule:
+		return c.store.IdeaForModule(r.Context(), id)
+	default:
+		return id, nil
+	}
+}
+
+func writeAccessError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errBadID):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	default:
+		log.Printf("error checking access: %v", err)
+		http.Error(w, "error checking access", http.StatusInternalServerError)
+	}
+}
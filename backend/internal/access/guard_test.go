@@ -0,0 +1,117 @@
+package access
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/middleware"
+)
+
+func TestGuard(t *testing.T) {
+	viewerIdea, editorIdea, ownerIdea := uuid.New(), uuid.New(), uuid.New()
+	viewerPlan, viewerArch, viewerModule := uuid.New(), uuid.New(), uuid.New()
+	editorArch := uuid.New()
+	editorWorkspace, viewerWorkspace := uuid.New(), uuid.New()
+
+	c := NewChecker(&fakeStore{
+		ideaRoles: map[uuid.UUID][]Role{
+			viewerIdea: {RoleViewer},
+			editorIdea: {RoleViewer, RoleEditor},
+			ownerIdea:  {RoleOwner},
+		},
+		workspaceRoles: map[uuid.UUID]Role{
+			editorWorkspace: RoleEditor,
+			viewerWorkspace: RoleViewer,
+		},
+		actionPlans:   map[uuid.UUID]uuid.UUID{viewerPlan: viewerIdea},
+		architectures: map[uuid.UUID]uuid.UUID{viewerArch: viewerIdea, editorArch: editorIdea},
+		modules:       map[uuid.UUID]uuid.UUID{viewerModule: viewerIdea},
+	})
+	guard := c.Guard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		// Ideas: read needs viewer, changes editor, deleting the idea owner
+		{"GET", "/ideation/ideas/" + viewerIdea.String(), http.StatusOK},
+		{"PUT", "/ideation/ideas/" + viewerIdea.String(), http.StatusForbidden},
+		{"PUT", "/ideation/ideas/" + editorIdea.String(), http.StatusOK},
+		{"POST", "/ideation/ideas/" + editorIdea.String() + "/messages", http.StatusOK},
+		{"DELETE", "/ideation/ideas/" + editorIdea.String(), http.StatusForbidden},
+		{"DELETE", "/ideation/ideas/" + ownerIdea.String(), http.StatusOK},
+		{"GET", "/ideation/ideas/" + uuid.NewString(), http.StatusNotFound},
+		{"GET", "/ideation/ideas/not-an-id", http.StatusBadRequest},
+
+		// Body-addressed routes are authorized by their use cases
+		{"POST", "/ideation/ideas", http.StatusOK},
+		{"POST", "/ideation/agent/chat", http.StatusOK},
+		{"POST", "/action-plan/agent/chat", http.StatusOK},
+		{"POST", "/action-plan", http.StatusOK},
+		{"POST", "/architecture", http.StatusOK},
+		{"POST", "/dev-modules", http.StatusOK},
+		{"POST", "/global-chat", http.StatusOK},
+
+		// Stages resolve to their idea
+		{"GET", "/action-plan/" + viewerPlan.String(), http.StatusOK},
+		{"PUT", "/action-plan/" + viewerPlan.String(), http.StatusForbidden},
+		{"GET", "/action-plan/" + uuid.NewString(), http.StatusNotFound},
+		{"GET", "/action-plan/by-idea/" + viewerIdea.String(), http.StatusOK},
+		{"GET", "/architecture/by-action-plan/" + viewerPlan.String(), http.StatusOK},
+		{"PUT", "/architecture/" + viewerArch.String(), http.StatusForbidden},
+		{"POST", "/architecture/" + viewerArch.String() + "/scaffold", http.StatusOK},
+		{"GET", "/architecture/" + viewerArch.String() + "/c4", http.StatusOK},
+		{"GET", "/architecture/" + viewerArch.String() + "/c4?mode=deterministic&format=json", http.StatusOK},
+		{"GET", "/architecture/" + viewerArch.String() + "/c4?mode=agent", http.StatusForbidden},
+		{"GET", "/architecture/" + editorArch.String() + "/c4?mode=agent", http.StatusOK},
+		{"GET", "/dev-modules/by-architecture/" + viewerArch.String(), http.StatusOK},
+		{"PUT", "/dev-modules/" + viewerModule.String(), http.StatusForbidden},
+		{"GET", "/global-chat/messages/" + viewerIdea.String(), http.StatusOK},
+
+		// Projects: any reader comments, collaborators and share links need owner
+		{"GET", "/projects/" + viewerIdea.String() + "/export", http.StatusOK},
+		{"POST", "/projects/" + viewerIdea.String() + "/comments", http.StatusOK},
+		{"POST", "/projects/" + editorIdea.String() + "/collaborators", http.StatusForbidden},
+		{"POST", "/projects/" + ownerIdea.String() + "/share-links", http.StatusOK},
+
+		// Imports need write access to the target workspace
+		{"POST", "/projects/import", http.StatusOK},
+		{"POST", "/projects/import?workspace_id=" + editorWorkspace.String(), http.StatusOK},
+		{"POST", "/projects/import?workspace_id=" + viewerWorkspace.String(), http.StatusForbidden},
+		{"POST", "/ideation/ideas/import-csv?workspace_id=" + viewerWorkspace.String(), http.StatusForbidden},
+		{"POST", "/ideation/ideas/from-document?workspace_id=" + uuid.NewString(), http.StatusNotFound},
+		{"POST", "/projects/import?workspace_id=nope", http.StatusBadRequest},
+
+		// Routes without a rule are denied
+		{"GET", "/ideation/unknown", http.StatusNotFound},
+		{"GET", "/action-plans", http.StatusNotFound},
+		{"DELETE", "/", http.StatusNotFound},
+	}
+
+	user := uuid.New()
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			r = r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, user))
+			w := httptest.NewRecorder()
+			guard.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		guard.ServeHTTP(w, httptest.NewRequest("GET", "/ideation/ideas/"+viewerIdea.String(), nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+}
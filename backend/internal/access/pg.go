@@ -0,0 +1,98 @@
+package access
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type pgStore struct{ db *sql.DB }
+
+// NewPGStore creates a Store backed by Postgres
+func NewPGStore(db *sql.DB) Store { return &pgStore{db: db} }
+
+func (s *pgStore) IdeaRoles(ctx context.Context, userID, ideaID uuid.UUID) ([]Role, error) {
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM ideation_ideas WHERE id = $1)`, ideaID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT m.role
+		  FROM ideation_ideas i
+		  JOIN workspace_members m ON m.workspace_id = i.workspace_id
+		 WHERE i.id = $1 AND m.user_id = $2
+		UNION ALL
+		SELECT role
+		  FROM project_collaborators
+		 WHERE idea_id = $1 AND user_id = $2
+	`, ideaID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []Role
+	for rows.Next() {
+		var role Role
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (s *pgStore) WorkspaceRole(ctx context.Context, userID, workspaceID uuid.UUID) (Role, error) {
+	var role sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT m.role
+		  FROM workspaces w
+		  LEFT JOIN workspace_members m ON m.workspace_id = w.id AND m.user_id = $2
+		 WHERE w.id = $1
+	`, workspaceID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return Role(role.String), nil
+}
+
+func (s *pgStore) IdeaForActionPlan(ctx context.Context, actionPlanID uuid.UUID) (uuid.UUID, error) {
+	return s.ideaID(ctx, `SELECT idea_id FROM action_plans WHERE id = $1`, actionPlanID)
+}
+
+func (s *pgStore) IdeaForArchitecture(ctx context.Context, architectureID uuid.UUID) (uuid.UUID, error) {
+	return s.ideaID(ctx, `
+		SELECT ap.idea_id
+		  FROM architectures a
+		  JOIN action_plans ap ON ap.id = a.action_plan_id
+		 WHERE a.id = $1
+	`, architectureID)
+}
+
+func (s *pgStore) IdeaForModule(ctx context.Context, moduleID uuid.UUID) (uuid.UUID, error) {
+	return s.ideaID(ctx, `
+		SELECT ap.idea_id
+		  FROM development_modules m
+		  JOIN architectures a ON a.id = m.architecture_id
+		  JOIN action_plans ap ON ap.id = a.action_plan_id
+		 WHERE m.id = $1
+	`, moduleID)
+}
+
+func (s *pgStore) ideaID(ctx context.Context, query string, id uuid.UUID) (uuid.UUID, error) {
+	var ideaID uuid.UUID
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&ideaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, ErrNotFound
+	}
+	return ideaID, err
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,7 +14,9 @@ import (
 
 	"github.com/dark/idea-forge/internal/actionplan/domain"
 	"github.com/dark/idea-forge/internal/actionplan/usecase"
+	"github.com/dark/idea-forge/internal/events"
 	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+	"github.com/dark/idea-forge/internal/middleware"
 	"github.com/google/uuid"
 )
 
@@ -92,7 +95,12 @@ func (h *Handlers) createActionPlan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	plan, err := h.Usecase.CreateActionPlan(r.Context(), ideaID)
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	plan, err := h.Usecase.CreateActionPlan(r.Context(), userID, ideaID)
+	if errors.Is(err, domain.ErrForbidden) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		return
@@ -109,7 +117,8 @@ func (h *Handlers) createActionPlan(w http.ResponseWriter, r *http.Request) {
 
 	// Enviar mensaje inicial del agente en background
 	go func() {
-		bgCtx, bgCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		// WithoutCancel conserva el usuario del request (atribución de uso de IA)
+		bgCtx, bgCancel := context.WithTimeout(context.WithoutCancel(r.Context()), 30*time.Second)
 		defer bgCancel()
 
 		if err := h.sendInitialAgentMessage(bgCtx, plan, ideaID); err != nil {
@@ -297,7 +306,12 @@ func (h *Handlers) chat(w http.ResponseWriter, r *http.Request) {
 		Role:         "user",
 		Content:      in.Message,
 	}
-	if err := h.Usecase.AddMessage(r.Context(), userMsg); err != nil {
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	if err := h.Usecase.AddMessage(r.Context(), userID, userMsg); err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -317,7 +331,7 @@ func (h *Handlers) chat(w http.ResponseWriter, r *http.Request) {
 		Role:         "assistant",
 		Content:      agentResp,
 	}
-	if err := h.Usecase.AddMessage(r.Context(), agentMsg); err != nil {
+	if err := h.Usecase.AddMessage(r.Context(), uuid.Nil, agentMsg); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -344,7 +358,7 @@ func (h *Handlers) sendInitialAgentMessage(ctx context.Context, plan *domain.Act
 		Content:      response,
 	}
 
-	return h.Usecase.AddMessage(ctx, msg)
+	return h.Usecase.AddMessage(ctx, uuid.Nil, msg)
 }
 
 func (h *Handlers) callGenkitAgent(ctx context.Context, plan *domain.ActionPlan, userMessage string) (string, error) {
@@ -452,7 +466,7 @@ func (h *Handlers) generateAndSaveInitialPlan(ctx context.Context, plan *domain.
 	plan.NonFunctionalRequirements = result.NonFunctionalRequirements
 	plan.BusinessLogicFlow = result.BusinessLogicFlow
 
-	return h.Usecase.UpdateActionPlan(ctx, plan)
+	return h.Usecase.UpdateActionPlan(events.WithSource(ctx, events.SourceAgent), plan)
 }
 
 func (h *Handlers) editSection(w http.ResponseWriter, r *http.Request) {
@@ -537,7 +551,7 @@ func (h *Handlers) editSection(w http.ResponseWriter, r *http.Request) {
 		case "business_logic_flow":
 			plan.BusinessLogicFlow = genkitResult.UpdatedSection
 		}
-		if err := h.Usecase.UpdateActionPlan(r.Context(), plan); err != nil {
+		if err := h.Usecase.UpdateActionPlan(events.WithSource(r.Context(), events.SourceAgent), plan); err != nil {
 			log.Printf("error updating plan after edit section: %v", err)
 		}
 	}
@@ -672,11 +686,10 @@ func (h *Handlers) propagateChanges(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if updated {
-		if err := h.Usecase.UpdateActionPlan(r.Context(), plan); err != nil {
+		if err := h.Usecase.ApplyPropagation(r.Context(), plan, in.Source); err != nil {
 			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 			return
 		}
-		log.Printf("[propagate] Action plan %s updated from %s", planID, in.Source)
 	}
 
 	writeJSON(w, map[string]interface{}{
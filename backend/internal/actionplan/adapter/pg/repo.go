@@ -8,23 +8,26 @@ import (
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/actionplan/domain"
 	"github.com/dark/idea-forge/internal/actionplan/port"
+	"github.com/dark/idea-forge/internal/events"
 )
 
 type repo struct{ db *sql.DB }
 
 func NewRepo(db *sql.DB) port.ActionPlanRepository { return &repo{db: db} }
 
-func (r *repo) Save(ctx context.Context, plan *domain.ActionPlan) error {
+func (r *repo) Save(ctx context.Context, plan *domain.ActionPlan, evts ...events.Event) error {
 	now := time.Now()
 	plan.CreatedAt = now
 	plan.UpdatedAt = now
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO action_plans
-		  (id, idea_id, status, functional_requirements, non_functional_requirements, business_logic_flow, completed, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
-	`, plan.ID, plan.IdeaID, plan.Status, plan.FunctionalRequirements, plan.NonFunctionalRequirements, plan.BusinessLogicFlow, plan.Completed, plan.CreatedAt, plan.UpdatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO action_plans
+			  (id, idea_id, status, functional_requirements, non_functional_requirements, business_logic_flow, completed, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+		`, plan.ID, plan.IdeaID, plan.Status, plan.FunctionalRequirements, plan.NonFunctionalRequirements, plan.BusinessLogicFlow, plan.Completed, plan.CreatedAt, plan.UpdatedAt)
+		return err
+	}, evts...)
 }
 
 func (r *repo) FindByID(ctx context.Context, id uuid.UUID) (*domain.ActionPlan, error) {
@@ -55,25 +58,29 @@ func (r *repo) FindByIdeaID(ctx context.Context, ideaID uuid.UUID) (*domain.Acti
 	return &plan, nil
 }
 
-func (r *repo) Update(ctx context.Context, plan *domain.ActionPlan) error {
+func (r *repo) Update(ctx context.Context, plan *domain.ActionPlan, evts ...events.Event) error {
 	plan.UpdatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, `
-		UPDATE action_plans
-		   SET status=$2, functional_requirements=$3, non_functional_requirements=$4, business_logic_flow=$5, completed=$6, updated_at=$7
-		 WHERE id=$1
-	`, plan.ID, plan.Status, plan.FunctionalRequirements, plan.NonFunctionalRequirements, plan.BusinessLogicFlow, plan.Completed, plan.UpdatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE action_plans
+			   SET status=$2, functional_requirements=$3, non_functional_requirements=$4, business_logic_flow=$5, completed=$6, updated_at=$7
+			 WHERE id=$1
+		`, plan.ID, plan.Status, plan.FunctionalRequirements, plan.NonFunctionalRequirements, plan.BusinessLogicFlow, plan.Completed, plan.UpdatedAt)
+		return err
+	}, evts...)
 }
 
-func (r *repo) AppendMessage(ctx context.Context, msg *domain.ActionPlanMessage) error {
+func (r *repo) AppendMessage(ctx context.Context, msg *domain.ActionPlanMessage, evts ...events.Event) error {
 	msg.CreatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO action_plan_messages (id, action_plan_id, role, content, created_at)
-		VALUES ($1,$2,$3,$4,$5)
-	`, msg.ID, msg.ActionPlanID, msg.Role, msg.Content, msg.CreatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO action_plan_messages (id, action_plan_id, role, content, created_at)
+			VALUES ($1,$2,$3,$4,$5)
+		`, msg.ID, msg.ActionPlanID, msg.Role, msg.Content, msg.CreatedAt)
+		return err
+	}, evts...)
 }
 
 func (r *repo) ListMessages(ctx context.Context, actionPlanID uuid.UUID, limit int) ([]domain.ActionPlanMessage, error) {
@@ -1,11 +1,15 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrForbidden is returned when the user cannot change the project
+var ErrForbidden = errors.New("forbidden")
+
 // ActionPlan represents a detailed action plan derived from a completed idea
 type ActionPlan struct {
 	ID                        uuid.UUID `json:"id" db:"id"`
@@ -18,3 +22,12 @@ type ActionPlan struct {
 	CreatedAt                 time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt                 time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// Sections returns the editable text sections keyed by their JSON name
+func (p *ActionPlan) Sections() map[string]string {
+	return map[string]string{
+		"functional_requirements":     p.FunctionalRequirements,
+		"non_functional_requirements": p.NonFunctionalRequirements,
+		"business_logic_flow":         p.BusinessLogicFlow,
+	}
+}
@@ -5,17 +5,24 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/actionplan/domain"
+	"github.com/dark/idea-forge/internal/events"
 )
 
-// ActionPlanRepository defines the interface for action plan data persistence
+// ActionPlanRepository defines the interface for action plan data persistence.
+// Mutations take the domain events they produce so both commit together.
 type ActionPlanRepository interface {
 	// ActionPlan operations
-	Save(ctx context.Context, plan *domain.ActionPlan) error
+	Save(ctx context.Context, plan *domain.ActionPlan, evts ...events.Event) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.ActionPlan, error)
 	FindByIdeaID(ctx context.Context, ideaID uuid.UUID) (*domain.ActionPlan, error)
-	Update(ctx context.Context, plan *domain.ActionPlan) error
+	Update(ctx context.Context, plan *domain.ActionPlan, evts ...events.Event) error
 
 	// Message operations
-	AppendMessage(ctx context.Context, msg *domain.ActionPlanMessage) error
+	AppendMessage(ctx context.Context, msg *domain.ActionPlanMessage, evts ...events.Event) error
 	ListMessages(ctx context.Context, actionPlanID uuid.UUID, limit int) ([]domain.ActionPlanMessage, error)
 }
+
+// AccessChecker tells whether a user can change a project
+type AccessChecker interface {
+	CanWrite(ctx context.Context, userID, ideaID uuid.UUID) (bool, error)
+}
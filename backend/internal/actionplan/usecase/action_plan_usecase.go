@@ -6,20 +6,27 @@ import (
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/actionplan/domain"
 	"github.com/dark/idea-forge/internal/actionplan/port"
+	"github.com/dark/idea-forge/internal/events"
 )
 
 // ActionPlanUsecase handles business logic for action plans
 type ActionPlanUsecase struct {
-	repo port.ActionPlanRepository
+	repo   port.ActionPlanRepository
+	access port.AccessChecker
 }
 
 // NewActionPlanUsecase creates a new action plan use case
-func NewActionPlanUsecase(repo port.ActionPlanRepository) *ActionPlanUsecase {
-	return &ActionPlanUsecase{repo: repo}
+func NewActionPlanUsecase(repo port.ActionPlanRepository, access port.AccessChecker) *ActionPlanUsecase {
+	return &ActionPlanUsecase{repo: repo, access: access}
 }
 
-// CreateActionPlan creates a new action plan from a completed idea
-func (uc *ActionPlanUsecase) CreateActionPlan(ctx context.Context, ideaID uuid.UUID) (*domain.ActionPlan, error) {
+// CreateActionPlan creates a new action plan from a completed idea.
+// The user needs write access to the idea (domain.ErrForbidden otherwise).
+func (uc *ActionPlanUsecase) CreateActionPlan(ctx context.Context, userID, ideaID uuid.UUID) (*domain.ActionPlan, error) {
+	if err := uc.authorize(ctx, userID, ideaID); err != nil {
+		return nil, err
+	}
+
 	// Check if action plan already exists for this idea
 	existing, err := uc.repo.FindByIdeaID(ctx, ideaID)
 	if err == nil && existing != nil {
@@ -36,7 +43,8 @@ func (uc *ActionPlanUsecase) CreateActionPlan(ctx context.Context, ideaID uuid.U
 		Completed:                 false,
 	}
 
-	if err := uc.repo.Save(ctx, plan); err != nil {
+	created := events.StageCreated{IdeaID: ideaID, Stage: events.StageActionPlan, StageID: plan.ID}
+	if err := uc.repo.Save(ctx, plan, created); err != nil {
 		return nil, err
 	}
 
@@ -53,17 +61,77 @@ func (uc *ActionPlanUsecase) GetActionPlanByIdeaID(ctx context.Context, ideaID u
 	return uc.repo.FindByIdeaID(ctx, ideaID)
 }
 
-// UpdateActionPlan updates an existing action plan
+// UpdateActionPlan updates an existing action plan, recording one event per changed section
 func (uc *ActionPlanUsecase) UpdateActionPlan(ctx context.Context, plan *domain.ActionPlan) error {
-	return uc.repo.Update(ctx, plan)
+	return uc.update(ctx, plan, "")
+}
+
+// ApplyPropagation updates sections of the plan from a change made elsewhere (source)
+func (uc *ActionPlanUsecase) ApplyPropagation(ctx context.Context, plan *domain.ActionPlan, source string) error {
+	return uc.update(ctx, plan, source)
 }
 
-// AddMessage adds a message to the action plan conversation
-func (uc *ActionPlanUsecase) AddMessage(ctx context.Context, msg *domain.ActionPlanMessage) error {
-	return uc.repo.AppendMessage(ctx, msg)
+func (uc *ActionPlanUsecase) update(ctx context.Context, plan *domain.ActionPlan, source string) error {
+	if source != "" {
+		ctx = events.WithSource(ctx, source)
+	}
+	previous, err := uc.repo.FindByID(ctx, plan.ID)
+	if err != nil {
+		return err
+	}
+
+	evts := events.ChangedSections(plan.IdeaID, events.StageActionPlan, plan.ID, previous.Sections(), plan.Sections())
+	if source != "" && len(evts) > 0 {
+		evts = append(evts, events.Propagation(source, evts))
+	}
+	if plan.Status != previous.Status || plan.Completed != previous.Completed {
+		evts = append(evts, events.StageStatusChanged{
+			IdeaID:    plan.IdeaID,
+			Stage:     events.StageActionPlan,
+			StageID:   plan.ID,
+			Status:    plan.Status,
+			Completed: plan.Completed,
+		})
+	}
+	return uc.repo.Update(ctx, plan, evts...)
+}
+
+// AddMessage adds a message to the action plan conversation. userID is the
+// author and needs write access (domain.ErrForbidden otherwise); uuid.Nil
+// posts on behalf of the agent.
+func (uc *ActionPlanUsecase) AddMessage(ctx context.Context, userID uuid.UUID, msg *domain.ActionPlanMessage) error {
+	plan, err := uc.repo.FindByID(ctx, msg.ActionPlanID)
+	if err != nil {
+		return err
+	}
+	if err := uc.authorize(ctx, userID, plan.IdeaID); err != nil {
+		return err
+	}
+	return uc.repo.AppendMessage(ctx, msg, events.ChatMessagePosted{
+		IdeaID:    plan.IdeaID,
+		Stage:     events.StageActionPlan,
+		MessageID: msg.ID,
+		Role:      msg.Role,
+		Content:   msg.Content,
+	})
 }
 
 // GetMessages retrieves messages for an action plan
 func (uc *ActionPlanUsecase) GetMessages(ctx context.Context, actionPlanID uuid.UUID, limit int) ([]domain.ActionPlanMessage, error) {
 	return uc.repo.ListMessages(ctx, actionPlanID, limit)
 }
+
+// authorize checks the user can change the idea; uuid.Nil is the system itself
+func (uc *ActionPlanUsecase) authorize(ctx context.Context, userID, ideaID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return nil
+	}
+	ok, err := uc.access.CanWrite(ctx, userID, ideaID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
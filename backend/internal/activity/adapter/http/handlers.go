@@ -0,0 +1,77 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dark/idea-forge/internal/activity/domain"
+	"github.com/dark/idea-forge/internal/activity/usecase"
+	"github.com/google/uuid"
+)
+
+// Handlers exposes project timelines. Read access to {ideaID} is checked by the access guard.
+type Handlers struct {
+	Usecase *usecase.ActivityUsecase
+}
+
+func (h *Handlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /projects/{ideaID}/activity", h.list)
+}
+
+func (h *Handlers) list(w http.ResponseWriter, r *http.Request) {
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := domain.Filter{
+		Stage:  q.Get("stage"),
+		Field:  q.Get("field"),
+		Action: q.Get("action"),
+		Source: q.Get("source"),
+		Limit:  queryInt(r, "limit"),
+		Offset: queryInt(r, "offset"),
+	}
+	if v := q.Get("actor_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "invalid actor_id", http.StatusBadRequest)
+			return
+		}
+		filter.ActorID = &id
+	}
+	for key, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := q.Get(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "invalid "+key+" (expected RFC 3339)", http.StatusBadRequest)
+				return
+			}
+			*dst = &t
+		}
+	}
+
+	page, err := h.Usecase.List(r.Context(), ideaID, filter)
+	if err != nil {
+		log.Printf("activity error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, page, http.StatusOK)
+}
+
+func queryInt(r *http.Request, key string) int {
+	n, _ := strconv.Atoi(r.URL.Query().Get(key))
+	return n
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
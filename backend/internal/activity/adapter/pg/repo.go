@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/activity/domain"
+	"github.com/dark/idea-forge/internal/activity/port"
+	"github.com/google/uuid"
+)
+
+type repo struct{ db *sql.DB }
+
+func NewRepo(db *sql.DB) port.ActivityRepository { return &repo{db: db} }
+
+func (r *repo) Record(ctx context.Context, a *domain.Activity) error {
+	var actor uuid.NullUUID
+	if a.Actor != nil {
+		actor = uuid.NullUUID{UUID: a.Actor.ID, Valid: true}
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO project_activity (id, idea_id, action, stage, field, summary, source, actor_id, occurred_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9
+		WHERE EXISTS (SELECT 1 FROM ideation_ideas WHERE id = $2)
+		ON CONFLICT (id) DO NOTHING
+	`, a.ID, a.IdeaID, a.Action, a.Stage, a.Field, a.Summary, a.Source, actor, a.OccurredAt)
+	return err
+}
+
+func (r *repo) List(ctx context.Context, ideaID uuid.UUID, filter domain.Filter) (*domain.Page, error) {
+	args := []any{ideaID}
+	conds := []string{"a.idea_id = $1"}
+	for _, eq := range []struct{ column, value string }{
+		{"a.stage", filter.Stage},
+		{"a.field", filter.Field},
+		{"a.action", filter.Action},
+		{"a.source", filter.Source},
+	} {
+		if eq.value != "" {
+			args = append(args, eq.value)
+			conds = append(conds, fmt.Sprintf("%s = $%d", eq.column, len(args)))
+		}
+	}
+	if filter.ActorID != nil {
+		args = append(args, *filter.ActorID)
+		conds = append(conds, fmt.Sprintf("a.actor_id = $%d", len(args)))
+	}
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		conds = append(conds, fmt.Sprintf("a.occurred_at >= $%d", len(args)))
+	}
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		conds = append(conds, fmt.Sprintf("a.occurred_at < $%d", len(args)))
+	}
+	where := "WHERE " + strings.Join(conds, " AND ")
+
+	page := &domain.Page{Activity: []domain.Activity{}}
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM project_activity a `+where, args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT a.id, a.idea_id, a.action, a.stage, a.field, a.summary, a.source, a.actor_id, COALESCE(u.username, ''), a.occurred_at
+		FROM project_activity a
+		LEFT JOIN users u ON u.id = a.actor_id
+		%s
+		ORDER BY a.occurred_at DESC, a.id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.Activity
+		var actor uuid.NullUUID
+		var username string
+		if err := rows.Scan(&a.ID, &a.IdeaID, &a.Action, &a.Stage, &a.Field, &a.Summary, &a.Source, &actor, &username, &a.OccurredAt); err != nil {
+			return nil, err
+		}
+		if actor.Valid {
+			a.Actor = &domain.Actor{ID: actor.UUID, Username: username}
+		}
+		page.Activity = append(page.Activity, a)
+	}
+	return page, rows.Err()
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StageModules groups the development modules in the timeline
+const StageModules = "dev_modules"
+
+// Activity is one entry of a project timeline: a change made by a user, an
+// agent or a propagation. Entries are derived from the domain events and kept
+// apart from the chat history.
+type Activity struct {
+	ID         uuid.UUID `json:"id"` // the event it was derived from
+	IdeaID     uuid.UUID `json:"idea_id"`
+	Action     string    `json:"action"` // event type, e.g. "action_plan.section_updated"
+	Stage      string    `json:"stage"`  // idea, action_plan, architecture or dev_modules
+	Field      string    `json:"field,omitempty"`
+	Summary    string    `json:"summary"`
+	Source     string    `json:"source"` // user, agent, system or a propagation source such as global_chat
+	Actor      *Actor    `json:"actor,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Actor is the user whose request caused the change
+type Actor struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+// Filter narrows a project timeline
+type Filter struct {
+	Stage   string
+	Field   string
+	Action  string
+	Source  string
+	ActorID *uuid.UUID
+	Since   *time.Time
+	Until   *time.Time
+	Limit   int
+	Offset  int
+}
+
+// Page is one page of a timeline, newest first
+type Page struct {
+	Activity []Activity `json:"activity"`
+	Total    int        `json:"total"`
+}
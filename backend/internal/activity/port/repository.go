@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/dark/idea-forge/internal/activity/domain"
+	"github.com/google/uuid"
+)
+
+// ActivityRepository persists project timelines
+type ActivityRepository interface {
+	// Record stores an entry once; recording the same ID again is a no-op, and
+	// entries of projects that no longer exist are dropped
+	Record(ctx context.Context, a *domain.Activity) error
+	List(ctx context.Context, ideaID uuid.UUID, filter domain.Filter) (*domain.Page, error)
+}
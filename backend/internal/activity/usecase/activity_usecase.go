@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/activity/domain"
+	"github.com/dark/idea-forge/internal/activity/port"
+	"github.com/dark/idea-forge/internal/events"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// ActivityUsecase builds and serves the per-project timelines
+type ActivityUsecase struct {
+	repo port.ActivityRepository
+}
+
+// NewActivityUsecase creates a new activity use case
+func NewActivityUsecase(repo port.ActivityRepository) *ActivityUsecase {
+	return &ActivityUsecase{repo: repo}
+}
+
+// List returns the timeline of a project, newest first
+func (uc *ActivityUsecase) List(ctx context.Context, ideaID uuid.UUID, filter domain.Filter) (*domain.Page, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return uc.repo.List(ctx, ideaID, filter)
+}
+
+// Record adds the entry for a domain event to the timeline. It is an events
+// dispatcher handler; events that are not project changes (chat messages,
+// deletions, ...) are skipped.
+func (uc *ActivityUsecase) Record(ctx context.Context, r events.Record) error {
+	e, err := r.Decode()
+	if err != nil {
+		return err
+	}
+	stage, field, summary, ok := describe(e, r.Source)
+	if !ok {
+		return nil
+	}
+
+	a := &domain.Activity{
+		ID:         r.ID,
+		IdeaID:     r.IdeaID,
+		Action:     r.Type,
+		Stage:      stage,
+		Field:      field,
+		Summary:    summary,
+		Source:     r.Source,
+		OccurredAt: r.OccurredAt,
+	}
+	if r.ActorID != nil {
+		a.Actor = &domain.Actor{ID: *r.ActorID}
+	}
+	return uc.repo.Record(ctx, a)
+}
+
+// describe tells where in the project an event happened and summarizes it
+func describe(e events.Event, source string) (stage, field, summary string, ok bool) {
+	switch e := e.(type) {
+	case *events.SectionEdited:
+		return e.Stage, e.Section, fmt.Sprintf("%s %s (%s)", editVerb(source), label(e.Section), label(e.Stage)), true
+	case *events.ProjectImported:
+		return events.StageIdea, "", fmt.Sprintf("Imported the project with %d development modules", e.Modules), true
+	case *events.IdeaCompleted:
+		return events.StageIdea, "completed", "Marked the idea as completed", true
+	case *events.StageCreated:
+		return e.Stage, "", "Created the " + label(e.Stage), true
+	case *events.StageStatusChanged:
+		summary = fmt.Sprintf("Set the %s status to %s", label(e.Stage), e.Status)
+		if e.Completed {
+			summary += " (completed)"
+		}
+		return e.Stage, "status", summary, true
+	case *events.ArchitectureGenerated:
+		return events.StageArchitecture, "", "Generated the architecture", true
+	case *events.ModulesGenerated:
+		return domain.StageModules, "", fmt.Sprintf("Generated %d development modules", len(e.Modules)), true
+	case *events.ModulesReplaced:
+		return domain.StageModules, "", fmt.Sprintf("Replaced the development modules (%d)", len(e.Modules)), true
+	case *events.ModuleCreated:
+		return domain.StageModules, e.Module.Name, fmt.Sprintf("Created module %q", e.Module.Name), true
+	case *events.ModuleUpdated:
+		return domain.StageModules, e.Module.Name, fmt.Sprintf("Edited module %q", e.Module.Name), true
+	case *events.ModuleDeleted:
+		return domain.StageModules, e.Name, fmt.Sprintf("Deleted module %q", e.Name), true
+	case *events.ModuleStatusChanged:
+		return domain.StageModules, e.Name, fmt.Sprintf("Moved module %q from %s to %s", e.Name, e.PreviousStatus, e.Status), true
+	}
+	return "", "", "", false
+}
+
+func editVerb(source string) string {
+	switch source {
+	case events.SourceUser:
+		return "Edited"
+	case events.SourceAgent:
+		return "Agent edited"
+	case events.SourceSystem:
+		return "Updated"
+	}
+	return "Propagated from " + label(source) + ":"
+}
+
+// label turns an identifier such as "functional_requirements" into words
+func label(s string) string {
+	return strings.ReplaceAll(s, "_", " ")
+}
@@ -0,0 +1,224 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dark/idea-forge/internal/admin/domain"
+	"github.com/dark/idea-forge/internal/admin/usecase"
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// Handlers exposes the admin API. Register it on a mux guarded by
+// middleware.RequireRole("admin").
+type Handlers struct {
+	Usecase *usecase.AdminUsecase
+}
+
+func (h *Handlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/users", h.listUsers)
+	mux.HandleFunc("GET /admin/users/{id}", h.getUser)
+	mux.HandleFunc("GET /admin/users/{id}/usage", h.getUsage)
+	mux.HandleFunc("POST /admin/users/{id}/suspend", h.suspendUser)
+	mux.HandleFunc("POST /admin/users/{id}/reactivate", h.reactivateUser)
+	mux.HandleFunc("POST /admin/users/{id}/force-password-reset", h.forcePasswordReset)
+	mux.HandleFunc("PUT /admin/users/{id}/role", h.changeRole)
+	mux.HandleFunc("GET /admin/audit-log", h.listAuditLog)
+}
+
+func (h *Handlers) listUsers(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := domain.UserFilter{
+		Query:  q.Get("q"),
+		Role:   q.Get("role"),
+		Status: q.Get("status"),
+		Limit:  queryInt(r, "limit"),
+		Offset: queryInt(r, "offset"),
+	}
+
+	page, err := h.Usecase.ListUsers(r.Context(), adminID, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, page, http.StatusOK)
+}
+
+func (h *Handlers) getUser(w http.ResponseWriter, r *http.Request) {
+	adminID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.Usecase.GetUser(r.Context(), adminID, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, user, http.StatusOK)
+}
+
+func (h *Handlers) getUsage(w http.ResponseWriter, r *http.Request) {
+	adminID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	usage, err := h.Usecase.GetUsage(r.Context(), adminID, userID, queryInt(r, "days"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, usage, http.StatusOK)
+}
+
+func (h *Handlers) suspendUser(w http.ResponseWriter, r *http.Request) {
+	adminID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.Usecase.SuspendUser(r.Context(), adminID, userID, in.Reason); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) reactivateUser(w http.ResponseWriter, r *http.Request) {
+	adminID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Usecase.ReactivateUser(r.Context(), adminID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) forcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	adminID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Usecase.ForcePasswordReset(r.Context(), adminID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) changeRole(w http.ResponseWriter, r *http.Request) {
+	adminID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Usecase.ChangeRole(r.Context(), adminID, userID, in.Role); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) listAuditLog(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := domain.AuditFilter{
+		Action: q.Get("action"),
+		Limit:  queryInt(r, "limit"),
+		Offset: queryInt(r, "offset"),
+	}
+	if v := q.Get("admin_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "invalid admin_id", http.StatusBadRequest)
+			return
+		}
+		filter.AdminID = &id
+	}
+	if v := q.Get("target_user_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "invalid target_user_id", http.StatusBadRequest)
+			return
+		}
+		filter.TargetUserID = &id
+	}
+
+	entries, err := h.Usecase.ListAuditLog(r.Context(), filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, entries, http.StatusOK)
+}
+
+// parseIDs returns the acting admin and the target user from the path
+func parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return adminID, userID, true
+}
+
+func queryInt(r *http.Request, key string) int {
+	n, _ := strconv.Atoi(r.URL.Query().Get(key))
+	return n
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrUserNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidRole:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrCannotModifySelf:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrAlreadyInStatus, domain.ErrUserNotActive:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,306 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dark/idea-forge/internal/admin/domain"
+	"github.com/dark/idea-forge/internal/admin/port"
+	"github.com/google/uuid"
+)
+
+type repo struct{ db *sql.DB }
+
+func NewRepo(db *sql.DB) port.AdminRepository { return &repo{db: db} }
+
+// userSummaryColumns selects a user with its idea and AI call counters
+const userSummaryColumns = `
+	u.id, u.username, u.email, u.status, u.role, u.created_at, u.updated_at,
+	(SELECT COUNT(*) FROM ideation_ideas i WHERE i.user_id = u.id),
+	(SELECT COUNT(*) FROM ai_usage_events e WHERE e.user_id = u.id)
+`
+
+func (r *repo) ListUsers(ctx context.Context, filter domain.UserFilter) (*domain.UserPage, error) {
+	var conds []string
+	var args []any
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		args = append(args, "%"+strings.ToLower(q)+"%")
+		conds = append(conds, fmt.Sprintf("(LOWER(u.username) LIKE $%d OR LOWER(u.email) LIKE $%d)", len(args), len(args)))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conds = append(conds, fmt.Sprintf("u.role = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("u.status = $%d", len(args)))
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	page := &domain.UserPage{Users: []domain.UserSummary{}}
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users u `+where, args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM users u
+		%s
+		ORDER BY u.created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, userSummaryColumns, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u domain.UserSummary
+		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.Status, &u.Role, &u.CreatedAt, &u.UpdatedAt, &u.IdeaCount, &u.AICalls); err != nil {
+			return nil, err
+		}
+		page.Users = append(page.Users, u)
+	}
+	return page, rows.Err()
+}
+
+func (r *repo) GetUser(ctx context.Context, id uuid.UUID) (*domain.UserSummary, error) {
+	var u domain.UserSummary
+	err := r.db.QueryRowContext(ctx, `SELECT `+userSummaryColumns+` FROM users u WHERE u.id = $1`, id).
+		Scan(&u.ID, &u.Username, &u.Email, &u.Status, &u.Role, &u.CreatedAt, &u.UpdatedAt, &u.IdeaCount, &u.AICalls)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *repo) GetUsage(ctx context.Context, id uuid.UUID, since time.Time) (*domain.UserUsage, error) {
+	usage := &domain.UserUsage{
+		UserID:            id,
+		Since:             since,
+		AICallsByDay:      []domain.DailyCount{},
+		AICallsByEndpoint: map[string]int{},
+	}
+
+	// Stage counters follow the idea -> action plan -> architecture -> modules chain
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(DISTINCT i.id),
+			COUNT(DISTINCT ap.id),
+			COUNT(DISTINCT a.id),
+			COUNT(DISTINCT m.id)
+		FROM ideation_ideas i
+		LEFT JOIN action_plans ap ON ap.idea_id = i.id
+		LEFT JOIN architectures a ON a.action_plan_id = ap.id
+		LEFT JOIN development_modules m ON m.architecture_id = a.id
+		WHERE i.user_id = $1
+	`, id).Scan(&usage.IdeaCount, &usage.ActionPlanCount, &usage.ArchitectureCount, &usage.ModuleCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastCall sql.NullTime
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE created_at >= $2), MAX(created_at)
+		FROM ai_usage_events
+		WHERE user_id = $1
+	`, id, since).Scan(&usage.AICallsTotal, &usage.AICallsInPeriod, &lastCall)
+	if err != nil {
+		return nil, err
+	}
+	if lastCall.Valid {
+		usage.LastAICallAt = &lastCall.Time
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT TO_CHAR(DATE(created_at), 'YYYY-MM-DD') AS day, COUNT(*)
+		FROM ai_usage_events
+		WHERE user_id = $1 AND created_at >= $2
+		GROUP BY day
+		ORDER BY day
+	`, id, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d domain.DailyCount
+		if err := rows.Scan(&d.Day, &d.Count); err != nil {
+			return nil, err
+		}
+		usage.AICallsByDay = append(usage.AICallsByDay, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	endpointRows, err := r.db.QueryContext(ctx, `
+		SELECT endpoint, COUNT(*)
+		FROM ai_usage_events
+		WHERE user_id = $1 AND created_at >= $2
+		GROUP BY endpoint
+	`, id, since)
+	if err != nil {
+		return nil, err
+	}
+	defer endpointRows.Close()
+	for endpointRows.Next() {
+		var endpoint string
+		var count int
+		if err := endpointRows.Scan(&endpoint, &count); err != nil {
+			return nil, err
+		}
+		usage.AICallsByEndpoint[endpoint] = count
+	}
+	return usage, endpointRows.Err()
+}
+
+func (r *repo) UpdateUserStatus(ctx context.Context, id uuid.UUID, status string, audit *domain.AuditEntry) error {
+	return r.mutateWithAudit(ctx, audit, `UPDATE users SET status = $2, updated_at = NOW() WHERE id = $1`, id, status)
+}
+
+func (r *repo) UpdateUserRole(ctx context.Context, id uuid.UUID, role string, audit *domain.AuditEntry) error {
+	return r.mutateWithAudit(ctx, audit, `UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1`, id, role)
+}
+
+// ClearPassword blanks the password hash so the old password stops working
+// until the user completes the reset flow
+func (r *repo) ClearPassword(ctx context.Context, id uuid.UUID, audit *domain.AuditEntry) error {
+	return r.mutateWithAudit(ctx, audit, `UPDATE users SET password_hash = '', updated_at = NOW() WHERE id = $1`, id)
+}
+
+func (r *repo) mutateWithAudit(ctx context.Context, audit *domain.AuditEntry, query string, args ...any) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	if err := insertAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repo) RecordAudit(ctx context.Context, entry *domain.AuditEntry) error {
+	return insertAudit(ctx, r.db, entry)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertAudit(ctx context.Context, db execer, entry *domain.AuditEntry) error {
+	var details []byte
+	if entry.Details != nil {
+		var err error
+		if details, err = json.Marshal(entry.Details); err != nil {
+			return err
+		}
+	}
+
+	var target uuid.NullUUID
+	if entry.TargetUserID != nil {
+		target = uuid.NullUUID{UUID: *entry.TargetUserID, Valid: true}
+	}
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO admin_audit_log (id, admin_id, action, target_user_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, entry.ID, entry.AdminID, entry.Action, target, nullableJSON(details), entry.CreatedAt)
+	return err
+}
+
+func (r *repo) ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var conds []string
+	var args []any
+	if filter.AdminID != nil {
+		args = append(args, *filter.AdminID)
+		conds = append(conds, fmt.Sprintf("admin_id = $%d", len(args)))
+	}
+	if filter.TargetUserID != nil {
+		args = append(args, *filter.TargetUserID)
+		conds = append(conds, fmt.Sprintf("target_user_id = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conds = append(conds, fmt.Sprintf("action = $%d", len(args)))
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, admin_id, action, target_user_id, details, created_at
+		FROM admin_audit_log
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var e domain.AuditEntry
+		var admin, target uuid.NullUUID
+		var details []byte
+		if err := rows.Scan(&e.ID, &admin, &e.Action, &target, &details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.AdminID = admin.UUID // zero when the admin account was deleted
+		if target.Valid {
+			e.TargetUserID = &target.UUID
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *repo) RecordAICall(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO ai_usage_events (id, user_id, endpoint, created_at)
+		VALUES ($1, $2, $3, NOW())
+	`, uuid.New(), userID, endpoint)
+	return err
+}
+
+func nullableJSON(b []byte) any {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited admin actions
+const (
+	ActionListUsers          = "list_users"
+	ActionViewUser           = "view_user"
+	ActionViewUsage          = "view_usage"
+	ActionSuspendUser        = "suspend_user"
+	ActionReactivateUser     = "reactivate_user"
+	ActionForcePasswordReset = "force_password_reset"
+	ActionChangeRole         = "change_role"
+)
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrInvalidRole      = errors.New("invalid role")
+	ErrCannotModifySelf = errors.New("admins cannot suspend, demote or reset themselves")
+	ErrAlreadyInStatus  = errors.New("user already has that status")
+	ErrUserNotActive    = errors.New("only active users can be suspended")
+)
+
+// UserSummary is a user as listed in the admin console
+type UserSummary struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Status    string    `json:"status"`
+	Role      string    `json:"role"`
+	IdeaCount int       `json:"idea_count"`
+	AICalls   int       `json:"ai_calls"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserFilter narrows the user listing
+type UserFilter struct {
+	Query  string // matches username or email
+	Role   string
+	Status string
+	Limit  int
+	Offset int
+}
+
+// UserPage is one page of the user listing
+type UserPage struct {
+	Users []UserSummary `json:"users"`
+	Total int           `json:"total"`
+}
+
+// UserUsage aggregates what a user has created and how much AI they consumed
+type UserUsage struct {
+	UserID            uuid.UUID      `json:"user_id"`
+	IdeaCount         int            `json:"idea_count"`
+	ActionPlanCount   int            `json:"action_plan_count"`
+	ArchitectureCount int            `json:"architecture_count"`
+	ModuleCount       int            `json:"module_count"`
+	AICallsTotal      int            `json:"ai_calls_total"`
+	AICallsInPeriod   int            `json:"ai_calls_in_period"`
+	AICallsByDay      []DailyCount   `json:"ai_calls_by_day"`
+	AICallsByEndpoint map[string]int `json:"ai_calls_by_endpoint"`
+	LastAICallAt      *time.Time     `json:"last_ai_call_at,omitempty"`
+	Since             time.Time      `json:"since"`
+}
+
+// DailyCount is a per-day counter
+type DailyCount struct {
+	Day   string `json:"day"`
+	Count int    `json:"count"`
+}
+
+// AuditEntry records one admin action
+type AuditEntry struct {
+	ID           uuid.UUID      `json:"id"`
+	AdminID      uuid.UUID      `json:"admin_id"`
+	Action       string         `json:"action"`
+	TargetUserID *uuid.UUID     `json:"target_user_id,omitempty"`
+	Details      map[string]any `json:"details,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// AuditFilter narrows the audit log listing
+type AuditFilter struct {
+	AdminID      *uuid.UUID
+	TargetUserID *uuid.UUID
+	Action       string
+	Limit        int
+	Offset       int
+}
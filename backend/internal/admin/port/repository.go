@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/dark/idea-forge/internal/admin/domain"
+	"github.com/google/uuid"
+)
+
+// AdminRepository defines persistence for the admin console.
+// Mutations take the audit entry so the change and its audit record commit together.
+type AdminRepository interface {
+	ListUsers(ctx context.Context, filter domain.UserFilter) (*domain.UserPage, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*domain.UserSummary, error)
+	GetUsage(ctx context.Context, id uuid.UUID, since time.Time) (*domain.UserUsage, error)
+
+	UpdateUserStatus(ctx context.Context, id uuid.UUID, status string, audit *domain.AuditEntry) error
+	UpdateUserRole(ctx context.Context, id uuid.UUID, role string, audit *domain.AuditEntry) error
+	ClearPassword(ctx context.Context, id uuid.UUID, audit *domain.AuditEntry) error
+
+	RecordAudit(ctx context.Context, entry *domain.AuditEntry) error
+	ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+
+	// RecordAICall stores one outgoing AI call attributed to a user
+	RecordAICall(ctx context.Context, userID uuid.UUID, endpoint string) error
+}
+
+// PasswordResetSender emails a password reset link to a user
+type PasswordResetSender interface {
+	SendPasswordReset(ctx context.Context, email string) error
+}
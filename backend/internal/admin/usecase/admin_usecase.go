@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/admin/domain"
+	"github.com/dark/idea-forge/internal/admin/port"
+	authdomain "github.com/dark/idea-forge/internal/auth/domain"
+)
+
+const (
+	defaultPageSize  = 50
+	maxPageSize      = 200
+	defaultUsageDays = 30
+)
+
+// AdminUsecase implements the admin console. Every action is written to the audit log.
+type AdminUsecase struct {
+	repo        port.AdminRepository
+	resetSender port.PasswordResetSender
+}
+
+// NewAdminUsecase creates a new admin use case
+func NewAdminUsecase(repo port.AdminRepository, resetSender port.PasswordResetSender) *AdminUsecase {
+	return &AdminUsecase{repo: repo, resetSender: resetSender}
+}
+
+// ListUsers lists and searches users
+func (uc *AdminUsecase) ListUsers(ctx context.Context, adminID uuid.UUID, filter domain.UserFilter) (*domain.UserPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	page, err := uc.repo.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.RecordAudit(ctx, newAudit(adminID, domain.ActionListUsers, nil, map[string]any{
+		"query":  filter.Query,
+		"role":   filter.Role,
+		"status": filter.Status,
+		"offset": filter.Offset,
+	})); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetUser returns one user
+func (uc *AdminUsecase) GetUser(ctx context.Context, adminID, userID uuid.UUID) (*domain.UserSummary, error) {
+	user, err := uc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.RecordAudit(ctx, newAudit(adminID, domain.ActionViewUser, &userID, nil)); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUsage returns per-user usage for the last days
+func (uc *AdminUsecase) GetUsage(ctx context.Context, adminID, userID uuid.UUID, days int) (*domain.UserUsage, error) {
+	if days <= 0 {
+		days = defaultUsageDays
+	}
+	if _, err := uc.repo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	usage, err := uc.repo.GetUsage(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.RecordAudit(ctx, newAudit(adminID, domain.ActionViewUsage, &userID, map[string]any{"days": days})); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// SuspendUser blocks an active account; the user loses access on the next request
+func (uc *AdminUsecase) SuspendUser(ctx context.Context, adminID, userID uuid.UUID, reason string) error {
+	if adminID == userID {
+		return domain.ErrCannotModifySelf
+	}
+	user, err := uc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status == string(authdomain.UserStatusSuspended) {
+		return domain.ErrAlreadyInStatus
+	}
+	if user.Status != string(authdomain.UserStatusActive) {
+		return domain.ErrUserNotActive
+	}
+
+	audit := newAudit(adminID, domain.ActionSuspendUser, &userID, map[string]any{"reason": reason})
+	return uc.repo.UpdateUserStatus(ctx, userID, string(authdomain.UserStatusSuspended), audit)
+}
+
+// ReactivateUser lifts a suspension
+func (uc *AdminUsecase) ReactivateUser(ctx context.Context, adminID, userID uuid.UUID) error {
+	user, err := uc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status != string(authdomain.UserStatusSuspended) {
+		return domain.ErrAlreadyInStatus
+	}
+
+	audit := newAudit(adminID, domain.ActionReactivateUser, &userID, nil)
+	return uc.repo.UpdateUserStatus(ctx, userID, string(authdomain.UserStatusActive), audit)
+}
+
+// ForcePasswordReset invalidates the current password and emails a reset link
+func (uc *AdminUsecase) ForcePasswordReset(ctx context.Context, adminID, userID uuid.UUID) error {
+	if adminID == userID {
+		return domain.ErrCannotModifySelf
+	}
+	user, err := uc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	audit := newAudit(adminID, domain.ActionForcePasswordReset, &userID, nil)
+	if err := uc.repo.ClearPassword(ctx, userID, audit); err != nil {
+		return err
+	}
+	return uc.resetSender.SendPasswordReset(ctx, user.Email)
+}
+
+// ChangeRole grants or revokes the admin role
+func (uc *AdminUsecase) ChangeRole(ctx context.Context, adminID, userID uuid.UUID, role string) error {
+	if role != string(authdomain.RoleUser) && role != string(authdomain.RoleAdmin) {
+		return domain.ErrInvalidRole
+	}
+	if adminID == userID {
+		return domain.ErrCannotModifySelf
+	}
+	user, err := uc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	audit := newAudit(adminID, domain.ActionChangeRole, &userID, map[string]any{"from": user.Role, "to": role})
+	return uc.repo.UpdateUserRole(ctx, userID, role, audit)
+}
+
+// ListAuditLog returns audit entries, newest first
+func (uc *AdminUsecase) ListAuditLog(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	return uc.repo.ListAudit(ctx, filter)
+}
+
+func newAudit(adminID uuid.UUID, action string, target *uuid.UUID, details map[string]any) *domain.AuditEntry {
+	return &domain.AuditEntry{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       action,
+		TargetUserID: target,
+		Details:      details,
+		CreatedAt:    time.Now(),
+	}
+}
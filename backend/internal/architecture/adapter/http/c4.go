@@ -0,0 +1,136 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/dark/idea-forge/internal/architecture/domain"
+	"github.com/dark/idea-forge/internal/architecture/usecase"
+	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+)
+
+// c4 exports the C4 model of the architecture and its modules:
+// GET /architecture/{id}/c4?mode=agent&format=json
+//
+// The model is deterministic; mode=agent asks the agent to name the
+// containers after the project, so the access guard requires write
+// permission for it. The default format is Structurizr DSL.
+func (h *Handlers) c4(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/architecture/"), "/c4")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	mode, format := r.URL.Query().Get("mode"), r.URL.Query().Get("format")
+	if mode != "" && mode != "deterministic" && mode != "agent" {
+		http.Error(w, "mode must be deterministic or agent", http.StatusBadRequest)
+		return
+	}
+	if format != "" && format != "dsl" && format != "json" {
+		http.Error(w, "format must be dsl or json", http.StatusBadRequest)
+		return
+	}
+
+	arch, err := h.Usecase.GetArchitecture(r.Context(), id)
+	if err != nil {
+		http.Error(w, "architecture not found", http.StatusNotFound)
+		return
+	}
+
+	modules, err := h.modules(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The system is named after the idea
+	var idea *ideadomain.Idea
+	if plan, err := h.ActionPlanUsecase.GetActionPlan(r.Context(), arch.ActionPlanID); err == nil {
+		idea, _ = h.IdeaUsecase.Execute(r.Context(), plan.IdeaID)
+	}
+	name, description := "", ""
+	if idea != nil {
+		name, description = idea.Title, idea.Objective
+	}
+
+	var agent usecase.ContainerAgent
+	if mode == "agent" {
+		agent = func(ctx context.Context, containers []domain.C4Element) ([]domain.C4Element, error) {
+			return h.callGenkitNameContainers(ctx, arch, idea, containers)
+		}
+	}
+	model, err := h.C4.Export(r.Context(), name, description, arch, modules, agent)
+	if err != nil {
+		log.Printf("error calling genkit to name containers of architecture %s: %v", id, err)
+		http.Error(w, "error calling agent", http.StatusBadGateway)
+		return
+	}
+
+	if format == "json" {
+		writeJSON(w, model, http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="workspace-`+id.String()[:8]+`.dsl"`)
+	w.Write([]byte(usecase.StructurizrDSL(model)))
+}
+
+// callGenkitNameContainers asks the agent for project specific names of the C4 containers
+func (h *Handlers) callGenkitNameContainers(ctx context.Context, arch *domain.Architecture, idea *ideadomain.Idea, containers []domain.C4Element) ([]domain.C4Element, error) {
+	genkitURL := os.Getenv("GENKIT_BASE_URL")
+	if genkitURL == "" {
+		genkitURL = "http://localhost:3001"
+	}
+
+	payload := map[string]interface{}{
+		"idea":         idea,
+		"architecture": arch,
+		"containers":   containers,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, genkitURL+"/architecture/name-containers", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	genkitToken := os.Getenv("GENKIT_TOKEN")
+	if genkitToken != "" {
+		req.Header.Set("Authorization", "Bearer "+genkitToken)
+	}
+
+	resp, err := h.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("genkit request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("genkit returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Containers []domain.C4Element `json:"containers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode containers response: %w", err)
+	}
+	return result.Containers, nil
+}
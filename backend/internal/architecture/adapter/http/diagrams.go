@@ -0,0 +1,85 @@
+package httpadapter
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+// diagrams serves the diagrams drawn from the architecture:
+//
+//	GET /architecture/{id}/diagrams         kinds and their formats
+//	GET /architecture/{id}/diagrams/{kind}  source text, ?format=mermaid|plantuml
+//
+// The ETag is the hash of the sections the diagram is drawn from, so clients
+// can revalidate with If-None-Match.
+func (h *Handlers) diagrams(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	idStr, kind, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/architecture/"), "/diagrams")
+	kind = strings.Trim(kind, "/")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	if kind == "" {
+		writeJSON(w, h.Diagrams.Kinds(), http.StatusOK)
+		return
+	}
+	if _, ok := h.Diagrams.Kinds()[kind]; !ok {
+		http.Error(w, "unknown diagram kind", http.StatusNotFound)
+		return
+	}
+
+	arch, err := h.Usecase.GetArchitecture(r.Context(), id)
+	if err != nil {
+		http.Error(w, "architecture not found", http.StatusNotFound)
+		return
+	}
+	in := domain.DiagramInput{Architecture: arch}
+	switch kind {
+	case domain.DiagramModules:
+		if in.Modules, err = h.modules(r.Context(), id); err != nil {
+			log.Printf("error loading modules of architecture %s: %v", id, err)
+			http.Error(w, "error loading modules", http.StatusInternalServerError)
+			return
+		}
+	case domain.DiagramFlow:
+		plan, err := h.ActionPlanUsecase.GetActionPlan(r.Context(), arch.ActionPlanID)
+		if err != nil {
+			http.Error(w, "action plan not found", http.StatusNotFound)
+			return
+		}
+		in.BusinessLogicFlow = plan.BusinessLogicFlow
+	}
+
+	diagram, err := h.Diagrams.Diagram(in, kind, r.URL.Query().Get("format"))
+	switch {
+	case errors.Is(err, domain.ErrUnknownDiagram):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, domain.ErrEmptyDiagram):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	etag := `"` + diagram.Hash + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("X-Diagram-Format", diagram.Format)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(diagram.Source))
+}
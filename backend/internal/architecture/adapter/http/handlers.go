@@ -3,7 +3,9 @@ package httpadapter
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,12 +16,17 @@ import (
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/architecture/domain"
 	"github.com/dark/idea-forge/internal/architecture/usecase"
+	"github.com/dark/idea-forge/internal/events"
 	actionplandomain "github.com/dark/idea-forge/internal/actionplan/domain"
 	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+	"github.com/dark/idea-forge/internal/middleware"
 )
 
 type Handlers struct {
 	Usecase         *usecase.ArchitectureUsecase
+	Scaffold        *usecase.ScaffoldUsecase
+	Diagrams        *usecase.DiagramUsecase
+	C4              *usecase.C4Usecase
 	HTTPClient      *http.Client
 	ActionPlanUsecase interface {
 		GetActionPlan(ctx context.Context, id uuid.UUID) (*actionplandomain.ActionPlan, error)
@@ -46,6 +53,28 @@ type DevModule struct {
 	Status           string
 }
 
+// modules loads the development modules of an architecture for the generators
+func (h *Handlers) modules(ctx context.Context, architectureID uuid.UUID) ([]domain.Module, error) {
+	devModules, err := h.DevModuleUsecase.GetModulesByArchitectureID(ctx, architectureID)
+	if err != nil {
+		return nil, err
+	}
+	modules := make([]domain.Module, len(devModules))
+	for i, m := range devModules {
+		modules[i] = domain.Module{
+			ID:               m.ID,
+			Name:             m.Name,
+			Description:      m.Description,
+			Functionality:    m.Functionality,
+			TechnicalDetails: m.TechnicalDetails,
+			Dependencies:     usecase.ParseDependencies(m.Dependencies),
+			Priority:         m.Priority,
+			Status:           m.Status,
+		}
+	}
+	return modules, nil
+}
+
 func (h *Handlers) Register(mux *http.ServeMux) {
 	mux.HandleFunc("/architecture", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
@@ -68,6 +97,22 @@ func (h *Handlers) Register(mux *http.ServeMux) {
 			h.editSection(w, r)
 			return
 		}
+		if strings.HasSuffix(r.URL.Path, "/scaffold") {
+			h.scaffold(w, r)
+			return
+		}
+		if strings.Contains(r.URL.Path, "/schema") {
+			h.schema(w, r)
+			return
+		}
+		if strings.Contains(r.URL.Path, "/diagrams") {
+			h.diagrams(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/c4") {
+			h.c4(w, r)
+			return
+		}
 		if r.Method == http.MethodGet {
 			h.getArchitecture(w, r)
 			return
@@ -104,9 +149,10 @@ func (h *Handlers) createArchitecture(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	arch, err := h.Usecase.CreateArchitecture(r.Context(), actionPlanID)
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	arch, err := h.Usecase.CreateArchitecture(r.Context(), userID, actionPlanID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeStageError(w, err)
 		return
 	}
 
@@ -126,7 +172,7 @@ func (h *Handlers) createArchitecture(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generar contenido inicial con IA
-	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(events.WithSource(r.Context(), events.SourceAgent), 60*time.Second)
 	defer cancel()
 
 	if err := h.generateAndSaveInitialContent(ctx, arch, actionPlan, idea); err != nil {
@@ -209,7 +255,7 @@ func (h *Handlers) generateAndSaveInitialContent(ctx context.Context, arch *doma
 	arch.ArchitecturePattern = aiResponse.ArchitecturePattern
 	arch.SystemArchitecture = aiResponse.SystemArchitecture
 
-	if err := h.Usecase.UpdateArchitecture(ctx, arch); err != nil {
+	if err := h.Usecase.SaveGenerated(ctx, arch); err != nil {
 		return fmt.Errorf("failed to save architecture content: %w", err)
 	}
 
@@ -454,8 +500,9 @@ func (h *Handlers) handleChat(w http.ResponseWriter, r *http.Request) {
 		Role:           "user",
 		Content:        req.Message,
 	}
-	if err := h.Usecase.AddMessage(r.Context(), userMsg); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	if err := h.Usecase.AddMessage(r.Context(), userID, userMsg); err != nil {
+		writeStageError(w, err)
 		return
 	}
 
@@ -519,7 +566,7 @@ func (h *Handlers) handleChat(w http.ResponseWriter, r *http.Request) {
 		Role:           "assistant",
 		Content:        genkitResp.Response,
 	}
-	if err := h.Usecase.AddMessage(r.Context(), assistantMsg); err != nil {
+	if err := h.Usecase.AddMessage(r.Context(), uuid.Nil, assistantMsg); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -613,7 +660,7 @@ func (h *Handlers) editSection(w http.ResponseWriter, r *http.Request) {
 		case "system_architecture":
 			arch.SystemArchitecture = genkitResult.UpdatedSection
 		}
-		if err := h.Usecase.UpdateArchitecture(r.Context(), arch); err != nil {
+		if err := h.Usecase.UpdateArchitecture(events.WithSource(r.Context(), events.SourceAgent), arch); err != nil {
 			log.Printf("error updating architecture after edit section: %v", err)
 		}
 	}
@@ -702,6 +749,18 @@ func (h *Handlers) callGenkitEditSection(ctx context.Context, arch *domain.Archi
 	return &result, nil
 }
 
+// writeStageError maps the errors of creating or chatting on a body-addressed stage
+func writeStageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func writeJSON(w http.ResponseWriter, data interface{}, status int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
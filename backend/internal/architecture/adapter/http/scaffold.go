@@ -0,0 +1,63 @@
+package httpadapter
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+// scaffold downloads a project skeleton generated from the architecture and
+// its modules: POST /architecture/{id}/scaffold?template=go
+func (h *Handlers) scaffold(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/architecture/"), "/scaffold")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	arch, err := h.Usecase.GetArchitecture(r.Context(), id)
+	if err != nil {
+		http.Error(w, "architecture not found", http.StatusNotFound)
+		return
+	}
+
+	modules, err := h.modules(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The project is named after the idea; the generator falls back to the architecture ID
+	name := ""
+	if plan, err := h.ActionPlanUsecase.GetActionPlan(r.Context(), arch.ActionPlanID); err == nil {
+		if idea, err := h.IdeaUsecase.Execute(r.Context(), plan.IdeaID); err == nil {
+			name = idea.Title
+		}
+	}
+
+	archive, err := h.Scaffold.Generate(name, arch, modules, r.URL.Query().Get("template"))
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownTemplate) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("error generating scaffold for architecture %s: %v", id, err)
+		http.Error(w, "error generating scaffold", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+archive.Filename+`"`)
+	w.Header().Set("X-Scaffold-Template", archive.Template)
+	w.Write(archive.Content)
+}
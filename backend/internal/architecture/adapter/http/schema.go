@@ -0,0 +1,94 @@
+package httpadapter
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/dark/idea-forge/internal/architecture/usecase"
+)
+
+// schema serves the analysis of the database schema:
+//
+//	GET  /architecture/{id}/schema             issues and parsed tables
+//	GET  /architecture/{id}/schema/migrations  goose migrations as a zip
+//	POST /architecture/{id}/schema/repair      asks the agent to fix the errors
+func (h *Handlers) schema(w http.ResponseWriter, r *http.Request) {
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/architecture/"), "/schema")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var method string
+	switch action {
+	case "", "/migrations":
+		method = http.MethodGet
+	case "/repair":
+		method = http.MethodPost
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != method {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	arch, err := h.Usecase.GetArchitecture(r.Context(), id)
+	if err != nil {
+		http.Error(w, "architecture not found", http.StatusNotFound)
+		return
+	}
+
+	switch action {
+	case "":
+		writeJSON(w, usecase.AnalyzeSchema(arch), http.StatusOK)
+
+	case "/migrations":
+		now := time.Now().UTC()
+		migrations := usecase.SchemaMigrations(arch.DatabaseSchema, now)
+		if len(migrations) == 0 {
+			http.Error(w, "the database schema has no SQL DDL", http.StatusUnprocessableEntity)
+			return
+		}
+		content, err := usecase.MigrationsArchive(migrations, now)
+		if err != nil {
+			log.Printf("error zipping migrations for architecture %s: %v", id, err)
+			http.Error(w, "error generating migrations", http.StatusInternalServerError)
+			return
+		}
+		// migrations of a schema with errors may not apply; the client can warn
+		report := usecase.AnalyzeSchema(arch)
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="migrations-`+id.String()[:8]+`.zip"`)
+		w.Header().Set("X-Schema-Errors", strconv.Itoa(report.Errors))
+		w.Write(content)
+
+	case "/repair":
+		var agentErr error
+		repair, err := h.Usecase.RepairSchema(r.Context(), arch, func(ctx context.Context, message string) (string, string, error) {
+			result, err := h.callGenkitEditSection(ctx, arch, "database_schema", message, nil, nil, arch.Sections())
+			if err != nil {
+				agentErr = err
+				return "", "", err
+			}
+			return result.Reply, result.UpdatedSection, nil
+		})
+		if err != nil {
+			if agentErr != nil {
+				log.Printf("error calling genkit to repair schema of architecture %s: %v", id, err)
+				http.Error(w, "error calling agent", http.StatusBadGateway)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, repair, http.StatusOK)
+	}
+}
@@ -8,23 +8,26 @@ import (
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/architecture/domain"
 	"github.com/dark/idea-forge/internal/architecture/port"
+	"github.com/dark/idea-forge/internal/events"
 )
 
 type repo struct{ db *sql.DB }
 
 func NewRepo(db *sql.DB) port.ArchitectureRepository { return &repo{db: db} }
 
-func (r *repo) Save(ctx context.Context, arch *domain.Architecture) error {
+func (r *repo) Save(ctx context.Context, arch *domain.Architecture, evts ...events.Event) error {
 	now := time.Now()
 	arch.CreatedAt = now
 	arch.UpdatedAt = now
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO architectures
-		  (id, action_plan_id, status, user_stories, database_type, database_schema, entities_relationships, tech_stack, architecture_pattern, system_architecture, completed, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
-	`, arch.ID, arch.ActionPlanID, arch.Status, arch.UserStories, arch.DatabaseType, arch.DatabaseSchema, arch.EntitiesRelationships, arch.TechStack, arch.ArchitecturePattern, arch.SystemArchitecture, arch.Completed, arch.CreatedAt, arch.UpdatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO architectures
+			  (id, action_plan_id, status, user_stories, database_type, database_schema, entities_relationships, tech_stack, architecture_pattern, system_architecture, completed, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+		`, arch.ID, arch.ActionPlanID, arch.Status, arch.UserStories, arch.DatabaseType, arch.DatabaseSchema, arch.EntitiesRelationships, arch.TechStack, arch.ArchitecturePattern, arch.SystemArchitecture, arch.Completed, arch.CreatedAt, arch.UpdatedAt)
+		return err
+	}, evts...)
 }
 
 func (r *repo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Architecture, error) {
@@ -55,25 +58,46 @@ func (r *repo) FindByActionPlanID(ctx context.Context, actionPlanID uuid.UUID) (
 	return &arch, nil
 }
 
-func (r *repo) Update(ctx context.Context, arch *domain.Architecture) error {
+func (r *repo) Update(ctx context.Context, arch *domain.Architecture, evts ...events.Event) error {
 	arch.UpdatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, `
-		UPDATE architectures
-		   SET status=$2, user_stories=$3, database_type=$4, database_schema=$5, entities_relationships=$6, tech_stack=$7, architecture_pattern=$8, system_architecture=$9, completed=$10, updated_at=$11
-		 WHERE id=$1
-	`, arch.ID, arch.Status, arch.UserStories, arch.DatabaseType, arch.DatabaseSchema, arch.EntitiesRelationships, arch.TechStack, arch.ArchitecturePattern, arch.SystemArchitecture, arch.Completed, arch.UpdatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE architectures
+			   SET status=$2, user_stories=$3, database_type=$4, database_schema=$5, entities_relationships=$6, tech_stack=$7, architecture_pattern=$8, system_architecture=$9, completed=$10, updated_at=$11
+			 WHERE id=$1
+		`, arch.ID, arch.Status, arch.UserStories, arch.DatabaseType, arch.DatabaseSchema, arch.EntitiesRelationships, arch.TechStack, arch.ArchitecturePattern, arch.SystemArchitecture, arch.Completed, arch.UpdatedAt)
+		return err
+	}, evts...)
+}
+
+func (r *repo) FindIdeaID(ctx context.Context, architectureID uuid.UUID) (uuid.UUID, error) {
+	var ideaID uuid.UUID
+	err := r.db.QueryRowContext(ctx, `
+		SELECT ap.idea_id
+		  FROM architectures a
+		  JOIN action_plans ap ON ap.id = a.action_plan_id
+		 WHERE a.id=$1
+	`, architectureID).Scan(&ideaID)
+	return ideaID, err
+}
+
+func (r *repo) FindIdeaIDByActionPlanID(ctx context.Context, actionPlanID uuid.UUID) (uuid.UUID, error) {
+	var ideaID uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT idea_id FROM action_plans WHERE id=$1`, actionPlanID).Scan(&ideaID)
+	return ideaID, err
 }
 
-func (r *repo) AppendMessage(ctx context.Context, msg *domain.ArchitectureMessage) error {
+func (r *repo) AppendMessage(ctx context.Context, msg *domain.ArchitectureMessage, evts ...events.Event) error {
 	msg.CreatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO architecture_messages (id, architecture_id, role, content, created_at)
-		VALUES ($1,$2,$3,$4,$5)
-	`, msg.ID, msg.ArchitectureID, msg.Role, msg.Content, msg.CreatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO architecture_messages (id, architecture_id, role, content, created_at)
+			VALUES ($1,$2,$3,$4,$5)
+		`, msg.ID, msg.ArchitectureID, msg.Role, msg.Content, msg.CreatedAt)
+		return err
+	}, evts...)
 }
 
 func (r *repo) ListMessages(ctx context.Context, architectureID uuid.UUID, limit int) ([]domain.ArchitectureMessage, error) {
@@ -0,0 +1,161 @@
+// Package scaffold holds the per-stack templates that render a project
+// skeleton from an architecture and its development modules.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+	"github.com/dark/idea-forge/internal/architecture/port"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var goStackPattern = regexp.MustCompile(`(?i)\b(go|golang)\b`)
+
+// goFiles maps the project level templates to their output paths
+var goFiles = []struct{ template, path string }{
+	{"go.mod.tmpl", "go.mod"},
+	{"readme.md.tmpl", "README.md"},
+	{"build_order.md.tmpl", "BUILD_ORDER.md"},
+	{"makefile.tmpl", "Makefile"},
+	{"gitignore.tmpl", ".gitignore"},
+	{"main.go.tmpl", "cmd/server/main.go"},
+	{"architecture.md.tmpl", "docs/architecture.md"},
+	{"database.md.tmpl", "docs/database.md"},
+}
+
+// goModuleFiles are rendered once per module into internal/<package>/
+var goModuleFiles = []struct{ template, name string }{
+	{"module_readme.md.tmpl", "README.md"},
+	{"module_doc.go.tmpl", "doc.go"},
+	{"module_service.go.tmpl", "service.go"},
+}
+
+// goProject is the data of the Go templates
+type goProject struct {
+	*domain.Scaffold
+	Modules []goModule
+}
+
+// goModule is a module with its dependencies resolved for the Go layout.
+// Imports are the modules built before it, which its service receives;
+// Deferred are dependencies inside a cycle, which cannot be imported.
+type goModule struct {
+	domain.Module
+	ModulePath string
+	Imports    []domain.Module
+	Deferred   []string
+	External   []string
+	Total      int
+}
+
+type goTemplate struct {
+	tmpl *template.Template
+}
+
+// NewGoTemplate creates the Go template set: a standard layout with one
+// package per module under internal/, an HTTP server that wires the modules
+// in build order and goose migrations
+func NewGoTemplate() port.ScaffoldTemplate {
+	tmpl := template.Must(template.New("go").Funcs(template.FuncMap{
+		"comment": comment,
+		"trim":    strings.TrimSpace,
+		"join":    strings.Join,
+		"orNone":  orNone,
+		"add":     func(a, b int) int { return a + b },
+	}).ParseFS(templateFS, "templates/go/*.tmpl"))
+	return &goTemplate{tmpl: tmpl}
+}
+
+func (t *goTemplate) Name() string { return "go" }
+
+func (t *goTemplate) Matches(techStack string) bool {
+	return goStackPattern.MatchString(techStack)
+}
+
+func (t *goTemplate) Render(s *domain.Scaffold) ([]domain.File, error) {
+	p := &goProject{Scaffold: s}
+	order := map[string]int{}
+	for _, m := range s.Modules {
+		order[m.Name] = m.Order
+	}
+	for _, m := range s.Modules {
+		gm := goModule{Module: m, ModulePath: s.Slug, Total: len(s.Modules)}
+		for _, dep := range m.Dependencies {
+			switch n, ok := order[dep]; {
+			case !ok:
+				gm.External = append(gm.External, dep)
+			case n < m.Order:
+				gm.Imports = append(gm.Imports, s.Modules[n-1])
+			default:
+				gm.Deferred = append(gm.Deferred, dep)
+			}
+		}
+		p.Modules = append(p.Modules, gm)
+	}
+
+	var files []domain.File
+	for _, f := range goFiles {
+		content, err := t.execute(f.template, f.path, p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, domain.File{Path: f.path, Content: content})
+	}
+	for _, m := range p.Modules {
+		for _, f := range goModuleFiles {
+			path := "internal/" + m.Package + "/" + f.name
+			content, err := t.execute(f.template, path, m)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, domain.File{Path: path, Content: content})
+		}
+	}
+	for _, m := range s.Migrations {
+		f := m.File()
+		files = append(files, domain.File{Path: "migrations/" + f.Path, Content: f.Content})
+	}
+	return files, nil
+}
+
+// execute renders a template; Go sources are gofmt'ed
+func (t *goTemplate) execute(name, path string, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, fmt.Errorf("render %s: %w", path, err)
+	}
+	if !strings.HasSuffix(path, ".go") {
+		return buf.Bytes(), nil
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", path, err)
+	}
+	return src, nil
+}
+
+// comment turns text into Go line comments
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("// "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// orNone returns the trimmed text, or a placeholder for empty sections
+func orNone(text string) string {
+	if text = strings.TrimSpace(text); text == "" {
+		return "_Sin definir._"
+	}
+	return text
+}
@@ -0,0 +1,135 @@
+package scaffold_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/architecture/adapter/scaffold"
+	"github.com/dark/idea-forge/internal/architecture/domain"
+	"github.com/dark/idea-forge/internal/architecture/usecase"
+)
+
+// unzip writes the archive under dir and returns the file contents by path
+func unzip(t *testing.T, archive []byte, dir string) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("reading zip: %v", err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("reading %s: %v", f.Name, err)
+		}
+		files[f.Name] = string(content)
+		path := filepath.Join(dir, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return files
+}
+
+func TestGoTemplateMatches(t *testing.T) {
+	tests := []struct {
+		stack string
+		want  bool
+	}{
+		{"Go 1.24 con PostgreSQL", true},
+		{"Backend: golang + gin", true},
+		{"Node.js y MongoDB", false},
+		{"Django", false},
+		{"MongoDB, Cargo", false},
+	}
+	tmpl := scaffold.NewGoTemplate()
+	for _, tt := range tests {
+		if got := tmpl.Matches(tt.stack); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.stack, got, tt.want)
+		}
+	}
+}
+
+// TestGoScaffoldBuilds generates a project whose module names collide with Go
+// keywords, each other and the generated main, plus a dependency cycle, and
+// compiles it
+func TestGoScaffoldBuilds(t *testing.T) {
+	arch := &domain.Architecture{
+		ID:             uuid.New(),
+		TechStack:      "Go",
+		DatabaseSchema: "CREATE TABLE users (id UUID PRIMARY KEY, email TEXT NOT NULL);\nCREATE TABLE posts (id UUID PRIMARY KEY, user_id UUID REFERENCES users(id));",
+	}
+	modules := []domain.Module{
+		{Name: "Gestión de usuarios", Priority: 1, Description: "Altas y bajas.\n\nCon */ comentarios */ raros"},
+		{Name: "http", Priority: 2, Dependencies: []string{"Gestión de usuarios"}},
+		{Name: "Port", Priority: 3, Dependencies: []string{"http", "stripe"}},
+		{Name: "Mux", Priority: 4, Dependencies: []string{"Port", "Gestión de usuarios"}},
+		{Name: "3D", Priority: 5, Dependencies: []string{"Ciclo B"}},
+		{Name: "Ciclo B", Priority: 6, Dependencies: []string{"3D"}},
+	}
+
+	archive, err := usecase.NewScaffoldUsecase(scaffold.NewGoTemplate()).Generate("Mi Tienda", arch, modules, "")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	dir := t.TempDir()
+	files := unzip(t, archive.Content, dir)
+
+	for _, path := range []string{
+		"mi-tienda/go.mod",
+		"mi-tienda/cmd/server/main.go",
+		"mi-tienda/BUILD_ORDER.md",
+		"mi-tienda/internal/gestionusuarios/service.go",
+		"mi-tienda/internal/httpmod/service.go",
+		"mi-tienda/internal/m3d/doc.go",
+	} {
+		if _, ok := files[path]; !ok {
+			t.Errorf("scaffold has no %s", path)
+		}
+	}
+	migrations := 0
+	for path, content := range files {
+		if strings.HasPrefix(path, "mi-tienda/migrations/") {
+			migrations++
+			if !strings.HasPrefix(content, "-- +goose Up\n") || !strings.Contains(content, "-- +goose Down\n") {
+				t.Errorf("%s is not a goose migration:\n%s", path, content)
+			}
+		}
+	}
+	if migrations != 2 {
+		t.Errorf("%d migrations, want one per table", migrations)
+	}
+	if !strings.Contains(files["mi-tienda/internal/m3d/service.go"], "Dependencias circulares") {
+		t.Error("module in a cycle should mention its deferred dependency")
+	}
+
+	if testing.Short() {
+		t.Skip("skipping go build in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	cmd := exec.Command(goBin, "build", "./...")
+	cmd.Dir = filepath.Join(dir, "mi-tienda")
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local", "GOPROXY=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated project does not build: %v\n%s", err, out)
+	}
+}
@@ -1,11 +1,15 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrForbidden is returned when the user cannot change the project
+var ErrForbidden = errors.New("forbidden")
+
 // Architecture represents the technical architecture and data design for a project
 type Architecture struct {
 	ID                    uuid.UUID `json:"id" db:"id"`
@@ -29,3 +33,16 @@ type Architecture struct {
 	CreatedAt             time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// Sections returns the editable text sections keyed by their JSON name
+func (a *Architecture) Sections() map[string]string {
+	return map[string]string{
+		"user_stories":           a.UserStories,
+		"database_type":          a.DatabaseType,
+		"database_schema":        a.DatabaseSchema,
+		"entities_relationships": a.EntitiesRelationships,
+		"tech_stack":             a.TechStack,
+		"architecture_pattern":   a.ArchitecturePattern,
+		"system_architecture":    a.SystemArchitecture,
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+// Kinds of C4 container
+const (
+	ContainerWeb      = "web"
+	ContainerMobile   = "mobile"
+	ContainerAPI      = "api"
+	ContainerWorker   = "worker"
+	ContainerDatabase = "database"
+	ContainerCache    = "cache"
+	ContainerQueue    = "queue"
+	ContainerStorage  = "storage"
+	ContainerSearch   = "search"
+)
+
+// C4Element is a person, software system, container or component of a C4
+// model. ID is its identifier in the Structurizr DSL, unique in the model.
+type C4Element struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Technology  string   `json:"technology,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// C4Container is a container of the system: an application or a data store
+// that runs on its own. The development modules are the components of the
+// container that runs the backend code.
+type C4Container struct {
+	C4Element
+	Kind       string      `json:"kind"`
+	Components []C4Element `json:"components,omitempty"`
+}
+
+// C4Relationship is an arrow between two elements, by ID
+type C4Relationship struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Description string `json:"description"`
+	Technology  string `json:"technology,omitempty"`
+}
+
+// C4Model is the C4 model of an architecture: the system with its users and
+// the external systems it uses (system context), its containers and their
+// components
+type C4Model struct {
+	System        C4Element        `json:"system"`
+	People        []C4Element      `json:"people"`
+	External      []C4Element      `json:"external,omitempty"`
+	Containers    []C4Container    `json:"containers"`
+	Relationships []C4Relationship `json:"relationships"`
+}
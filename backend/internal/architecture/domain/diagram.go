@@ -0,0 +1,41 @@
+package domain
+
+import "errors"
+
+// Diagram kinds
+const (
+	DiagramER         = "er"         // entities and relationships of the database schema
+	DiagramComponents = "components" // components of the system architecture
+	DiagramModules    = "modules"    // dependencies between the development modules
+	DiagramFlow       = "flow"       // business logic flows of the action plan
+)
+
+// Diagram formats
+const (
+	DiagramMermaid  = "mermaid"
+	DiagramPlantUML = "plantuml"
+)
+
+var (
+	// ErrUnknownDiagram is returned for a diagram kind or format that is not generated
+	ErrUnknownDiagram = errors.New("unknown diagram")
+	// ErrEmptyDiagram is returned when the sections a diagram comes from have nothing to draw
+	ErrEmptyDiagram = errors.New("nothing to draw")
+)
+
+// DiagramInput is what the diagrams are drawn from: the architecture, its
+// development modules and the business logic flow of its action plan
+type DiagramInput struct {
+	Architecture      *Architecture
+	Modules           []Module
+	BusinessLogicFlow string
+}
+
+// Diagram is the source text of a diagram. Hash fingerprints the sections it
+// was drawn from, so it changes only when one of them does.
+type Diagram struct {
+	Kind   string `json:"kind"`
+	Format string `json:"format"`
+	Source string `json:"source"`
+	Hash   string `json:"hash"`
+}
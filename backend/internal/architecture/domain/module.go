@@ -0,0 +1,18 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Module is a development module as the architecture's generators (scaffold,
+// diagrams) see it
+type Module struct {
+	ID               uuid.UUID
+	Order            int // position in the build order, from 1; set by the scaffold
+	Name             string
+	Package          string // lowercase identifier, unique in the project; set by the scaffold
+	Description      string
+	Functionality    string
+	TechnicalDetails string
+	Dependencies     []string // names of the modules it depends on, or IDs before normalization
+	Priority         int
+	Status           string
+}
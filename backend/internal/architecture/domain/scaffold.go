@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnknownTemplate is returned when the requested scaffold template is not registered
+var ErrUnknownTemplate = errors.New("unknown scaffold template")
+
+// Scaffold is the project skeleton a stack template renders: the
+// architecture, its modules in build order and the migrations derived from
+// the database schema
+type Scaffold struct {
+	Name         string // project title
+	Slug         string // ASCII name for the root directory and module path
+	Architecture *Architecture
+	Modules      []Module // in build order, with Order and Package set
+	// Cycles lists the modules whose dependencies form a cycle; they are
+	// appended to the build order by priority
+	Cycles      []string
+	Migrations  []Migration
+	GeneratedAt time.Time
+}
+
+// Migration is a SQL migration with the statements to apply and revert it
+type Migration struct {
+	Version string // goose version (timestamp)
+	Name    string // snake_case description
+	Up      string
+	Down    string
+}
+
+// File renders the migration as a goose SQL file named <version>_<name>.sql
+func (m Migration) File() File {
+	content := "-- +goose Up\n" + m.Up + "\n\n-- +goose Down\n" + m.Down + "\n"
+	return File{Path: m.Version + "_" + m.Name + ".sql", Content: []byte(content)}
+}
+
+// File is a file of a rendered scaffold, relative to the project root
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// ScaffoldArchive is a rendered scaffold ready to be downloaded
+type ScaffoldArchive struct {
+	Filename string
+	Template string
+	Content  []byte
+}
@@ -0,0 +1,73 @@
+package domain
+
+// Severities of a schema issue
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Kinds of schema issue
+const (
+	IssueNoSQL         = "no_sql"               // the section has no SQL DDL
+	IssueSyntax        = "syntax"               // the statement is not valid PostgreSQL
+	IssueUnsupported   = "unsupported"          // valid in another dialect (MySQL) but not in PostgreSQL
+	IssueUnknownType   = "unknown_type"         // column type not built in nor declared
+	IssueDuplicate     = "duplicate"            // table or column defined twice
+	IssueUnknownTable  = "unknown_table"        // index or ALTER on a table that is not defined
+	IssueUnknownColumn = "unknown_column"       // key or index on a column the table does not have
+	IssueDanglingFK    = "dangling_foreign_key" // foreign key to a missing table or column
+	IssueMissingEntity = "missing_entity"       // entity of entities_relationships without a table
+	IssueNotReversible = "not_reversible"       // statement the down migration cannot revert
+)
+
+// SchemaIssue is a problem found in the database schema. Line is the line of
+// the database_schema section the statement starts at, 0 when not tied to one.
+type SchemaIssue struct {
+	Severity string `json:"severity"`
+	Kind     string `json:"kind"`
+	Line     int    `json:"line,omitempty"`
+	Table    string `json:"table,omitempty"`
+	Message  string `json:"message"`
+}
+
+// SchemaColumn is a column of a parsed table
+type SchemaColumn struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	NotNull bool   `json:"not_null"`
+}
+
+// ForeignKey is a reference from columns of a table to another table
+type ForeignKey struct {
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns"`
+}
+
+// SchemaTable is a table of the parsed schema
+type SchemaTable struct {
+	Name        string         `json:"name"`
+	Line        int            `json:"line"`
+	Columns     []SchemaColumn `json:"columns"`
+	PrimaryKey  []string       `json:"primary_key,omitempty"`
+	ForeignKeys []ForeignKey   `json:"foreign_keys,omitempty"`
+}
+
+// SchemaReport is the result of analyzing the database schema of an
+// architecture. Valid means there are tables and no error issues.
+type SchemaReport struct {
+	Valid    bool          `json:"valid"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Tables   []SchemaTable `json:"tables"`
+	Issues   []SchemaIssue `json:"issues"`
+}
+
+// SchemaRepair is the outcome of asking the agent to fix a schema: the
+// reports before and after, and whether the repaired schema was kept
+type SchemaRepair struct {
+	Applied bool          `json:"applied"`
+	Reply   string        `json:"reply,omitempty"`
+	Before  *SchemaReport `json:"before"`
+	After   *SchemaReport `json:"after,omitempty"`
+}
@@ -5,17 +5,28 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/architecture/domain"
+	"github.com/dark/idea-forge/internal/events"
 )
 
-// ArchitectureRepository defines the interface for architecture data persistence
+// ArchitectureRepository defines the interface for architecture data persistence.
+// Mutations take the domain events they produce so both commit together.
 type ArchitectureRepository interface {
 	// Architecture operations
-	Save(ctx context.Context, arch *domain.Architecture) error
+	Save(ctx context.Context, arch *domain.Architecture, evts ...events.Event) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Architecture, error)
 	FindByActionPlanID(ctx context.Context, actionPlanID uuid.UUID) (*domain.Architecture, error)
-	Update(ctx context.Context, arch *domain.Architecture) error
+	Update(ctx context.Context, arch *domain.Architecture, evts ...events.Event) error
+	// FindIdeaID returns the idea the architecture belongs to (through its action plan)
+	FindIdeaID(ctx context.Context, architectureID uuid.UUID) (uuid.UUID, error)
+	// FindIdeaIDByActionPlanID returns the idea an action plan belongs to
+	FindIdeaIDByActionPlanID(ctx context.Context, actionPlanID uuid.UUID) (uuid.UUID, error)
 
 	// Message operations
-	AppendMessage(ctx context.Context, msg *domain.ArchitectureMessage) error
+	AppendMessage(ctx context.Context, msg *domain.ArchitectureMessage, evts ...events.Event) error
 	ListMessages(ctx context.Context, architectureID uuid.UUID, limit int) ([]domain.ArchitectureMessage, error)
 }
+
+// AccessChecker tells whether a user can change a project
+type AccessChecker interface {
+	CanWrite(ctx context.Context, userID, ideaID uuid.UUID) (bool, error)
+}
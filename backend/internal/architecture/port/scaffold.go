@@ -0,0 +1,13 @@
+package port
+
+import "github.com/dark/idea-forge/internal/architecture/domain"
+
+// ScaffoldTemplate renders the project skeleton for a technology stack
+type ScaffoldTemplate interface {
+	// Name identifies the template in requests, e.g. "go"
+	Name() string
+	// Matches reports whether the template fits the architecture's tech stack
+	Matches(techStack string) bool
+	// Render returns the files of the skeleton, with paths relative to the project root
+	Render(s *domain.Scaffold) ([]domain.File, error)
+}
@@ -6,20 +6,31 @@ import (
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/architecture/domain"
 	"github.com/dark/idea-forge/internal/architecture/port"
+	"github.com/dark/idea-forge/internal/events"
 )
 
 // ArchitectureUsecase handles business logic for architecture design
 type ArchitectureUsecase struct {
-	repo port.ArchitectureRepository
+	repo   port.ArchitectureRepository
+	access port.AccessChecker
 }
 
 // NewArchitectureUsecase creates a new architecture use case
-func NewArchitectureUsecase(repo port.ArchitectureRepository) *ArchitectureUsecase {
-	return &ArchitectureUsecase{repo: repo}
+func NewArchitectureUsecase(repo port.ArchitectureRepository, access port.AccessChecker) *ArchitectureUsecase {
+	return &ArchitectureUsecase{repo: repo, access: access}
 }
 
-// CreateArchitecture creates a new architecture from a completed action plan
-func (uc *ArchitectureUsecase) CreateArchitecture(ctx context.Context, actionPlanID uuid.UUID) (*domain.Architecture, error) {
+// CreateArchitecture creates a new architecture from a completed action plan.
+// The user needs write access to the idea (domain.ErrForbidden otherwise).
+func (uc *ArchitectureUsecase) CreateArchitecture(ctx context.Context, userID, actionPlanID uuid.UUID) (*domain.Architecture, error) {
+	ideaID, err := uc.repo.FindIdeaIDByActionPlanID(ctx, actionPlanID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authorize(ctx, userID, ideaID); err != nil {
+		return nil, err
+	}
+
 	// Check if architecture already exists for this action plan
 	existing, err := uc.repo.FindByActionPlanID(ctx, actionPlanID)
 	if err == nil && existing != nil {
@@ -40,7 +51,8 @@ func (uc *ArchitectureUsecase) CreateArchitecture(ctx context.Context, actionPla
 		Completed:             false,
 	}
 
-	if err := uc.repo.Save(ctx, arch); err != nil {
+	created := events.StageCreated{IdeaID: ideaID, Stage: events.StageArchitecture, StageID: arch.ID}
+	if err := uc.repo.Save(ctx, arch, created); err != nil {
 		return nil, err
 	}
 
@@ -57,17 +69,89 @@ func (uc *ArchitectureUsecase) GetArchitectureByActionPlanID(ctx context.Context
 	return uc.repo.FindByActionPlanID(ctx, actionPlanID)
 }
 
-// UpdateArchitecture updates an existing architecture
+// UpdateArchitecture updates an existing architecture, recording one event per changed section
 func (uc *ArchitectureUsecase) UpdateArchitecture(ctx context.Context, arch *domain.Architecture) error {
-	return uc.repo.Update(ctx, arch)
+	return uc.update(ctx, arch, "", false)
+}
+
+// ApplyPropagation updates sections of the architecture from a change made elsewhere (source)
+func (uc *ArchitectureUsecase) ApplyPropagation(ctx context.Context, arch *domain.Architecture, source string) error {
+	return uc.update(ctx, arch, source, false)
 }
 
-// AddMessage adds a message to the architecture conversation
-func (uc *ArchitectureUsecase) AddMessage(ctx context.Context, msg *domain.ArchitectureMessage) error {
-	return uc.repo.AppendMessage(ctx, msg)
+// SaveGenerated stores the AI-generated content of a new architecture
+func (uc *ArchitectureUsecase) SaveGenerated(ctx context.Context, arch *domain.Architecture) error {
+	return uc.update(ctx, arch, "", true)
+}
+
+func (uc *ArchitectureUsecase) update(ctx context.Context, arch *domain.Architecture, source string, generated bool) error {
+	if source != "" {
+		ctx = events.WithSource(ctx, source)
+	}
+	previous, err := uc.repo.FindByID(ctx, arch.ID)
+	if err != nil {
+		return err
+	}
+	ideaID, err := uc.repo.FindIdeaID(ctx, arch.ID)
+	if err != nil {
+		return err
+	}
+
+	evts := events.ChangedSections(ideaID, events.StageArchitecture, arch.ID, previous.Sections(), arch.Sections())
+	if source != "" && len(evts) > 0 {
+		evts = append(evts, events.Propagation(source, evts))
+	}
+	if arch.Status != previous.Status || arch.Completed != previous.Completed {
+		evts = append(evts, events.StageStatusChanged{
+			IdeaID:    ideaID,
+			Stage:     events.StageArchitecture,
+			StageID:   arch.ID,
+			Status:    arch.Status,
+			Completed: arch.Completed,
+		})
+	}
+	if generated {
+		evts = append(evts, events.ArchitectureGenerated{IdeaID: ideaID, ArchitectureID: arch.ID})
+	}
+	return uc.repo.Update(ctx, arch, evts...)
+}
+
+// AddMessage adds a message to the architecture conversation. userID is the
+// author and needs write access (domain.ErrForbidden otherwise); uuid.Nil
+// posts on behalf of the agent.
+func (uc *ArchitectureUsecase) AddMessage(ctx context.Context, userID uuid.UUID, msg *domain.ArchitectureMessage) error {
+	ideaID, err := uc.repo.FindIdeaID(ctx, msg.ArchitectureID)
+	if err != nil {
+		return err
+	}
+	if err := uc.authorize(ctx, userID, ideaID); err != nil {
+		return err
+	}
+	return uc.repo.AppendMessage(ctx, msg, events.ChatMessagePosted{
+		IdeaID:    ideaID,
+		Stage:     events.StageArchitecture,
+		MessageID: msg.ID,
+		Role:      msg.Role,
+		Content:   msg.Content,
+	})
 }
 
 // GetMessages retrieves messages for an architecture
 func (uc *ArchitectureUsecase) GetMessages(ctx context.Context, architectureID uuid.UUID, limit int) ([]domain.ArchitectureMessage, error) {
 	return uc.repo.ListMessages(ctx, architectureID, limit)
 }
+
+// authorize checks the user can change the idea; uuid.Nil is the system itself
+func (uc *ArchitectureUsecase) authorize(ctx context.Context, userID, ideaID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return nil
+	}
+	ok, err := uc.access.CanWrite(ctx, userID, ideaID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
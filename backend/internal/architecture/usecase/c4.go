@@ -0,0 +1,510 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+// externalKind marks the technologies that are software systems of their own
+const externalKind = "external"
+
+// c4Technologies are the technologies the tech stack is scanned for, with
+// the kind of container they run in. Earlier entries win: "React Native" is
+// a mobile app, not a React web app.
+var c4Technologies = []struct {
+	kind  string
+	name  string
+	words []string // lowercase, as written
+}{
+	{domain.ContainerMobile, "React Native", []string{"react native"}},
+	{domain.ContainerMobile, "Flutter", []string{"flutter"}},
+	{domain.ContainerMobile, "Expo", []string{"expo"}},
+	{domain.ContainerMobile, "Ionic", []string{"ionic"}},
+	{domain.ContainerMobile, "Swift", []string{"swiftui", "swift"}},
+	{domain.ContainerMobile, "Android", []string{"android"}},
+	{domain.ContainerWeb, "Next.js", []string{"next.js", "nextjs"}},
+	{domain.ContainerWeb, "Nuxt", []string{"nuxt", "nuxt.js"}},
+	{domain.ContainerWeb, "React", []string{"react", "react.js", "reactjs"}},
+	{domain.ContainerWeb, "Vue", []string{"vue", "vue.js", "vuejs"}},
+	{domain.ContainerWeb, "Angular", []string{"angular"}},
+	{domain.ContainerWeb, "Svelte", []string{"sveltekit", "svelte"}},
+	{domain.ContainerWeb, "Tailwind CSS", []string{"tailwind", "tailwindcss"}},
+	{domain.ContainerWorker, "Celery", []string{"celery"}},
+	{domain.ContainerWorker, "Sidekiq", []string{"sidekiq"}},
+	{domain.ContainerWorker, "BullMQ", []string{"bullmq"}},
+	{domain.ContainerAPI, "NestJS", []string{"nestjs", "nest.js"}},
+	{domain.ContainerAPI, "Express", []string{"express", "express.js"}},
+	{domain.ContainerAPI, "Fastify", []string{"fastify"}},
+	{domain.ContainerAPI, "Node.js", []string{"node.js", "nodejs", "node"}},
+	{domain.ContainerAPI, "FastAPI", []string{"fastapi"}},
+	{domain.ContainerAPI, "Django", []string{"django"}},
+	{domain.ContainerAPI, "Flask", []string{"flask"}},
+	{domain.ContainerAPI, "Python", []string{"python"}},
+	{domain.ContainerAPI, "Spring Boot", []string{"spring boot", "spring"}},
+	{domain.ContainerAPI, "Java", []string{"java"}},
+	{domain.ContainerAPI, "Ruby on Rails", []string{"ruby on rails", "rails"}},
+	{domain.ContainerAPI, "Laravel", []string{"laravel"}},
+	{domain.ContainerAPI, "PHP", []string{"php"}},
+	{domain.ContainerAPI, "ASP.NET", []string{"asp.net", ".net", "dotnet"}},
+	{domain.ContainerAPI, "Gin", []string{"gin"}},
+	{domain.ContainerAPI, "Fiber", []string{"fiber"}},
+	{domain.ContainerAPI, "Go", []string{"golang", "go"}},
+	{domain.ContainerDatabase, "Firestore", []string{"firestore"}},
+	{domain.ContainerDatabase, "PostgreSQL", []string{"postgresql", "postgres"}},
+	{domain.ContainerDatabase, "MySQL", []string{"mysql"}},
+	{domain.ContainerDatabase, "MariaDB", []string{"mariadb"}},
+	{domain.ContainerDatabase, "MongoDB", []string{"mongodb", "mongo"}},
+	{domain.ContainerDatabase, "SQLite", []string{"sqlite"}},
+	{domain.ContainerDatabase, "SQL Server", []string{"sql server", "mssql"}},
+	{domain.ContainerDatabase, "DynamoDB", []string{"dynamodb"}},
+	{domain.ContainerDatabase, "Supabase", []string{"supabase"}},
+	{domain.ContainerDatabase, "Cassandra", []string{"cassandra"}},
+	{domain.ContainerCache, "Redis", []string{"redis"}},
+	{domain.ContainerCache, "Memcached", []string{"memcached"}},
+	{domain.ContainerQueue, "RabbitMQ", []string{"rabbitmq"}},
+	{domain.ContainerQueue, "Kafka", []string{"apache kafka", "kafka"}},
+	{domain.ContainerQueue, "Amazon SQS", []string{"amazon sqs", "aws sqs", "sqs"}},
+	{domain.ContainerQueue, "NATS", []string{"nats"}},
+	{domain.ContainerQueue, "Pub/Sub", []string{"pub sub", "pubsub"}},
+	{domain.ContainerStorage, "Amazon S3", []string{"amazon s3", "aws s3", "s3"}},
+	{domain.ContainerStorage, "Cloud Storage", []string{"cloud storage", "gcs"}},
+	{domain.ContainerStorage, "MinIO", []string{"minio"}},
+	{domain.ContainerSearch, "Elasticsearch", []string{"elasticsearch", "elastic search"}},
+	{domain.ContainerSearch, "OpenSearch", []string{"opensearch"}},
+	{domain.ContainerSearch, "Meilisearch", []string{"meilisearch"}},
+	{externalKind, "Stripe", []string{"stripe"}},
+	{externalKind, "PayPal", []string{"paypal"}},
+	{externalKind, "Mercado Pago", []string{"mercado pago", "mercadopago"}},
+	{externalKind, "SendGrid", []string{"sendgrid"}},
+	{externalKind, "Mailgun", []string{"mailgun"}},
+	{externalKind, "Twilio", []string{"twilio"}},
+	{externalKind, "Auth0", []string{"auth0"}},
+	{externalKind, "Firebase", []string{"firebase"}},
+	{externalKind, "Google Maps", []string{"google maps"}},
+	{externalKind, "OpenAI", []string{"openai"}},
+	{externalKind, "Gemini", []string{"gemini"}},
+	{externalKind, "Cloudinary", []string{"cloudinary"}},
+	{externalKind, "Algolia", []string{"algolia"}},
+}
+
+// c4Containers describes each kind of container: its name, description and
+// DSL tag, and how the containers that run code use it
+var c4Containers = map[string]struct {
+	name, description, tag, uses string
+}{
+	domain.ContainerWeb:      {"Aplicación web", "Interfaz que los usuarios usan desde el navegador", "Web Browser", ""},
+	domain.ContainerMobile:   {"Aplicación móvil", "Aplicación que los usuarios instalan en su teléfono", "Mobile App", ""},
+	domain.ContainerAPI:      {"API", "Expone la lógica de negocio a los clientes", "", ""},
+	domain.ContainerWorker:   {"Procesos en segundo plano", "Ejecuta las tareas asíncronas y programadas", "", ""},
+	domain.ContainerDatabase: {"Base de datos", "Guarda los datos del sistema", "Database", "Lee y escribe datos"},
+	domain.ContainerCache:    {"Caché", "Guarda datos de acceso frecuente y sesiones", "Database", "Lee y escribe en caché"},
+	domain.ContainerQueue:    {"Cola de mensajes", "Reparte eventos y tareas entre los procesos", "Queue", "Publica y consume mensajes"},
+	domain.ContainerStorage:  {"Almacenamiento de archivos", "Guarda los archivos que suben los usuarios", "Storage", "Guarda y lee archivos"},
+	domain.ContainerSearch:   {"Motor de búsqueda", "Indexa el contenido para las búsquedas", "Database", "Indexa y busca"},
+}
+
+// code containers are listed first, in this order; each data store gets its own
+var (
+	codeContainers  = []string{domain.ContainerWeb, domain.ContainerMobile, domain.ContainerAPI, domain.ContainerWorker}
+	storeContainers = []string{domain.ContainerDatabase, domain.ContainerCache, domain.ContainerQueue, domain.ContainerStorage, domain.ContainerSearch}
+
+	// reservedDSL are keywords of the Structurizr DSL an identifier cannot take
+	reservedDSL = []string{"workspace", "model", "views", "person", "softwaresystem", "container", "component", "group", "enterprise", "deploymentenvironment", "element", "relationship", "styles", "theme", "themes", "branding", "terminology", "configuration", "this", "tags", "description", "technology", "url", "properties", "perspectives"}
+
+	// "Como administrador, quiero..." / "As a customer, I want..."
+	userRolePattern = regexp.MustCompile(`(?i)\b(?:como|as an?)\s+(?:(?:un|una|el|la|the)\s+)?([^,.;:\n*]{2,40}?)\s*,?\s+(?:quiero|deseo|necesito|puedo|i want|i need|i can)\b`)
+)
+
+const maxPeople = 5
+
+// ContainerAgent names the containers of a C4 model after the project. It
+// gets the containers as generated and returns, by ID, the new name and
+// description of the ones it renames.
+type ContainerAgent func(ctx context.Context, containers []domain.C4Element) ([]domain.C4Element, error)
+
+// C4Usecase exports an architecture and its development modules as a C4 model
+type C4Usecase struct{}
+
+// NewC4Usecase creates a C4 use case
+func NewC4Usecase() *C4Usecase {
+	return &C4Usecase{}
+}
+
+// Export builds the C4 model of an architecture. The model is deterministic:
+// the people are the roles of the user stories, the containers the
+// technologies of the tech stack and the components the development modules
+// with their dependencies. With an agent the containers are then named after
+// the project; names it leaves empty or repeats are kept as generated.
+func (uc *C4Usecase) Export(ctx context.Context, name, description string, arch *domain.Architecture, modules []domain.Module, agent ContainerAgent) (*domain.C4Model, error) {
+	model := c4Model(name, description, arch, modules)
+	if agent == nil {
+		return model, nil
+	}
+
+	containers := make([]domain.C4Element, len(model.Containers))
+	for i, c := range model.Containers {
+		containers[i] = c.C4Element
+	}
+	named, err := agent(ctx, containers)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range named {
+		i := slices.IndexFunc(model.Containers, func(c domain.C4Container) bool { return c.ID == n.ID })
+		n.Name = plainText(n.Name, 60)
+		if i < 0 || n.Name == "" || slices.ContainsFunc(model.Containers, func(c domain.C4Container) bool { return c.ID != n.ID && strings.EqualFold(c.Name, n.Name) }) {
+			continue
+		}
+		model.Containers[i].Name = n.Name
+		if d := plainText(n.Description, 200); d != "" {
+			model.Containers[i].Description = d
+		}
+	}
+	return model, nil
+}
+
+// c4Model builds the deterministic C4 model
+func c4Model(name, description string, arch *domain.Architecture, modules []domain.Module) *domain.C4Model {
+	ids := map[string]bool{}
+	newID := func(base string) string {
+		id := strings.ReplaceAll(diagramID(base), "-", "_")
+		if slices.Contains(reservedDSL, id) {
+			id += "_"
+		}
+		candidate := id
+		for n := 2; ids[candidate]; n++ {
+			candidate = fmt.Sprintf("%s%d", id, n)
+		}
+		ids[candidate] = true
+		return candidate
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = "Sistema"
+	}
+	model := &domain.C4Model{System: domain.C4Element{ID: newID("system"), Name: plainText(name, 60), Description: plainText(description, 200)}}
+
+	for _, role := range userRoles(arch.UserStories) {
+		model.People = append(model.People, domain.C4Element{ID: newID(role), Name: role, Description: "Rol de las historias de usuario"})
+	}
+	if len(model.People) == 0 {
+		model.People = append(model.People, domain.C4Element{ID: newID("user"), Name: "Usuario", Description: "Persona que usa el sistema"})
+	}
+
+	techs := stackTechnologies(arch.TechStack)
+	for _, t := range techs[externalKind] {
+		model.External = append(model.External, domain.C4Element{ID: newID(t), Name: t, Description: "Sistema externo", Tags: []string{"External"}})
+	}
+
+	container := func(kind, technology, name string) domain.C4Container {
+		info := c4Containers[kind]
+		c := domain.C4Container{Kind: kind, C4Element: domain.C4Element{Name: name, Description: info.description, Technology: technology}}
+		if info.tag != "" {
+			c.Tags = []string{info.tag}
+		}
+		return c
+	}
+	for _, kind := range codeContainers {
+		if len(techs[kind]) > 0 {
+			c := container(kind, strings.Join(techs[kind], ", "), c4Containers[kind].name)
+			c.ID = newID(kind)
+			model.Containers = append(model.Containers, c)
+		}
+	}
+	// the modules run in the backend, so there is one even if the stack names none
+	if !slices.ContainsFunc(model.Containers, func(c domain.C4Container) bool { return c.Kind == domain.ContainerAPI }) &&
+		(len(modules) > 0 || len(model.Containers) == 0) {
+		c := container(domain.ContainerAPI, "", c4Containers[domain.ContainerAPI].name)
+		c.ID = newID(domain.ContainerAPI)
+		i := slices.IndexFunc(model.Containers, func(c domain.C4Container) bool { return c.Kind == domain.ContainerWorker })
+		if i < 0 {
+			i = len(model.Containers)
+		}
+		model.Containers = slices.Insert(model.Containers, i, c)
+	}
+	for _, kind := range storeContainers {
+		for _, t := range techs[kind] {
+			name := c4Containers[kind].name
+			if len(techs[kind]) > 1 {
+				name += " " + t
+			}
+			c := container(kind, t, name)
+			c.ID = newID(t)
+			model.Containers = append(model.Containers, c)
+		}
+	}
+
+	byKind := func(kinds ...string) []domain.C4Container {
+		var cs []domain.C4Container
+		for _, c := range model.Containers {
+			if slices.Contains(kinds, c.Kind) {
+				cs = append(cs, c)
+			}
+		}
+		return cs
+	}
+	relate := func(from, to, description, technology string) {
+		model.Relationships = append(model.Relationships, domain.C4Relationship{From: from, To: to, Description: description, Technology: technology})
+	}
+	clients := byKind(domain.ContainerWeb, domain.ContainerMobile)
+	apis := byKind(domain.ContainerAPI)
+	workers := byKind(domain.ContainerWorker)
+	stores := byKind(storeContainers...)
+	for _, p := range model.People {
+		entry := clients
+		if len(entry) == 0 {
+			entry = apis
+		}
+		for _, c := range entry {
+			relate(p.ID, c.ID, "Usa", "")
+		}
+	}
+	for _, c := range clients {
+		for _, api := range apis {
+			relate(c.ID, api.ID, "Hace llamadas a la API", "HTTPS/JSON")
+		}
+	}
+	for _, from := range append(apis, workers...) {
+		for _, s := range stores {
+			if from.Kind == domain.ContainerWorker && s.Kind != domain.ContainerDatabase && s.Kind != domain.ContainerQueue {
+				continue
+			}
+			relate(from.ID, s.ID, c4Containers[s.Kind].uses, "")
+		}
+	}
+
+	// the modules are the components of the API; they use the modules they
+	// depend on and the stores and external systems they mention
+	if len(apis) == 0 || len(modules) == 0 {
+		for _, e := range model.External {
+			if len(apis) > 0 {
+				relate(apis[0].ID, e.ID, "Usa", "")
+			}
+		}
+		return model
+	}
+	host := slices.IndexFunc(model.Containers, func(c domain.C4Container) bool { return c.ID == apis[0].ID })
+	ordered, _ := buildOrder(modules)
+	// dependencies name a module; with repeated names, the first one
+	componentIDs := map[string]string{}
+	for _, m := range ordered {
+		c := domain.C4Element{ID: newID(m.Name), Name: plainText(m.Name, 60), Description: plainText(m.Description, 200)}
+		if _, ok := componentIDs[m.Name]; !ok {
+			componentIDs[m.Name] = c.ID
+		}
+		model.Containers[host].Components = append(model.Containers[host].Components, c)
+	}
+	used := map[string]bool{}
+	for i, m := range ordered {
+		from := model.Containers[host].Components[i].ID
+		for _, dep := range m.Dependencies {
+			if to, ok := componentIDs[dep]; ok {
+				relate(from, to, "Usa", "")
+			}
+		}
+		text := " " + strings.Join(techWords(m.Name+" "+m.Description+" "+m.Functionality+" "+m.TechnicalDetails+" "+strings.Join(m.Dependencies, " ")), " ") + " "
+		for _, s := range stores {
+			if mentions(text, s.Technology) {
+				relate(from, s.ID, c4Containers[s.Kind].uses, "")
+			}
+		}
+		for _, e := range model.External {
+			if mentions(text, e.Name) {
+				relate(from, e.ID, "Usa", "")
+				used[e.ID] = true
+			}
+		}
+	}
+	for _, e := range model.External {
+		if !used[e.ID] {
+			relate(apis[0].ID, e.ID, "Usa", "")
+		}
+	}
+	return model
+}
+
+// stackTechnologies returns the technologies named in the tech stack by kind
+// of container, in catalog order
+func stackTechnologies(stack string) map[string][]string {
+	text := " " + strings.Join(techWords(stack), " ") + " "
+	found := map[string][]string{}
+	for _, t := range c4Technologies {
+		for _, w := range t.words {
+			if strings.Contains(text, " "+w+" ") {
+				found[t.kind] = append(found[t.kind], t.name)
+				// "react native" is not also "react"
+				for _, w := range t.words {
+					text = strings.ReplaceAll(text, " "+w+" ", "  ")
+				}
+				break
+			}
+		}
+	}
+	return found
+}
+
+// mentions reports whether text, as given by techWords, names the technology
+func mentions(text, technology string) bool {
+	for _, t := range c4Technologies {
+		if t.name == technology {
+			return slices.ContainsFunc(t.words, func(w string) bool { return strings.Contains(text, " "+w+" ") })
+		}
+	}
+	return false
+}
+
+// techWords lowercases text into words, keeping the dots and signs of
+// technology names such as node.js, .net or c#
+func techWords(text string) []string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '+' || r == '#' || r > 0x7f)
+	}) {
+		if w = strings.TrimRight(w, "."); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// userRoles returns the roles of the user stories, "Como administrador,
+// quiero..." giving Administrador
+func userRoles(stories string) []string {
+	var roles []string
+	for _, m := range userRolePattern.FindAllStringSubmatch(markdownPattern.ReplaceAllString(stories, ""), -1) {
+		role := strings.TrimSpace(m[1])
+		if role == "" || slices.ContainsFunc(roles, func(r string) bool { return sameEntity(entityKey(r), entityKey(role)) }) {
+			continue
+		}
+		r := []rune(role)
+		roles = append(roles, strings.ToUpper(string(r[0]))+string(r[1:]))
+		if len(roles) == maxPeople {
+			break
+		}
+	}
+	return roles
+}
+
+// StructurizrDSL writes a C4 model as a Structurizr DSL workspace with the
+// system context, container and component views
+func StructurizrDSL(m *domain.C4Model) string {
+	paths := map[string]string{}
+	for _, e := range append(slices.Clone(m.People), m.External...) {
+		paths[e.ID] = e.ID
+	}
+	paths[m.System.ID] = m.System.ID
+	for _, c := range m.Containers {
+		paths[c.ID] = m.System.ID + "." + c.ID
+		for _, comp := range c.Components {
+			paths[comp.ID] = m.System.ID + "." + c.ID + "." + comp.ID
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "workspace %s %s {\n\n", dslString(m.System.Name), dslString(m.System.Description))
+	b.WriteString("    !identifiers hierarchical\n\n    model {\n")
+	for _, p := range m.People {
+		fmt.Fprintf(&b, "        %s = person %s\n", p.ID, dslElement(p, false))
+	}
+	for _, e := range m.External {
+		fmt.Fprintf(&b, "        %s = softwareSystem %s\n", e.ID, dslElement(e, false))
+	}
+	fmt.Fprintf(&b, "\n        %s = softwareSystem %s {\n", m.System.ID, dslElement(m.System, false))
+	for _, c := range m.Containers {
+		fmt.Fprintf(&b, "            %s = container %s", c.ID, dslElement(c.C4Element, true))
+		if len(c.Components) == 0 {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(" {\n")
+		for _, comp := range c.Components {
+			fmt.Fprintf(&b, "                %s = component %s\n", comp.ID, dslElement(comp, true))
+		}
+		b.WriteString("            }\n")
+	}
+	b.WriteString("        }\n\n")
+	for _, r := range m.Relationships {
+		fmt.Fprintf(&b, "        %s -> %s %s", paths[r.From], paths[r.To], dslString(r.Description))
+		if r.Technology != "" {
+			b.WriteString(" " + dslString(r.Technology))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("    }\n\n    views {\n")
+
+	view := func(kind, scope, key, description string) {
+		fmt.Fprintf(&b, "        %s %s %s %s {\n            include *\n            autolayout lr\n        }\n\n", kind, scope, dslString(key), dslString(description))
+	}
+	view("systemContext", m.System.ID, "contexto", "Contexto del sistema")
+	view("container", m.System.ID, "contenedores", "Contenedores")
+	for _, c := range m.Containers {
+		if len(c.Components) > 0 {
+			view("component", paths[c.ID], "componentes_"+c.ID, "Componentes de "+c.Name)
+		}
+	}
+	b.WriteString(`        styles {
+            element "Person" {
+                shape Person
+                background #08427b
+                color #ffffff
+            }
+            element "Software System" {
+                background #1168bd
+                color #ffffff
+            }
+            element "External" {
+                background #999999
+                color #ffffff
+            }
+            element "Container" {
+                background #438dd5
+                color #ffffff
+            }
+            element "Component" {
+                background #85bbf0
+                color #000000
+            }
+            element "Web Browser" {
+                shape WebBrowser
+            }
+            element "Mobile App" {
+                shape MobileDevicePortrait
+            }
+            element "Database" {
+                shape Cylinder
+            }
+            element "Queue" {
+                shape Pipe
+            }
+            element "Storage" {
+                shape Folder
+            }
+        }
+    }
+}
+`)
+	return b.String()
+}
+
+// dslElement writes the name, description, technology and tags of an
+// element; people and software systems have no technology
+func dslElement(e domain.C4Element, technology bool) string {
+	args := []string{dslString(e.Name), dslString(e.Description)}
+	if technology && (e.Technology != "" || len(e.Tags) > 0) {
+		args = append(args, dslString(e.Technology))
+	}
+	if len(e.Tags) > 0 {
+		args = append(args, dslString(strings.Join(e.Tags, ",")))
+	}
+	return strings.Join(args, " ")
+}
+
+// dslString quotes a DSL string, which has no escape for quotes
+func dslString(s string) string {
+	return `"` + strings.ReplaceAll(plainText(s, 200), `"`, "'") + `"`
+}
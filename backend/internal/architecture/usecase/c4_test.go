@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+func TestStackTechnologies(t *testing.T) {
+	tests := []struct {
+		stack string
+		want  map[string][]string
+	}{
+		{
+			stack: "Frontend: React Native y Next.js. Backend: Node.js (Express) con PostgreSQL y Redis",
+			want: map[string][]string{
+				domain.ContainerMobile:   {"React Native"},
+				domain.ContainerWeb:      {"Next.js"},
+				domain.ContainerAPI:      {"Express", "Node.js"},
+				domain.ContainerDatabase: {"PostgreSQL"},
+				domain.ContainerCache:    {"Redis"},
+			},
+		},
+		{
+			stack: "Go + Gin, pagos con Stripe, archivos en AWS S3, .NET para informes",
+			want: map[string][]string{
+				domain.ContainerAPI:     {"ASP.NET", "Gin", "Go"},
+				domain.ContainerStorage: {"Amazon S3"},
+				externalKind:            {"Stripe"},
+			},
+		},
+		// words inside others are not technologies: "goal" is not Go, "reaction" not React
+		{stack: "Our goal is a fast reaction", want: map[string][]string{}},
+		{stack: "", want: map[string][]string{}},
+	}
+	for _, tt := range tests {
+		if got := stackTechnologies(tt.stack); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("stackTechnologies(%q) = %v, want %v", tt.stack, got, tt.want)
+		}
+	}
+}
+
+func TestUserRoles(t *testing.T) {
+	tests := []struct {
+		stories string
+		want    []string
+	}{
+		{"Como **administrador**, quiero banear usuarios.\nComo un cliente quiero pagar.\nComo administradora, deseo ver informes.", []string{"Administrador", "Cliente", "Administradora"}},
+		{"As a customer, I want to pay. As an admin I need reports. As a Customer, I can log in.", []string{"Customer", "Admin"}},
+		{"Como a, quiero b", nil},
+		{"Como usuario 1 quiero\nComo usuario 2 quiero\nComo usuario 3 quiero\nComo usuario 4 quiero\nComo usuario 5 quiero\nComo usuario 6 quiero", []string{"Usuario 1", "Usuario 2", "Usuario 3", "Usuario 4", "Usuario 5"}},
+	}
+	for _, tt := range tests {
+		if got := userRoles(tt.stories); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("userRoles(%q) = %q, want %q", tt.stories, got, tt.want)
+		}
+	}
+}
+
+// c4Architecture names modules after DSL keywords and the containers they
+// run in, to exercise the identifiers
+func c4Architecture() (*domain.Architecture, []domain.Module) {
+	arch := &domain.Architecture{
+		TechStack:   "React, NestJS, PostgreSQL, MongoDB, RabbitMQ, Celery, Stripe y SendGrid",
+		UserStories: "Como comprador, quiero pagar.\nComo vendedor, quiero publicar.",
+	}
+	modules := []domain.Module{
+		{Name: "Pagos", Priority: 2, Description: "Cobros con Stripe", Dependencies: []string{"API"}},
+		{Name: "API", Priority: 1, TechnicalDetails: "Guarda en PostgreSQL"},
+		{Name: "Model", Priority: 3, Description: `Modelo "principal"`, Dependencies: []string{"Pagos", "Google Analytics"}},
+		{Name: "Pagos", Priority: 4},
+	}
+	return arch, modules
+}
+
+func TestC4Model(t *testing.T) {
+	arch, modules := c4Architecture()
+	m := c4Model("Mercado", "Compra y venta", arch, modules)
+
+	if names := elementNames(m.People); !reflect.DeepEqual(names, []string{"Comprador", "Vendedor"}) {
+		t.Errorf("people = %v", names)
+	}
+	if names := elementNames(m.External); !reflect.DeepEqual(names, []string{"Stripe", "SendGrid"}) {
+		t.Errorf("external systems = %v", names)
+	}
+	var kinds []string
+	for _, c := range m.Containers {
+		kinds = append(kinds, c.Kind+":"+c.Name)
+	}
+	wantKinds := []string{"web:Aplicación web", "api:API", "worker:Procesos en segundo plano", "database:Base de datos PostgreSQL", "database:Base de datos MongoDB", "queue:Cola de mensajes"}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Errorf("containers = %v, want %v", kinds, wantKinds)
+	}
+
+	// identifiers are unique across the model and never DSL keywords
+	ids := map[string]bool{}
+	for _, id := range modelIDs(m) {
+		if ids[id] {
+			t.Errorf("identifier %s is used twice", id)
+		}
+		if slices.Contains(reservedDSL, id) {
+			t.Errorf("identifier %s is a DSL keyword", id)
+		}
+		ids[id] = true
+	}
+	api := m.Containers[1]
+	if got := elementIDs(api.Components); !reflect.DeepEqual(got, []string{"api2", "pagos", "model_", "pagos2"}) {
+		t.Errorf("component ids = %v", got)
+	}
+
+	rels := map[string]bool{}
+	for _, r := range m.Relationships {
+		if !ids[r.From] || !ids[r.To] {
+			t.Errorf("relationship %s -> %s uses an unknown element", r.From, r.To)
+		}
+		rels[r.From+" -> "+r.To] = true
+	}
+	for _, want := range []string{
+		"comprador -> web", "web -> api", "api -> postgresql", "api -> rabbitmq",
+		"worker -> postgresql", "worker -> rabbitmq",
+		"pagos -> api2", "pagos -> stripe", "api2 -> postgresql", "model_ -> pagos",
+		"api -> sendgrid",
+	} {
+		if !rels[want] {
+			t.Errorf("missing relationship %s", want)
+		}
+	}
+	for _, absent := range []string{"comprador -> api", "api -> stripe", "worker -> web"} {
+		if rels[absent] {
+			t.Errorf("unexpected relationship %s", absent)
+		}
+	}
+}
+
+func TestC4ModelWithoutStack(t *testing.T) {
+	tests := []struct {
+		name       string
+		modules    []domain.Module
+		stack      string
+		containers []string
+	}{
+		{name: "an API is always drawn", containers: []string{"api"}},
+		{name: "a web app without modules has no API", stack: "Vue", containers: []string{"web"}},
+		{name: "the modules need an API", stack: "Vue, Sidekiq", modules: []domain.Module{{Name: "core"}}, containers: []string{"web", "api", "worker"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := c4Model(" ", "", &domain.Architecture{TechStack: tt.stack}, tt.modules)
+			var kinds []string
+			for _, c := range m.Containers {
+				kinds = append(kinds, c.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.containers) {
+				t.Errorf("containers = %v, want %v", kinds, tt.containers)
+			}
+			if m.System.Name != "Sistema" || len(m.People) != 1 || m.People[0].Name != "Usuario" {
+				t.Errorf("system %q with people %v", m.System.Name, m.People)
+			}
+		})
+	}
+}
+
+func TestC4Export(t *testing.T) {
+	arch, modules := c4Architecture()
+	uc := NewC4Usecase()
+
+	var asked []domain.C4Element
+	agent := func(ctx context.Context, containers []domain.C4Element) ([]domain.C4Element, error) {
+		asked = containers
+		return []domain.C4Element{
+			{ID: "web", Name: "**Tienda** web", Description: "La tienda"},
+			{ID: "api", Name: "tienda WEB"},              // repeats another name
+			{ID: "worker", Name: "  ", Description: "x"}, // empty name
+			{ID: "nope", Name: "Fantasma"},               // unknown container
+			{ID: "postgresql", Name: "Catálogo"},
+		}, nil
+	}
+	m, err := uc.Export(context.Background(), "Mercado", "", arch, modules, agent)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if len(asked) != len(m.Containers) {
+		t.Errorf("agent got %d containers, want %d", len(asked), len(m.Containers))
+	}
+	var names []string
+	for _, c := range m.Containers {
+		names = append(names, c.Name)
+	}
+	want := []string{"Tienda web", "API", "Procesos en segundo plano", "Catálogo", "Base de datos MongoDB", "Cola de mensajes"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("containers = %v, want %v", names, want)
+	}
+	if m.Containers[0].Description != "La tienda" || m.Containers[3].Description != c4Containers[domain.ContainerDatabase].description {
+		t.Errorf("descriptions = %q, %q", m.Containers[0].Description, m.Containers[3].Description)
+	}
+
+	failing := func(ctx context.Context, containers []domain.C4Element) ([]domain.C4Element, error) {
+		return nil, errors.New("agent down")
+	}
+	if _, err := uc.Export(context.Background(), "Mercado", "", arch, modules, failing); err == nil {
+		t.Error("Export should fail when the agent does")
+	}
+	plain, err := uc.Export(context.Background(), "Mercado", "", arch, modules, nil)
+	if err != nil || !reflect.DeepEqual(plain, c4Model("Mercado", "", arch, modules)) {
+		t.Errorf("Export without agent = %+v, %v", plain, err)
+	}
+}
+
+var (
+	dslDeclaration  = regexp.MustCompile(`^(\w+) = (person|softwareSystem|container|component) "`)
+	dslRelationship = regexp.MustCompile(`^([\w.]+) -> ([\w.]+) "`)
+	dslView         = regexp.MustCompile(`^(systemContext|container|component) ([\w.]+) "`)
+)
+
+// TestStructurizrDSL checks the workspace is well formed: strings have no
+// inner quotes, braces balance, and the relationships and views only use
+// the hierarchical identifiers declared in the model
+func TestStructurizrDSL(t *testing.T) {
+	arch, modules := c4Architecture()
+	m := c4Model(`Mercado "Libre" {beta}`, "Compra\ny venta", arch, modules)
+	dsl := StructurizrDSL(m)
+
+	if !strings.HasPrefix(dsl, "workspace \"Mercado 'Libre' {beta}\" \"Compra y venta\" {\n\n    !identifiers hierarchical\n") {
+		t.Errorf("workspace header:\n%s", dsl[:min(len(dsl), 120)])
+	}
+
+	declared := map[string]bool{}
+	var scope []string // identifiers of the enclosing elements
+	var opened []int   // brace depth inside each of them
+	depth := 0
+	for i, line := range strings.Split(dsl, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.Count(trimmed, `"`)%2 != 0 {
+			t.Fatalf("line %d has an unbalanced quote: %s", i+1, line)
+		}
+		// braces inside strings do not count
+		code := regexp.MustCompile(`"[^"]*"`).ReplaceAllString(trimmed, `""`)
+		if d := dslDeclaration.FindStringSubmatch(trimmed); d != nil {
+			path := strings.Join(append(slices.Clone(scope), d[1]), ".")
+			if declared[path] {
+				t.Errorf("line %d declares %s twice", i+1, path)
+			}
+			declared[path] = true
+			if strings.HasSuffix(code, "{") {
+				scope, opened = append(scope, d[1]), append(opened, depth+1)
+			}
+		}
+		if r := dslRelationship.FindStringSubmatch(trimmed); r != nil {
+			for _, id := range r[1:3] {
+				if !declared[id] {
+					t.Errorf("line %d relates %s, which is not declared before", i+1, id)
+				}
+			}
+		}
+		if v := dslView.FindStringSubmatch(trimmed); v != nil && !declared[v[2]] {
+			t.Errorf("line %d has a view of %s, which is not declared", i+1, v[2])
+		}
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+		if len(opened) > 0 && depth < opened[len(opened)-1] {
+			scope, opened = scope[:len(scope)-1], opened[:len(opened)-1]
+		}
+		if depth < 0 {
+			t.Fatalf("line %d closes a brace that is not open", i+1)
+		}
+	}
+	if depth != 0 {
+		t.Errorf("%d braces left open", depth)
+	}
+
+	for _, want := range []string{
+		"system.api.pagos -> system.api.api2 \"Usa\"\n",
+		"system.api.pagos -> stripe \"Usa\"\n",
+		"system.web -> system.api \"Hace llamadas a la API\" \"HTTPS/JSON\"\n",
+		"model_ = component \"Model\" \"Modelo 'principal'\"\n",
+		"postgresql = container \"Base de datos PostgreSQL\" \"Guarda los datos del sistema\" \"PostgreSQL\" \"Database\"\n",
+		"component system.api \"componentes_api\" \"Componentes de API\" {\n",
+	} {
+		if !strings.Contains(dsl, want) {
+			t.Errorf("DSL misses %q", want)
+		}
+	}
+}
+
+func elementNames(elements []domain.C4Element) []string {
+	var names []string
+	for _, e := range elements {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func elementIDs(elements []domain.C4Element) []string {
+	var ids []string
+	for _, e := range elements {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// modelIDs lists the identifiers of every element of a model
+func modelIDs(m *domain.C4Model) []string {
+	ids := append([]string{m.System.ID}, elementIDs(m.People)...)
+	ids = append(ids, elementIDs(m.External)...)
+	for _, c := range m.Containers {
+		ids = append(ids, c.ID)
+		ids = append(ids, elementIDs(c.Components)...)
+	}
+	return ids
+}
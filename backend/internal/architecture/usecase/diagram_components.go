@@ -0,0 +1,349 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+// Roles of a component, in the order requests flow through them
+const (
+	roleClient   = "client"
+	roleEntry    = "entry"
+	roleService  = "service"
+	roleData     = "data"
+	roleExternal = "external"
+)
+
+// componentRoles finds the role of a component by the words of its name; the
+// first match wins, so "API Gateway" is an entry point and not a service
+var componentRoles = []struct {
+	role  string
+	words []string
+}{
+	{roleData, []string{"base de datos", "database", "db", "postgres", "postgresql", "mysql", "mongo", "mongodb", "redis", "cache", "almacenamiento", "storage", "s3", "bucket", "elasticsearch", "cola", "queue", "kafka", "rabbitmq", "sqs", "broker"}},
+	{roleEntry, []string{"gateway", "balanceador", "load balancer", "cdn", "proxy", "nginx", "ingress"}},
+	{roleClient, []string{"frontend", "front end", "cliente", "client", "web", "movil", "mobile", "app", "interfaz", "ui", "navegador", "browser", "spa"}},
+	{roleExternal, []string{"externo", "externa", "externos", "external", "tercero", "terceros", "third party", "pasarela", "stripe", "paypal", "sendgrid", "twilio"}},
+}
+
+var (
+	queueWords = []string{"cola", "queue", "kafka", "rabbitmq", "sqs", "broker"}
+
+	roleTitles = map[string]string{
+		roleClient:   "Clientes",
+		roleEntry:    "Entrada",
+		roleService:  "Servicios",
+		roleData:     "Datos",
+		roleExternal: "Servicios externos",
+	}
+
+	// sections of the system architecture that list concerns, not components
+	concernSections = []string{"segur", "secur", "escalab", "scalab", "rendimiento", "performance", "monitor", "benefic", "ventaja", "advantage"}
+
+	arrowPattern  = regexp.MustCompile(`\s*(?:<?-{1,2}>|→|⟶|=>)\s*`)
+	bulletPattern = regexp.MustCompile(`^\s*(?:[-*+•]|\d+[.)])\s+`)
+	// A[Frontend] and |HTTP| of arrow chains written as Mermaid
+	nodePattern      = regexp.MustCompile(`^\w+\s*[\[({>]+(?:"(.+)"|(.+?))[\])}]+$`)
+	edgeLabelPattern = regexp.MustCompile(`^\|[^|]*\|\s*`)
+)
+
+const maxComponents = 30
+
+// component is a box of the component diagram
+type component struct {
+	id, name, role string
+	queue          bool
+}
+
+// componentGraph holds the components of the system architecture and the
+// arrows between them
+type componentGraph struct {
+	components []*component
+	edges      [][2]*component
+}
+
+// add returns the component named name, adding it when new. Names longer
+// than a few words are prose, not components, and give nil.
+func (g *componentGraph) add(name string) *component {
+	name = strings.Trim(strings.TrimSpace(markdownPattern.ReplaceAllString(name, "")), " :.-|+[]│┌┐└┘─")
+	if n := len(strings.Fields(name)); n == 0 || n > 5 {
+		return nil
+	}
+	key := entityKey(parenPattern.ReplaceAllString(name, ""))
+	for _, c := range g.components {
+		if sameEntity(key, entityKey(parenPattern.ReplaceAllString(c.name, ""))) {
+			return c
+		}
+	}
+	if len(g.components) >= maxComponents {
+		return nil
+	}
+	c := &component{id: fmt.Sprintf("c%d", len(g.components)+1), name: name, role: roleService}
+	words := " " + slugify(name, " ") + " "
+	for _, r := range componentRoles {
+		if slices.ContainsFunc(r.words, func(w string) bool { return strings.Contains(words, " "+w+" ") }) {
+			c.role = r.role
+			break
+		}
+	}
+	c.queue = slices.ContainsFunc(queueWords, func(w string) bool { return strings.Contains(words, " "+w+" ") })
+	g.components = append(g.components, c)
+	return c
+}
+
+func (g *componentGraph) connect(from, to *component) {
+	if from != nil && to != nil && from != to && !slices.Contains(g.edges, [2]*component{from, to}) {
+		g.edges = append(g.edges, [2]*component{from, to})
+	}
+}
+
+func (g *componentGraph) byRole(role string) []*component {
+	var cs []*component
+	for _, c := range g.components {
+		if c.role == role {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// systemComponents reads the components of the system architecture: the
+// first bold term of each bullet, and the boxes of arrow chains such as
+// "Frontend -> API -> PostgreSQL". Without arrows, clients are connected to
+// the entry points, these to the services, and the services to the data
+// stores and external services. The second result tells whether the arrows
+// were inferred.
+func systemComponents(text string) (*componentGraph, bool) {
+	g := &componentGraph{}
+	concern, explicit := false, false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+			title := slugify(m[2], " ")
+			concern = slices.ContainsFunc(concernSections, func(s string) bool { return strings.Contains(title, s) })
+			continue
+		}
+		if arrowPattern.MatchString(trimmed) {
+			var prev *component
+			for _, part := range arrowPattern.Split(bulletPattern.ReplaceAllString(trimmed, ""), -1) {
+				part = edgeLabelPattern.ReplaceAllString(strings.TrimSpace(part), "")
+				if m := nodePattern.FindStringSubmatch(part); m != nil {
+					part = m[1] + m[2]
+				}
+				c := g.add(part)
+				if prev != nil && c != nil {
+					g.connect(prev, c)
+					explicit = true
+				}
+				prev = c
+			}
+			continue
+		}
+		if !concern && bulletPattern.MatchString(line) {
+			if b := boldPattern.FindStringSubmatch(trimmed); b != nil {
+				g.add(b[1])
+			}
+		}
+	}
+	if explicit {
+		return g, false
+	}
+
+	var tiers [][]*component
+	for _, role := range []string{roleClient, roleEntry, roleService} {
+		if cs := g.byRole(role); len(cs) > 0 {
+			tiers = append(tiers, cs)
+		}
+	}
+	for i := 1; i < len(tiers); i++ {
+		for _, from := range tiers[i-1] {
+			for _, to := range tiers[i] {
+				g.connect(from, to)
+			}
+		}
+	}
+	if len(tiers) > 0 {
+		for _, from := range tiers[len(tiers)-1] {
+			for _, to := range append(g.byRole(roleData), g.byRole(roleExternal)...) {
+				g.connect(from, to)
+			}
+		}
+	}
+	return g, true
+}
+
+// componentDiagram draws the components of the system architecture grouped
+// by role
+func componentDiagram(in domain.DiagramInput, format string) (string, error) {
+	g, inferred := systemComponents(in.Architecture.SystemArchitecture)
+	if len(g.components) == 0 {
+		return "", fmt.Errorf("%w: the system architecture names no components", domain.ErrEmptyDiagram)
+	}
+	// a user drives the clients when the architecture draws no arrows
+	clients := g.byRole(roleClient)
+	user := inferred && len(clients) > 0
+
+	var b strings.Builder
+	if format == domain.DiagramPlantUML {
+		b.WriteString("@startuml\nleft to right direction\n")
+		if user {
+			b.WriteString("actor \"Usuario\" as user\n")
+		}
+		for _, role := range []string{roleClient, roleEntry, roleService, roleData, roleExternal} {
+			cs := g.byRole(role)
+			if len(cs) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "package %s {\n", plantUMLLabel(roleTitles[role]))
+			for _, c := range cs {
+				shape := "component"
+				switch {
+				case c.queue:
+					shape = "queue"
+				case c.role == roleData:
+					shape = "database"
+				case c.role == roleExternal:
+					shape = "cloud"
+				}
+				fmt.Fprintf(&b, "  %s %s as %s\n", shape, plantUMLLabel(c.name), c.id)
+			}
+			b.WriteString("}\n")
+		}
+		if user {
+			for _, c := range clients {
+				fmt.Fprintf(&b, "user --> %s\n", c.id)
+			}
+		}
+		for _, e := range g.edges {
+			fmt.Fprintf(&b, "%s --> %s\n", e[0].id, e[1].id)
+		}
+		b.WriteString("@enduml\n")
+		return b.String(), nil
+	}
+
+	b.WriteString("flowchart LR\n")
+	if user {
+		b.WriteString("    user((\"Usuario\"))\n")
+	}
+	for _, role := range []string{roleClient, roleEntry, roleService, roleData, roleExternal} {
+		cs := g.byRole(role)
+		if len(cs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "    subgraph %s[%s]\n", role, mermaidLabel(roleTitles[role]))
+		for _, c := range cs {
+			if c.role == roleData {
+				fmt.Fprintf(&b, "        %s[(%s)]\n", c.id, mermaidLabel(c.name))
+			} else {
+				fmt.Fprintf(&b, "        %s[%s]\n", c.id, mermaidLabel(c.name))
+			}
+		}
+		b.WriteString("    end\n")
+	}
+	if user {
+		for _, c := range clients {
+			fmt.Fprintf(&b, "    user --> %s\n", c.id)
+		}
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "    %s --> %s\n", e[0].id, e[1].id)
+	}
+	return b.String(), nil
+}
+
+// moduleDiagram draws each development module with arrows to the modules it
+// depends on, colored by status. Dependencies that are not modules are drawn
+// dashed, and modules in a dependency cycle are outlined in red.
+func moduleDiagram(in domain.DiagramInput, format string) (string, error) {
+	if len(in.Modules) == 0 {
+		return "", fmt.Errorf("%w: the architecture has no development modules", domain.ErrEmptyDiagram)
+	}
+	ordered, cycles := buildOrder(in.Modules)
+
+	ids := map[string]string{}
+	for i, m := range ordered {
+		ids[m.Name] = fmt.Sprintf("m%d", i+1)
+	}
+	var external []string
+	for _, m := range ordered {
+		for _, dep := range m.Dependencies {
+			if _, ok := ids[dep]; !ok {
+				external = append(external, dep)
+				ids[dep] = fmt.Sprintf("e%d", len(external))
+			}
+		}
+	}
+	status := func(m domain.Module) string {
+		if m.Status == "completed" || m.Status == "in_progress" {
+			return m.Status
+		}
+		return "pending"
+	}
+
+	var b strings.Builder
+	if format == domain.DiagramPlantUML {
+		b.WriteString("@startuml\nskinparam componentStyle rectangle\n")
+		b.WriteString("skinparam component {\n" +
+			"  BackgroundColor<<completed>> #dcfce7\n" +
+			"  BackgroundColor<<in_progress>> #fef9c3\n" +
+			"  BackgroundColor<<pending>> #f3f4f6\n" +
+			"  BorderColor<<cycle>> #dc2626\n" +
+			"  BorderStyle<<external>> dashed\n" +
+			"}\n")
+		for _, m := range ordered {
+			stereotypes := "<<" + status(m) + ">>"
+			if slices.Contains(cycles, m.Name) {
+				stereotypes += " <<cycle>>"
+			}
+			fmt.Fprintf(&b, "component %s as %s %s\n", plantUMLLabel(m.Name), ids[m.Name], stereotypes)
+		}
+		for _, name := range external {
+			fmt.Fprintf(&b, "component %s as %s <<external>>\n", plantUMLLabel(name), ids[name])
+		}
+		for _, m := range ordered {
+			for _, dep := range m.Dependencies {
+				arrow := "-->"
+				if slices.Contains(external, dep) {
+					arrow = "..>"
+				}
+				fmt.Fprintf(&b, "%s %s %s\n", ids[m.Name], arrow, ids[dep])
+			}
+		}
+		b.WriteString("@enduml\n")
+		return b.String(), nil
+	}
+
+	b.WriteString("flowchart TD\n")
+	for _, m := range ordered {
+		fmt.Fprintf(&b, "    %s[%s]:::%s\n", ids[m.Name], mermaidLabel(m.Name), status(m))
+	}
+	for _, name := range external {
+		fmt.Fprintf(&b, "    %s[%s]:::external\n", ids[name], mermaidLabel(name))
+	}
+	for _, m := range ordered {
+		for _, dep := range m.Dependencies {
+			arrow := "-->"
+			if slices.Contains(external, dep) {
+				arrow = "-.->"
+			}
+			fmt.Fprintf(&b, "    %s %s %s\n", ids[m.Name], arrow, ids[dep])
+		}
+	}
+	if len(cycles) > 0 {
+		var cs []string
+		for _, name := range cycles {
+			cs = append(cs, ids[name])
+		}
+		fmt.Fprintf(&b, "    class %s cycle\n", strings.Join(cs, ","))
+	}
+	b.WriteString("    classDef completed fill:#dcfce7,stroke:#16a34a\n" +
+		"    classDef in_progress fill:#fef9c3,stroke:#ca8a04\n" +
+		"    classDef pending fill:#f3f4f6,stroke:#6b7280\n" +
+		"    classDef external fill:#fff,stroke:#6b7280,stroke-dasharray:4 4\n" +
+		"    classDef cycle stroke:#dc2626,stroke-width:2px\n")
+	return b.String(), nil
+}
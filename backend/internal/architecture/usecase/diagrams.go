@@ -0,0 +1,381 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+// diagramGenerator draws a kind of diagram from some of the inputs
+type diagramGenerator struct {
+	formats []string // the first one is the default
+	inputs  func(in domain.DiagramInput) []string
+	draw    func(in domain.DiagramInput, format string) (string, error)
+}
+
+var diagramGenerators = map[string]diagramGenerator{
+	domain.DiagramER: {
+		formats: []string{domain.DiagramMermaid},
+		inputs: func(in domain.DiagramInput) []string {
+			return []string{in.Architecture.DatabaseSchema, in.Architecture.EntitiesRelationships}
+		},
+		draw: erDiagram,
+	},
+	domain.DiagramComponents: {
+		formats: []string{domain.DiagramMermaid, domain.DiagramPlantUML},
+		inputs:  func(in domain.DiagramInput) []string { return []string{in.Architecture.SystemArchitecture} },
+		draw:    componentDiagram,
+	},
+	domain.DiagramModules: {
+		formats: []string{domain.DiagramMermaid, domain.DiagramPlantUML},
+		inputs: func(in domain.DiagramInput) []string {
+			var lines []string
+			for _, m := range in.Modules {
+				lines = append(lines, fmt.Sprintf("%s|%s|%s|%d|%s", m.ID, m.Name, m.Status, m.Priority, strings.Join(m.Dependencies, ",")))
+			}
+			return lines
+		},
+		draw: moduleDiagram,
+	},
+	domain.DiagramFlow: {
+		formats: []string{domain.DiagramMermaid},
+		inputs:  func(in domain.DiagramInput) []string { return []string{in.BusinessLogicFlow} },
+		draw:    flowDiagram,
+	},
+}
+
+// DiagramUsecase draws the diagrams of an architecture as Mermaid or
+// PlantUML source. Each diagram is cached until a section it is drawn from
+// changes.
+type DiagramUsecase struct {
+	mu    sync.Mutex
+	cache map[string]*domain.Diagram // by architecture, kind and format
+}
+
+// NewDiagramUsecase creates a diagram use case with an empty cache
+func NewDiagramUsecase() *DiagramUsecase {
+	return &DiagramUsecase{cache: map[string]*domain.Diagram{}}
+}
+
+// Kinds returns the diagram kinds with their formats, the default first
+func (uc *DiagramUsecase) Kinds() map[string][]string {
+	kinds := map[string][]string{}
+	for kind, g := range diagramGenerators {
+		kinds[kind] = g.formats
+	}
+	return kinds
+}
+
+// Diagram returns the diagram of a kind in a format; empty picks the kind's
+// default format
+func (uc *DiagramUsecase) Diagram(in domain.DiagramInput, kind, format string) (*domain.Diagram, error) {
+	g, ok := diagramGenerators[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: kind %q", domain.ErrUnknownDiagram, kind)
+	}
+	if format == "" {
+		format = g.formats[0]
+	}
+	if !slices.Contains(g.formats, format) {
+		return nil, fmt.Errorf("%w: %s diagrams are available as %s", domain.ErrUnknownDiagram, kind, strings.Join(g.formats, ", "))
+	}
+
+	h := sha256.New()
+	for _, s := range append([]string{kind, format}, g.inputs(in)...) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	hash := hex.EncodeToString(h.Sum(nil))[:16]
+	key := in.Architecture.ID.String() + "/" + kind + "/" + format
+
+	uc.mu.Lock()
+	cached := uc.cache[key]
+	uc.mu.Unlock()
+	if cached != nil && cached.Hash == hash {
+		return cached, nil
+	}
+
+	source, err := g.draw(in, format)
+	if err != nil {
+		return nil, err
+	}
+	d := &domain.Diagram{Kind: kind, Format: format, Source: source, Hash: hash}
+	uc.mu.Lock()
+	uc.cache[key] = d
+	uc.mu.Unlock()
+	return d, nil
+}
+
+// erDiagram draws the tables of the schema with their keys and foreign keys,
+// plus the relationships of the entities section the schema lacks
+func erDiagram(in domain.DiagramInput, _ string) (string, error) {
+	m := &schemaModel{byKey: map[string]*tableDef{}}
+	if sql := schemaSQL(in.Architecture.DatabaseSchema); strings.TrimSpace(sql) != "" {
+		m = parseSchema(sql)
+	}
+
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, t := range m.tables {
+		fmt.Fprintf(&b, "    %s {\n", diagramID(t.name.key))
+		for _, c := range t.columns {
+			var keys []string
+			if slices.Contains(t.primaryKey, c.name.key) {
+				keys = append(keys, "PK")
+			}
+			if slices.ContainsFunc(t.foreignKeys, func(fk *foreignKeyDef) bool {
+				return slices.ContainsFunc(fk.columns, func(n sqlName) bool { return n.key == c.name.key })
+			}) {
+				keys = append(keys, "FK")
+			}
+			if !slices.Contains(t.primaryKey, c.name.key) && t.isUnique([]string{c.name.key}) {
+				keys = append(keys, "UK")
+			}
+			line := erType(c.typ) + " " + diagramID(c.name.key)
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			fmt.Fprintf(&b, "        %s\n", line)
+		}
+		b.WriteString("    }\n")
+	}
+
+	// the referenced table is the parent: exactly one when the key is
+	// required, and the child has at most one row per parent when the key
+	// is unique. Keys that are part of the child's primary key identify it.
+	related := map[[2]*tableDef]bool{}
+	for _, t := range m.tables {
+		for _, fk := range t.foreignKeys {
+			ref := m.byKey[fk.refTable.key]
+			if ref == nil {
+				continue
+			}
+			cols := nameKeys(fk.columns)
+			parent, child, line := "|o", "o{", ".."
+			// primary key columns are NOT NULL even when the key is a table constraint
+			if !slices.ContainsFunc(cols, func(c string) bool {
+				col := t.column(c)
+				return col == nil || !col.notNull && !slices.Contains(t.primaryKey, c)
+			}) {
+				parent = "||"
+			}
+			if t.isUnique(cols) {
+				child = "o|"
+			}
+			if !slices.ContainsFunc(cols, func(c string) bool { return !slices.Contains(t.primaryKey, c) }) {
+				line = "--"
+			}
+			fmt.Fprintf(&b, "    %s %s%s%s %s : %q\n", diagramID(ref.name.key), parent, line, child, diagramID(t.name.key), strings.Join(cols, ", "))
+			related[[2]*tableDef{ref, t}], related[[2]*tableDef{t, ref}] = true, true
+		}
+	}
+
+	relations := 0
+	for _, rel := range entityRelations(in.Architecture.EntitiesRelationships) {
+		from, to := m.tableFor(rel.from), m.tableFor(rel.to)
+		if from != nil && to != nil && related[[2]*tableDef{from, to}] {
+			continue
+		}
+		fmt.Fprintf(&b, "    %s %s..%s %s : \"\"\n", m.entityID(rel.from, from), leftCardinality(rel.fromCard), rightCardinality(rel.toCard), m.entityID(rel.to, to))
+		relations++
+	}
+
+	if len(m.tables) == 0 && relations == 0 {
+		return "", fmt.Errorf("%w: the architecture has no database schema nor entity relationships", domain.ErrEmptyDiagram)
+	}
+	return b.String(), nil
+}
+
+// entityRelation is a line of the text diagram of the entities section,
+// e.g. Usuario (1) ----< (N) Ordenes
+type entityRelation struct {
+	from, to         []string // entity names, see entityNames
+	fromCard, toCard string
+}
+
+func entityRelations(text string) []entityRelation {
+	var rels []entityRelation
+	for _, line := range strings.Split(text, "\n") {
+		if m := relationPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			rels = append(rels, entityRelation{
+				from:     entityNames(m[1]),
+				fromCard: strings.ToLower(m[2]),
+				toCard:   strings.ToLower(m[3]),
+				to:       entityNames(m[4]),
+			})
+		}
+	}
+	return rels
+}
+
+// tableFor returns the table of an entity, matched as in checkEntities
+func (m *schemaModel) tableFor(names []string) *tableDef {
+	for _, name := range names {
+		for _, t := range m.tables {
+			if sameEntity(entityKey(name), entityKey(t.name.key[strings.LastIndex(t.name.key, ".")+1:])) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// entityID names an entity after its table, or after itself when it has none
+func (m *schemaModel) entityID(names []string, t *tableDef) string {
+	if t != nil {
+		return diagramID(t.name.key)
+	}
+	return diagramID(names[0])
+}
+
+// leftCardinality and rightCardinality turn (1), (0..1), (N)... into the
+// Mermaid markers for each side of a relationship
+func leftCardinality(card string) string {
+	switch card {
+	case "1":
+		return "||"
+	case "0", "0..1":
+		return "|o"
+	case "1..n", "1..m", "1..*":
+		return "}|"
+	}
+	return "}o"
+}
+
+func rightCardinality(card string) string {
+	switch card {
+	case "1":
+		return "||"
+	case "0", "0..1":
+		return "o|"
+	case "1..n", "1..m", "1..*":
+		return "|{"
+	}
+	return "o{"
+}
+
+// erType shortens a column type to a Mermaid attribute type: varchar(255) is varchar
+func erType(typ string) string {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	array := strings.HasSuffix(typ, "[]")
+	if i := strings.IndexAny(typ, "(["); i >= 0 {
+		typ = typ[:i]
+	}
+	typ = diagramID(typ)
+	if array {
+		typ += "[]"
+	}
+	return typ
+}
+
+// diagramID turns a name into an identifier both Mermaid and PlantUML accept
+func diagramID(name string) string {
+	id := slugify(name, "_")
+	switch {
+	case id == "":
+		return "x"
+	case id[0] >= '0' && id[0] <= '9':
+		return "n" + id
+	}
+	return id
+}
+
+var (
+	flowTitlePattern = regexp.MustCompile(`(?i)^(?:#+\s*)?(?:\*\*)?\s*((?:flujo|flow|proceso|process)\b.*?)\s*(?:\*\*)?\s*:?$`)
+	flowStepPattern  = regexp.MustCompile(`^\s*(?:\d+[.)]|[-*•]|[a-z][.)])\s+(.+)$`)
+	markdownPattern  = regexp.MustCompile("\\*\\*|__|`")
+)
+
+// businessFlow is a flow of the business logic section with its steps
+type businessFlow struct {
+	title string
+	steps []string
+}
+
+// businessFlows splits the business logic section into flows: a flow starts
+// at a "FLUJO 1: ..." line or a heading, and its steps are the numbered or
+// bulleted lines that follow
+func businessFlows(text string) []businessFlow {
+	var flows []businessFlow
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+		case flowTitlePattern.MatchString(trimmed) && !flowStepPattern.MatchString(line):
+			flows = append(flows, businessFlow{title: flowTitlePattern.FindStringSubmatch(trimmed)[1]})
+		case strings.HasPrefix(trimmed, "#"):
+			flows = append(flows, businessFlow{title: strings.TrimSpace(strings.TrimLeft(trimmed, "#"))})
+		default:
+			if m := flowStepPattern.FindStringSubmatch(line); m != nil {
+				if len(flows) == 0 {
+					flows = append(flows, businessFlow{title: "Flujo"})
+				}
+				flows[len(flows)-1].steps = append(flows[len(flows)-1].steps, m[1])
+			}
+		}
+	}
+	return slices.DeleteFunc(flows, func(f businessFlow) bool { return len(f.steps) == 0 })
+}
+
+// flowDiagram draws each business flow as a chain of steps; steps that
+// state a condition are drawn as decisions
+func flowDiagram(in domain.DiagramInput, _ string) (string, error) {
+	flows := businessFlows(in.BusinessLogicFlow)
+	if len(flows) == 0 {
+		return "", fmt.Errorf("%w: the action plan has no business logic flows", domain.ErrEmptyDiagram)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for i, f := range flows {
+		fmt.Fprintf(&b, "    subgraph f%d[%s]\n        direction TB\n", i+1, mermaidLabel(f.title))
+		for j, step := range f.steps {
+			id := fmt.Sprintf("f%ds%d", i+1, j+1)
+			lower := strings.ToLower(step)
+			if strings.HasPrefix(lower, "si ") || strings.HasPrefix(lower, "if ") || strings.HasSuffix(step, "?") {
+				fmt.Fprintf(&b, "        %s{%s}\n", id, mermaidLabel(step))
+			} else {
+				fmt.Fprintf(&b, "        %s[%s]\n", id, mermaidLabel(step))
+			}
+			if j > 0 {
+				fmt.Fprintf(&b, "        f%ds%d --> %s\n", i+1, j, id)
+			}
+		}
+		b.WriteString("    end\n")
+	}
+	return b.String(), nil
+}
+
+// mermaidLabel quotes a label, dropping Markdown and shortening long text
+func mermaidLabel(s string) string {
+	return `"` + strings.ReplaceAll(diagramText(s), `"`, "#quot;") + `"`
+}
+
+// plantUMLLabel quotes a label for PlantUML, which has no escape for quotes
+func plantUMLLabel(s string) string {
+	return `"` + strings.ReplaceAll(diagramText(s), `"`, "'") + `"`
+}
+
+func diagramText(s string) string {
+	return plainText(s, 80)
+}
+
+// plainText joins s into one line without Markdown, cut at a word boundary
+// when longer than limit runes
+func plainText(s string, limit int) string {
+	s = strings.Join(strings.Fields(markdownPattern.ReplaceAllString(s, "")), " ")
+	if r := []rune(s); len(r) > limit {
+		cut := string(r[:limit])
+		if i := strings.LastIndex(cut, " "); i > limit/2 {
+			cut = cut[:i]
+		}
+		s = cut + "…"
+	}
+	return s
+}
@@ -0,0 +1,350 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+func diagramInput() domain.DiagramInput {
+	return domain.DiagramInput{
+		Architecture: &domain.Architecture{
+			ID: uuid.New(),
+			DatabaseSchema: "```sql\n" +
+				"CREATE TABLE users (id UUID PRIMARY KEY, email VARCHAR(255) UNIQUE);\n" +
+				"CREATE TABLE orders (id UUID PRIMARY KEY, user_id UUID NOT NULL REFERENCES users(id), tags TEXT[]);\n" +
+				"```",
+			EntitiesRelationships: "Usuario (users) (1) ----< (N) Pedidos (orders)\nPedido (1) ---- (0..1) Factura",
+			SystemArchitecture:    "- **Frontend React**\n- **API REST**\n- **PostgreSQL**",
+		},
+		Modules: []domain.Module{
+			{Name: "api", Status: "completed", Priority: 1},
+			{Name: "web", Status: "in_progress", Priority: 2, Dependencies: []string{"api"}},
+		},
+		BusinessLogicFlow: "FLUJO 1: Compra\n1. Añadir al carrito\n2. Pagar",
+	}
+}
+
+func TestDiagramKindsAndFormats(t *testing.T) {
+	empty := domain.DiagramInput{Architecture: &domain.Architecture{ID: uuid.New()}}
+	tests := []struct {
+		name       string
+		in         domain.DiagramInput
+		kind       string
+		format     string
+		wantFormat string
+		wantErr    error
+	}{
+		{name: "er defaults to mermaid", in: diagramInput(), kind: domain.DiagramER, wantFormat: domain.DiagramMermaid},
+		{name: "components as plantuml", in: diagramInput(), kind: domain.DiagramComponents, format: domain.DiagramPlantUML, wantFormat: domain.DiagramPlantUML},
+		{name: "er has no plantuml", in: diagramInput(), kind: domain.DiagramER, format: domain.DiagramPlantUML, wantErr: domain.ErrUnknownDiagram},
+		{name: "unknown kind", in: diagramInput(), kind: "sequence", wantErr: domain.ErrUnknownDiagram},
+		{name: "unknown format", in: diagramInput(), kind: domain.DiagramModules, format: "graphviz", wantErr: domain.ErrUnknownDiagram},
+		{name: "empty er", in: empty, kind: domain.DiagramER, wantErr: domain.ErrEmptyDiagram},
+		{name: "empty components", in: empty, kind: domain.DiagramComponents, wantErr: domain.ErrEmptyDiagram},
+		{name: "empty modules", in: empty, kind: domain.DiagramModules, wantErr: domain.ErrEmptyDiagram},
+		{name: "empty flow", in: empty, kind: domain.DiagramFlow, wantErr: domain.ErrEmptyDiagram},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDiagramUsecase().Diagram(tt.in, tt.kind, tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Diagram err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (d.Kind != tt.kind || d.Format != tt.wantFormat || d.Source == "") {
+				t.Errorf("diagram = %+v", d)
+			}
+		})
+	}
+
+	kinds := NewDiagramUsecase().Kinds()
+	if len(kinds) != 4 || kinds[domain.DiagramER][0] != domain.DiagramMermaid {
+		t.Errorf("Kinds = %v", kinds)
+	}
+}
+
+func TestDiagramCache(t *testing.T) {
+	uc := NewDiagramUsecase()
+	in := diagramInput()
+	first, err := uc.Diagram(in, domain.DiagramER, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a section the diagram is not drawn from changes nothing
+	in.Architecture.SystemArchitecture = "- **Otro**"
+	in.BusinessLogicFlow = ""
+	if again, _ := uc.Diagram(in, domain.DiagramER, ""); again != first {
+		t.Error("diagram was redrawn for an unrelated change")
+	}
+
+	in.Architecture.EntitiesRelationships = ""
+	changed, err := uc.Diagram(in, domain.DiagramER, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == first || changed.Hash == first.Hash {
+		t.Error("diagram was not redrawn after its section changed")
+	}
+
+	// each architecture has its own entry
+	other := diagramInput()
+	other.Architecture.EntitiesRelationships = ""
+	if d, _ := uc.Diagram(other, domain.DiagramER, ""); d == changed || d.Hash != changed.Hash {
+		t.Error("architectures with the same sections should share the hash but not the entry")
+	}
+}
+
+func TestERDiagram(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		rels   string
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "keys and cardinalities from the schema",
+			schema: diagramInput().Architecture.DatabaseSchema,
+			want: []string{
+				"    users {\n        uuid id PK\n        varchar email UK\n    }\n",
+				"        text[] tags\n",
+				`    users ||..o{ orders : "user_id"`,
+			},
+		},
+		{
+			name:   "optional, unique and identifying keys",
+			schema: "CREATE TABLE a (id INT PRIMARY KEY);\nCREATE TABLE b (a_id INT UNIQUE REFERENCES a);\nCREATE TABLE c (a_id INT REFERENCES a, n INT, PRIMARY KEY (a_id, n));",
+			want:   []string{`    a |o..o| b : "a_id"`, `    a ||--o{ c : "a_id"`},
+		},
+		{
+			name:   "relationships the schema lacks",
+			schema: diagramInput().Architecture.DatabaseSchema,
+			rels:   diagramInput().Architecture.EntitiesRelationships,
+			want:   []string{`    pedido ||..o| factura : ""`},
+			absent: []string{`users }o..o{ orders`},
+		},
+		{
+			name: "relationships without a schema",
+			rels: "1. Cliente (0..1) ---- (1..N) Dirección\n- 3D Modelo (N) ---- (M) Ñandú",
+			want: []string{`    cliente |o..|{ direccion : ""`, `    n3d_modelo }o..o{ nandu : ""`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := erDiagram(domain.DiagramInput{Architecture: &domain.Architecture{DatabaseSchema: tt.schema, EntitiesRelationships: tt.rels}}, "")
+			if err != nil {
+				t.Fatalf("erDiagram: %v", err)
+			}
+			if !strings.HasPrefix(source, "erDiagram\n") {
+				t.Errorf("source does not start with erDiagram:\n%s", source)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(source, want) {
+					t.Errorf("missing %q in:\n%s", want, source)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(source, absent) {
+					t.Errorf("unexpected %q in:\n%s", absent, source)
+				}
+			}
+		})
+	}
+
+	// a schema that does not parse still draws what it can
+	source, err := erDiagram(domain.DiagramInput{Architecture: &domain.Architecture{DatabaseSchema: "CREATE TABLE ok (id INT);\nCREATE TABLE (broken;"}}, "")
+	if err != nil || !strings.Contains(source, "    ok {") {
+		t.Errorf("erDiagram of a broken schema = %q, %v", source, err)
+	}
+}
+
+func TestComponentDiagram(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		format string
+		want   []string
+		absent []string
+	}{
+		{
+			name: "roles and inferred arrows",
+			text: "## Componentes\n- **App móvil**: cliente\n- **API Gateway**\n- **Servicio de pagos**\n- **Redis** como caché\n- **Stripe**\n## Seguridad\n- **JWT** en cada petición",
+			want: []string{
+				"flowchart LR\n    user((\"Usuario\"))\n",
+				"    subgraph client[\"Clientes\"]\n        c1[\"App móvil\"]\n    end\n",
+				"        c4[(\"Redis\")]\n",
+				"    user --> c1\n    c1 --> c2\n    c2 --> c3\n    c3 --> c4\n    c3 --> c5\n",
+			},
+			absent: []string{"JWT"},
+		},
+		{
+			name:   "explicit arrows are kept as written",
+			text:   "Frontend --> API --> PostgreSQL\nAPI -> |eventos| Kafka\n- **Frontend**",
+			want:   []string{"    c1 --> c2\n    c2 --> c3\n    c2 --> c4\n"},
+			absent: []string{"user"},
+		},
+		{
+			name: "mermaid nodes and odd names",
+			text: `A[Web "SPA"] --> B{API} --> C[(Base de datos)]`,
+			want: []string{`c1["Web #quot;SPA#quot;"]`, `c3[("Base de datos")]`},
+		},
+		{
+			name:   "plantuml shapes",
+			text:   `- **Web "SPA"**` + "\n- **API**\n- **PostgreSQL**\n- **Cola RabbitMQ**\n- **SendGrid**",
+			format: domain.DiagramPlantUML,
+			want: []string{
+				"@startuml\nleft to right direction\nactor \"Usuario\" as user\n",
+				`component "Web 'SPA'" as c1`,
+				`database "PostgreSQL" as c3`,
+				`queue "Cola RabbitMQ" as c4`,
+				`cloud "SendGrid" as c5`,
+				"user --> c1\n",
+				"@enduml\n",
+			},
+		},
+		{
+			name:   "long prose is not a component",
+			text:   "- **Un texto largo que explica la arquitectura entera** y no es un componente\n- **API**",
+			want:   []string{`c1["API"]`},
+			absent: []string{"texto largo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := componentDiagram(domain.DiagramInput{Architecture: &domain.Architecture{SystemArchitecture: tt.text}}, tt.format)
+			if err != nil {
+				t.Fatalf("componentDiagram: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(source, want) {
+					t.Errorf("missing %q in:\n%s", want, source)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(source, absent) {
+					t.Errorf("unexpected %q in:\n%s", absent, source)
+				}
+			}
+		})
+	}
+
+	var many strings.Builder
+	for i := range 40 {
+		fmt.Fprintf(&many, "- **Servicio %d**\n", i+1)
+	}
+	g, _ := systemComponents(many.String())
+	if len(g.components) != maxComponents {
+		t.Errorf("%d components, want at most %d", len(g.components), maxComponents)
+	}
+}
+
+func TestModuleDiagram(t *testing.T) {
+	modules := []domain.Module{
+		{Name: "web", Status: "in_progress", Priority: 3, Dependencies: []string{"api", "Stripe"}},
+		{Name: "api", Status: "completed", Priority: 1},
+		{Name: `Módulo "A"`, Priority: 4, Dependencies: []string{"B"}},
+		{Name: "B", Status: "archived", Priority: 5, Dependencies: []string{`Módulo "A"`}},
+	}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{
+			format: domain.DiagramMermaid,
+			want: []string{
+				"flowchart TD\n    m1[\"api\"]:::completed\n    m2[\"web\"]:::in_progress\n    m3[\"Módulo #quot;A#quot;\"]:::pending\n    m4[\"B\"]:::pending\n",
+				"    e1[\"Stripe\"]:::external\n",
+				"    m2 --> m1\n    m2 -.-> e1\n    m3 --> m4\n    m4 --> m3\n",
+				"    class m3,m4 cycle\n",
+			},
+		},
+		{
+			format: domain.DiagramPlantUML,
+			want: []string{
+				"component \"api\" as m1 <<completed>>\n",
+				"component \"Módulo 'A'\" as m3 <<pending>> <<cycle>>\n",
+				"component \"Stripe\" as e1 <<external>>\n",
+				"m2 ..> e1\n",
+				"@enduml\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			source, err := moduleDiagram(domain.DiagramInput{Modules: modules}, tt.format)
+			if err != nil {
+				t.Fatalf("moduleDiagram: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(source, want) {
+					t.Errorf("missing %q in:\n%s", want, source)
+				}
+			}
+		})
+	}
+}
+
+func TestFlowDiagram(t *testing.T) {
+	text := "Intro sin pasos\n\n" +
+		"FLUJO 1: **Registro**\n1. El usuario envía el formulario\n2. ¿El email ya existe?\n3. Si es válido, se crea la cuenta\n\n" +
+		"## Flujo vacío\n\n" +
+		"### Pago\n- Cobrar con \"Stripe\"\n- Enviar recibo"
+	source, err := flowDiagram(domain.DiagramInput{BusinessLogicFlow: text}, "")
+	if err != nil {
+		t.Fatalf("flowDiagram: %v", err)
+	}
+	want := "flowchart TD\n" +
+		"    subgraph f1[\"FLUJO 1: Registro\"]\n        direction TB\n" +
+		"        f1s1[\"El usuario envía el formulario\"]\n" +
+		"        f1s2{\"¿El email ya existe?\"}\n        f1s1 --> f1s2\n" +
+		"        f1s3{\"Si es válido, se crea la cuenta\"}\n        f1s2 --> f1s3\n" +
+		"    end\n" +
+		"    subgraph f2[\"Pago\"]\n        direction TB\n" +
+		"        f2s1[\"Cobrar con #quot;Stripe#quot;\"]\n" +
+		"        f2s2[\"Enviar recibo\"]\n        f2s1 --> f2s2\n" +
+		"    end\n"
+	if source != want {
+		t.Errorf("source:\n%s\nwant:\n%s", source, want)
+	}
+
+	// steps before any title go to a default flow
+	if flows := businessFlows("1. Uno\n2. Dos"); len(flows) != 1 || flows[0].title != "Flujo" || len(flows[0].steps) != 2 {
+		t.Errorf("businessFlows = %+v", flows)
+	}
+}
+
+func TestDiagramText(t *testing.T) {
+	tests := []struct{ in, mermaid, plantUML, id string }{
+		{"**API** `REST`", `"API REST"`, `"API REST"`, "api_rest"},
+		{`Dice "hola"`, `"Dice #quot;hola#quot;"`, `"Dice 'hola'"`, "dice_hola"},
+		{"  varias\n  líneas ", `"varias líneas"`, `"varias líneas"`, "varias_lineas"},
+		{"3 capas", `"3 capas"`, `"3 capas"`, "n3_capas"},
+		{"¿?", `"¿?"`, `"¿?"`, "x"},
+	}
+	for _, tt := range tests {
+		if got := mermaidLabel(tt.in); got != tt.mermaid {
+			t.Errorf("mermaidLabel(%q) = %s, want %s", tt.in, got, tt.mermaid)
+		}
+		if got := plantUMLLabel(tt.in); got != tt.plantUML {
+			t.Errorf("plantUMLLabel(%q) = %s, want %s", tt.in, got, tt.plantUML)
+		}
+		if got := diagramID(tt.in); got != tt.id {
+			t.Errorf("diagramID(%q) = %s, want %s", tt.in, got, tt.id)
+		}
+	}
+
+	long := strings.Repeat("palabra ", 20)
+	got := plainText(long, 80)
+	if !strings.HasSuffix(got, "palabra…") || len([]rune(got)) > 81 {
+		t.Errorf("plainText cut = %q", got)
+	}
+}
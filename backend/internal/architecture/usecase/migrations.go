@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+var sqlFenceLanguages = []string{"sql", "postgres", "postgresql", "psql", "pgsql", "plpgsql"}
+
+// SchemaMigrations derives goose migrations from the SQL DDL in a database
+// schema section: a setup migration with schemas, extensions and types, one
+// migration per table with its indexes, comments and rows, created after the
+// tables it references, then the foreign keys closing reference cycles and
+// the views, functions and triggers. Every migration drops what it creates,
+// so they revert in reverse order. Versions are consecutive seconds from
+// start. Statements that do not parse are left out.
+func SchemaMigrations(schema string, start time.Time) []domain.Migration {
+	sql := schemaSQL(schema)
+	if strings.TrimSpace(sql) == "" {
+		return nil
+	}
+	m := parseSchema(sql)
+
+	type group struct {
+		name     string
+		up, down []ddlStatement
+	}
+	var groups []group
+	if len(m.setup) > 0 {
+		groups = append(groups, group{name: "setup", up: m.setup, down: m.setup})
+	}
+	order, deferred := m.tableOrder()
+	for _, t := range order {
+		up := append([]ddlStatement{{up: createTableSQL(t, deferred)}}, t.after...)
+		down := []ddlStatement{{down: "DROP TABLE IF EXISTS " + t.name.sql + ";"}}
+		groups = append(groups, group{name: "create_" + slugify(t.name.key, "_"), up: up, down: down})
+	}
+	if len(deferred) > 0 {
+		g := group{name: "add_foreign_keys"}
+		for _, t := range order {
+			for _, fk := range t.foreignKeys {
+				if deferred[fk] {
+					add, drop := deferredForeignKey(t, fk)
+					g.up = append(g.up, ddlStatement{up: add})
+					g.down = append(g.down, ddlStatement{down: drop})
+				}
+			}
+		}
+		groups = append(groups, g)
+	}
+	if len(m.objects) > 0 {
+		groups = append(groups, group{name: "create_objects", up: m.objects, down: m.objects})
+	}
+
+	migrations := make([]domain.Migration, len(groups))
+	for i, g := range groups {
+		var up, down []string
+		for _, s := range g.up {
+			up = append(up, gooseStatement(s))
+		}
+		// reverted in reverse order
+		for _, s := range slices.Backward(g.down) {
+			if s.down != "" {
+				down = append(down, s.down)
+			}
+		}
+		migrations[i] = domain.Migration{
+			Version: start.Add(time.Duration(i) * time.Second).Format("20060102150405"),
+			Name:    g.name,
+			Up:      strings.Join(up, "\n\n"),
+			Down:    strings.Join(down, "\n"),
+		}
+	}
+	return migrations
+}
+
+// MigrationsArchive zips migrations as goose files under migrations/
+func MigrationsArchive(migrations []domain.Migration, modified time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, m := range migrations {
+		f := m.File()
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: "migrations/" + f.Path, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gooseStatement terminates a statement; goose splits on semicolons unless
+// told a statement has its own (function bodies)
+func gooseStatement(s ddlStatement) string {
+	up := strings.TrimSuffix(s.up, ";") + ";"
+	if s.dollar {
+		return "-- +goose StatementBegin\n" + up + "\n-- +goose StatementEnd"
+	}
+	return up
+}
+
+// tableOrder sorts the tables so each one is created after the tables it
+// references, keeping the schema's order otherwise. When the remaining
+// tables reference each other, the first one is created anyway and its
+// references to tables not created yet are deferred.
+func (m *schemaModel) tableOrder() ([]*tableDef, map[*foreignKeyDef]bool) {
+	pending := slices.Clone(m.tables)
+	created := map[string]bool{}
+	deferred := map[*foreignKeyDef]bool{}
+	ready := func(t *tableDef, fk *foreignKeyDef) bool {
+		ref := fk.refTable.key
+		return ref == t.name.key || created[ref] || m.byKey[ref] == nil
+	}
+
+	var order []*tableDef
+	for len(pending) > 0 {
+		next := slices.IndexFunc(pending, func(t *tableDef) bool {
+			return !slices.ContainsFunc(t.foreignKeys, func(fk *foreignKeyDef) bool { return !ready(t, fk) })
+		})
+		if next < 0 {
+			next = 0
+			for _, fk := range pending[0].foreignKeys {
+				if !ready(pending[0], fk) {
+					deferred[fk] = true
+				}
+			}
+		}
+		t := pending[next]
+		created[t.name.key] = true
+		order = append(order, t)
+		pending = slices.Delete(pending, next, next+1)
+	}
+	return order, deferred
+}
+
+// createTableSQL writes the CREATE TABLE of a table with the columns and
+// constraints as written, leaving out the deferred foreign keys
+func createTableSQL(t *tableDef, deferred map[*foreignKeyDef]bool) string {
+	var elems []string
+	for _, c := range t.columns {
+		def := c.sql
+		if slices.ContainsFunc(t.foreignKeys, func(fk *foreignKeyDef) bool { return fk.column == c && deferred[fk] }) {
+			def = c.bare
+		}
+		elems = append(elems, def)
+	}
+	elems = append(elems, t.constraints...)
+	for _, fk := range t.foreignKeys {
+		if fk.column == nil && !deferred[fk] {
+			elems = append(elems, fk.clause)
+		}
+	}
+	sql := "CREATE TABLE " + t.name.sql + " (\n    " + strings.Join(elems, ",\n    ") + "\n)"
+	if t.tail != "" {
+		sql += " " + t.tail
+	}
+	return sql + ";"
+}
+
+// deferredForeignKey returns the statements adding and dropping a foreign
+// key outside its table; unnamed keys get PostgreSQL's default name
+func deferredForeignKey(t *tableDef, fk *foreignKeyDef) (string, string) {
+	name := fk.name
+	if name == "" {
+		table := t.name.key[strings.LastIndex(t.name.key, ".")+1:]
+		name = table + "_" + strings.Join(nameKeys(fk.columns), "_") + "_fkey"
+		if len(name) > 63 {
+			name = name[:63]
+		}
+	}
+	clause := fk.clause
+	if fk.column != nil || fk.name == "" {
+		clause = "CONSTRAINT " + name + " FOREIGN KEY (" + joinSQL(fk.columns) + ") " + fk.references
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", t.name.sql, clause),
+		fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", t.name.sql, name)
+}
+
+// schemaSQL returns the SQL of a schema section: its SQL code blocks (and
+// unlabeled ones with CREATE TABLE), or the whole text when it has no code
+// blocks but looks like DDL. The other lines are blanked, so positions in the
+// SQL are lines of the section.
+func schemaSQL(schema string) string {
+	lines := strings.Split(schema, "\n")
+	if !strings.Contains(schema, "```") {
+		if strings.Contains(strings.ToUpper(schema), "CREATE TABLE") {
+			return schema
+		}
+		return ""
+	}
+
+	out := make([]string, len(lines))
+	found := false
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		lang := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(trimmed, "```")))
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), "```") {
+			end++
+		}
+		block := lines[i+1 : end]
+		if slices.Contains(sqlFenceLanguages, lang) || lang == "" && strings.Contains(strings.ToUpper(strings.Join(block, "\n")), "CREATE TABLE") {
+			copy(out[i+1:], block)
+			found = true
+		}
+		i = end
+	}
+	if !found {
+		return ""
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+	"github.com/dark/idea-forge/internal/architecture/port"
+)
+
+// reservedPackages are names a module package cannot take: Go keywords,
+// predeclared identifiers and the packages the generated main imports
+var reservedPackages = []string{
+	"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for",
+	"func", "go", "goto", "if", "import", "interface", "map", "package", "range", "return",
+	"select", "struct", "switch", "type", "var",
+	"any", "bool", "error", "string", "int", "len", "new", "nil", "true", "false",
+	"main", "internal", "cmd", "migrations", "docs", "http", "log", "os", "context",
+}
+
+// packageStopwords are dropped from module names when deriving package names
+var packageStopwords = []string{"de", "del", "la", "las", "el", "los", "y", "e", "para", "en", "con", "the", "and", "of", "for"}
+
+// ScaffoldUsecase renders project skeletons with pluggable per-stack templates
+type ScaffoldUsecase struct {
+	templates []port.ScaffoldTemplate
+}
+
+// NewScaffoldUsecase creates a scaffold use case. The first template is the
+// default when none matches the architecture's tech stack.
+func NewScaffoldUsecase(templates ...port.ScaffoldTemplate) *ScaffoldUsecase {
+	return &ScaffoldUsecase{templates: templates}
+}
+
+// Templates returns the names of the registered templates
+func (uc *ScaffoldUsecase) Templates() []string {
+	names := make([]string, len(uc.templates))
+	for i, t := range uc.templates {
+		names[i] = t.Name()
+	}
+	return names
+}
+
+// Generate renders the skeleton of a project as a zip with a single root
+// directory. template selects a template by name; empty picks the first one
+// matching the tech stack. Modules are placed in build order: dependencies
+// first, then by priority.
+func (uc *ScaffoldUsecase) Generate(name string, arch *domain.Architecture, modules []domain.Module, template string) (*domain.ScaffoldArchive, error) {
+	tmpl, err := uc.template(template, arch.TechStack)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	s := &domain.Scaffold{
+		Name:         strings.TrimSpace(name),
+		Slug:         slugify(name, "-"),
+		Architecture: arch,
+		GeneratedAt:  now,
+		Migrations:   SchemaMigrations(arch.DatabaseSchema, now),
+	}
+	if s.Slug == "" {
+		s.Slug = "project-" + arch.ID.String()[:8]
+	}
+	s.Modules, s.Cycles = buildOrder(modules)
+	assignPackages(s.Modules)
+
+	files, err := tmpl.Render(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: s.Slug + "/" + f.Path, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &domain.ScaffoldArchive{Filename: s.Slug + "-scaffold.zip", Template: tmpl.Name(), Content: buf.Bytes()}, nil
+}
+
+func (uc *ScaffoldUsecase) template(name, techStack string) (port.ScaffoldTemplate, error) {
+	if len(uc.templates) == 0 {
+		return nil, domain.ErrUnknownTemplate
+	}
+	if name != "" {
+		for _, t := range uc.templates {
+			if t.Name() == name {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %q (available: %s)", domain.ErrUnknownTemplate, name, strings.Join(uc.Templates(), ", "))
+	}
+	for _, t := range uc.templates {
+		if t.Matches(techStack) {
+			return t, nil
+		}
+	}
+	return uc.templates[0], nil
+}
+
+// buildOrder sorts modules so each one comes after the modules it depends on,
+// picking the lowest priority among the ready ones. Dependencies given as
+// module IDs become names; unknown ones are kept as external. Modules left in
+// a cycle are appended by priority and reported.
+func buildOrder(modules []domain.Module) ([]domain.Module, []string) {
+	pending := slices.Clone(modules)
+	slices.SortStableFunc(pending, func(a, b domain.Module) int { return a.Priority - b.Priority })
+
+	byName := map[string]bool{}
+	for _, m := range pending {
+		byName[m.Name] = true
+	}
+	for i := range pending {
+		var deps []string
+		for _, dep := range pending[i].Dependencies {
+			for _, other := range pending {
+				if other.ID.String() == dep {
+					dep = other.Name
+					break
+				}
+			}
+			if dep != pending[i].Name && !slices.Contains(deps, dep) {
+				deps = append(deps, dep)
+			}
+		}
+		pending[i].Dependencies = deps
+	}
+
+	var ordered []domain.Module
+	done := map[string]bool{}
+	for len(pending) > 0 {
+		next := slices.IndexFunc(pending, func(m domain.Module) bool {
+			for _, dep := range m.Dependencies {
+				if byName[dep] && !done[dep] {
+					return false
+				}
+			}
+			return true
+		})
+		if next < 0 {
+			break
+		}
+		done[pending[next].Name] = true
+		ordered = append(ordered, pending[next])
+		pending = slices.Delete(pending, next, next+1)
+	}
+
+	var cycles []string
+	for _, m := range pending {
+		cycles = append(cycles, m.Name)
+	}
+	ordered = append(ordered, pending...)
+	for i := range ordered {
+		ordered[i].Order = i + 1
+	}
+	return ordered, cycles
+}
+
+// assignPackages gives every module a unique lowercase package name
+func assignPackages(modules []domain.Module) {
+	used := map[string]bool{}
+	for i := range modules {
+		var words []string
+		for _, w := range strings.Split(slugify(modules[i].Name, " "), " ") {
+			if w != "" && !slices.Contains(packageStopwords, w) {
+				words = append(words, w)
+			}
+		}
+		name := strings.Join(words, "")
+		if len(name) > 30 {
+			name = name[:30]
+		}
+		switch {
+		case name == "":
+			name = "module"
+		case unicode.IsDigit(rune(name[0])):
+			name = "m" + name
+		}
+		if slices.Contains(reservedPackages, name) {
+			name += "mod"
+		}
+		candidate := name
+		for n := 2; used[candidate]; n++ {
+			candidate = fmt.Sprintf("%s%d", name, n)
+		}
+		used[candidate] = true
+		modules[i].Package = candidate
+	}
+}
+
+// slugify lowercases s to ASCII letters and digits, folding Spanish accents
+// and joining words with sep
+func slugify(s, sep string) string {
+	var b strings.Builder
+	gap := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+		case strings.ContainsRune("áàäâ", r):
+			r = 'a'
+		case strings.ContainsRune("éèëê", r):
+			r = 'e'
+		case strings.ContainsRune("íìïî", r):
+			r = 'i'
+		case strings.ContainsRune("óòöô", r):
+			r = 'o'
+		case strings.ContainsRune("úùüû", r):
+			r = 'u'
+		case r == 'ñ':
+			r = 'n'
+		case r == 'ç':
+			r = 'c'
+		default:
+			gap = b.Len() > 0
+			continue
+		}
+		if gap {
+			b.WriteString(sep)
+			gap = false
+		}
+		b.WriteRune(r)
+		if b.Len() >= 60 {
+			break
+		}
+	}
+	return b.String()
+}
+
+// ParseDependencies reads the JSON array of module names stored with a
+// development module, falling back to the legacy comma separated text
+func ParseDependencies(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	var deps []string
+	if err := json.Unmarshal([]byte(raw), &deps); err != nil {
+		deps = strings.Split(raw, ",")
+	}
+	out := deps[:0]
+	for _, d := range deps {
+		if d = strings.TrimSpace(d); d != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+// stubTemplate renders one file listing the modules in build order
+type stubTemplate struct {
+	name  string
+	stack string
+}
+
+func (t stubTemplate) Name() string { return t.name }
+
+func (t stubTemplate) Matches(techStack string) bool {
+	return strings.Contains(strings.ToLower(techStack), t.stack)
+}
+
+func (t stubTemplate) Render(s *domain.Scaffold) ([]domain.File, error) {
+	var b strings.Builder
+	for _, m := range s.Modules {
+		b.WriteString(m.Package + "\n")
+	}
+	return []domain.File{{Path: "modules.txt", Content: []byte(b.String())}}, nil
+}
+
+func moduleNames(modules []domain.Module) []string {
+	var names []string
+	for _, m := range modules {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+func TestBuildOrder(t *testing.T) {
+	authID := uuid.New()
+	tests := []struct {
+		name    string
+		modules []domain.Module
+		order   []string
+		cycles  []string
+		deps    map[string][]string // normalized dependencies, when checked
+	}{
+		{
+			name:    "by priority without dependencies",
+			modules: []domain.Module{{Name: "b", Priority: 2}, {Name: "a", Priority: 1}, {Name: "c", Priority: 2}},
+			order:   []string{"a", "b", "c"},
+		},
+		{
+			name: "dependencies first",
+			modules: []domain.Module{
+				{Name: "web", Priority: 1, Dependencies: []string{"api"}},
+				{Name: "api", Priority: 2, Dependencies: []string{"db"}},
+				{Name: "db", Priority: 3},
+			},
+			order: []string{"db", "api", "web"},
+		},
+		{
+			name: "ids, self references, duplicates and external dependencies",
+			modules: []domain.Module{
+				{Name: "api", Priority: 1, Dependencies: []string{authID.String(), "api", "auth", "stripe"}},
+				{ID: authID, Name: "auth", Priority: 2},
+			},
+			order: []string{"auth", "api"},
+			deps:  map[string][]string{"api": {"auth", "stripe"}},
+		},
+		{
+			name: "cycle is appended by priority and reported",
+			modules: []domain.Module{
+				{Name: "a", Priority: 1, Dependencies: []string{"b"}},
+				{Name: "b", Priority: 2, Dependencies: []string{"a"}},
+				{Name: "c", Priority: 3},
+				{Name: "d", Priority: 4, Dependencies: []string{"a"}},
+			},
+			order:  []string{"c", "a", "b", "d"},
+			cycles: []string{"a", "b", "d"},
+		},
+		{name: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := make([]domain.Module, len(tt.modules))
+			copy(input, tt.modules)
+			ordered, cycles := buildOrder(input)
+
+			if got := moduleNames(ordered); !reflect.DeepEqual(got, tt.order) {
+				t.Fatalf("order = %v, want %v", got, tt.order)
+			}
+			if !reflect.DeepEqual(cycles, tt.cycles) {
+				t.Errorf("cycles = %v, want %v", cycles, tt.cycles)
+			}
+			for i, m := range ordered {
+				if m.Order != i+1 {
+					t.Errorf("%s.Order = %d, want %d", m.Name, m.Order, i+1)
+				}
+				if want, ok := tt.deps[m.Name]; ok && !reflect.DeepEqual(m.Dependencies, want) {
+					t.Errorf("%s dependencies = %v, want %v", m.Name, m.Dependencies, want)
+				}
+			}
+			if len(input) > 0 && !reflect.DeepEqual(input, tt.modules) {
+				t.Error("buildOrder modified its input")
+			}
+		})
+	}
+}
+
+func TestAssignPackages(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  []string
+	}{
+		{[]string{"Gestión de Usuarios", "API REST"}, []string{"gestionusuarios", "apirest"}},
+		{[]string{"Auth", "auth", "AUTH"}, []string{"auth", "auth2", "auth3"}},
+		{[]string{"http", "Type", "main"}, []string{"httpmod", "typemod", "mainmod"}},
+		{[]string{"3D viewer", "¿?", ""}, []string{"m3dviewer", "module", "module2"}},
+		{[]string{strings.Repeat("muy largo ", 10)}, []string{strings.Repeat("muylargo", 4)[:30]}},
+	}
+	for _, tt := range tests {
+		modules := make([]domain.Module, len(tt.names))
+		for i, n := range tt.names {
+			modules[i].Name = n
+		}
+		assignPackages(modules)
+		var got []string
+		for _, m := range modules {
+			got = append(got, m.Package)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("packages of %q = %v, want %v", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct{ in, sep, want string }{
+		{"Idea Forge", "-", "idea-forge"},
+		{"  Añadir   Módulos!! ", "-", "anadir-modulos"},
+		{"Façade/Ünïcode", "_", "facade_unicode"},
+		{"日本語", "-", ""},
+		// Cut after 60 bytes, never on a separator
+		{strings.Repeat("ab ", 40), "-", strings.Repeat("ab-", 20) + "a"},
+	}
+	for _, tt := range tests {
+		if got := slugify(tt.in, tt.sep); got != tt.want {
+			t.Errorf("slugify(%q, %q) = %q, want %q", tt.in, tt.sep, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateScaffold(t *testing.T) {
+	uc := NewScaffoldUsecase(stubTemplate{name: "node", stack: "node"}, stubTemplate{name: "go", stack: "go"})
+	arch := &domain.Architecture{ID: uuid.New(), TechStack: "Backend en Go con PostgreSQL"}
+	modules := []domain.Module{{Name: "web", Priority: 1, Dependencies: []string{"api"}}, {Name: "api", Priority: 2}}
+
+	tests := []struct {
+		name     string
+		title    string
+		template string
+		wantTmpl string
+		wantErr  error
+	}{
+		{name: "picks the template matching the stack", title: "Mi Proyecto", wantTmpl: "go"},
+		{name: "explicit template", title: "Mi Proyecto", template: "node", wantTmpl: "node"},
+		{name: "unknown template", title: "Mi Proyecto", template: "rails", wantErr: domain.ErrUnknownTemplate},
+		{name: "title without ascii letters", title: "¿?", wantTmpl: "go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive, err := uc.Generate(tt.title, arch, modules, tt.template)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Generate err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if archive.Template != tt.wantTmpl {
+				t.Errorf("template = %q, want %q", archive.Template, tt.wantTmpl)
+			}
+
+			slug := slugify(tt.title, "-")
+			if slug == "" {
+				slug = "project-" + arch.ID.String()[:8]
+			}
+			if archive.Filename != slug+"-scaffold.zip" {
+				t.Errorf("filename = %q", archive.Filename)
+			}
+			zr, err := zip.NewReader(bytes.NewReader(archive.Content), int64(len(archive.Content)))
+			if err != nil {
+				t.Fatalf("reading zip: %v", err)
+			}
+			if len(zr.File) != 1 || zr.File[0].Name != slug+"/modules.txt" {
+				t.Fatalf("zip entries = %v", zr.File)
+			}
+			rc, _ := zr.File[0].Open()
+			defer rc.Close()
+			var content bytes.Buffer
+			content.ReadFrom(rc)
+			if content.String() != "api\nweb\n" {
+				t.Errorf("modules rendered in order %q, want api then web", content.String())
+			}
+		})
+	}
+
+	if _, err := NewScaffoldUsecase().Generate("x", arch, nil, ""); !errors.Is(err, domain.ErrUnknownTemplate) {
+		t.Errorf("Generate without templates = %v, want ErrUnknownTemplate", err)
+	}
+}
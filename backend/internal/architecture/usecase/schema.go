@@ -0,0 +1,397 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+	"github.com/dark/idea-forge/internal/events"
+)
+
+// builtinTypes are the PostgreSQL types, and those of common extensions,
+// a column may use without declaring them
+var builtinTypes = []string{
+	"smallint", "integer", "int", "int2", "int4", "int8", "bigint", "smallserial", "serial", "bigserial",
+	"serial2", "serial4", "serial8", "decimal", "numeric", "real", "float", "float4", "float8", "double",
+	"money", "varchar", "char", "character", "bpchar", "text", "citext", "name", "bytea",
+	"timestamp", "timestamptz", "date", "time", "timetz", "interval", "boolean", "bool",
+	"uuid", "json", "jsonb", "xml", "inet", "cidr", "macaddr", "macaddr8", "bit", "varbit",
+	"point", "line", "lseg", "box", "path", "polygon", "circle", "tsvector", "tsquery",
+	"int4range", "int8range", "numrange", "tsrange", "tstzrange", "daterange", "int4multirange",
+	"oid", "regclass", "hstore", "ltree", "geometry", "geography", "vector",
+}
+
+// integerTypes accept no length, unlike MySQL's INT(11)
+var integerTypes = []string{"smallint", "integer", "int", "int2", "int4", "int8", "bigint", "smallserial", "serial", "bigserial"}
+
+// mysqlTypes are column types of other databases with their PostgreSQL equivalent
+var mysqlTypes = map[string]string{
+	"datetime":   "timestamp",
+	"tinyint":    "smallint or boolean",
+	"mediumint":  "integer",
+	"tinytext":   "text",
+	"mediumtext": "text",
+	"longtext":   "text",
+	"tinyblob":   "bytea",
+	"blob":       "bytea",
+	"mediumblob": "bytea",
+	"longblob":   "bytea",
+	"nvarchar":   "varchar",
+	"enum":       "a type created with CREATE TYPE ... AS ENUM",
+	"set":        "an array or a lookup table",
+	"year":       "smallint",
+	"image":      "bytea",
+	"ntext":      "text",
+	"clob":       "text",
+	"number":     "numeric",
+	"varchar2":   "varchar",
+}
+
+// typeFamilies groups the types a foreign key may join on
+var typeFamilies = map[string]string{
+	"smallint": "integer", "integer": "integer", "int": "integer", "int2": "integer", "int4": "integer",
+	"int8": "integer", "bigint": "integer", "smallserial": "integer", "serial": "integer",
+	"bigserial": "integer", "serial2": "integer", "serial4": "integer", "serial8": "integer",
+	"varchar": "text", "char": "text", "character": "text", "text": "text", "citext": "text", "bpchar": "text",
+	"uuid": "uuid", "numeric": "numeric", "decimal": "numeric",
+}
+
+// AnalyzeSchema parses the database schema of an architecture as PostgreSQL
+// DDL. It reports syntax errors, dangling foreign keys and the entities of
+// the entities and relationships section that have no table.
+func AnalyzeSchema(arch *domain.Architecture) *domain.SchemaReport {
+	report := &domain.SchemaReport{Tables: []domain.SchemaTable{}, Issues: []domain.SchemaIssue{}}
+	sql := schemaSQL(arch.DatabaseSchema)
+	if strings.TrimSpace(sql) == "" {
+		report.Issues = append(report.Issues, domain.SchemaIssue{
+			Severity: domain.SeverityError,
+			Kind:     domain.IssueNoSQL,
+			Message:  "the database schema has no SQL DDL; add the CREATE TABLE statements in a ```sql block",
+		})
+		report.Errors = 1
+		return report
+	}
+
+	m := parseSchema(sql)
+	if len(m.tables) == 0 {
+		m.issue(domain.SeverityError, domain.IssueNoSQL, 0, "", "the database schema defines no tables")
+	}
+	m.checkEntities(arch.EntitiesRelationships)
+
+	for _, t := range m.tables {
+		st := domain.SchemaTable{Name: t.name.key, Line: t.line, Columns: []domain.SchemaColumn{}, PrimaryKey: t.primaryKey}
+		for _, c := range t.columns {
+			st.Columns = append(st.Columns, domain.SchemaColumn{Name: c.name.key, Type: c.typ, NotNull: c.notNull})
+		}
+		for _, fk := range t.foreignKeys {
+			st.ForeignKeys = append(st.ForeignKeys, domain.ForeignKey{
+				Columns:    nameKeys(fk.columns),
+				RefTable:   fk.refTable.key,
+				RefColumns: nameKeys(fk.refColumns),
+			})
+		}
+		report.Tables = append(report.Tables, st)
+	}
+
+	// issues in section order; those without a line go last
+	slices.SortStableFunc(m.issues, func(a, b domain.SchemaIssue) int {
+		if (a.Line == 0) != (b.Line == 0) {
+			return cmp.Compare(b.Line, a.Line)
+		}
+		return cmp.Compare(a.Line, b.Line)
+	})
+	report.Issues = append(report.Issues, m.issues...)
+	for _, i := range report.Issues {
+		if i.Severity == domain.SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	report.Valid = report.Errors == 0
+	return report
+}
+
+// check validates the parsed schema: column types, key columns and the
+// foreign keys' targets
+func (m *schemaModel) check() {
+	for _, t := range m.tables {
+		for _, c := range t.columns {
+			m.checkType(t, c)
+		}
+		for _, col := range t.primaryKey {
+			if t.column(col) == nil {
+				m.issue(domain.SeverityError, domain.IssueUnknownColumn, t.line, t.name.key, "primary key of %s uses column %s, which the table does not have", t.name.sql, col)
+			}
+		}
+		for _, fk := range t.foreignKeys {
+			m.checkForeignKey(t, fk)
+		}
+	}
+}
+
+func (m *schemaModel) checkType(t *tableDef, c *columnDef) {
+	typ := strings.ToLower(strings.TrimSpace(c.typ))
+	base := strings.TrimSuffix(strings.TrimSuffix(strings.Trim(typ, `"`), "[]"), " array")
+	if i := strings.IndexAny(base, " (["); i >= 0 {
+		base = base[:i]
+	}
+	base = strings.TrimPrefix(base, "pg_catalog.")
+	key := strings.TrimPrefix(strings.Trim(base, `"`), "public.")
+	switch {
+	case m.types[key]:
+	case mysqlTypes[base] != "" && !slices.Contains(builtinTypes, base):
+		m.issue(domain.SeverityError, domain.IssueUnsupported, c.line, t.name.key, "column %s.%s: type %s does not exist in PostgreSQL; use %s", t.name.sql, c.name.sql, strings.ToUpper(base), mysqlTypes[base])
+	case slices.Contains(integerTypes, base) && strings.Contains(typ, "("):
+		m.issue(domain.SeverityError, domain.IssueUnsupported, c.line, t.name.key, "column %s.%s: %s takes no display width in PostgreSQL", t.name.sql, c.name.sql, c.typ)
+	case base == "double" && !strings.Contains(typ, "precision"):
+		m.issue(domain.SeverityError, domain.IssueUnsupported, c.line, t.name.key, "column %s.%s: use DOUBLE PRECISION", t.name.sql, c.name.sql)
+	case !slices.Contains(builtinTypes, base):
+		m.issue(domain.SeverityWarning, domain.IssueUnknownType, c.line, t.name.key, "column %s.%s: type %s is not built in nor declared in the schema", t.name.sql, c.name.sql, c.typ)
+	}
+}
+
+func (m *schemaModel) checkForeignKey(t *tableDef, fk *foreignKeyDef) {
+	for _, col := range fk.columns {
+		if t.column(col.key) == nil {
+			m.issue(domain.SeverityError, domain.IssueUnknownColumn, fk.line, t.name.key, "foreign key of %s uses column %s, which the table does not have", t.name.sql, col.sql)
+			return
+		}
+	}
+	ref := m.byKey[fk.refTable.key]
+	if ref == nil {
+		m.issue(domain.SeverityError, domain.IssueDanglingFK, fk.line, t.name.key, "%s.%s references table %s, which is not defined", t.name.sql, joinSQL(fk.columns), fk.refTable.sql)
+		return
+	}
+	refCols := nameKeys(fk.refColumns)
+	if len(refCols) == 0 {
+		if ref.primaryKey == nil {
+			m.issue(domain.SeverityError, domain.IssueDanglingFK, fk.line, t.name.key, "%s.%s references %s, which has no primary key", t.name.sql, joinSQL(fk.columns), ref.name.sql)
+			return
+		}
+		refCols = ref.primaryKey
+	}
+	for _, col := range refCols {
+		if ref.column(col) == nil {
+			m.issue(domain.SeverityError, domain.IssueDanglingFK, fk.line, t.name.key, "%s.%s references column %s.%s, which does not exist", t.name.sql, joinSQL(fk.columns), ref.name.sql, col)
+			return
+		}
+	}
+	switch {
+	case len(refCols) != len(fk.columns):
+		m.issue(domain.SeverityError, domain.IssueDanglingFK, fk.line, t.name.key, "foreign key %s.%s has %d columns but references %d", t.name.sql, joinSQL(fk.columns), len(fk.columns), len(refCols))
+		return
+	case !ref.isUnique(refCols):
+		m.issue(domain.SeverityError, domain.IssueDanglingFK, fk.line, t.name.key, "%s.%s references %s (%s), which is not a primary key nor unique", t.name.sql, joinSQL(fk.columns), ref.name.sql, strings.Join(refCols, ", "))
+		return
+	}
+	for i, col := range fk.columns {
+		from, to := typeFamily(t.column(col.key).typ), typeFamily(ref.column(refCols[i]).typ)
+		if from != "" && to != "" && from != to {
+			m.issue(domain.SeverityError, domain.IssueDanglingFK, fk.line, t.name.key, "%s.%s (%s) cannot reference %s.%s (%s): incompatible types",
+				t.name.sql, col.sql, t.column(col.key).typ, ref.name.sql, refCols[i], ref.column(refCols[i]).typ)
+		}
+	}
+}
+
+func typeFamily(typ string) string {
+	base := strings.ToLower(strings.TrimSpace(typ))
+	if i := strings.IndexAny(base, " (["); i >= 0 {
+		base = base[:i]
+	}
+	return typeFamilies[base]
+}
+
+func joinSQL(names []sqlName) string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = n.sql
+	}
+	return strings.Join(out, ", ")
+}
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{2,6})\s+(.+?)\s*#*$`)
+	cardinality        = `\(\s*(0|1|n|m|\*|[01]\.\.[1nm*])\s*\)`
+	relationPattern    = regexp.MustCompile(`(?i)^\s*(?:[-*]\s+)?(.+?)\s*` + cardinality + `\s*[-<>|o{}=.]+\s*` + cardinality + `\s*(.+?)\s*$`)
+	boldPattern        = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	parenPattern       = regexp.MustCompile(`\(([^)]*)\)`)
+	nonEntitySections  = []string{"relacion", "diagrama", "nota", "relation", "diagram"}
+	entityLabelPattern = regexp.MustCompile(`(?i)^(?:\d+[.)]\s*|(?:entidad|entity|tabla|table)\s*:\s*)`)
+)
+
+// entityRef is an entity named in the entities and relationships section
+type entityRef struct {
+	names    []string // the name and the alternatives given in parentheses
+	line     int
+	severity string
+}
+
+// entityReferences finds the entities of the entities and relationships
+// section: its ### headings and the sides of the text diagram lines are
+// errors when they have no table; the bold words of the relationship list
+// only warnings, since they are prose
+func entityReferences(text string) []entityRef {
+	var refs []entityRef
+	entitySection := true
+	for i, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+			title := strings.ToLower(slugify(m[2], " "))
+			if len(m[1]) == 2 {
+				entitySection = !slices.ContainsFunc(nonEntitySections, func(s string) bool { return strings.Contains(title, s) })
+				continue
+			}
+			if entitySection {
+				refs = append(refs, entityRef{names: entityNames(m[2]), line: i + 1, severity: domain.SeverityError})
+			}
+			continue
+		}
+		if m := relationPattern.FindStringSubmatch(trimmed); m != nil {
+			for _, side := range []string{m[1], m[4]} {
+				refs = append(refs, entityRef{names: entityNames(side), line: i + 1, severity: domain.SeverityError})
+			}
+			continue
+		}
+		if !entitySection && (strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ")) {
+			for _, b := range boldPattern.FindAllStringSubmatch(trimmed, -1) {
+				refs = append(refs, entityRef{names: entityNames(b[1]), line: i + 1, severity: domain.SeverityWarning})
+			}
+		}
+	}
+	return refs
+}
+
+// entityNames cleans an entity title: "1. Usuario (users)" gives Usuario and users
+func entityNames(title string) []string {
+	title = strings.Trim(strings.TrimSpace(title), "*`_")
+	title = entityLabelPattern.ReplaceAllString(title, "")
+	var names []string
+	for _, m := range parenPattern.FindAllStringSubmatch(title, -1) {
+		names = append(names, m[1])
+	}
+	main := strings.TrimSpace(parenPattern.ReplaceAllString(title, ""))
+	return append([]string{main}, names...)
+}
+
+// checkEntities reports the entities of the entities and relationships
+// section that match no table. Names match ignoring case, accents,
+// articles and plurals: "Detalle de Orden" matches detalles_orden.
+func (m *schemaModel) checkEntities(text string) {
+	seen := map[string]bool{}
+	for _, ref := range entityReferences(text) {
+		key := entityKey(ref.names[0])
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		found := slices.ContainsFunc(ref.names, func(name string) bool {
+			return slices.ContainsFunc(m.tables, func(t *tableDef) bool {
+				table := t.name.key[strings.LastIndex(t.name.key, ".")+1:]
+				return sameEntity(entityKey(name), entityKey(table))
+			})
+		})
+		if !found {
+			m.issue(ref.severity, domain.IssueMissingEntity, 0, "", "entity %q of entities_relationships (line %d) has no table in the schema", ref.names[0], ref.line)
+		}
+	}
+}
+
+// entityKey lowercases a name to its words without accents nor stopwords
+func entityKey(name string) string {
+	var words []string
+	for _, w := range strings.Fields(slugify(name, " ")) {
+		if !slices.Contains(packageStopwords, w) {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// sameEntity compares entity keys word by word, allowing Spanish and English plurals
+func sameEntity(a, b string) bool {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) == 0 || len(wa) != len(wb) {
+		return false
+	}
+	for i := range wa {
+		if !slices.ContainsFunc(wordForms(wa[i]), func(f string) bool { return slices.Contains(wordForms(wb[i]), f) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func wordForms(w string) []string {
+	forms := []string{w}
+	if len(w) > 3 && strings.HasSuffix(w, "s") {
+		forms = append(forms, w[:len(w)-1])
+		if strings.HasSuffix(w, "es") {
+			forms = append(forms, w[:len(w)-2])
+		}
+	}
+	return forms
+}
+
+// SchemaAgent rewrites the database schema section following a message,
+// returning its reply and the whole new section
+type SchemaAgent func(ctx context.Context, message string) (reply, schema string, err error)
+
+// RepairSchema sends the errors of the database schema to an agent and keeps
+// its rewrite only when it has fewer errors than the current schema
+func (uc *ArchitectureUsecase) RepairSchema(ctx context.Context, arch *domain.Architecture, agent SchemaAgent) (*domain.SchemaRepair, error) {
+	repair := &domain.SchemaRepair{Before: AnalyzeSchema(arch)}
+	if repair.Before.Errors == 0 {
+		return repair, nil
+	}
+	reply, schema, err := agent(ctx, SchemaRepairMessage(repair.Before))
+	if err != nil {
+		return nil, err
+	}
+	repair.Reply = reply
+	if strings.TrimSpace(schema) == "" {
+		return repair, nil
+	}
+
+	candidate := *arch
+	candidate.DatabaseSchema = schema
+	repair.After = AnalyzeSchema(&candidate)
+	if repair.After.Errors >= repair.Before.Errors {
+		return repair, nil
+	}
+	if err := uc.update(events.WithSource(ctx, events.SourceAgent), &candidate, "", false); err != nil {
+		return nil, err
+	}
+	arch.DatabaseSchema = schema
+	repair.Applied = true
+	return repair, nil
+}
+
+// SchemaRepairMessage asks the architecture agent to fix the errors of a
+// schema report, in the language of the agent's prompts
+func SchemaRepairMessage(report *domain.SchemaReport) string {
+	var b strings.Builder
+	b.WriteString("El esquema de base de datos no es DDL válido de PostgreSQL. Corrige estos problemas sin cambiar el diseño ")
+	b.WriteString("ni quitar tablas, conservando la explicación para personas no técnicas. El SQL debe ir en un bloque ```sql ")
+	b.WriteString("con sentencias válidas en PostgreSQL, creando cada tabla antes de las que la referencian.\n\nProblemas encontrados:\n")
+	n := 0
+	for _, issue := range report.Issues {
+		if issue.Severity != domain.SeverityError {
+			continue
+		}
+		if n++; n > 30 {
+			fmt.Fprintf(&b, "- ... y %d más\n", report.Errors-30)
+			break
+		}
+		if issue.Line > 0 {
+			fmt.Fprintf(&b, "- Línea %d: %s\n", issue.Line, issue.Message)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", issue.Message)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,830 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+// schemaModel is a database schema parsed from PostgreSQL DDL
+type schemaModel struct {
+	tables  []*tableDef
+	byKey   map[string]*tableDef
+	types   map[string]bool // types and domains the schema declares
+	setup   []ddlStatement  // schemas, extensions, types, domains and sequences
+	objects []ddlStatement  // views, functions, triggers and other statements
+	issues  []domain.SchemaIssue
+	// later holds the statements on tables (indexes, ALTER, COMMENT,
+	// INSERT), resolved once every table is known
+	later []func()
+}
+
+// ddlStatement is a statement of a migration and the one reverting it
+type ddlStatement struct {
+	line   int
+	up     string
+	down   string // empty when dropping its table or object reverts it
+	dollar bool
+}
+
+type tableDef struct {
+	name        sqlName
+	line        int
+	columns     []*columnDef
+	constraints []string // table constraints other than foreign keys, as written
+	foreignKeys []*foreignKeyDef
+	primaryKey  []string
+	unique      [][]string // column sets with a primary key, unique constraint or index
+	tail        string     // options after the column list: PARTITION BY, INHERITS...
+	after       []ddlStatement
+}
+
+type columnDef struct {
+	name    sqlName
+	line    int
+	typ     string
+	notNull bool
+	sql     string // the definition as written
+	bare    string // the definition without its REFERENCES clause
+}
+
+type foreignKeyDef struct {
+	name       string // constraint name as written, if any
+	line       int
+	columns    []sqlName
+	refTable   sqlName
+	refColumns []sqlName
+	references string     // REFERENCES clause as written
+	clause     string     // whole table constraint as written; empty for column references
+	column     *columnDef // set for a REFERENCES on a column
+}
+
+func (t *tableDef) column(key string) *columnDef {
+	for _, c := range t.columns {
+		if c.name.key == key {
+			return c
+		}
+	}
+	return nil
+}
+
+// isUnique reports whether the columns have a primary key or unique constraint
+func (t *tableDef) isUnique(cols []string) bool {
+	for _, u := range t.unique {
+		if len(u) == len(cols) && !slices.ContainsFunc(cols, func(c string) bool { return !slices.Contains(u, c) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSchema parses the SQL of a schema section. Statements that do not
+// parse are reported and left out; the rest of the schema is still analyzed.
+func parseSchema(sql string) *schemaModel {
+	m := &schemaModel{byKey: map[string]*tableDef{}, types: map[string]bool{}}
+	toks, lexErr := lexSQL(sql)
+	if lexErr != nil {
+		m.issue(domain.SeverityError, domain.IssueSyntax, lexErr.line, "", "%s", lexErr.msg)
+	}
+	stmts := splitTokens(toks)
+	if lexErr != nil && len(toks) > 0 && !toks[len(toks)-1].is(";") {
+		// the statement cut by the error would only add noise
+		stmts = stmts[:len(stmts)-1]
+	}
+	backticks := false
+	for _, stmt := range stmts {
+		if !backticks && slices.ContainsFunc(stmt, func(t token) bool { return t.backtick }) {
+			backticks = true
+			m.issue(domain.SeverityError, domain.IssueUnsupported, stmt[0].line, "", "backtick quoted identifiers are MySQL syntax; use double quotes or none")
+		}
+		p := &parser{src: sql, toks: stmt}
+		if err := m.statement(p); err != nil {
+			line := stmt[0].line
+			var se *syntaxError
+			if errors.As(err, &se) && se.line > 0 {
+				line = se.line
+			}
+			m.issue(domain.SeverityError, domain.IssueSyntax, line, "", "%s: %v", statementLabel(p), err)
+		}
+	}
+	for _, f := range m.later {
+		f()
+	}
+	m.check()
+	return m
+}
+
+func (m *schemaModel) issue(severity, kind string, line int, table, format string, args ...any) {
+	m.issues = append(m.issues, domain.SchemaIssue{
+		Severity: severity,
+		Kind:     kind,
+		Line:     line,
+		Table:    table,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// statementModifiers are skipped when naming a statement
+var statementModifiers = []string{"or", "replace", "unique", "global", "local", "temp", "temporary", "unlogged", "materialized", "recursive", "constraint"}
+
+// statementLabel names a statement by its first words, e.g. "CREATE TABLE"
+func statementLabel(p *parser) string {
+	var words []string
+	for _, t := range p.toks {
+		if t.kind != tokWord || len(words) == 2 {
+			break
+		}
+		if len(words) == 1 && slices.Contains(statementModifiers, strings.ToLower(t.text)) {
+			continue
+		}
+		words = append(words, strings.ToUpper(t.text))
+	}
+	if len(words) == 0 {
+		return "statement"
+	}
+	return strings.Join(words, " ")
+}
+
+func (m *schemaModel) statement(p *parser) error {
+	line := p.peek().line
+	whole := p.text(0, len(p.toks))
+	stmt := ddlStatement{line: line, up: whole, dollar: slices.ContainsFunc(p.toks, func(t token) bool { return t.dollar })}
+	first := strings.ToLower(p.peek().text)
+	switch {
+	case p.accept("create"):
+		return m.create(p, stmt)
+	case p.accept("alter", "table"):
+		return m.alterTable(p, stmt)
+	case p.accept("comment", "on"):
+		return m.comment(p, stmt)
+	case p.accept("insert", "into"):
+		table, err := p.name("table name")
+		if err != nil {
+			return err
+		}
+		m.onTable(table, stmt)
+		return nil
+	case slices.Contains([]string{"begin", "commit", "end", "rollback", "start"}, first):
+		// goose runs every migration in its own transaction
+		return nil
+	case first == "drop" || first == "use" || first == "set" || first == "truncate":
+		m.issue(domain.SeverityWarning, domain.IssueUnsupported, line, "", "%s statement ignored: migrations only create the schema", strings.ToUpper(first))
+		return nil
+	case slices.Contains([]string{"alter", "grant", "revoke", "do", "select", "update", "delete", "call"}, first):
+		if first == "do" || first == "update" || first == "delete" || first == "grant" || first == "revoke" {
+			m.issue(domain.SeverityWarning, domain.IssueNotReversible, line, "", "%s statement is not reverted by the down migration", strings.ToUpper(first))
+		}
+		m.objects = append(m.objects, stmt)
+		return nil
+	}
+	return p.fail("unknown statement %s", p.peek().describe())
+}
+
+func (m *schemaModel) create(p *parser, stmt ddlStatement) error {
+	p.accept("or", "replace")
+	p.acceptAny("global", "local")
+	p.acceptAny("temp", "temporary", "unlogged")
+	switch {
+	case p.accept("table"):
+		return m.createTable(p, stmt.line)
+	case p.accept("unique", "index") || p.accept("index"):
+		return m.createIndex(p, stmt)
+	case p.accept("extension"):
+		p.accept("if", "not", "exists")
+		n, err := p.name("extension name")
+		if err != nil {
+			return err
+		}
+		stmt.down = "DROP EXTENSION IF EXISTS " + n.sql + ";"
+		m.setup = append(m.setup, stmt)
+	case p.accept("schema"):
+		p.accept("if", "not", "exists")
+		n, err := p.name("schema name")
+		if err != nil {
+			return err
+		}
+		stmt.down = "DROP SCHEMA IF EXISTS " + n.sql + ";"
+		// schemas go first: the other objects may live in them
+		m.setup = append([]ddlStatement{stmt}, m.setup...)
+	case p.accept("type") || p.accept("domain"):
+		kind := strings.ToUpper(p.toks[p.pos-1].text)
+		n, err := p.name(strings.ToLower(kind) + " name")
+		if err != nil {
+			return err
+		}
+		if kind == "TYPE" && !p.done() && !p.accept("as") {
+			return p.fail("expected AS, found %s", p.peek().describe())
+		}
+		if err := p.skipUntil(nil); err != nil {
+			return err
+		}
+		m.types[n.key] = true
+		stmt.down = "DROP " + kind + " IF EXISTS " + n.sql + ";"
+		m.setup = append(m.setup, stmt)
+	case p.accept("sequence"):
+		p.accept("if", "not", "exists")
+		n, err := p.name("sequence name")
+		if err != nil {
+			return err
+		}
+		stmt.down = "DROP SEQUENCE IF EXISTS " + n.sql + ";"
+		m.setup = append(m.setup, stmt)
+	case p.accept("recursive", "view") || p.accept("view") || p.accept("materialized", "view"):
+		kind := "VIEW"
+		if p.toks[p.pos-2].is("materialized") {
+			kind = "MATERIALIZED VIEW"
+		}
+		p.accept("if", "not", "exists")
+		n, err := p.name("view name")
+		if err != nil {
+			return err
+		}
+		stmt.down = "DROP " + kind + " IF EXISTS " + n.sql + ";"
+		m.objects = append(m.objects, stmt)
+	case p.accept("function") || p.accept("procedure"):
+		kind := strings.ToUpper(p.toks[p.pos-1].text)
+		n, err := p.name(strings.ToLower(kind) + " name")
+		if err != nil {
+			return err
+		}
+		from, to, err := p.group()
+		if err != nil {
+			return err
+		}
+		if err := p.skipUntil(nil); err != nil {
+			return err
+		}
+		stmt.down = "DROP " + kind + " IF EXISTS " + n.sql + "(" + signature(p, from, to) + ");"
+		m.objects = append(m.objects, stmt)
+	case p.accept("trigger") || p.accept("constraint", "trigger") || p.accept("policy"):
+		kind := "TRIGGER"
+		if p.toks[p.pos-1].is("policy") {
+			kind = "POLICY"
+		}
+		n, err := p.ident(strings.ToLower(kind) + " name")
+		if err != nil {
+			return err
+		}
+		if err := p.skipUntil(func(t token) bool { return t.is("on") }); err != nil {
+			return err
+		}
+		if err := p.expect("on"); err != nil {
+			return err
+		}
+		table, err := p.name("table name")
+		if err != nil {
+			return err
+		}
+		if err := p.skipUntil(nil); err != nil {
+			return err
+		}
+		stmt.down = "DROP " + kind + " IF EXISTS " + n.sql + " ON " + table.sql + ";"
+		m.objects = append(m.objects, stmt)
+	case p.accept("database"):
+		m.issue(domain.SeverityWarning, domain.IssueUnsupported, stmt.line, "", "CREATE DATABASE statement ignored: migrations run inside the database")
+	default:
+		m.issue(domain.SeverityWarning, domain.IssueNotReversible, stmt.line, "", "%s statement is not reverted by the down migration", statementLabel(p))
+		m.objects = append(m.objects, stmt)
+	}
+	return nil
+}
+
+// signature returns the argument types of a function for DROP FUNCTION,
+// without their defaults
+func signature(p *parser, from, to int) string {
+	var args []string
+	for _, arg := range p.split(from, to) {
+		end := slices.IndexFunc(arg.toks, func(t token) bool { return t.is("default") || t.is("=") })
+		if end < 0 {
+			end = len(arg.toks)
+		}
+		if s := arg.text(0, end); s != "" {
+			args = append(args, s)
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// tableOptions may follow the column list of a CREATE TABLE
+var tableOptions = []string{"inherits", "partition", "using", "with", "without", "tablespace", "on"}
+
+func (m *schemaModel) createTable(p *parser, line int) error {
+	p.accept("if", "not", "exists")
+	name, err := p.name("table name")
+	if err != nil {
+		return err
+	}
+	if !p.peek().is("(") {
+		return p.fail("expected ( with the columns of %s, found %s", name.sql, p.peek().describe())
+	}
+	from, to, err := p.group()
+	if err != nil {
+		return err
+	}
+	t := &tableDef{name: name, line: line}
+	if !p.done() {
+		start := p.pos
+		switch first := strings.ToLower(p.peek().text); {
+		case slices.Contains(tableOptions, first):
+			if err := p.skipUntil(nil); err != nil {
+				return err
+			}
+			t.tail = p.text(start, p.pos)
+		case slices.Contains([]string{"engine", "default", "charset", "collate", "auto_increment", "comment", "row_format"}, first):
+			m.issue(domain.SeverityError, domain.IssueUnsupported, p.peek().line, name.key, "table options %q are MySQL syntax", p.rest())
+		default:
+			return p.fail("unexpected %s after the columns of %s", p.peek().describe(), name.sql)
+		}
+	}
+
+	if other := m.byKey[name.key]; other != nil {
+		m.issue(domain.SeverityError, domain.IssueDuplicate, line, name.key, "table %s is already defined at line %d", name.sql, other.line)
+		return nil
+	}
+	m.tables = append(m.tables, t)
+	m.byKey[name.key] = t
+
+	parts := p.split(from, to)
+	for i, part := range parts {
+		if part.done() {
+			// the line of the comma before the empty element, or of the parenthesis
+			at := p.toks[to-1].line
+			if prev := parts[max(i-1, 0)].toks; i > 0 && len(prev) > 0 {
+				at = prev[len(prev)-1].line
+			}
+			msg := "empty column list"
+			if to > from {
+				msg = "empty element in the column list (trailing or doubled comma?)"
+			}
+			m.issue(domain.SeverityError, domain.IssueSyntax, at, name.key, "CREATE TABLE %s: %s", name.sql, msg)
+			continue
+		}
+		if err := m.tableElement(t, part); err != nil {
+			m.elementError(t, part, err)
+		}
+	}
+	return nil
+}
+
+// elementError reports a column or constraint that does not parse; the
+// rest of the table is still analyzed
+func (m *schemaModel) elementError(t *tableDef, p *parser, err error) {
+	line := p.toks[0].line
+	var se *syntaxError
+	if errors.As(err, &se) && se.line > 0 {
+		line = se.line
+	}
+	m.issue(domain.SeverityError, domain.IssueSyntax, line, t.name.key, "table %s: %v", t.name.sql, err)
+}
+
+// tableElement parses a column or a table constraint
+func (m *schemaModel) tableElement(t *tableDef, p *parser) error {
+	first := p.peek()
+	switch {
+	case slices.ContainsFunc([]string{"constraint", "primary", "unique", "check", "foreign", "exclude"}, first.is):
+		return m.tableConstraint(t, p)
+	case first.is("like"):
+		t.constraints = append(t.constraints, p.rest())
+		return nil
+	case isMySQLIndex(p):
+		m.issue(domain.SeverityError, domain.IssueUnsupported, first.line, t.name.key, "table %s: inline %s definitions are MySQL syntax; use CREATE INDEX", t.name.sql, strings.ToUpper(first.text))
+		return nil
+	}
+	return m.column(t, p)
+}
+
+// isMySQLIndex reports whether the element is a MySQL KEY or INDEX
+// definition, e.g. KEY idx_email (email), rather than a column named so
+func isMySQLIndex(p *parser) bool {
+	if !slices.ContainsFunc([]string{"key", "index", "fulltext", "spatial"}, p.peek().is) {
+		return false
+	}
+	n := 1
+	if k := p.peekAt(n).kind; k == tokWord || k == tokQuoted {
+		if p.peekAt(n).is("key") || p.peekAt(n).is("index") {
+			n++
+		}
+		if k := p.peekAt(n).kind; (k == tokWord || k == tokQuoted) && p.peekAt(n+1).is("(") {
+			n++
+		}
+	}
+	return p.peekAt(n).is("(") && (p.peekAt(n+1).kind == tokWord || p.peekAt(n+1).kind == tokQuoted)
+}
+
+func (m *schemaModel) tableConstraint(t *tableDef, p *parser) error {
+	name := ""
+	if p.accept("constraint") {
+		n, err := p.ident("constraint name")
+		if err != nil {
+			return err
+		}
+		name = n.sql
+	}
+	switch {
+	case p.accept("primary", "key"):
+		cols, err := p.identList("column name")
+		if err != nil {
+			return err
+		}
+		if t.primaryKey != nil {
+			m.issue(domain.SeverityError, domain.IssueDuplicate, p.toks[0].line, t.name.key, "table %s has more than one primary key", t.name.sql)
+		}
+		t.primaryKey = nameKeys(cols)
+		t.unique = append(t.unique, t.primaryKey)
+	case p.accept("unique"):
+		if p.accept("key") || p.accept("index") {
+			return p.fail("UNIQUE %s is MySQL syntax; use UNIQUE (columns)", strings.ToUpper(p.toks[p.pos-1].text))
+		}
+		if p.accept("nulls") {
+			p.accept("not")
+			if err := p.expect("distinct"); err != nil {
+				return err
+			}
+		}
+		cols, err := p.identList("column name")
+		if err != nil {
+			return err
+		}
+		t.unique = append(t.unique, nameKeys(cols))
+	case p.accept("check"):
+		if _, _, err := p.group(); err != nil {
+			return err
+		}
+		p.accept("no", "inherit")
+	case p.accept("exclude"):
+		// EXCLUDE [USING method] (element WITH operator, ...)
+	case p.accept("foreign", "key"):
+		cols, err := p.identList("column name")
+		if err != nil {
+			return err
+		}
+		fk, err := m.references(p)
+		if err != nil {
+			return err
+		}
+		fk.name, fk.columns = name, cols
+		if !p.done() {
+			return p.fail("unexpected %s after the foreign key", p.peek().describe())
+		}
+		fk.clause = p.text(0, len(p.toks))
+		t.foreignKeys = append(t.foreignKeys, fk)
+		return nil
+	default:
+		return p.fail("expected a constraint, found %s", p.peek().describe())
+	}
+	// index parameters, DEFERRABLE...
+	if err := p.skipUntil(nil); err != nil {
+		return err
+	}
+	t.constraints = append(t.constraints, p.text(0, len(p.toks)))
+	return nil
+}
+
+// references parses a REFERENCES clause with its match, referential actions
+// and deferral options
+func (m *schemaModel) references(p *parser) (*foreignKeyDef, error) {
+	start := p.pos
+	fk := &foreignKeyDef{line: p.peek().line}
+	if err := p.expect("references"); err != nil {
+		return nil, err
+	}
+	ref, err := p.name("table name")
+	if err != nil {
+		return nil, err
+	}
+	fk.refTable = ref
+	if p.peek().is("(") {
+		if fk.refColumns, err = p.identList("column name"); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		switch {
+		case p.accept("match"):
+			if !p.acceptAny("full", "partial", "simple") {
+				return nil, p.fail("expected FULL, PARTIAL or SIMPLE, found %s", p.peek().describe())
+			}
+		case p.accept("on", "delete") || p.accept("on", "update"):
+			switch {
+			case p.accept("cascade") || p.accept("restrict") || p.accept("no", "action"):
+			case p.accept("set", "null") || p.accept("set", "default"):
+				if p.peek().is("(") {
+					if _, err := p.identList("column name"); err != nil {
+						return nil, err
+					}
+				}
+			default:
+				return nil, p.fail("expected CASCADE, RESTRICT, NO ACTION, SET NULL or SET DEFAULT, found %s", p.peek().describe())
+			}
+		case p.accept("deferrable") || p.accept("not", "deferrable"):
+		case p.accept("initially"):
+			if !p.acceptAny("deferred", "immediate") {
+				return nil, p.fail("expected DEFERRED or IMMEDIATE, found %s", p.peek().describe())
+			}
+		default:
+			fk.references = p.text(start, p.pos)
+			return fk, nil
+		}
+	}
+}
+
+// columnConstraints are the words a column's constraints can start with;
+// a column's type ends at the first one
+var columnConstraints = []string{
+	"constraint", "not", "null", "default", "primary", "unique", "check", "references", "generated",
+	"collate", "deferrable", "initially",
+	"auto_increment", "unsigned", "comment", "on", "identity",
+}
+
+func isColumnConstraint(t token) bool {
+	return t.kind == tokWord && slices.ContainsFunc(columnConstraints, t.is)
+}
+
+func (m *schemaModel) column(t *tableDef, p *parser) error {
+	name, err := p.ident("column name")
+	if err != nil {
+		return err
+	}
+	c := &columnDef{name: name, line: p.toks[0].line, sql: p.text(0, len(p.toks))}
+	typeStart := p.pos
+	if err := p.skipUntil(isColumnConstraint); err != nil {
+		return err
+	}
+	if p.pos == typeStart {
+		return p.fail("column %s has no type", name.sql)
+	}
+	c.typ = p.text(typeStart, p.pos)
+
+	refFrom, refTo := -1, -1
+	constraintAt := -1
+	for !p.done() {
+		at := p.pos
+		switch {
+		case p.accept("constraint"):
+			if _, err := p.ident("constraint name"); err != nil {
+				return err
+			}
+			constraintAt = at
+			continue
+		case p.accept("not", "null"):
+			c.notNull = true
+		case p.accept("null"):
+		case p.accept("default"):
+			// the value may itself be a constraint word: DEFAULT NULL
+			if p.done() || p.peek().is(",") {
+				return p.fail("column %s: DEFAULT without a value", name.sql)
+			}
+			p.pos++
+			if err := p.skipUntil(isColumnConstraint); err != nil {
+				return err
+			}
+		case p.accept("primary", "key"):
+			if t.primaryKey != nil {
+				m.issue(domain.SeverityError, domain.IssueDuplicate, c.line, t.name.key, "table %s has more than one primary key", t.name.sql)
+			}
+			c.notNull = true
+			t.primaryKey = []string{name.key}
+			t.unique = append(t.unique, t.primaryKey)
+		case p.accept("unique"):
+			if p.accept("nulls") {
+				p.accept("not")
+				if err := p.expect("distinct"); err != nil {
+					return err
+				}
+			}
+			t.unique = append(t.unique, []string{name.key})
+		case p.accept("check"):
+			if _, _, err := p.group(); err != nil {
+				return err
+			}
+			p.accept("no", "inherit")
+		case p.peek().is("references"):
+			fk, err := m.references(p)
+			if err != nil {
+				return err
+			}
+			fk.columns, fk.column = []sqlName{name}, c
+			if constraintAt == at-2 {
+				fk.name = p.toks[at-1].text
+				at = constraintAt
+			}
+			refFrom, refTo = at, p.pos
+			t.foreignKeys = append(t.foreignKeys, fk)
+		case p.accept("generated"):
+			if !p.accept("always") && !p.accept("by", "default") {
+				return p.fail("expected ALWAYS or BY DEFAULT, found %s", p.peek().describe())
+			}
+			if err := p.expect("as"); err != nil {
+				return err
+			}
+			if p.accept("identity") {
+				if p.peek().is("(") {
+					if _, _, err := p.group(); err != nil {
+						return err
+					}
+				}
+			} else {
+				if _, _, err := p.group(); err != nil {
+					return err
+				}
+				if err := p.expect("stored"); err != nil {
+					return err
+				}
+			}
+		case p.accept("collate"):
+			if _, err := p.name("collation"); err != nil {
+				return err
+			}
+		case p.accept("deferrable") || p.accept("not", "deferrable"):
+		case p.accept("initially"):
+			if !p.acceptAny("deferred", "immediate") {
+				return p.fail("expected DEFERRED or IMMEDIATE, found %s", p.peek().describe())
+			}
+		case p.accept("auto_increment") || p.accept("identity"):
+			m.issue(domain.SeverityError, domain.IssueUnsupported, c.line, t.name.key, "column %s.%s: %s is not PostgreSQL; use GENERATED ALWAYS AS IDENTITY or SERIAL", t.name.sql, name.sql, strings.ToUpper(p.toks[at].text))
+			if p.peek().is("(") {
+				if _, _, err := p.group(); err != nil {
+					return err
+				}
+			}
+		case p.accept("unsigned"):
+			m.issue(domain.SeverityError, domain.IssueUnsupported, c.line, t.name.key, "column %s.%s: UNSIGNED is MySQL syntax; use a CHECK (%s >= 0) constraint", t.name.sql, name.sql, name.sql)
+		case p.accept("on", "update"):
+			m.issue(domain.SeverityError, domain.IssueUnsupported, c.line, t.name.key, "column %s.%s: ON UPDATE is MySQL syntax; use a trigger", t.name.sql, name.sql)
+			if err := p.skipUntil(isColumnConstraint); err != nil {
+				return err
+			}
+		case p.accept("comment"):
+			m.issue(domain.SeverityError, domain.IssueUnsupported, c.line, t.name.key, "column %s.%s: inline COMMENT is MySQL syntax; use COMMENT ON COLUMN", t.name.sql, name.sql)
+			p.next()
+		default:
+			return p.fail("unexpected %s in column %s", p.peek().describe(), name.sql)
+		}
+		constraintAt = -1
+	}
+	if constraintAt >= 0 {
+		return p.fail("column %s: CONSTRAINT without a constraint", name.sql)
+	}
+
+	c.bare = c.sql
+	if refFrom >= 0 {
+		c.bare = strings.TrimSpace(p.text(0, refFrom) + " " + p.text(refTo, len(p.toks)))
+	}
+	if t.column(name.key) != nil {
+		m.issue(domain.SeverityError, domain.IssueDuplicate, c.line, t.name.key, "column %s.%s is defined twice", t.name.sql, name.sql)
+		return nil
+	}
+	t.columns = append(t.columns, c)
+	return nil
+}
+
+func (m *schemaModel) createIndex(p *parser, stmt ddlStatement) error {
+	unique := p.toks[p.pos-2].is("unique")
+	p.accept("concurrently")
+	p.accept("if", "not", "exists")
+	var name sqlName
+	if !p.peek().is("on") {
+		n, err := p.name("index name")
+		if err != nil {
+			return err
+		}
+		name = n
+	}
+	if err := p.expect("on"); err != nil {
+		return err
+	}
+	p.accept("only")
+	table, err := p.name("table name")
+	if err != nil {
+		return err
+	}
+	if p.accept("using") {
+		p.next()
+	}
+	from, to, err := p.group()
+	if err != nil {
+		return err
+	}
+	if err := p.skipUntil(nil); err != nil {
+		return err
+	}
+
+	// plain column elements are checked; expressions are left alone
+	var cols []sqlName
+	plain := true
+	for _, part := range p.split(from, to) {
+		n, err := part.ident("column name")
+		part.acceptAny("asc", "desc")
+		if part.accept("nulls") {
+			part.acceptAny("first", "last")
+		}
+		if err != nil || !part.done() {
+			plain = false
+			continue
+		}
+		cols = append(cols, n)
+	}
+
+	m.later = append(m.later, func() {
+		t := m.byKey[table.key]
+		if t == nil {
+			m.issue(domain.SeverityError, domain.IssueUnknownTable, stmt.line, table.key, "index %s is on table %s, which is not defined", name.sql, table.sql)
+			if name.sql != "" {
+				stmt.down = "DROP INDEX IF EXISTS " + name.sql + ";"
+			}
+			m.objects = append(m.objects, stmt)
+			return
+		}
+		for _, c := range cols {
+			if t.column(c.key) == nil {
+				m.issue(domain.SeverityError, domain.IssueUnknownColumn, stmt.line, t.name.key, "index on %s uses column %s, which the table does not have", t.name.sql, c.sql)
+			}
+		}
+		if unique && plain {
+			t.unique = append(t.unique, nameKeys(cols))
+		}
+		t.after = append(t.after, stmt)
+	})
+	return nil
+}
+
+func (m *schemaModel) alterTable(p *parser, stmt ddlStatement) error {
+	p.accept("if", "exists")
+	p.accept("only")
+	name, err := p.name("table name")
+	if err != nil {
+		return err
+	}
+	p.accept("*")
+	if p.done() {
+		return p.fail("ALTER TABLE %s without an action", name.sql)
+	}
+	actions := p.split(p.pos, len(p.toks))
+	m.later = append(m.later, func() {
+		t := m.byKey[name.key]
+		if t == nil {
+			m.issue(domain.SeverityError, domain.IssueUnknownTable, stmt.line, name.key, "ALTER TABLE on %s, which is not defined", name.sql)
+			m.objects = append(m.objects, stmt)
+			return
+		}
+		// added columns and constraints become part of the table; other
+		// actions run after it is created
+		for _, a := range actions {
+			if !a.accept("add") {
+				t.after = append(t.after, ddlStatement{line: stmt.line, up: "ALTER TABLE " + name.sql + " " + a.rest() + ";", dollar: stmt.dollar})
+				continue
+			}
+			var err error
+			if a.accept("column") {
+				a.accept("if", "not", "exists")
+				err = m.column(t, a.sub(a.pos, len(a.toks)))
+			} else {
+				err = m.tableElement(t, a.sub(a.pos, len(a.toks)))
+			}
+			if err != nil {
+				m.elementError(t, a, err)
+			}
+		}
+	})
+	return nil
+}
+
+func (m *schemaModel) comment(p *parser, stmt ddlStatement) error {
+	switch {
+	case p.accept("table"):
+		table, err := p.name("table name")
+		if err != nil {
+			return err
+		}
+		m.onTable(table, stmt)
+	case p.accept("column"):
+		n, err := p.name("column name")
+		if err != nil {
+			return err
+		}
+		i := strings.LastIndex(n.key, ".")
+		if i < 0 {
+			return p.fail("COMMENT ON COLUMN needs table.column, found %q", n.sql)
+		}
+		m.onTable(sqlName{sql: n.sql[:strings.LastIndex(n.sql, ".")], key: n.key[:i]}, stmt)
+	default:
+		m.objects = append(m.objects, stmt)
+	}
+	return nil
+}
+
+// onTable attaches a statement to the migration creating its table
+func (m *schemaModel) onTable(table sqlName, stmt ddlStatement) {
+	m.later = append(m.later, func() {
+		t := m.byKey[table.key]
+		if t == nil {
+			m.issue(domain.SeverityError, domain.IssueUnknownTable, stmt.line, table.key, "statement on table %s, which is not defined", table.sql)
+			m.objects = append(m.objects, stmt)
+			return
+		}
+		t.after = append(t.after, stmt)
+	})
+}
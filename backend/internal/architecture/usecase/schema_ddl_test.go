@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dark/idea-forge/internal/architecture/domain"
+)
+
+func TestLexSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		texts   []string
+		errLine int // line of the lex error, 0 when there is none
+	}{
+		{
+			name:  "comments are skipped",
+			src:   "CREATE -- a comment\n/* block /* nested */ still */ TABLE",
+			texts: []string{"CREATE", "TABLE"},
+		},
+		{
+			name:  "strings and quoted identifiers",
+			src:   `'it''s' E'a\'b' "Odd ""name""" x'1F'`,
+			texts: []string{`'it''s'`, `E'a\'b'`, `"Odd ""name"""`, `x'1F'`},
+		},
+		{
+			name:  "dollar quoting keeps semicolons",
+			src:   "AS $body$ BEGIN; RETURN 1; END $body$;",
+			texts: []string{"AS", "$body$ BEGIN; RETURN 1; END $body$", ";"},
+		},
+		{
+			name:  "operators, casts and parameters",
+			src:   "a::text>=$1",
+			texts: []string{"a", "::", "text", ">=", "$1"},
+		},
+		{name: "unterminated string", src: "SELECT\n'open", texts: []string{"SELECT"}, errLine: 2},
+		{name: "unterminated comment", src: "x /* /* */", texts: []string{"x"}, errLine: 1},
+		{name: "unterminated dollar quote", src: "\n\n$$ never closed", errLine: 3},
+		{name: "unterminated identifier", src: `"open`, errLine: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toks, err := lexSQL(tt.src)
+			var texts []string
+			for _, tok := range toks {
+				texts = append(texts, tok.text)
+			}
+			if !reflect.DeepEqual(texts, tt.texts) {
+				t.Errorf("tokens = %q, want %q", texts, tt.texts)
+			}
+			switch {
+			case tt.errLine == 0 && err != nil:
+				t.Errorf("unexpected error at line %d: %s", err.line, err.msg)
+			case tt.errLine != 0 && (err == nil || err.line != tt.errLine):
+				t.Errorf("error = %+v, want one at line %d", err, tt.errLine)
+			}
+		})
+	}
+}
+
+func TestSchemaSQL(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{name: "no code blocks nor DDL", schema: "Usaremos PostgreSQL.", want: ""},
+		{name: "bare DDL", schema: "CREATE TABLE a (id INT);", want: "CREATE TABLE a (id INT);"},
+		{
+			name:   "only sql blocks, keeping lines",
+			schema: "Intro\n```sql\nCREATE TABLE a (id INT);\n```\n```json\n{}\n```\n```\nCREATE TABLE b (id INT);\n```",
+			want:   "\n\nCREATE TABLE a (id INT);\n\n\n\n\n\nCREATE TABLE b (id INT);\n",
+		},
+		{name: "unlabeled block without DDL", schema: "```\nnpm install\n```", want: ""},
+	}
+	for _, tt := range tests {
+		if got := schemaSQL(tt.schema); got != tt.want {
+			t.Errorf("%s: schemaSQL = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// issueKeys renders issues as kind@line for comparison
+func issueKeys(issues []domain.SchemaIssue) []string {
+	var keys []string
+	for _, i := range issues {
+		keys = append(keys, fmt.Sprintf("%s@%d", i.Kind, i.Line))
+	}
+	return keys
+}
+
+func TestAnalyzeSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		issues []string // kind@line, in report order
+	}{
+		{
+			name: "valid schema",
+			sql: `CREATE EXTENSION IF NOT EXISTS citext;
+CREATE TYPE estado AS ENUM ('activo', 'baja');
+CREATE TABLE users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email CITEXT NOT NULL UNIQUE,
+    estado estado NOT NULL DEFAULT 'activo'
+);
+CREATE TABLE posts (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "Title" TEXT
+);
+CREATE INDEX idx_posts_author ON posts (author_id);`,
+		},
+		{
+			name:   "MySQL dialect",
+			sql:    "CREATE TABLE `users` (\n  id INT(11) AUTO_INCREMENT,\n  born DATETIME,\n  KEY idx_born (born)\n) ENGINE=InnoDB;",
+			issues: []string{"unsupported@1", "unsupported@2", "unsupported@2", "unsupported@3", "unsupported@4", "unsupported@5"},
+		},
+		{
+			name:   "reserved word and trailing comma",
+			sql:    "CREATE TABLE t (\n  user TEXT,\n  id INT,\n);",
+			issues: []string{"syntax@2", "syntax@3"},
+		},
+		{
+			name:   "dangling and mistyped foreign keys",
+			sql:    "CREATE TABLE a (id UUID PRIMARY KEY, code TEXT);\nCREATE TABLE b (\n  a_id INT REFERENCES a(id),\n  c_id INT REFERENCES c(id),\n  code TEXT REFERENCES a(code)\n);",
+			issues: []string{"dangling_foreign_key@3", "dangling_foreign_key@4", "dangling_foreign_key@5"},
+		},
+		{
+			name:   "duplicates",
+			sql:    "CREATE TABLE a (id INT PRIMARY KEY, id INT, PRIMARY KEY (id));\nCREATE TABLE a (x INT);",
+			issues: []string{"duplicate@1", "duplicate@1", "duplicate@2"},
+		},
+		{
+			name:   "statements on unknown tables and columns",
+			sql:    "CREATE TABLE a (id INT);\nCREATE INDEX ON a (missing);\nALTER TABLE b ADD COLUMN x INT;\nCOMMENT ON TABLE c IS 'x';",
+			issues: []string{"unknown_column@2", "unknown_table@3", "unknown_table@4"},
+		},
+		{
+			name:   "a bad statement does not hide the others",
+			sql:    "CREATE TABLE a (id INT PRIMARY KEY);\nFROBNICATE everything;\nCREATE TABLE b (a_id INT REFERENCES a);",
+			issues: []string{"syntax@2"},
+		},
+		{
+			name:   "unterminated string drops only the cut statement",
+			sql:    "CREATE TABLE a (id INT);\nINSERT INTO a VALUES ('oops);",
+			issues: []string{"syntax@2"},
+		},
+		{
+			name:   "ignored and irreversible statements",
+			sql:    "DROP TABLE IF EXISTS a;\nCREATE TABLE a (id INT);\nUPDATE a SET id = 1;",
+			issues: []string{"unsupported@1", "not_reversible@3"},
+		},
+		{name: "no tables", sql: "CREATE EXTENSION pgcrypto;", issues: []string{"no_sql@0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := AnalyzeSchema(&domain.Architecture{DatabaseSchema: "```sql\n" + tt.sql + "\n```"})
+			// the fence adds a line before the SQL
+			var got []string
+			for _, i := range report.Issues {
+				if i.Line > 0 {
+					i.Line--
+				}
+				got = append(got, fmt.Sprintf("%s@%d", i.Kind, i.Line))
+			}
+			if !reflect.DeepEqual(got, tt.issues) {
+				t.Errorf("issues = %q, want %q", got, tt.issues)
+				for _, i := range report.Issues {
+					t.Log(i.Message)
+				}
+			}
+			errors := 0
+			for _, i := range report.Issues {
+				if i.Severity == domain.SeverityError {
+					errors++
+				}
+			}
+			if report.Errors != errors || report.Valid != (errors == 0) {
+				t.Errorf("errors = %d, valid = %v; %d issues are errors", report.Errors, report.Valid, errors)
+			}
+		})
+	}
+
+	if report := AnalyzeSchema(&domain.Architecture{DatabaseSchema: "Sin SQL"}); report.Valid || !slices.Equal(issueKeys(report.Issues), []string{"no_sql@0"}) {
+		t.Errorf("schema without SQL: %+v", report)
+	}
+}
+
+func TestAnalyzeSchemaEntities(t *testing.T) {
+	entities := "## Entidades\n\n### Usuario (users)\n\n### Detalle de Orden\n\n### Factura\n\n## Relaciones\n\n- **Usuario** tiene muchos **Pedidos**\n"
+	sql := "CREATE TABLE users (id INT PRIMARY KEY);\nCREATE TABLE detalles_orden (id INT PRIMARY KEY);"
+	report := AnalyzeSchema(&domain.Architecture{DatabaseSchema: sql, EntitiesRelationships: entities})
+
+	var missing []string
+	for _, i := range report.Issues {
+		if i.Kind == domain.IssueMissingEntity {
+			missing = append(missing, i.Severity+": "+i.Message)
+		}
+	}
+	want := []string{
+		`error: entity "Factura" of entities_relationships (line 7) has no table in the schema`,
+		`warning: entity "Pedidos" of entities_relationships (line 11) has no table in the schema`,
+	}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing entities:\n%q\nwant:\n%q", missing, want)
+	}
+}
+
+// cyclicSchema has tables created before those they reference, a reference
+// cycle, a function with its own semicolons and a type
+const cyclicSchema = "```sql\n" + `CREATE TYPE rol AS ENUM ('admin', 'miembro');
+CREATE TABLE miembros (
+    id UUID PRIMARY KEY,
+    equipo_id UUID NOT NULL REFERENCES equipos(id),
+    rol rol NOT NULL
+);
+CREATE TABLE equipos (
+    id UUID PRIMARY KEY,
+    lider_id UUID,
+    CONSTRAINT equipos_lider_fk FOREIGN KEY (lider_id) REFERENCES miembros(id)
+);
+CREATE TABLE tareas (id UUID PRIMARY KEY, equipo_id UUID REFERENCES equipos);
+CREATE INDEX idx_tareas_equipo ON tareas (equipo_id);
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.id := NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER tareas_touch BEFORE UPDATE ON tareas FOR EACH ROW EXECUTE FUNCTION touch();` + "\n```"
+
+func TestSchemaMigrations(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	migrations := SchemaMigrations(cyclicSchema, start)
+
+	var names, versions []string
+	for _, m := range migrations {
+		names = append(names, m.Name)
+		versions = append(versions, m.Version)
+	}
+	wantNames := []string{"setup", "create_miembros", "create_equipos", "create_tareas", "add_foreign_keys", "create_objects"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("migrations = %v, want %v", names, wantNames)
+	}
+	if versions[0] != "20250301120000" || versions[5] != "20250301120005" {
+		t.Errorf("versions = %v, want consecutive seconds from start", versions)
+	}
+
+	byName := map[string]domain.Migration{}
+	for _, m := range migrations {
+		byName[m.Name] = m
+	}
+	// miembros is created first, so its reference to equipos is deferred
+	if up := byName["create_miembros"].Up; strings.Contains(up, "REFERENCES") || !strings.Contains(up, "equipo_id UUID NOT NULL") {
+		t.Errorf("create_miembros keeps the deferred reference:\n%s", up)
+	}
+	if up := byName["create_equipos"].Up; !strings.Contains(up, "equipos_lider_fk") {
+		t.Errorf("create_equipos lost its foreign key:\n%s", up)
+	}
+	if up := byName["create_tareas"].Up; !strings.Contains(up, "CREATE INDEX idx_tareas_equipo") {
+		t.Errorf("index should go with its table:\n%s", up)
+	}
+	fks := byName["add_foreign_keys"]
+	if fks.Up != "ALTER TABLE miembros ADD CONSTRAINT miembros_equipo_id_fkey FOREIGN KEY (equipo_id) REFERENCES equipos(id);" ||
+		fks.Down != "ALTER TABLE miembros DROP CONSTRAINT IF EXISTS miembros_equipo_id_fkey;" {
+		t.Errorf("deferred foreign key:\nup: %s\ndown: %s", fks.Up, fks.Down)
+	}
+
+	objects := byName["create_objects"]
+	if !strings.Contains(objects.Up, "-- +goose StatementBegin\nCREATE FUNCTION touch()") || !strings.Contains(objects.Up, "$$ LANGUAGE plpgsql;\n-- +goose StatementEnd") {
+		t.Errorf("function body must be one goose statement:\n%s", objects.Up)
+	}
+	if objects.Down != "DROP TRIGGER IF EXISTS tareas_touch ON tareas;\nDROP FUNCTION IF EXISTS touch();" {
+		t.Errorf("objects are reverted in reverse order:\n%s", objects.Down)
+	}
+	if setup := byName["setup"]; setup.Down != "DROP TYPE IF EXISTS rol;" {
+		t.Errorf("setup down = %q", setup.Down)
+	}
+
+	for _, schema := range []string{"", "Sin SQL", "```sql\n-- nada\n```"} {
+		if got := SchemaMigrations(schema, start); len(got) != 0 {
+			t.Errorf("SchemaMigrations(%q) = %v, want none", schema, got)
+		}
+	}
+}
+
+// TestSchemaMigrationsRoundTrip applies the up migrations in order as one
+// script: it must parse as the same valid schema, and the down migrations
+// run in reverse must drop everything the ups create
+func TestSchemaMigrationsRoundTrip(t *testing.T) {
+	before := AnalyzeSchema(&domain.Architecture{DatabaseSchema: cyclicSchema})
+	if !before.Valid {
+		t.Fatalf("schema is not valid: %+v", before.Issues)
+	}
+
+	migrations := SchemaMigrations(cyclicSchema, time.Now())
+	var up, down []string
+	for _, m := range migrations {
+		up = append(up, m.Up)
+	}
+	for _, m := range slices.Backward(migrations) {
+		down = append(down, m.Down)
+	}
+	after := AnalyzeSchema(&domain.Architecture{DatabaseSchema: strings.Join(up, "\n")})
+	if !after.Valid {
+		t.Fatalf("applied migrations are not a valid schema: %+v", after.Issues)
+	}
+	if len(after.Tables) != len(before.Tables) {
+		t.Fatalf("%d tables after the round trip, want %d", len(after.Tables), len(before.Tables))
+	}
+	for _, table := range before.Tables {
+		i := slices.IndexFunc(after.Tables, func(t domain.SchemaTable) bool { return t.Name == table.Name })
+		if i < 0 {
+			t.Errorf("table %s is lost", table.Name)
+			continue
+		}
+		got := after.Tables[i]
+		got.Line = table.Line
+		if !reflect.DeepEqual(got, table) {
+			t.Errorf("table %s changed:\n%+v\nwant:\n%+v", table.Name, got, table)
+		}
+	}
+
+	reverted := strings.Join(down, "\n")
+	for _, drop := range []string{"DROP TABLE IF EXISTS tareas", "DROP TABLE IF EXISTS equipos", "DROP TABLE IF EXISTS miembros", "DROP TYPE IF EXISTS rol"} {
+		if !strings.Contains(reverted, drop) {
+			t.Errorf("down migrations do not %s", drop)
+		}
+	}
+	// the foreign key closing the cycle goes before the tables it joins
+	if strings.Index(reverted, "DROP CONSTRAINT IF EXISTS miembros_equipo_id_fkey") > strings.Index(reverted, "DROP TABLE IF EXISTS equipos") {
+		t.Error("deferred foreign key must be dropped before its tables")
+	}
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokWord             // keyword or bare identifier
+	tokQuoted           // "quoted identifier"
+	tokString           // 'string', E'string' or $tag$string$tag$
+	tokNumber
+	tokSymbol // punctuation and operators
+)
+
+// token is a lexical token of SQL source; start and end are byte offsets
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+	line  int
+	// dollar marks $tag$ strings, which goose must not split on semicolons
+	dollar bool
+	// backtick marks `identifiers`, quoted the MySQL way
+	backtick bool
+}
+
+// is reports whether the token is the given keyword or symbol
+func (t token) is(s string) bool {
+	return (t.kind == tokWord || t.kind == tokSymbol) && strings.EqualFold(t.text, s)
+}
+
+// describe names the token in error messages
+func (t token) describe() string {
+	if t.kind == tokEOF {
+		return "end of statement"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lexError is an unterminated string, identifier or comment
+type lexError struct {
+	line int
+	msg  string
+}
+
+// lexSQL tokenizes PostgreSQL source, skipping comments. Lexing stops at the
+// first unterminated string or comment, which is returned as an error.
+func lexSQL(src string) ([]token, *lexError) {
+	var toks []token
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		start, startLine := i, line
+		tok := token{start: i, line: line}
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+			continue
+		case strings.HasPrefix(src[i:], "--"):
+			if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(src)
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			// block comments nest in PostgreSQL
+			depth, j := 0, i
+			for j < len(src) {
+				if strings.HasPrefix(src[j:], "/*") {
+					depth, j = depth+1, j+2
+				} else if strings.HasPrefix(src[j:], "*/") {
+					depth, j = depth-1, j+2
+					if depth == 0 {
+						break
+					}
+				} else {
+					j++
+				}
+			}
+			if depth > 0 {
+				return toks, &lexError{startLine, "unterminated /* comment"}
+			}
+			line += strings.Count(src[i:j], "\n")
+			i = j
+			continue
+		case c == '\'':
+			end, ok := scanQuoted(src, i, '\'', false)
+			if !ok {
+				return toks, &lexError{startLine, "unterminated string literal"}
+			}
+			tok.kind, i = tokString, end
+		case strings.ContainsRune("eE", rune(c)) && i+1 < len(src) && src[i+1] == '\'':
+			end, ok := scanQuoted(src, i+1, '\'', true)
+			if !ok {
+				return toks, &lexError{startLine, "unterminated string literal"}
+			}
+			tok.kind, i = tokString, end
+		case strings.ContainsRune("bBxXnN", rune(c)) && i+1 < len(src) && src[i+1] == '\'':
+			end, ok := scanQuoted(src, i+1, '\'', false)
+			if !ok {
+				return toks, &lexError{startLine, "unterminated string literal"}
+			}
+			tok.kind, i = tokString, end
+		case c == '"' || c == '`':
+			end, ok := scanQuoted(src, i, c, false)
+			if !ok {
+				return toks, &lexError{startLine, "unterminated quoted identifier"}
+			}
+			tok.kind, tok.backtick, i = tokQuoted, c == '`', end
+		case c == '$' && dollarTag(src[i:]) != "":
+			tag := dollarTag(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return toks, &lexError{startLine, "unterminated dollar-quoted string " + tag}
+			}
+			tok.kind, tok.dollar, i = tokString, true, i+len(tag)+end+len(tag)
+		case isIdentStart(c):
+			for i < len(src) && (isIdentStart(src[i]) || src[i] >= '0' && src[i] <= '9' || src[i] == '$') {
+				i++
+			}
+			tok.kind = tokWord
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' || src[i] == '_') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+					i++
+				}
+			}
+			tok.kind = tokNumber
+		case c == '$' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			// positional parameter
+			for i++; i < len(src) && src[i] >= '0' && src[i] <= '9'; i++ {
+			}
+			tok.kind = tokSymbol
+		case strings.ContainsRune("+-*/<>=~!@#%^&|?", rune(c)):
+			for i < len(src) && strings.ContainsRune("+-*/<>=~!@#%^&|?", rune(src[i])) && !strings.HasPrefix(src[i:], "--") && !strings.HasPrefix(src[i:], "/*") {
+				i++
+			}
+			tok.kind = tokSymbol
+		case c == ':' && strings.HasPrefix(src[i:], "::"):
+			tok.kind, i = tokSymbol, i+2
+		default:
+			tok.kind, i = tokSymbol, i+1
+		}
+		tok.end = i
+		tok.text = src[start:i]
+		line += strings.Count(tok.text, "\n")
+		toks = append(toks, tok)
+	}
+	return toks, nil
+}
+
+// scanQuoted returns the end of the quoted text opening at src[i]; a doubled
+// quote is an escaped quote, and so is a backslash escaped one in E'...' strings
+func scanQuoted(src string, i int, quote byte, backslash bool) (int, bool) {
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			if backslash {
+				j++
+			}
+		case quote:
+			if j+1 < len(src) && src[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1, true
+		}
+	}
+	return len(src), false
+}
+
+// dollarTag returns the $tag$ opening a dollar quoted string at the start of s
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// splitTokens splits tokens into statements on top level semicolons
+func splitTokens(toks []token) [][]token {
+	var stmts [][]token
+	start := 0
+	for i, t := range toks {
+		if t.is(";") {
+			if i > start {
+				stmts = append(stmts, toks[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(toks) {
+		stmts = append(stmts, toks[start:])
+	}
+	return stmts
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// reservedWords cannot name a table or column unless quoted in PostgreSQL
+var reservedWords = []string{
+	"all", "analyse", "analyze", "and", "any", "array", "as", "asc", "asymmetric", "authorization",
+	"binary", "both", "case", "cast", "check", "collate", "collation", "column", "concurrently",
+	"constraint", "create", "cross", "current_catalog", "current_date", "current_role",
+	"current_schema", "current_time", "current_timestamp", "current_user", "default", "deferrable",
+	"desc", "distinct", "do", "else", "end", "except", "false", "fetch", "for", "foreign", "freeze",
+	"from", "full", "grant", "group", "having", "ilike", "in", "initially", "inner", "intersect",
+	"into", "is", "isnull", "join", "lateral", "leading", "left", "like", "limit", "localtime",
+	"localtimestamp", "natural", "not", "notnull", "null", "offset", "on", "only", "or", "order",
+	"outer", "overlaps", "placing", "primary", "references", "returning", "right", "select",
+	"session_user", "similar", "some", "symmetric", "system_user", "table", "tablesample", "then",
+	"to", "trailing", "true", "union", "unique", "user", "using", "variadic", "verbose", "when",
+	"where", "window", "with",
+}
+
+// syntaxError is a statement that does not parse as PostgreSQL
+type syntaxError struct {
+	line int
+	msg  string
+}
+
+func (e *syntaxError) Error() string { return e.msg }
+
+// sqlName is a possibly schema qualified name: sql as written and a lookup
+// key, lowercased unless quoted and without the default public schema
+type sqlName struct {
+	sql string
+	key string
+}
+
+// parser is a cursor over the tokens of a statement
+type parser struct {
+	src  string
+	toks []token
+	pos  int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.toks) }
+
+func (p *parser) peek() token { return p.peekAt(0) }
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.toks) {
+		return token{kind: tokEOF}
+	}
+	return p.toks[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the keywords when the next tokens are all of them
+func (p *parser) accept(words ...string) bool {
+	for i, w := range words {
+		if !p.peekAt(i).is(w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+// acceptAny consumes the next token when it is one of the keywords
+func (p *parser) acceptAny(words ...string) bool {
+	for _, w := range words {
+		if p.accept(w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(words ...string) error {
+	for _, w := range words {
+		if !p.accept(w) {
+			return p.fail("expected %s, found %s", strings.ToUpper(w), p.peek().describe())
+		}
+	}
+	return nil
+}
+
+// fail builds a syntax error at the current token
+func (p *parser) fail(format string, args ...any) error {
+	return &syntaxError{line: p.line(), msg: fmt.Sprintf(format, args...)}
+}
+
+// line is the line of the current token, or of the last one at the end
+func (p *parser) line() int {
+	switch {
+	case len(p.toks) == 0:
+		return 0
+	case p.done():
+		return p.toks[len(p.toks)-1].line
+	}
+	return p.peek().line
+}
+
+// text returns the source of the tokens [from, to)
+func (p *parser) text(from, to int) string {
+	if from >= to {
+		return ""
+	}
+	return p.src[p.toks[from].start:p.toks[to-1].end]
+}
+
+// rest returns the source of the remaining tokens and consumes them
+func (p *parser) rest() string {
+	s := p.text(p.pos, len(p.toks))
+	p.pos = len(p.toks)
+	return s
+}
+
+// ident reads a table, column or constraint name
+func (p *parser) ident(what string) (sqlName, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokQuoted:
+		p.pos++
+		name := strings.ReplaceAll(t.text[1:len(t.text)-1], t.text[:1]+t.text[:1], t.text[:1])
+		return sqlName{sql: t.text, key: name}, nil
+	case t.kind != tokWord:
+		return sqlName{}, p.fail("expected %s, found %s", what, t.describe())
+	case slices.Contains(reservedWords, strings.ToLower(t.text)):
+		return sqlName{}, p.fail("%q is a reserved word in PostgreSQL and cannot be used as %s unless quoted", t.text, what)
+	}
+	p.pos++
+	return sqlName{sql: t.text, key: strings.ToLower(t.text)}, nil
+}
+
+// name reads a possibly schema qualified name
+func (p *parser) name(what string) (sqlName, error) {
+	start := p.pos
+	n, err := p.ident(what)
+	if err != nil {
+		return n, err
+	}
+	keys := []string{n.key}
+	for p.peek().is(".") {
+		p.pos++
+		part, err := p.ident(what)
+		if err != nil {
+			return n, err
+		}
+		keys = append(keys, part.key)
+	}
+	if len(keys) > 1 && keys[0] == "public" {
+		keys = keys[1:]
+	}
+	return sqlName{sql: p.text(start, p.pos), key: strings.Join(keys, ".")}, nil
+}
+
+// group consumes a parenthesized group and returns the token range inside it
+func (p *parser) group() (int, int, error) {
+	if !p.peek().is("(") {
+		return 0, 0, p.fail("expected (, found %s", p.peek().describe())
+	}
+	return p.balanced()
+}
+
+// balanced consumes the group opening at the current ( or [
+func (p *parser) balanced() (int, int, error) {
+	open := p.pos
+	depth := 0
+	for ; !p.done(); p.pos++ {
+		switch t := p.peek(); {
+		case t.is("(") || t.is("["):
+			depth++
+		case t.is(")") || t.is("]"):
+			depth--
+			if depth == 0 {
+				p.pos++
+				return open + 1, p.pos - 1, nil
+			}
+		}
+	}
+	p.pos = open
+	return 0, 0, p.fail("unbalanced parentheses")
+}
+
+// skipUntil consumes tokens and whole groups until stop matches a token at
+// the top level or the statement ends
+func (p *parser) skipUntil(stop func(token) bool) error {
+	for !p.done() {
+		t := p.peek()
+		switch {
+		case stop != nil && stop(t):
+			return nil
+		case t.is("(") || t.is("["):
+			if _, _, err := p.balanced(); err != nil {
+				return err
+			}
+		case t.is(")") || t.is("]"):
+			return p.fail("unexpected %s", t.describe())
+		default:
+			p.pos++
+		}
+	}
+	return nil
+}
+
+// identList reads a parenthesized list of names
+func (p *parser) identList(what string) ([]sqlName, error) {
+	from, to, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	sub := p.sub(from, to)
+	var names []sqlName
+	for {
+		n, err := sub.ident(what)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+		if sub.done() {
+			return names, nil
+		}
+		if !sub.accept(",") {
+			return nil, sub.fail("expected , or ), found %s", sub.peek().describe())
+		}
+	}
+}
+
+// sub returns a parser over the tokens [from, to)
+func (p *parser) sub(from, to int) *parser {
+	return &parser{src: p.src, toks: p.toks[from:to]}
+}
+
+// split returns parsers over the top level comma separated parts of the
+// tokens [from, to); empty parts are kept so callers can report them
+func (p *parser) split(from, to int) []*parser {
+	var parts []*parser
+	depth, start := 0, from
+	for i := from; i < to; i++ {
+		switch t := p.toks[i]; {
+		case t.is("(") || t.is("["):
+			depth++
+		case t.is(")") || t.is("]"):
+			depth--
+		case t.is(",") && depth == 0:
+			parts = append(parts, p.sub(start, i))
+			start = i + 1
+		}
+	}
+	return append(parts, p.sub(start, to))
+}
+
+func nameKeys(names []sqlName) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = n.key
+	}
+	return out
+}
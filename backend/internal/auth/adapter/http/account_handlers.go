@@ -0,0 +1,180 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/usecase"
+	"github.com/dark/idea-forge/internal/middleware"
+)
+
+// AccountHandler expone la autogestión de la cuenta del usuario autenticado
+type AccountHandler struct {
+	changePasswordUC *usecase.ChangePasswordUseCase
+	updateProfileUC  *usecase.UpdateProfileUseCase
+	changeEmailUC    *usecase.ChangeEmailUseCase
+	deleteAccountUC  *usecase.DeleteAccountUseCase
+}
+
+func NewAccountHandler(
+	changePasswordUC *usecase.ChangePasswordUseCase,
+	updateProfileUC *usecase.UpdateProfileUseCase,
+	changeEmailUC *usecase.ChangeEmailUseCase,
+	deleteAccountUC *usecase.DeleteAccountUseCase,
+) *AccountHandler {
+	return &AccountHandler{
+		changePasswordUC: changePasswordUC,
+		updateProfileUC:  updateProfileUC,
+		changeEmailUC:    changeEmailUC,
+		deleteAccountUC:  deleteAccountUC,
+	}
+}
+
+// ChangePassword cambia la contraseña verificando la actual (o un login reciente si no tiene)
+func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var input usecase.ChangePasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	input.AuthenticatedAt, _ = middleware.GetAuthenticatedAtFromContext(r.Context())
+
+	if err := h.changePasswordUC.Execute(r.Context(), userID, input); err != nil {
+		respondError(w, accountErrorStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Contraseña actualizada exitosamente",
+	})
+}
+
+// UpdateProfile actualiza los datos de perfil (nombre de usuario e idioma de los emails)
+func (h *AccountHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var input usecase.UpdateProfileInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	user, err := h.updateProfileUC.Execute(r.Context(), userID, input)
+	if err != nil {
+		respondError(w, accountErrorStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+// RequestEmailChange envía un código de confirmación al nuevo email
+func (h *AccountHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var input usecase.RequestEmailChangeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	input.AuthenticatedAt, _ = middleware.GetAuthenticatedAtFromContext(r.Context())
+
+	if err := h.changeEmailUC.Request(r.Context(), userID, input); err != nil {
+		respondError(w, accountErrorStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Te enviamos un código de confirmación al nuevo email",
+	})
+}
+
+// ConfirmEmailChange aplica el cambio de email con el código recibido
+func (h *AccountHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var input usecase.ConfirmEmailChangeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	user, err := h.changeEmailUC.Confirm(r.Context(), userID, input)
+	if err != nil {
+		respondError(w, accountErrorStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+// DeleteAccount elimina la cuenta aplicando la política elegida sobre las ideas
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	var input usecase.DeleteAccountInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	input.AuthenticatedAt, _ = middleware.GetAuthenticatedAtFromContext(r.Context())
+
+	if err := h.deleteAccountUC.Execute(r.Context(), userID, input); err != nil {
+		respondError(w, accountErrorStatus(err), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// accountErrorStatus traduce errores de dominio a códigos HTTP
+func accountErrorStatus(err error) int {
+	// Lleva los nombres de los workspaces afectados
+	if errors.Is(err, domain.ErrSoleWorkspaceOwner) {
+		return http.StatusConflict
+	}
+
+	switch err {
+	case domain.ErrUserNotFound:
+		return http.StatusNotFound
+	case domain.ErrInvalidCurrentPassword, domain.ErrRecentLoginRequired:
+		return http.StatusForbidden
+	case domain.ErrEmailAlreadyExists, domain.ErrUsernameAlreadyExists:
+		return http.StatusConflict
+	case domain.ErrInvalidVerificationCode:
+		return http.StatusNotFound
+	case domain.ErrExpiredVerificationCode, domain.ErrCodeAlreadyUsed:
+		return http.StatusGone
+	case domain.ErrTooManyCodeAttempts:
+		return http.StatusTooManyRequests
+	case domain.ErrInvalidEmail, domain.ErrWeakPassword, domain.ErrPasswordMismatch,
+		domain.ErrInvalidUsername, domain.ErrInvalidLocale, domain.ErrSameEmail, domain.ErrInvalidDeletionPolicy:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
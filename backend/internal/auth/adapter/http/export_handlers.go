@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/usecase"
+	"github.com/dark/idea-forge/internal/middleware"
+)
+
+// ExportHandler expone la exportación de datos personales
+type ExportHandler struct {
+	exportDataUC *usecase.ExportDataUseCase
+}
+
+func NewExportHandler(exportDataUC *usecase.ExportDataUseCase) *ExportHandler {
+	return &ExportHandler{exportDataUC: exportDataUC}
+}
+
+// RequestExport genera la exportación en background y envía el link por email
+func (h *ExportHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Usuario no autenticado")
+		return
+	}
+
+	go func() {
+		bgCtx, bgCancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer bgCancel()
+
+		if err := h.exportDataUC.Execute(bgCtx, userID); err != nil {
+			log.Printf("error exporting data for user %s: %v", userID, err)
+		}
+	}()
+
+	respondJSON(w, http.StatusAccepted, map[string]string{
+		"message": "Estamos preparando tus datos. Recibirás un email con el link de descarga.",
+	})
+}
+
+// DownloadExport entrega el archivo zip si el token es válido, no expiró y no se usó
+func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+
+	export, err := h.exportDataUC.Download(r.Context(), token)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == domain.ErrInvalidExportToken {
+			statusCode = http.StatusNotFound
+		} else if err == domain.ErrExpiredExportToken || err == domain.ErrTokenAlreadyUsed {
+			statusCode = http.StatusGone
+		}
+		respondError(w, statusCode, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="idea-forge-export-`+export.CreatedAt.Format("20060102")+`.zip"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Archive)
+}
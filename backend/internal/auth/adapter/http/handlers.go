@@ -55,7 +55,8 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		if err == domain.ErrEmailAlreadyExists || err == domain.ErrUsernameAlreadyExists {
 			statusCode = http.StatusConflict
 		} else if err == domain.ErrInvalidEmail || err == domain.ErrWeakPassword ||
-			err == domain.ErrPasswordMismatch || err == domain.ErrInvalidUsername {
+			err == domain.ErrPasswordMismatch || err == domain.ErrInvalidUsername ||
+			err == domain.ErrInvalidLocale {
 			statusCode = http.StatusBadRequest
 		}
 		respondError(w, statusCode, err.Error())
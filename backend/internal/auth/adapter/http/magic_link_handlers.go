@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/usecase"
+)
+
+// MagicLinkHandler expone el inicio de sesión sin contraseña
+type MagicLinkHandler struct {
+	magicLinkUC *usecase.MagicLinkUseCase
+}
+
+func NewMagicLinkHandler(magicLinkUC *usecase.MagicLinkUseCase) *MagicLinkHandler {
+	return &MagicLinkHandler{magicLinkUC: magicLinkUC}
+}
+
+// RequestMagicLink envía un link de acceso por email
+func (h *MagicLinkHandler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var input usecase.RequestMagicLinkInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	if err := h.magicLinkUC.Request(r.Context(), input); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Si el email existe, recibirás un link para iniciar sesión",
+	})
+}
+
+// ConsumeMagicLink canjea el link por un token de sesión
+func (h *MagicLinkHandler) ConsumeMagicLink(w http.ResponseWriter, r *http.Request) {
+	var input usecase.ConsumeMagicLinkInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	output, err := h.magicLinkUC.Consume(r.Context(), input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err {
+		case domain.ErrInvalidMagicLink:
+			statusCode = http.StatusNotFound
+		case domain.ErrExpiredMagicLink, domain.ErrTokenAlreadyUsed:
+			statusCode = http.StatusGone
+		case domain.ErrUserNotVerified, domain.ErrUserSuspended:
+			statusCode = http.StatusForbidden
+		}
+		respondError(w, statusCode, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, output)
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/usecase"
+)
+
+// oidcStateCookie guarda el hash del state para comprobar en el callback que vuelve el mismo navegador
+const oidcStateCookie = "oidc_state"
+
+// OIDCHandler expone el login con proveedores OpenID Connect
+type OIDCHandler struct {
+	oidcLoginUC *usecase.OIDCLoginUseCase
+}
+
+func NewOIDCHandler(oidcLoginUC *usecase.OIDCLoginUseCase) *OIDCHandler {
+	return &OIDCHandler{oidcLoginUC: oidcLoginUC}
+}
+
+// ListProviders devuelve los proveedores configurados
+func (h *OIDCHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string][]string{
+		"providers": h.oidcLoginUC.Providers(),
+	})
+}
+
+// Authorize inicia el login y devuelve la URL del proveedor
+func (h *OIDCHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	output, err := h.oidcLoginUC.Start(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		if err == domain.ErrOIDCProviderNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("oidc authorize error: %v", err)
+		respondError(w, http.StatusBadGateway, "No se pudo contactar al proveedor de identidad")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    output.Binding,
+		Path:     "/auth/oidc",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	respondJSON(w, http.StatusOK, output)
+}
+
+// Callback canjea el código recibido del proveedor por un token de sesión
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	var input usecase.OIDCCallbackInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	if cookie, err := r.Cookie(oidcStateCookie); err == nil {
+		input.Binding = cookie.Value
+	}
+	// La cookie sirve para un solo intento
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     "/auth/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	output, err := h.oidcLoginUC.Callback(r.Context(), input)
+	if err != nil {
+		statusCode := http.StatusBadGateway
+		message := "No se pudo completar el login con el proveedor de identidad"
+		switch {
+		case err == domain.ErrInvalidOIDCState, err == domain.ErrOIDCProviderNotFound:
+			statusCode, message = http.StatusBadRequest, err.Error()
+		case err == domain.ErrExpiredOIDCState:
+			statusCode, message = http.StatusGone, err.Error()
+		case err == domain.ErrOIDCEmailNotVerified:
+			statusCode, message = http.StatusUnauthorized, err.Error()
+		case errors.Is(err, domain.ErrInvalidIDToken):
+			statusCode, message = http.StatusUnauthorized, domain.ErrInvalidIDToken.Error()
+		case err == domain.ErrUserSuspended:
+			statusCode, message = http.StatusForbidden, err.Error()
+		}
+		log.Printf("oidc callback error: %v", err)
+		respondError(w, statusCode, message)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, output)
+}
+
+// isHTTPS indica si el navegador llegó por HTTPS (directo o detrás de un proxy)
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,45 @@
+package oidc
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ProvidersFromEnv lee los proveedores configurados por variables de entorno:
+//
+//	OIDC_PROVIDERS=corp,google
+//	OIDC_CORP_ISSUER, OIDC_CORP_CLIENT_ID, OIDC_CORP_CLIENT_SECRET,
+//	OIDC_CORP_REDIRECT_URL (por defecto <frontendURL>/auth/oidc/callback),
+//	OIDC_CORP_SCOPES (por defecto "openid email profile")
+//
+// El emisor puede ser http:// para apuntar a un servidor OIDC local de pruebas.
+func ProvidersFromEnv(frontendURL string, httpClient *http.Client) ([]*Provider, error) {
+	var providers []*Provider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		config := Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			return nil, fmt.Errorf("oidc provider %q: %sISSUER and %sCLIENT_ID are required", name, prefix, prefix)
+		}
+		if config.RedirectURL == "" {
+			config.RedirectURL = frontendURL + "/auth/oidc/callback"
+		}
+
+		providers = append(providers, NewProvider(config, httpClient))
+	}
+	return providers, nil
+}
@@ -0,0 +1,236 @@
+// Package oidctest levanta un proveedor OpenID Connect local para los tests:
+// discovery, endpoint de token con PKCE S256 y JWKS con rotación de claves.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Server es un IdP de pruebas. Cada código de autorización recuerda el
+// code_challenge y el nonce con los que se pidió y sólo se puede canjear una vez.
+type Server struct {
+	*httptest.Server
+
+	ClientID string
+	Subject  string
+	Email    string
+
+	// Claims, si no es nil, modifica los claims del ID token antes de firmarlo
+	Claims func(claims jwt.MapClaims)
+	// Forge firma los ID tokens con una clave que no está en el JWKS, manteniendo el kid publicado
+	Forge bool
+
+	t          *testing.T
+	mu         sync.Mutex
+	keys       map[string]*rsa.PrivateKey
+	signingKid string
+	codes      map[string]authRequest
+	jwksHits   int
+	nextCode   int
+	nextKid    int
+}
+
+type authRequest struct {
+	challenge string
+	nonce     string
+}
+
+// NewServer arranca el IdP con una clave de firma publicada en el JWKS
+func NewServer(t *testing.T, clientID string) *Server {
+	t.Helper()
+
+	s := &Server{
+		ClientID: clientID,
+		Subject:  "subject-1",
+		Email:    "ada@example.com",
+		t:        t,
+		keys:     map[string]*rsa.PrivateKey{},
+		codes:    map[string]authRequest{},
+	}
+	s.RotateKey(false)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/jwks", s.handleJWKS)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Issuer devuelve el emisor que anuncia el discovery
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// RotateKey genera una clave nueva y firma con ella a partir de ahora.
+// Con keepOld=false las claves anteriores dejan de publicarse en el JWKS.
+func (s *Server) RotateKey(keepOld bool) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		s.t.Fatalf("generating rsa key: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !keepOld {
+		s.keys = map[string]*rsa.PrivateKey{}
+	}
+	s.nextKid++
+	s.signingKid = "key-" + strconv.Itoa(s.nextKid)
+	s.keys[s.signingKid] = key
+}
+
+// JWKSHits cuenta cuántas veces se pidió el JWKS
+func (s *Server) JWKSHits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jwksHits
+}
+
+// Authorize simula que el usuario aprueba la URL de autorización y devuelve
+// el código que el IdP enviaría al redirect_uri
+func (s *Server) Authorize(authURL string) string {
+	s.t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		s.t.Fatalf("parsing auth url: %v", err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != s.ClientID || q.Get("response_type") != "code" {
+		s.t.Fatalf("unexpected authorization request: %s", authURL)
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		s.t.Fatalf("authorization request without PKCE S256: %s", authURL)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextCode++
+	code := "code-" + strconv.Itoa(s.nextCode)
+	s.codes[code] = authRequest{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	return code
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("client_id") != s.ClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	s.mu.Lock()
+	req, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	// RFC 7636 §4.6: BASE64URL(SHA256(code_verifier)) debe coincidir con el challenge
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != req.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := s.signIDToken(req.nonce)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (s *Server) signIDToken(nonce string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.URL,
+		"aud":            s.ClientID,
+		"sub":            s.Subject,
+		"email":          s.Email,
+		"email_verified": true,
+		"name":           "Ada Lovelace",
+		"nonce":          nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+	if s.Claims != nil {
+		s.Claims(claims)
+	}
+
+	s.mu.Lock()
+	kid := s.signingKid
+	key := s.keys[kid]
+	s.mu.Unlock()
+
+	if s.Forge {
+		forged, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return "", err
+		}
+		key = forged
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jwksHits++
+
+	keys := make([]map[string]string, 0, len(s.keys))
+	for kid, key := range s.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"keys": keys})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,209 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/dark/idea-forge/internal/auth/adapter/oidc"
+	"github.com/dark/idea-forge/internal/auth/adapter/oidc/oidctest"
+	"github.com/dark/idea-forge/internal/auth/domain"
+)
+
+const (
+	clientID = "idea-forge"
+	verifier = "verifier-0123456789-0123456789-0123456789-abcdef"
+)
+
+func newProvider(idp *oidctest.Server) *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		Name:        "corp",
+		Issuer:      idp.Issuer(),
+		ClientID:    clientID,
+		RedirectURL: "http://localhost:3000/auth/oidc/callback",
+	}, idp.Client())
+}
+
+func challenge(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// login recorre el flujo completo: URL de autorización, aprobación y canje
+func login(t *testing.T, idp *oidctest.Server, p *oidc.Provider, verifierSent, nonceSent string) (*domain.ExternalIdentity, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", challenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code := idp.Authorize(authURL)
+	return p.Exchange(ctx, code, verifierSent, nonceSent)
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	idp := oidctest.NewServer(t, clientID)
+	p := newProvider(idp)
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state-1", "nonce-1", challenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", authURL, err)
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             clientID,
+		"redirect_uri":          "http://localhost:3000/auth/oidc/callback",
+		"scope":                 "openid email profile",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        challenge(verifier),
+		"code_challenge_method": "S256",
+	}
+	for param, value := range want {
+		if got := u.Query().Get(param); got != value {
+			t.Errorf("%s = %q, want %q", param, got, value)
+		}
+	}
+	if u.Scheme+"://"+u.Host+u.Path != idp.Issuer()+"/authorize" {
+		t.Errorf("authorization endpoint = %q", authURL)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	idp := oidctest.NewServer(t, clientID)
+
+	identity, err := login(t, idp, newProvider(idp), verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Provider != "corp" || identity.Subject != idp.Subject {
+		t.Errorf("identity = %s/%s, want corp/%s", identity.Provider, identity.Subject, idp.Subject)
+	}
+	if identity.Email != idp.Email || !identity.EmailVerified {
+		t.Errorf("email = %q verified=%v, want %q verified", identity.Email, identity.EmailVerified, idp.Email)
+	}
+}
+
+func TestExchangeRejectsWrongCodeVerifier(t *testing.T) {
+	idp := oidctest.NewServer(t, clientID)
+
+	_, err := login(t, idp, newProvider(idp), "another-verifier-0123456789-0123456789-0123", "nonce-1")
+	if err == nil {
+		t.Fatal("Exchange succeeded with a code_verifier that does not match the challenge")
+	}
+}
+
+func TestExchangeRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims func(jwt.MapClaims)
+		nonce  string
+	}{
+		{
+			name:   "wrong audience",
+			claims: func(c jwt.MapClaims) { c["aud"] = "another-client" },
+		},
+		{
+			name:   "wrong issuer",
+			claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		},
+		{
+			name:  "wrong nonce",
+			nonce: "nonce-from-another-login",
+		},
+		{
+			name: "expired",
+			claims: func(c jwt.MapClaims) {
+				c["iat"] = time.Now().Add(-time.Hour).Unix()
+				c["exp"] = time.Now().Add(-10 * time.Minute).Unix()
+			},
+		},
+		{
+			name:   "without expiration",
+			claims: func(c jwt.MapClaims) { delete(c, "exp") },
+		},
+		{
+			name:   "issued in the future",
+			claims: func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() },
+		},
+		{
+			name:   "without subject",
+			claims: func(c jwt.MapClaims) { delete(c, "sub") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := oidctest.NewServer(t, clientID)
+			idp.Claims = tt.claims
+			nonce := tt.nonce
+			if nonce == "" {
+				nonce = "nonce-1"
+			}
+
+			_, err := login(t, idp, newProvider(idp), verifier, nonce)
+			if !errors.Is(err, domain.ErrInvalidIDToken) {
+				t.Fatalf("err = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestExchangeFollowsKeyRotation(t *testing.T) {
+	idp := oidctest.NewServer(t, clientID)
+	p := newProvider(idp)
+
+	if _, err := login(t, idp, p, verifier, "nonce-1"); err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+	if _, err := login(t, idp, p, verifier, "nonce-1"); err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if hits := idp.JWKSHits(); hits != 1 {
+		t.Fatalf("jwks fetched %d times, want 1 while the key is cached", hits)
+	}
+
+	// El IdP rota la clave y retira la anterior: un kid desconocido recarga el JWKS
+	idp.RotateKey(false)
+	if _, err := login(t, idp, p, verifier, "nonce-1"); err != nil {
+		t.Fatalf("login after rotation: %v", err)
+	}
+	if hits := idp.JWKSHits(); hits != 2 {
+		t.Fatalf("jwks fetched %d times, want 2 after rotation", hits)
+	}
+}
+
+func TestExchangeRejectsForgedSignature(t *testing.T) {
+	idp := oidctest.NewServer(t, clientID)
+	idp.Forge = true
+
+	_, err := login(t, idp, newProvider(idp), verifier, "nonce-1")
+	if !errors.Is(err, domain.ErrInvalidIDToken) {
+		t.Fatalf("err = %v, want ErrInvalidIDToken", err)
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	idp := oidctest.NewServer(t, clientID)
+	p := oidc.NewProvider(oidc.Config{
+		Name:     "corp",
+		Issuer:   idp.Issuer() + "/tenant",
+		ClientID: clientID,
+	}, idp.Client())
+
+	if _, err := p.AuthCodeURL(context.Background(), "state", "nonce", challenge(verifier)); err == nil {
+		t.Fatal("AuthCodeURL succeeded against a discovery document for another issuer")
+	}
+}
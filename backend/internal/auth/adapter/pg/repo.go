@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/auth/domain"
@@ -20,15 +22,23 @@ func NewUserRepository(db *sql.DB) *userRepository {
 // CreateUser crea un nuevo usuario
 func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, username, email, password_hash, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, username, email, password_hash, status, role, locale, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
+	if user.Role == "" {
+		user.Role = domain.RoleUser
+	}
+	if user.Locale == "" {
+		user.Locale = domain.DefaultLocale
+	}
 	_, err := r.db.ExecContext(ctx, query,
 		user.ID,
 		user.Username,
 		user.Email,
 		user.PasswordHash,
 		user.Status,
+		user.Role,
+		user.Locale,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -48,7 +58,7 @@ func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) erro
 // GetUserByID obtiene un usuario por ID
 func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, status, created_at, updated_at
+		SELECT id, username, email, password_hash, status, role, locale, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -59,6 +69,8 @@ func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain
 		&user.Email,
 		&user.PasswordHash,
 		&user.Status,
+		&user.Role,
+		&user.Locale,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -74,7 +86,7 @@ func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domain
 // GetUserByEmail obtiene un usuario por email
 func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, status, created_at, updated_at
+		SELECT id, username, email, password_hash, status, role, locale, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -85,6 +97,8 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*dom
 		&user.Email,
 		&user.PasswordHash,
 		&user.Status,
+		&user.Role,
+		&user.Locale,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -100,7 +114,7 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*dom
 // GetUserByUsername obtiene un usuario por nombre de usuario
 func (r *userRepository) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, status, created_at, updated_at
+		SELECT id, username, email, password_hash, status, role, locale, created_at, updated_at
 		FROM users
 		WHERE username = $1
 	`
@@ -111,6 +125,8 @@ func (r *userRepository) GetUserByUsername(ctx context.Context, username string)
 		&user.Email,
 		&user.PasswordHash,
 		&user.Status,
+		&user.Role,
+		&user.Locale,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -127,7 +143,7 @@ func (r *userRepository) GetUserByUsername(ctx context.Context, username string)
 func (r *userRepository) UpdateUser(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
-		SET username = $2, email = $3, password_hash = $4, status = $5, updated_at = $6
+		SET username = $2, email = $3, password_hash = $4, status = $5, locale = $6, updated_at = $7
 		WHERE id = $1
 	`
 	result, err := r.db.ExecContext(ctx, query,
@@ -136,6 +152,7 @@ func (r *userRepository) UpdateUser(ctx context.Context, user *domain.User) erro
 		user.Email,
 		user.PasswordHash,
 		user.Status,
+		user.Locale,
 		user.UpdatedAt,
 	)
 	if err != nil {
@@ -153,6 +170,127 @@ func (r *userRepository) UpdateUser(ctx context.Context, user *domain.User) erro
 	return nil
 }
 
+// DeleteUser elimina un usuario aplicando la política indicada sobre sus ideas
+func (r *userRepository) DeleteUser(ctx context.Context, id uuid.UUID, policy domain.DeletionPolicy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := transferSoleOwnerships(ctx, tx, id); err != nil {
+		return err
+	}
+
+	var statements []string
+	switch policy {
+	case domain.DeletionPolicyCascade:
+		// Las FK en cascada eliminan planes, arquitecturas, módulos y chats derivados.
+		// Las ideas de workspaces compartidos pertenecen al equipo: se conservan sin autor.
+		// Los eventos de las ideas eliminadas se borran con ellas porque copian sus chats.
+		statements = []string{
+			`DELETE FROM outbox_events WHERE idea_id IN (SELECT id FROM ideation_ideas WHERE user_id = $1 AND workspace_id IN (SELECT id FROM workspaces WHERE personal AND created_by = $1))`,
+			`DELETE FROM ideation_ideas WHERE user_id = $1 AND workspace_id IN (SELECT id FROM workspaces WHERE personal AND created_by = $1)`,
+			`UPDATE ideation_ideas SET user_id = NULL WHERE user_id = $1`,
+			`UPDATE action_plans SET user_id = NULL WHERE user_id = $1`,
+			`UPDATE architectures SET user_id = NULL WHERE user_id = $1`,
+			`DELETE FROM workspaces WHERE personal AND created_by = $1`,
+		}
+	case domain.DeletionPolicyAnonymize:
+		statements = []string{
+			`UPDATE ideation_ideas SET user_id = NULL WHERE user_id = $1`,
+			`UPDATE action_plans SET user_id = NULL WHERE user_id = $1`,
+			`UPDATE architectures SET user_id = NULL WHERE user_id = $1`,
+		}
+	default:
+		return domain.ErrInvalidDeletionPolicy
+	}
+	// Los eventos y el historial de actividad que quedan no vuelven a nombrar al usuario
+	statements = append(statements,
+		`UPDATE outbox_events SET actor_id = NULL WHERE actor_id = $1`,
+		`UPDATE project_activity SET actor_id = NULL WHERE actor_id = $1`,
+	)
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt, id); err != nil {
+			return err
+		}
+	}
+
+	// Códigos, tokens y solicitudes pendientes se eliminan en cascada
+	result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return tx.Commit()
+}
+
+// transferSoleOwnerships pasa los workspaces compartidos en los que el usuario es el
+// único propietario al editor más antiguo. Si alguno no tiene editores la baja se
+// bloquea con ErrSoleWorkspaceOwner, nombrando esos workspaces.
+func transferSoleOwnerships(ctx context.Context, tx *sql.Tx, userID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE workspace_members m
+		   SET role = 'owner'
+		  FROM (
+			SELECT DISTINCT ON (e.workspace_id) e.workspace_id, e.user_id
+			  FROM workspace_members o
+			  JOIN workspaces w ON w.id = o.workspace_id AND NOT w.personal
+			  JOIN workspace_members e ON e.workspace_id = o.workspace_id AND e.role = 'editor'
+			 WHERE o.user_id = $1 AND o.role = 'owner'
+			   AND NOT EXISTS (
+				SELECT 1 FROM workspace_members x
+				 WHERE x.workspace_id = o.workspace_id AND x.role = 'owner' AND x.user_id <> $1
+			   )
+			 ORDER BY e.workspace_id, e.created_at, e.user_id
+		  ) heir
+		 WHERE m.workspace_id = heir.workspace_id AND m.user_id = heir.user_id
+	`, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT w.name
+		  FROM workspace_members o
+		  JOIN workspaces w ON w.id = o.workspace_id AND NOT w.personal
+		 WHERE o.user_id = $1 AND o.role = 'owner'
+		   AND NOT EXISTS (
+			SELECT 1 FROM workspace_members x
+			 WHERE x.workspace_id = o.workspace_id AND x.role = 'owner' AND x.user_id <> $1
+		   )
+		 ORDER BY w.name
+	`, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrSoleWorkspaceOwner, strings.Join(names, ", "))
+	}
+	return nil
+}
+
 // CreateVerificationCode crea un código de verificación
 func (r *userRepository) CreateVerificationCode(ctx context.Context, code *domain.EmailVerificationCode) error {
 	query := `
@@ -252,3 +390,297 @@ func (r *userRepository) MarkTokenAsUsed(ctx context.Context, tokenID uuid.UUID)
 	_, err := r.db.ExecContext(ctx, query, tokenID)
 	return err
 }
+
+// CreateMagicLinkToken crea un token de inicio de sesión sin contraseña
+func (r *userRepository) CreateMagicLinkToken(ctx context.Context, token *domain.MagicLinkToken) error {
+	query := `
+		INSERT INTO magic_link_tokens (id, user_id, token, expires_at, used, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID,
+		token.UserID,
+		token.Token,
+		token.ExpiresAt,
+		token.Used,
+		token.CreatedAt,
+	)
+	return err
+}
+
+// GetMagicLinkToken obtiene un token de magic link
+func (r *userRepository) GetMagicLinkToken(ctx context.Context, token string) (*domain.MagicLinkToken, error) {
+	query := `
+		SELECT id, user_id, token, expires_at, used, created_at
+		FROM magic_link_tokens
+		WHERE token = $1
+	`
+	magicToken := &domain.MagicLinkToken{}
+	err := r.db.QueryRowContext(ctx, query, token).Scan(
+		&magicToken.ID,
+		&magicToken.UserID,
+		&magicToken.Token,
+		&magicToken.ExpiresAt,
+		&magicToken.Used,
+		&magicToken.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidMagicLink
+		}
+		return nil, err
+	}
+	return magicToken, nil
+}
+
+// MarkMagicLinkTokenAsUsed marca un magic link como usado.
+// Solo un consumo concurrente puede ganar; el resto recibe ErrTokenAlreadyUsed.
+func (r *userRepository) MarkMagicLinkTokenAsUsed(ctx context.Context, tokenID uuid.UUID) error {
+	query := `UPDATE magic_link_tokens SET used = TRUE WHERE id = $1 AND used = FALSE`
+	result, err := r.db.ExecContext(ctx, query, tokenID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrTokenAlreadyUsed
+	}
+	return nil
+}
+
+// CreateOIDCState guarda un login OIDC en curso
+func (r *userRepository) CreateOIDCState(ctx context.Context, state *domain.OIDCAuthState) error {
+	query := `
+		INSERT INTO oidc_auth_states (id, state, provider, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		state.ID,
+		state.State,
+		state.Provider,
+		state.Nonce,
+		state.CodeVerifier,
+		state.ExpiresAt,
+		state.CreatedAt,
+	)
+	return err
+}
+
+// ConsumeOIDCState obtiene y elimina un login OIDC en curso (cada state se usa una sola vez)
+func (r *userRepository) ConsumeOIDCState(ctx context.Context, state string) (*domain.OIDCAuthState, error) {
+	query := `
+		DELETE FROM oidc_auth_states
+		WHERE state = $1
+		RETURNING id, state, provider, nonce, code_verifier, expires_at, created_at
+	`
+	authState := &domain.OIDCAuthState{}
+	err := r.db.QueryRowContext(ctx, query, state).Scan(
+		&authState.ID,
+		&authState.State,
+		&authState.Provider,
+		&authState.Nonce,
+		&authState.CodeVerifier,
+		&authState.ExpiresAt,
+		&authState.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+	return authState, nil
+}
+
+// GetUserIdentity obtiene la identidad externa vinculada a un usuario
+func (r *userRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+	identity := &domain.UserIdentity{}
+	err := r.db.QueryRowContext(ctx, query, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+// CreateUserIdentity vincula una identidad externa a un usuario
+func (r *userRepository) CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+	)
+	return err
+}
+
+// CreateEmailChangeRequest crea una solicitud de cambio de email
+func (r *userRepository) CreateEmailChangeRequest(ctx context.Context, req *domain.EmailChangeRequest) error {
+	query := `
+		INSERT INTO email_change_requests (id, user_id, new_email, code, expires_at, used, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		req.ID,
+		req.UserID,
+		req.NewEmail,
+		req.Code,
+		req.ExpiresAt,
+		req.Used,
+		req.CreatedAt,
+	)
+	return err
+}
+
+// GetLatestEmailChangeRequest obtiene la solicitud de cambio de email más reciente del usuario
+func (r *userRepository) GetLatestEmailChangeRequest(ctx context.Context, userID uuid.UUID) (*domain.EmailChangeRequest, error) {
+	query := `
+		SELECT id, user_id, new_email, code, expires_at, used, attempts, created_at
+		FROM email_change_requests
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	req := &domain.EmailChangeRequest{}
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&req.ID,
+		&req.UserID,
+		&req.NewEmail,
+		&req.Code,
+		&req.ExpiresAt,
+		&req.Used,
+		&req.Attempts,
+		&req.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidVerificationCode
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+// RecordEmailChangeAttempt suma un intento de confirmación en una sola sentencia,
+// así las peticiones concurrentes no pueden probar más códigos que el límite
+func (r *userRepository) RecordEmailChangeAttempt(ctx context.Context, requestID uuid.UUID) (int, error) {
+	query := `UPDATE email_change_requests SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts`
+	var attempts int
+	err := r.db.QueryRowContext(ctx, query, requestID).Scan(&attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Otra petición la borró al agotar los intentos
+		return 0, domain.ErrTooManyCodeAttempts
+	}
+	return attempts, err
+}
+
+// DeleteEmailChangeRequest borra una solicitud de cambio de email
+func (r *userRepository) DeleteEmailChangeRequest(ctx context.Context, requestID uuid.UUID) error {
+	query := `DELETE FROM email_change_requests WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, requestID)
+	return err
+}
+
+// MarkEmailChangeRequestAsUsed marca una solicitud de cambio de email como usada
+func (r *userRepository) MarkEmailChangeRequestAsUsed(ctx context.Context, requestID uuid.UUID) error {
+	query := `UPDATE email_change_requests SET used = TRUE WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, requestID)
+	return err
+}
+
+// CreateDataExport guarda un archivo de exportación de datos
+func (r *userRepository) CreateDataExport(ctx context.Context, export *domain.DataExport) error {
+	query := `
+		INSERT INTO data_exports (id, user_id, token, archive, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		export.ID,
+		export.UserID,
+		export.Token,
+		export.Archive,
+		export.ExpiresAt,
+		export.CreatedAt,
+	)
+	return err
+}
+
+// GetDataExport obtiene una exportación por su token de descarga
+func (r *userRepository) GetDataExport(ctx context.Context, token string) (*domain.DataExport, error) {
+	query := `
+		SELECT id, user_id, token, archive, expires_at, downloaded_at, created_at
+		FROM data_exports
+		WHERE token = $1
+	`
+	export := &domain.DataExport{}
+	var downloadedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, token).Scan(
+		&export.ID,
+		&export.UserID,
+		&export.Token,
+		&export.Archive,
+		&export.ExpiresAt,
+		&downloadedAt,
+		&export.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidExportToken
+		}
+		return nil, err
+	}
+	if downloadedAt.Valid {
+		export.DownloadedAt = &downloadedAt.Time
+	}
+	return export, nil
+}
+
+// MarkDataExportDownloaded marca la exportación como descargada y borra el archivo.
+// Solo una descarga concurrente puede ganar; el resto recibe ErrTokenAlreadyUsed.
+func (r *userRepository) MarkDataExportDownloaded(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE data_exports SET downloaded_at = NOW(), archive = NULL WHERE id = $1 AND downloaded_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrTokenAlreadyUsed
+	}
+	return nil
+}
+
+// PurgeExpiredDataExports elimina las exportaciones vencidas con sus archivos
+func (r *userRepository) PurgeExpiredDataExports(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM data_exports WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
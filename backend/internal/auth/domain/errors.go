@@ -17,14 +17,38 @@ var (
 	ErrWeakPassword           = errors.New("contraseña débil")
 	ErrPasswordMismatch       = errors.New("las contraseñas no coinciden")
 	ErrInvalidUsername        = errors.New("nombre de usuario inválido")
+	ErrInvalidLocale          = errors.New("idioma no soportado")
 
 	// Errores de verificación
 	ErrInvalidVerificationCode = errors.New("código de verificación inválido")
 	ErrExpiredVerificationCode = errors.New("código de verificación expirado")
 	ErrCodeAlreadyUsed         = errors.New("código ya utilizado")
+	ErrTooManyCodeAttempts     = errors.New("demasiados intentos fallidos; solicita un código nuevo")
 
 	// Errores de reset de contraseña
 	ErrInvalidResetToken      = errors.New("token de reset inválido")
 	ErrExpiredResetToken      = errors.New("token de reset expirado")
 	ErrTokenAlreadyUsed       = errors.New("token ya utilizado")
+
+	// Errores de magic link
+	ErrInvalidMagicLink       = errors.New("magic link inválido")
+	ErrExpiredMagicLink       = errors.New("magic link expirado")
+
+	// Errores de login OIDC
+	ErrOIDCProviderNotFound   = errors.New("proveedor de identidad no configurado")
+	ErrInvalidOIDCState       = errors.New("estado de login inválido")
+	ErrExpiredOIDCState       = errors.New("el login expiró, intenta nuevamente")
+	ErrInvalidIDToken         = errors.New("token de identidad inválido")
+	ErrOIDCEmailNotVerified   = errors.New("el proveedor no verificó el email")
+
+	// Errores de gestión de cuenta
+	ErrInvalidCurrentPassword = errors.New("la contraseña actual es incorrecta")
+	ErrRecentLoginRequired    = errors.New("vuelve a iniciar sesión para confirmar esta acción")
+	ErrSameEmail              = errors.New("el nuevo email es igual al actual")
+	ErrInvalidDeletionPolicy  = errors.New("política de eliminación inválida")
+	ErrSoleWorkspaceOwner     = errors.New("eres el único propietario de workspaces compartidos sin editores que puedan heredarlos; transfiere la propiedad o elimínalos antes de borrar tu cuenta")
+
+	// Errores de exportación de datos
+	ErrInvalidExportToken     = errors.New("link de descarga inválido")
+	ErrExpiredExportToken     = errors.New("link de descarga expirado")
 )
@@ -15,6 +15,44 @@ const (
 	UserStatusSuspended           UserStatus = "suspended"
 )
 
+// UserRole representa el rol de un usuario
+type UserRole string
+
+const (
+	RoleUser  UserRole = "user"
+	RoleAdmin UserRole = "admin"
+)
+
+// DeletionPolicy define qué pasa con las ideas de un usuario al eliminar su cuenta
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyCascade elimina las ideas y todo lo derivado (plan, arquitectura, módulos, chats)
+	DeletionPolicyCascade DeletionPolicy = "cascade"
+	// DeletionPolicyAnonymize conserva las ideas pero sin propietario
+	DeletionPolicyAnonymize DeletionPolicy = "anonymize"
+)
+
+// IsValid indica si la política es una de las soportadas
+func (p DeletionPolicy) IsValid() bool {
+	return p == DeletionPolicyCascade || p == DeletionPolicyAnonymize
+}
+
+// Idiomas de los emails (deben existir en internal/mail/templates)
+const DefaultLocale = "es"
+
+var Locales = []string{"es", "en"}
+
+// IsValidLocale indica si hay emails en ese idioma
+func IsValidLocale(locale string) bool {
+	for _, l := range Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
 // User representa un usuario del sistema
 type User struct {
 	ID           uuid.UUID  `json:"id"`
@@ -22,6 +60,8 @@ type User struct {
 	Email        string     `json:"email"`
 	PasswordHash string     `json:"-"` // No exponer en JSON
 	Status       UserStatus `json:"status"`
+	Role         UserRole   `json:"role"`
+	Locale       string     `json:"locale"` // idioma de los emails
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
 }
@@ -46,6 +86,76 @@ type PasswordResetToken struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// MagicLinkToken representa un token de inicio de sesión sin contraseña
+type MagicLinkToken struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Used      bool      `json:"used"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExternalIdentity son los datos verificados que entrega un proveedor OIDC tras el login
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// UserIdentity vincula un usuario con su cuenta en un proveedor OIDC
+type UserIdentity struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OIDCAuthState guarda los secretos de un login OIDC en curso (state, nonce y verificador PKCE)
+type OIDCAuthState struct {
+	ID           uuid.UUID `json:"id"`
+	State        string    `json:"state"`
+	Provider     string    `json:"provider"`
+	Nonce        string    `json:"-"`
+	CodeVerifier string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// EmailChangeRequest representa una solicitud de cambio de email pendiente de verificación
+type EmailChangeRequest struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	NewEmail  string    `json:"new_email"`
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Used      bool      `json:"used"`
+	Attempts  int       `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DataExport representa un archivo con todos los datos del usuario listo para descargar.
+// El link sirve una sola vez: al descargarlo se marca DownloadedAt y se borra el archivo.
+type DataExport struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	Token        string     `json:"-"`
+	Archive      []byte     `json:"-"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	DownloadedAt *time.Time `json:"downloaded_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// ExportFile es un archivo dentro de la exportación de datos
+type ExportFile struct {
+	Path    string
+	Content []byte
+}
+
 // IsExpired verifica si el código de verificación está expirado
 func (c *EmailVerificationCode) IsExpired() bool {
 	return time.Now().After(c.ExpiresAt)
@@ -55,3 +165,23 @@ func (c *EmailVerificationCode) IsExpired() bool {
 func (t *PasswordResetToken) IsExpired() bool {
 	return time.Now().After(t.ExpiresAt)
 }
+
+// IsExpired verifica si el magic link está expirado
+func (t *MagicLinkToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsExpired verifica si el login OIDC en curso está expirado
+func (s *OIDCAuthState) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// IsExpired verifica si la solicitud de cambio de email está expirada
+func (r *EmailChangeRequest) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// IsExpired verifica si el link de descarga de la exportación está expirado
+func (e *DataExport) IsExpired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
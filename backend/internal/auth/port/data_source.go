@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/dark/idea-forge/internal/auth/domain"
+)
+
+// UserDataSource provee los datos de proyectos del usuario para la exportación
+type UserDataSource interface {
+	CollectUserFiles(ctx context.Context, userID uuid.UUID) ([]domain.ExportFile, error)
+}
@@ -6,4 +6,9 @@ import "context"
 type EmailService interface {
 	SendVerificationCode(ctx context.Context, email, username, code string) error
 	SendPasswordResetLink(ctx context.Context, email, username, resetLink string) error
+	SendMagicLink(ctx context.Context, email, username, magicLink string) error
+	SendEmailChangeCode(ctx context.Context, newEmail, username, code string) error
+	SendDataExportLink(ctx context.Context, email, username, downloadLink string) error
+	SendWorkspaceInvitation(ctx context.Context, email, inviterName, workspaceName, acceptLink string) error
+	SendCommentMention(ctx context.Context, email, username, authorName, projectTitle, anchor, excerpt, link string) error
 }
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+)
+
+// IdentityProvider define un proveedor OpenID Connect para el login externo
+type IdentityProvider interface {
+	// Name identifica al proveedor en las rutas y en las identidades vinculadas
+	Name() string
+	// AuthCodeURL arma la URL de autorización (authorization code + PKCE S256)
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange canjea el código, valida el ID token contra el JWKS y devuelve la identidad
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalIdentity, error)
+}
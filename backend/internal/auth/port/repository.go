@@ -15,6 +15,7 @@ type UserRepository interface {
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
 	UpdateUser(ctx context.Context, user *domain.User) error
+	DeleteUser(ctx context.Context, id uuid.UUID, policy domain.DeletionPolicy) error
 
 	// Email verification operations
 	CreateVerificationCode(ctx context.Context, code *domain.EmailVerificationCode) error
@@ -25,4 +26,29 @@ type UserRepository interface {
 	CreateResetToken(ctx context.Context, token *domain.PasswordResetToken) error
 	GetResetToken(ctx context.Context, token string) (*domain.PasswordResetToken, error)
 	MarkTokenAsUsed(ctx context.Context, tokenID uuid.UUID) error
+
+	// Magic link operations
+	CreateMagicLinkToken(ctx context.Context, token *domain.MagicLinkToken) error
+	GetMagicLinkToken(ctx context.Context, token string) (*domain.MagicLinkToken, error)
+	MarkMagicLinkTokenAsUsed(ctx context.Context, tokenID uuid.UUID) error
+
+	// OIDC operations
+	CreateOIDCState(ctx context.Context, state *domain.OIDCAuthState) error
+	ConsumeOIDCState(ctx context.Context, state string) (*domain.OIDCAuthState, error)
+	GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) error
+
+	// Email change operations
+	CreateEmailChangeRequest(ctx context.Context, req *domain.EmailChangeRequest) error
+	GetLatestEmailChangeRequest(ctx context.Context, userID uuid.UUID) (*domain.EmailChangeRequest, error)
+	// RecordEmailChangeAttempt suma un intento de confirmación y devuelve los hechos hasta ahora
+	RecordEmailChangeAttempt(ctx context.Context, requestID uuid.UUID) (int, error)
+	DeleteEmailChangeRequest(ctx context.Context, requestID uuid.UUID) error
+	MarkEmailChangeRequestAsUsed(ctx context.Context, requestID uuid.UUID) error
+
+	// Data export operations
+	CreateDataExport(ctx context.Context, export *domain.DataExport) error
+	GetDataExport(ctx context.Context, token string) (*domain.DataExport, error)
+	MarkDataExportDownloaded(ctx context.Context, id uuid.UUID) error
+	PurgeExpiredDataExports(ctx context.Context) (int64, error)
 }
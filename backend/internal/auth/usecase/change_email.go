@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+)
+
+// maxEmailChangeAttempts es cuántos códigos se pueden probar por solicitud;
+// al agotarlos se borra y hay que pedir un código nuevo
+const maxEmailChangeAttempts = 5
+
+type ChangeEmailUseCase struct {
+	repo         port.UserRepository
+	emailService port.EmailService
+}
+
+func NewChangeEmailUseCase(repo port.UserRepository, emailService port.EmailService) *ChangeEmailUseCase {
+	return &ChangeEmailUseCase{
+		repo:         repo,
+		emailService: emailService,
+	}
+}
+
+type RequestEmailChangeInput struct {
+	NewEmail string `json:"new_email"`
+	Password string `json:"password"`
+	// AuthenticatedAt es cuándo inició sesión el usuario; confirma la identidad si no tiene contraseña
+	AuthenticatedAt time.Time `json:"-"`
+}
+
+type ConfirmEmailChangeInput struct {
+	Code string `json:"code"`
+}
+
+// Request valida el nuevo email y envía un código de confirmación a esa dirección
+func (uc *ChangeEmailUseCase) Request(ctx context.Context, userID uuid.UUID, input RequestEmailChangeInput) error {
+	newEmail := strings.TrimSpace(input.NewEmail)
+	if err := validateEmail(newEmail); err != nil {
+		return err
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := confirmIdentity(user, input.Password, input.AuthenticatedAt); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(user.Email, newEmail) {
+		return domain.ErrSameEmail
+	}
+
+	if err := uc.ensureEmailAvailable(ctx, newEmail); err != nil {
+		return err
+	}
+
+	code := generateVerificationCode()
+	req := &domain.EmailChangeRequest{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		Code:      code,
+		ExpiresAt: time.Now().Add(15 * time.Minute),
+		Used:      false,
+		CreatedAt: time.Now(),
+	}
+
+	if err := uc.repo.CreateEmailChangeRequest(ctx, req); err != nil {
+		return err
+	}
+
+	return uc.emailService.SendEmailChangeCode(ctx, newEmail, user.Username, code)
+}
+
+// Confirm aplica el cambio de email si el código es el de la última solicitud.
+// Cada intento se cuenta antes de comparar el código, así ni las peticiones
+// concurrentes pueden probar más de maxEmailChangeAttempts códigos.
+func (uc *ChangeEmailUseCase) Confirm(ctx context.Context, userID uuid.UUID, input ConfirmEmailChangeInput) (*domain.User, error) {
+	req, err := uc.repo.GetLatestEmailChangeRequest(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Used {
+		return nil, domain.ErrCodeAlreadyUsed
+	}
+
+	if req.IsExpired() {
+		return nil, domain.ErrExpiredVerificationCode
+	}
+
+	attempts, err := uc.repo.RecordEmailChangeAttempt(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > maxEmailChangeAttempts {
+		return nil, domain.ErrTooManyCodeAttempts
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(input.Code)), []byte(req.Code)) != 1 {
+		if attempts == maxEmailChangeAttempts {
+			if err := uc.repo.DeleteEmailChangeRequest(ctx, req.ID); err != nil {
+				return nil, err
+			}
+			return nil, domain.ErrTooManyCodeAttempts
+		}
+		return nil, domain.ErrInvalidVerificationCode
+	}
+
+	// El email pudo haber sido tomado mientras el código estaba pendiente
+	if err := uc.ensureEmailAvailable(ctx, req.NewEmail); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Email = req.NewEmail
+	user.UpdatedAt = time.Now()
+
+	if err := uc.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.MarkEmailChangeRequestAsUsed(ctx, req.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (uc *ChangeEmailUseCase) ensureEmailAvailable(ctx context.Context, email string) error {
+	existingUser, err := uc.repo.GetUserByEmail(ctx, email)
+	if err != nil && err != domain.ErrUserNotFound {
+		return err
+	}
+	if existingUser != nil {
+		return domain.ErrEmailAlreadyExists
+	}
+	return nil
+}
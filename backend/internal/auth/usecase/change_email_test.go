@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+)
+
+// codeMailer guarda el último código de cambio de email enviado
+type codeMailer struct {
+	port.EmailService
+	code string
+}
+
+func (m *codeMailer) SendEmailChangeCode(ctx context.Context, newEmail, username, code string) error {
+	m.code = code
+	return nil
+}
+
+// wrongCode devuelve un código de seis dígitos distinto de code
+func wrongCode(code string, n int) string {
+	for {
+		guess := []byte("000000")
+		guess[5] += byte(n % 10)
+		guess[4] += byte(n / 10 % 10)
+		if string(guess) != code {
+			return string(guess)
+		}
+		n++
+	}
+}
+
+func TestConfirmEmailChangeLimitsAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		wrongGuesses int
+		wantLastErr  error
+		wantChanged  bool
+	}{
+		{name: "right code", wantChanged: true},
+		{name: "right code after some wrong ones", wrongGuesses: maxEmailChangeAttempts - 1, wantChanged: true},
+		{name: "right code after the request is exhausted", wrongGuesses: maxEmailChangeAttempts, wantLastErr: domain.ErrInvalidVerificationCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			user := &domain.User{ID: uuid.New(), Email: "ada@example.com", Username: "ada"}
+			repo.users[user.ID] = user
+			mailer := &codeMailer{}
+			uc := NewChangeEmailUseCase(repo, mailer)
+			ctx := context.Background()
+
+			err := uc.Request(ctx, user.ID, RequestEmailChangeInput{NewEmail: "ada@new.example.com", AuthenticatedAt: time.Now()})
+			if err != nil {
+				t.Fatalf("Request: %v", err)
+			}
+
+			for i := 1; i <= tt.wrongGuesses; i++ {
+				want := domain.ErrInvalidVerificationCode
+				if i == maxEmailChangeAttempts {
+					want = domain.ErrTooManyCodeAttempts
+				}
+				if _, err := uc.Confirm(ctx, user.ID, ConfirmEmailChangeInput{Code: wrongCode(mailer.code, i)}); !errors.Is(err, want) {
+					t.Fatalf("guess %d: err = %v, want %v", i, err, want)
+				}
+			}
+
+			_, err = uc.Confirm(ctx, user.ID, ConfirmEmailChangeInput{Code: mailer.code})
+			if !errors.Is(err, tt.wantLastErr) {
+				t.Fatalf("right code: err = %v, want %v", err, tt.wantLastErr)
+			}
+			if changed := user.Email == "ada@new.example.com"; changed != tt.wantChanged {
+				t.Fatalf("email = %q, changed %v, want %v", user.Email, changed, tt.wantChanged)
+			}
+			if tt.wantChanged {
+				if _, err := uc.Confirm(ctx, user.ID, ConfirmEmailChangeInput{Code: mailer.code}); !errors.Is(err, domain.ErrCodeAlreadyUsed) {
+					t.Fatalf("reused code: err = %v, want %v", err, domain.ErrCodeAlreadyUsed)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmEmailChangeAfterLockout(t *testing.T) {
+	repo := newFakeRepo()
+	user := &domain.User{ID: uuid.New(), Email: "ada@example.com", Username: "ada"}
+	repo.users[user.ID] = user
+	mailer := &codeMailer{}
+	uc := NewChangeEmailUseCase(repo, mailer)
+	ctx := context.Background()
+	input := RequestEmailChangeInput{NewEmail: "ada@new.example.com", AuthenticatedAt: time.Now()}
+
+	if err := uc.Request(ctx, user.ID, input); err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	for i := 1; i <= maxEmailChangeAttempts; i++ {
+		uc.Confirm(ctx, user.ID, ConfirmEmailChangeInput{Code: wrongCode(mailer.code, i)})
+	}
+	if len(repo.emailChanges) != 0 {
+		t.Fatalf("exhausted request was not deleted")
+	}
+
+	if err := uc.Request(ctx, user.ID, input); err != nil {
+		t.Fatalf("second Request: %v", err)
+	}
+	if _, err := uc.Confirm(ctx, user.ID, ConfirmEmailChangeInput{Code: mailer.code}); err != nil {
+		t.Fatalf("Confirm with a new code: %v", err)
+	}
+	if user.Email != "ada@new.example.com" {
+		t.Fatalf("email = %q, want the new one", user.Email)
+	}
+}
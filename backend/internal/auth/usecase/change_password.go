@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+)
+
+type ChangePasswordUseCase struct {
+	repo port.UserRepository
+}
+
+func NewChangePasswordUseCase(repo port.UserRepository) *ChangePasswordUseCase {
+	return &ChangePasswordUseCase{repo: repo}
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+	ConfirmPassword string `json:"confirm_password"`
+	// AuthenticatedAt es cuándo inició sesión el usuario; confirma la identidad si aún no tiene contraseña
+	AuthenticatedAt time.Time `json:"-"`
+}
+
+// Execute cambia la contraseña, o define la primera si la cuenta entra por OIDC o magic link
+func (uc *ChangePasswordUseCase) Execute(ctx context.Context, userID uuid.UUID, input ChangePasswordInput) error {
+	// Validar nueva contraseña
+	if input.NewPassword != input.ConfirmPassword {
+		return domain.ErrPasswordMismatch
+	}
+
+	if err := validatePassword(input.NewPassword); err != nil {
+		return err
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Verificar contraseña actual, o un inicio de sesión reciente si no tiene
+	if err := confirmIdentity(user, input.CurrentPassword, input.AuthenticatedAt); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash = string(hashedPassword)
+	user.UpdatedAt = time.Now()
+
+	return uc.repo.UpdateUser(ctx, user)
+}
+
+// checkPassword verifica la contraseña actual del usuario
+func checkPassword(user *domain.User, password string) error {
+	if user.PasswordHash == "" {
+		return domain.ErrInvalidCurrentPassword
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return domain.ErrInvalidCurrentPassword
+	}
+	return nil
+}
+
+// recentLoginWindow es cuánto tiempo después de iniciar sesión un usuario sin
+// contraseña puede confirmar acciones sensibles sin volver a autenticarse
+const recentLoginWindow = 10 * time.Minute
+
+// confirmIdentity confirma la identidad antes de una acción sensible: con la
+// contraseña si el usuario tiene una, o con un inicio de sesión reciente si
+// entra solo por OIDC o magic link
+func confirmIdentity(user *domain.User, password string, authenticatedAt time.Time) error {
+	if user.PasswordHash != "" {
+		return checkPassword(user, password)
+	}
+	if authenticatedAt.IsZero() || time.Since(authenticatedAt) > recentLoginWindow {
+		return domain.ErrRecentLoginRequired
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+)
+
+func TestChangePasswordConfirmsIdentity(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		passwordHash    string
+		currentPassword string
+		authenticatedAt time.Time
+		wantErr         error
+	}{
+		{name: "right password", passwordHash: string(hash), currentPassword: "correct-horse"},
+		{name: "wrong password", passwordHash: string(hash), currentPassword: "wrong", wantErr: domain.ErrInvalidCurrentPassword},
+		{name: "password user with fresh login still needs it", passwordHash: string(hash), authenticatedAt: time.Now(), wantErr: domain.ErrInvalidCurrentPassword},
+		{name: "passwordless with fresh login sets the first one", authenticatedAt: time.Now().Add(-time.Minute)},
+		{name: "passwordless with old login", authenticatedAt: time.Now().Add(-time.Hour), wantErr: domain.ErrRecentLoginRequired},
+		{name: "passwordless without login time", wantErr: domain.ErrRecentLoginRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			user := &domain.User{ID: uuid.New(), Email: "ada@example.com", PasswordHash: tt.passwordHash}
+			repo.users[user.ID] = user
+
+			err := NewChangePasswordUseCase(repo).Execute(context.Background(), user.ID, ChangePasswordInput{
+				CurrentPassword: tt.currentPassword,
+				NewPassword:     "Battery-Staple-9",
+				ConfirmPassword: "Battery-Staple-9",
+				AuthenticatedAt: tt.authenticatedAt,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			changed := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("Battery-Staple-9")) == nil
+			if changed != (tt.wantErr == nil) {
+				t.Fatalf("password changed = %v, want %v", changed, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+)
+
+type DeleteAccountUseCase struct {
+	repo          port.UserRepository
+	defaultPolicy domain.DeletionPolicy
+}
+
+// NewDeleteAccountUseCase crea el caso de uso; defaultPolicy se aplica cuando la solicitud no indica una
+func NewDeleteAccountUseCase(repo port.UserRepository, defaultPolicy domain.DeletionPolicy) *DeleteAccountUseCase {
+	if !defaultPolicy.IsValid() {
+		defaultPolicy = domain.DeletionPolicyAnonymize
+	}
+	return &DeleteAccountUseCase{
+		repo:          repo,
+		defaultPolicy: defaultPolicy,
+	}
+}
+
+type DeleteAccountInput struct {
+	Password string                `json:"password"`
+	Policy   domain.DeletionPolicy `json:"policy"`
+	// AuthenticatedAt es cuándo inició sesión el usuario; confirma la identidad si no tiene contraseña
+	AuthenticatedAt time.Time `json:"-"`
+}
+
+func (uc *DeleteAccountUseCase) Execute(ctx context.Context, userID uuid.UUID, input DeleteAccountInput) error {
+	policy := input.Policy
+	if policy == "" {
+		policy = uc.defaultPolicy
+	}
+	if !policy.IsValid() {
+		return domain.ErrInvalidDeletionPolicy
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Confirmar identidad antes de una acción irreversible
+	if err := confirmIdentity(user, input.Password, input.AuthenticatedAt); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteUser(ctx, user.ID, policy)
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+)
+
+func TestDeleteAccountConfirmsIdentity(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		passwordHash    string
+		password        string
+		authenticatedAt time.Time
+		wantErr         error
+	}{
+		{name: "right password", passwordHash: string(hash), password: "correct-horse"},
+		{name: "wrong password", passwordHash: string(hash), password: "wrong", wantErr: domain.ErrInvalidCurrentPassword},
+		{name: "password user with fresh login still needs it", passwordHash: string(hash), authenticatedAt: time.Now(), wantErr: domain.ErrInvalidCurrentPassword},
+		{name: "passwordless with fresh login", authenticatedAt: time.Now().Add(-time.Minute)},
+		{name: "passwordless with old login", authenticatedAt: time.Now().Add(-time.Hour), wantErr: domain.ErrRecentLoginRequired},
+		{name: "passwordless without login time", wantErr: domain.ErrRecentLoginRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			user := &domain.User{ID: uuid.New(), Email: "ada@example.com", PasswordHash: tt.passwordHash}
+			repo.users[user.ID] = user
+
+			err := NewDeleteAccountUseCase(repo, domain.DeletionPolicyAnonymize).Execute(context.Background(), user.ID, DeleteAccountInput{
+				Password:        tt.password,
+				AuthenticatedAt: tt.authenticatedAt,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if deleted := len(repo.deleted) == 1; deleted != (tt.wantErr == nil) {
+				t.Fatalf("deleted = %v, want %v", deleted, tt.wantErr == nil)
+			}
+		})
+	}
+}
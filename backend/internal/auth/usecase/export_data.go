@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+)
+
+// exportPurgeInterval es cada cuánto se eliminan las exportaciones vencidas
+const exportPurgeInterval = time.Hour
+
+type ExportDataUseCase struct {
+	repo         port.UserRepository
+	emailService port.EmailService
+	dataSource   port.UserDataSource
+	apiURL       string
+}
+
+func NewExportDataUseCase(repo port.UserRepository, emailService port.EmailService, dataSource port.UserDataSource, apiURL string) *ExportDataUseCase {
+	return &ExportDataUseCase{
+		repo:         repo,
+		emailService: emailService,
+		dataSource:   dataSource,
+		apiURL:       apiURL,
+	}
+}
+
+// Execute genera el archivo con todos los datos del usuario y envía el link de descarga por email
+func (uc *ExportDataUseCase) Execute(ctx context.Context, userID uuid.UUID) error {
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	files, err := uc.dataSource.CollectUserFiles(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("error recolectando datos: %w", err)
+	}
+
+	archive, err := buildExportArchive(user, files)
+	if err != nil {
+		return fmt.Errorf("error generando archivo: %w", err)
+	}
+
+	token := uuid.New().String()
+	export := &domain.DataExport{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Token:     token,
+		Archive:   archive,
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+		CreatedAt: time.Now(),
+	}
+
+	if err := uc.repo.CreateDataExport(ctx, export); err != nil {
+		return err
+	}
+
+	downloadLink := uc.apiURL + "/auth/export/" + token
+	return uc.emailService.SendDataExportLink(ctx, user.Email, user.Username, downloadLink)
+}
+
+// Download obtiene una exportación vigente por su token. El link sirve una sola vez:
+// la exportación se marca como descargada (y se borra el archivo) antes de entregarla.
+func (uc *ExportDataUseCase) Download(ctx context.Context, token string) (*domain.DataExport, error) {
+	export, err := uc.repo.GetDataExport(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.DownloadedAt != nil {
+		return nil, domain.ErrTokenAlreadyUsed
+	}
+	if export.IsExpired() {
+		return nil, domain.ErrExpiredExportToken
+	}
+
+	if err := uc.repo.MarkDataExportDownloaded(ctx, export.ID); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// Run elimina periódicamente las exportaciones vencidas hasta que ctx termine
+func (uc *ExportDataUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(exportPurgeInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := uc.repo.PurgeExpiredDataExports(ctx); err != nil {
+			log.Printf("error purging expired data exports: %v", err)
+		} else if n > 0 {
+			log.Printf("purged %d expired data exports", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// buildExportArchive arma el zip con el perfil y los archivos de proyectos
+func buildExportArchive(user *domain.User, files []domain.ExportFile) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	profile, err := json.MarshalIndent(user, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var readme strings.Builder
+	fmt.Fprintf(&readme, "# Exportación de datos de %s\n\n", user.Username)
+	fmt.Fprintf(&readme, "- Email: %s\n", user.Email)
+	fmt.Fprintf(&readme, "- Estado: %s\n", user.Status)
+	fmt.Fprintf(&readme, "- Cuenta creada: %s\n", user.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&readme, "- Exportado: %s\n\n", time.Now().Format("2006-01-02 15:04"))
+	readme.WriteString("Cada proyecto está en `projects/<id>/`: `project.json` contiene todos los datos, ")
+	readme.WriteString("los archivos `.md` son versiones legibles y `chats/` contiene los historiales de conversación.\n")
+
+	entries := append([]domain.ExportFile{
+		{Path: "profile.json", Content: profile},
+		{Path: "README.md", Content: []byte(readme.String())},
+	}, files...)
+
+	for _, f := range entries {
+		w, err := zw.Create(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+	"github.com/google/uuid"
+)
+
+// fakeRepo es un port.UserRepository en memoria para los tests de los casos de uso.
+// Los métodos que un test no necesita quedan sin implementar y fallan con panic.
+type fakeRepo struct {
+	port.UserRepository
+
+	mu         sync.Mutex
+	users      map[uuid.UUID]*domain.User
+	oidcStates map[string]*domain.OIDCAuthState
+	identities map[string]*domain.UserIdentity
+	deleted    []uuid.UUID
+
+	emailChanges []*domain.EmailChangeRequest
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		users:      map[uuid.UUID]*domain.User{},
+		oidcStates: map[string]*domain.OIDCAuthState{},
+		identities: map[string]*domain.UserIdentity{},
+	}
+}
+
+func (r *fakeRepo) CreateUser(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeRepo) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *fakeRepo) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *fakeRepo) UpdateUser(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeRepo) DeleteUser(ctx context.Context, id uuid.UUID, policy domain.DeletionPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func (r *fakeRepo) CreateOIDCState(ctx context.Context, state *domain.OIDCAuthState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.oidcStates[state.State] = state
+	return nil
+}
+
+func (r *fakeRepo) ConsumeOIDCState(ctx context.Context, state string) (*domain.OIDCAuthState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	authState, ok := r.oidcStates[state]
+	if !ok {
+		return nil, domain.ErrInvalidOIDCState
+	}
+	delete(r.oidcStates, state)
+	return authState, nil
+}
+
+func (r *fakeRepo) GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	identity, ok := r.identities[provider+"/"+subject]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return identity, nil
+}
+
+func (r *fakeRepo) CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.identities[identity.Provider+"/"+identity.Subject] = identity
+	return nil
+}
+
+func (r *fakeRepo) CreateEmailChangeRequest(ctx context.Context, req *domain.EmailChangeRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emailChanges = append(r.emailChanges, req)
+	return nil
+}
+
+func (r *fakeRepo) GetLatestEmailChangeRequest(ctx context.Context, userID uuid.UUID) (*domain.EmailChangeRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.emailChanges) - 1; i >= 0; i-- {
+		if req := r.emailChanges[i]; req.UserID == userID {
+			copied := *req
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrInvalidVerificationCode
+}
+
+func (r *fakeRepo) RecordEmailChangeAttempt(ctx context.Context, requestID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, req := range r.emailChanges {
+		if req.ID == requestID {
+			req.Attempts++
+			return req.Attempts, nil
+		}
+	}
+	return 0, domain.ErrTooManyCodeAttempts
+}
+
+func (r *fakeRepo) DeleteEmailChangeRequest(ctx context.Context, requestID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emailChanges = slices.DeleteFunc(r.emailChanges, func(req *domain.EmailChangeRequest) bool {
+		return req.ID == requestID
+	})
+	return nil
+}
+
+func (r *fakeRepo) MarkEmailChangeRequestAsUsed(ctx context.Context, requestID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, req := range r.emailChanges {
+		if req.ID == requestID {
+			req.Used = true
+		}
+	}
+	return nil
+}
@@ -63,6 +63,12 @@ func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginOu
 		return nil, domain.ErrInvalidCredentials
 	}
 
+	return newLoginOutput(user, uc.jwtSecret)
+}
+
+// newLoginOutput verifica que el usuario esté activo y emite su sesión.
+// Es compartido por todos los métodos de inicio de sesión.
+func newLoginOutput(user *domain.User, jwtSecret string) (*LoginOutput, error) {
 	// Verificar que el usuario esté activo
 	if user.Status == domain.UserStatusPendingVerification {
 		return nil, domain.ErrUserNotVerified
@@ -73,7 +79,7 @@ func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginOu
 	}
 
 	// Generar JWT token
-	token, err := uc.generateToken(user)
+	token, err := generateToken(user, jwtSecret)
 	if err != nil {
 		return nil, err
 	}
@@ -84,15 +90,16 @@ func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginOu
 	}, nil
 }
 
-func (uc *LoginUseCase) generateToken(user *domain.User) (string, error) {
+func generateToken(user *domain.User, jwtSecret string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id":  user.ID.String(),
 		"username": user.Username,
 		"email":    user.Email,
+		"role":     string(user.Role),
 		"exp":      time.Now().Add(7 * 24 * time.Hour).Unix(), // 7 días
 		"iat":      time.Now().Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(uc.jwtSecret))
+	return token.SignedString([]byte(jwtSecret))
 }
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+	"github.com/google/uuid"
+)
+
+// magicLinkTTL es la vigencia de un magic link
+const magicLinkTTL = 15 * time.Minute
+
+// MagicLinkUseCase permite iniciar sesión sin contraseña mediante un link enviado por email
+type MagicLinkUseCase struct {
+	repo         port.UserRepository
+	emailService port.EmailService
+	frontendURL  string
+	jwtSecret    string
+}
+
+func NewMagicLinkUseCase(repo port.UserRepository, emailService port.EmailService, frontendURL, jwtSecret string) *MagicLinkUseCase {
+	return &MagicLinkUseCase{
+		repo:         repo,
+		emailService: emailService,
+		frontendURL:  frontendURL,
+		jwtSecret:    jwtSecret,
+	}
+}
+
+type RequestMagicLinkInput struct {
+	Email string `json:"email"`
+}
+
+type ConsumeMagicLinkInput struct {
+	Token string `json:"token"`
+}
+
+// Request envía un link de acceso de un solo uso al email indicado
+func (uc *MagicLinkUseCase) Request(ctx context.Context, input RequestMagicLinkInput) error {
+	// Buscar usuario por email
+	user, err := uc.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		// No revelar si el email existe o no (por seguridad)
+		if err == domain.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// Generar token único
+	token := uuid.New().String()
+	magicToken := &domain.MagicLinkToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Token:     token,
+		ExpiresAt: time.Now().Add(magicLinkTTL),
+		Used:      false,
+		CreatedAt: time.Now(),
+	}
+
+	if err := uc.repo.CreateMagicLinkToken(ctx, magicToken); err != nil {
+		return err
+	}
+
+	// Crear magic link
+	magicLink := uc.frontendURL + "/auth/magic-link?token=" + token
+
+	// Enviar email
+	return uc.emailService.SendMagicLink(ctx, user.Email, user.Username, magicLink)
+}
+
+// Consume canjea el magic link por una sesión, igual que un login normal
+func (uc *MagicLinkUseCase) Consume(ctx context.Context, input ConsumeMagicLinkInput) (*LoginOutput, error) {
+	// Obtener token
+	magicToken, err := uc.repo.GetMagicLinkToken(ctx, input.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar si el token ya fue usado
+	if magicToken.Used {
+		return nil, domain.ErrTokenAlreadyUsed
+	}
+
+	// Verificar si el token está expirado
+	if magicToken.IsExpired() {
+		return nil, domain.ErrExpiredMagicLink
+	}
+
+	// Obtener usuario
+	user, err := uc.repo.GetUserByID(ctx, magicToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Marcar token como usado antes de emitir la sesión
+	if err := uc.repo.MarkMagicLinkTokenAsUsed(ctx, magicToken.ID); err != nil {
+		return nil, err
+	}
+
+	return newLoginOutput(user, uc.jwtSecret)
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+	"github.com/google/uuid"
+)
+
+// oidcStateTTL es el tiempo máximo entre iniciar el login y volver del proveedor
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCLoginUseCase implementa el login con proveedores OpenID Connect
+type OIDCLoginUseCase struct {
+	repo      port.UserRepository
+	providers map[string]port.IdentityProvider
+	jwtSecret string
+}
+
+func NewOIDCLoginUseCase(repo port.UserRepository, providers []port.IdentityProvider, jwtSecret string) *OIDCLoginUseCase {
+	byName := make(map[string]port.IdentityProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &OIDCLoginUseCase{
+		repo:      repo,
+		providers: byName,
+		jwtSecret: jwtSecret,
+	}
+}
+
+type OIDCStartOutput struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	// Binding liga el state al navegador que inició el login (se guarda en una cookie)
+	Binding string `json:"-"`
+}
+
+type OIDCCallbackInput struct {
+	State string `json:"state"`
+	Code  string `json:"code"`
+	// Binding es el valor de la cookie del navegador que vuelve del proveedor
+	Binding string `json:"-"`
+}
+
+// Providers lista los nombres de los proveedores configurados
+func (uc *OIDCLoginUseCase) Providers() []string {
+	names := make([]string, 0, len(uc.providers))
+	for name := range uc.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start genera state, nonce y verificador PKCE y devuelve la URL de autorización
+func (uc *OIDCLoginUseCase) Start(ctx context.Context, providerName string) (*OIDCStartOutput, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+
+	authState := &domain.OIDCAuthState{
+		ID:           uuid.New(),
+		State:        randomToken(),
+		Provider:     providerName,
+		Nonce:        randomToken(),
+		CodeVerifier: randomToken(),
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+		CreatedAt:    time.Now(),
+	}
+
+	if err := uc.repo.CreateOIDCState(ctx, authState); err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, authState.State, authState.Nonce, codeChallengeS256(authState.CodeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCStartOutput{
+		AuthorizationURL: authURL,
+		State:            authState.State,
+		Binding:          stateBinding(authState.State),
+	}, nil
+}
+
+// Callback canjea el código, resuelve el usuario y emite la sesión normal
+func (uc *OIDCLoginUseCase) Callback(ctx context.Context, input OIDCCallbackInput) (*LoginOutput, error) {
+	// El state debe volver al mismo navegador que inició el login: si no, alguien podría
+	// completar su propio login en el navegador de otra persona (login CSRF)
+	if input.State == "" || subtle.ConstantTimeCompare([]byte(stateBinding(input.State)), []byte(input.Binding)) != 1 {
+		return nil, domain.ErrInvalidOIDCState
+	}
+
+	// El state se consume siempre, aunque el resto falle
+	authState, err := uc.repo.ConsumeOIDCState(ctx, input.State)
+	if err != nil {
+		return nil, err
+	}
+
+	if authState.IsExpired() {
+		return nil, domain.ErrExpiredOIDCState
+	}
+
+	provider, ok := uc.providers[authState.Provider]
+	if !ok {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+
+	identity, err := provider.Exchange(ctx, input.Code, authState.CodeVerifier, authState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.resolveUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLoginOutput(user, uc.jwtSecret)
+}
+
+// resolveUser busca la identidad ya vinculada; si no existe la vincula al usuario
+// con el mismo email verificado o crea uno nuevo.
+func (uc *OIDCLoginUseCase) resolveUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	linked, err := uc.repo.GetUserIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return uc.repo.GetUserByID(ctx, linked.UserID)
+	}
+	if err != domain.ErrUserNotFound {
+		return nil, err
+	}
+
+	// Vincular por email solo si el proveedor lo verificó
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, domain.ErrOIDCEmailNotVerified
+	}
+
+	user, err := uc.repo.GetUserByEmail(ctx, identity.Email)
+	if err != nil && err != domain.ErrUserNotFound {
+		return nil, err
+	}
+
+	if user == nil {
+		user, err = uc.createUser(ctx, identity)
+		if err != nil {
+			return nil, err
+		}
+	} else if user.Status == domain.UserStatusPendingVerification {
+		// El proveedor ya verificó el email
+		user.Status = domain.UserStatusActive
+		user.UpdatedAt = time.Now()
+		if err := uc.repo.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.repo.CreateUserIdentity(ctx, &domain.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser da de alta un usuario activo sin contraseña (puede definirla luego con forgot-password)
+func (uc *OIDCLoginUseCase) createUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	username, err := uc.availableUsername(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:        uuid.New(),
+		Username:  username,
+		Email:     identity.Email,
+		Status:    domain.UserStatusActive,
+		Role:      domain.RoleUser,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := uc.repo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+var invalidUsernameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// availableUsername deriva un username válido y libre a partir del email
+func (uc *OIDCLoginUseCase) availableUsername(ctx context.Context, email string) (string, error) {
+	base := invalidUsernameChars.ReplaceAllString(strings.SplitN(email, "@", 2)[0], "_")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	for len(base) < 3 {
+		base += "_"
+	}
+
+	candidate := base
+	for i := 0; i < 10; i++ {
+		_, err := uc.repo.GetUserByUsername(ctx, candidate)
+		if err == domain.ErrUserNotFound {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%s", base, generateVerificationCode()[:4])
+	}
+	return "", domain.ErrUsernameAlreadyExists
+}
+
+// randomToken genera un valor aleatorio de 256 bits en base64url
+func randomToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// stateBinding es el hash del state que se guarda en la cookie del navegador
+func stateBinding(state string) string {
+	sum := sha256.Sum256([]byte("oidc-state:" + state))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// codeChallengeS256 deriva el code_challenge PKCE (RFC 7636)
+func codeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dark/idea-forge/internal/auth/adapter/oidc"
+	"github.com/dark/idea-forge/internal/auth/adapter/oidc/oidctest"
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+)
+
+func newOIDCLogin(t *testing.T) (*OIDCLoginUseCase, *fakeRepo, *oidctest.Server) {
+	t.Helper()
+	idp := oidctest.NewServer(t, "idea-forge")
+	provider := oidc.NewProvider(oidc.Config{
+		Name:        "corp",
+		Issuer:      idp.Issuer(),
+		ClientID:    "idea-forge",
+		RedirectURL: "http://localhost:3000/auth/oidc/callback",
+	}, idp.Client())
+
+	repo := newFakeRepo()
+	return NewOIDCLoginUseCase(repo, []port.IdentityProvider{provider}, "test-secret"), repo, idp
+}
+
+func TestOIDCCallbackRequiresStateBinding(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		binding func(start *OIDCStartOutput, other *OIDCStartOutput) string
+		wantErr error
+	}{
+		{
+			name:    "same browser",
+			binding: func(start, other *OIDCStartOutput) string { return start.Binding },
+		},
+		{
+			name:    "no cookie",
+			binding: func(start, other *OIDCStartOutput) string { return "" },
+			wantErr: domain.ErrInvalidOIDCState,
+		},
+		{
+			name:    "cookie from another login",
+			binding: func(start, other *OIDCStartOutput) string { return other.Binding },
+			wantErr: domain.ErrInvalidOIDCState,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, idp := newOIDCLogin(t)
+
+			start, err := uc.Start(ctx, "corp")
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			other, err := uc.Start(ctx, "corp")
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			code := idp.Authorize(start.AuthorizationURL)
+
+			out, err := uc.Callback(ctx, OIDCCallbackInput{
+				State:   start.State,
+				Code:    code,
+				Binding: tt.binding(start, other),
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Callback err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				// Un callback rechazado no consume el state: el navegador legítimo aún puede terminar
+				if _, ok := repo.oidcStates[start.State]; !ok {
+					t.Fatal("rejected callback consumed the state")
+				}
+				return
+			}
+			if out.Token == "" || out.User.Email != idp.Email {
+				t.Fatalf("Callback = %+v, want a session for %s", out, idp.Email)
+			}
+		})
+	}
+}
+
+func TestOIDCCallbackStateIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	uc, _, idp := newOIDCLogin(t)
+
+	start, err := uc.Start(ctx, "corp")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	input := OIDCCallbackInput{State: start.State, Code: idp.Authorize(start.AuthorizationURL), Binding: start.Binding}
+	if _, err := uc.Callback(ctx, input); err != nil {
+		t.Fatalf("first Callback: %v", err)
+	}
+	if _, err := uc.Callback(ctx, input); !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Fatalf("replayed Callback err = %v, want ErrInvalidOIDCState", err)
+	}
+}
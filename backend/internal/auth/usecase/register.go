@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"log"
 	"math/big"
 	"regexp"
 	"time"
@@ -32,6 +33,7 @@ type RegisterInput struct {
 	Email           string `json:"email"`
 	Password        string `json:"password"`
 	ConfirmPassword string `json:"confirm_password"`
+	Locale          string `json:"locale"` // opcional, idioma de los emails
 }
 
 func (uc *RegisterUseCase) Execute(ctx context.Context, input RegisterInput) (*domain.User, error) {
@@ -52,6 +54,10 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, input RegisterInput) (*d
 		return nil, domain.ErrPasswordMismatch
 	}
 
+	if input.Locale != "" && !domain.IsValidLocale(input.Locale) {
+		return nil, domain.ErrInvalidLocale
+	}
+
 	// Verificar que el email no esté registrado
 	existingUser, err := uc.repo.GetUserByEmail(ctx, input.Email)
 	if err != nil && err != domain.ErrUserNotFound {
@@ -84,6 +90,8 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, input RegisterInput) (*d
 		Email:        input.Email,
 		PasswordHash: string(hashedPassword),
 		Status:       domain.UserStatusPendingVerification,
+		Role:         domain.RoleUser,
+		Locale:       input.Locale,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -107,10 +115,10 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, input RegisterInput) (*d
 		return nil, err
 	}
 
-	// Enviar email con código
+	// Encolar email con código (se reintenta si el servidor SMTP falla)
 	if err := uc.emailService.SendVerificationCode(ctx, user.Email, user.Username, code); err != nil {
-		// Log error pero no fallar el registro
-		fmt.Printf("Error sending verification email: %v\n", err)
+		// El usuario puede pedir que se reenvíe el código
+		log.Printf("error queueing verification email for %s: %v", user.ID, err)
 	}
 
 	return user, nil
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/auth/domain"
+	"github.com/dark/idea-forge/internal/auth/port"
+)
+
+type UpdateProfileUseCase struct {
+	repo port.UserRepository
+}
+
+func NewUpdateProfileUseCase(repo port.UserRepository) *UpdateProfileUseCase {
+	return &UpdateProfileUseCase{repo: repo}
+}
+
+// UpdateProfileInput cambia el nombre de usuario y/o el idioma de los emails
+type UpdateProfileInput struct {
+	Username string `json:"username"`
+	Locale   string `json:"locale"`
+}
+
+func (uc *UpdateProfileUseCase) Execute(ctx context.Context, userID uuid.UUID, input UpdateProfileInput) (*domain.User, error) {
+	if input.Username != "" || input.Locale == "" {
+		if err := validateUsername(input.Username); err != nil {
+			return nil, err
+		}
+	}
+	if input.Locale != "" && !domain.IsValidLocale(input.Locale) {
+		return nil, domain.ErrInvalidLocale
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	if input.Username != "" && user.Username != input.Username {
+		// Verificar que el username no esté en uso
+		existingUser, err := uc.repo.GetUserByUsername(ctx, input.Username)
+		if err != nil && err != domain.ErrUserNotFound {
+			return nil, err
+		}
+		if existingUser != nil {
+			return nil, domain.ErrUsernameAlreadyExists
+		}
+		user.Username = input.Username
+		changed = true
+	}
+	if input.Locale != "" && user.Locale != input.Locale {
+		user.Locale = input.Locale
+		changed = true
+	}
+	if !changed {
+		return user, nil
+	}
+	user.UpdatedAt = time.Now()
+
+	if err := uc.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,191 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/dark/idea-forge/internal/comment/domain"
+	"github.com/dark/idea-forge/internal/comment/usecase"
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// Handlers exposes comment threads. Read access to {ideaID} is checked by the access guard.
+type Handlers struct {
+	Usecase *usecase.CommentUsecase
+}
+
+func (h *Handlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /projects/{ideaID}/comments", h.listThreads)
+	mux.HandleFunc("POST /projects/{ideaID}/comments", h.createThread)
+	mux.HandleFunc("POST /projects/{ideaID}/comments/{threadID}/replies", h.reply)
+	mux.HandleFunc("DELETE /projects/{ideaID}/comments/{threadID}/replies/{commentID}", h.deleteComment)
+	mux.HandleFunc("POST /projects/{ideaID}/comments/{threadID}/resolve", h.resolve)
+	mux.HandleFunc("POST /projects/{ideaID}/comments/{threadID}/unresolve", h.unresolve)
+}
+
+func (h *Handlers) listThreads(w http.ResponseWriter, r *http.Request) {
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+
+	filter := domain.ThreadFilter{Anchor: r.URL.Query().Get("anchor")}
+	if v := r.URL.Query().Get("resolved"); v != "" {
+		resolved, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "invalid resolved", http.StatusBadRequest)
+			return
+		}
+		filter.Resolved = &resolved
+	}
+
+	threads, err := h.Usecase.ListThreads(r.Context(), ideaID, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, threads, http.StatusOK)
+}
+
+func (h *Handlers) createThread(w http.ResponseWriter, r *http.Request) {
+	userID, ideaID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	var in usecase.CreateThreadInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	thread, err := h.Usecase.CreateThread(r.Context(), userID, ideaID, in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, thread, http.StatusCreated)
+}
+
+func (h *Handlers) reply(w http.ResponseWriter, r *http.Request) {
+	userID, ideaID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	threadID, err := uuid.Parse(r.PathValue("threadID"))
+	if err != nil {
+		http.Error(w, "invalid thread id", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	var in struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.Usecase.Reply(r.Context(), userID, ideaID, threadID, in.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, comment, http.StatusCreated)
+}
+
+func (h *Handlers) deleteComment(w http.ResponseWriter, r *http.Request) {
+	userID, ideaID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	threadID, err := uuid.Parse(r.PathValue("threadID"))
+	if err != nil {
+		http.Error(w, "invalid thread id", http.StatusBadRequest)
+		return
+	}
+	commentID, err := uuid.Parse(r.PathValue("commentID"))
+	if err != nil {
+		http.Error(w, "invalid comment id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Usecase.DeleteComment(r.Context(), userID, ideaID, threadID, commentID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) resolve(w http.ResponseWriter, r *http.Request) {
+	h.setResolved(w, r, true)
+}
+
+func (h *Handlers) unresolve(w http.ResponseWriter, r *http.Request) {
+	h.setResolved(w, r, false)
+}
+
+func (h *Handlers) setResolved(w http.ResponseWriter, r *http.Request, resolved bool) {
+	userID, ideaID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	threadID, err := uuid.Parse(r.PathValue("threadID"))
+	if err != nil {
+		http.Error(w, "invalid thread id", http.StatusBadRequest)
+		return
+	}
+
+	thread, err := h.Usecase.SetResolved(r.Context(), userID, ideaID, threadID, resolved)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, thread, http.StatusOK)
+}
+
+// parseIDs returns the authenticated user and the idea from the path
+func parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, ideaID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrThreadNotFound),
+		errors.Is(err, domain.ErrCommentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidAnchor),
+		errors.Is(err, domain.ErrInvalidRange),
+		errors.Is(err, domain.ErrSectionMissing),
+		errors.Is(err, domain.ErrEmptyBody):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotAuthor):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("comment error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
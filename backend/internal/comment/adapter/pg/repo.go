@@ -0,0 +1,255 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/comment/domain"
+	"github.com/dark/idea-forge/internal/comment/port"
+	"github.com/google/uuid"
+)
+
+type repo struct{ db *sql.DB }
+
+func NewRepo(db *sql.DB) port.CommentRepository { return &repo{db: db} }
+
+const threadColumns = `t.id, t.idea_id, t.anchor, t.range_start, t.range_end, t.quoted_text, t.resolved_by, t.resolved_at, t.outdated, t.created_by, t.created_at, t.updated_at`
+
+const commentColumns = `c.id, c.thread_id, c.author_id, COALESCE(u.username, ''), c.body, c.created_at`
+
+func (r *repo) CreateThread(ctx context.Context, thread *domain.Thread, first *domain.Comment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO comment_threads (id, idea_id, anchor, range_start, range_end, quoted_text, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`, thread.ID, thread.IdeaID, thread.Anchor, thread.RangeStart, thread.RangeEnd, thread.QuotedText, thread.CreatedBy).
+		Scan(&thread.CreatedAt, &thread.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertComment(ctx, tx, first); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repo) GetThread(ctx context.Context, ideaID, threadID uuid.UUID) (*domain.Thread, error) {
+	threads, err := r.listThreads(ctx, `t.idea_id = $1 AND t.id = $2`, ideaID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if len(threads) == 0 {
+		return nil, domain.ErrThreadNotFound
+	}
+	return &threads[0], nil
+}
+
+func (r *repo) ListThreads(ctx context.Context, ideaID uuid.UUID, filter domain.ThreadFilter) ([]domain.Thread, error) {
+	conds := []string{"t.idea_id = $1"}
+	args := []any{ideaID}
+	if filter.Anchor != "" {
+		args = append(args, filter.Anchor)
+		conds = append(conds, fmt.Sprintf("t.anchor = $%d", len(args)))
+	}
+	if filter.Resolved != nil {
+		if *filter.Resolved {
+			conds = append(conds, "t.resolved_at IS NOT NULL")
+		} else {
+			conds = append(conds, "t.resolved_at IS NULL")
+		}
+	}
+	return r.listThreads(ctx, strings.Join(conds, " AND "), args...)
+}
+
+// listThreads loads the matching threads and then all their comments in one query
+func (r *repo) listThreads(ctx context.Context, where string, args ...any) ([]domain.Thread, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+threadColumns+`
+		FROM comment_threads t
+		WHERE `+where+`
+		ORDER BY t.created_at
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	threads := []domain.Thread{}
+	index := map[uuid.UUID]int{}
+	for rows.Next() {
+		t, err := scanThread(rows)
+		if err != nil {
+			return nil, err
+		}
+		t.Comments = []domain.Comment{}
+		index[t.ID] = len(threads)
+		threads = append(threads, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(threads) == 0 {
+		return threads, nil
+	}
+
+	commentRows, err := r.db.QueryContext(ctx, `
+		SELECT `+commentColumns+`
+		FROM comments c
+		JOIN comment_threads t ON t.id = c.thread_id
+		LEFT JOIN users u ON u.id = c.author_id
+		WHERE `+where+`
+		ORDER BY c.created_at
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer commentRows.Close()
+
+	for commentRows.Next() {
+		c, err := scanComment(commentRows)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[c.ThreadID]; ok {
+			threads[i].Comments = append(threads[i].Comments, *c)
+		}
+	}
+	return threads, commentRows.Err()
+}
+
+func (r *repo) AddComment(ctx context.Context, comment *domain.Comment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertComment(ctx, tx, comment); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE comment_threads SET updated_at = NOW() WHERE id = $1`, comment.ThreadID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repo) GetComment(ctx context.Context, threadID, commentID uuid.UUID) (*domain.Comment, error) {
+	c, err := scanComment(r.db.QueryRowContext(ctx, `
+		SELECT `+commentColumns+`
+		FROM comments c
+		LEFT JOIN users u ON u.id = c.author_id
+		WHERE c.thread_id = $1 AND c.id = $2
+	`, threadID, commentID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCommentNotFound
+	}
+	return c, err
+}
+
+func (r *repo) DeleteComment(ctx context.Context, threadID, commentID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM comments WHERE thread_id = $1 AND id = $2`, threadID, commentID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	// Un hilo sin comentarios no tiene sentido
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM comment_threads t
+		WHERE t.id = $1 AND NOT EXISTS (SELECT 1 FROM comments c WHERE c.thread_id = t.id)
+	`, threadID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repo) SetResolved(ctx context.Context, threadID uuid.UUID, resolvedBy *uuid.UUID) error {
+	query := `UPDATE comment_threads SET resolved_by = NULL, resolved_at = NULL, updated_at = NOW() WHERE id = $1`
+	args := []any{threadID}
+	if resolvedBy != nil {
+		query = `UPDATE comment_threads SET resolved_by = $2, resolved_at = NOW(), updated_at = NOW() WHERE id = $1`
+		args = append(args, *resolvedBy)
+	}
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrThreadNotFound
+	}
+	return nil
+}
+
+func (r *repo) UpdateAnchor(ctx context.Context, thread *domain.Thread) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE comment_threads SET range_start = $2, range_end = $3, outdated = $4 WHERE id = $1
+	`, thread.ID, thread.RangeStart, thread.RangeEnd, thread.Outdated)
+	return err
+}
+
+func insertComment(ctx context.Context, tx *sql.Tx, c *domain.Comment) error {
+	return tx.QueryRowContext(ctx, `
+		INSERT INTO comments (id, thread_id, author_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`, c.ID, c.ThreadID, c.AuthorID, c.Body).Scan(&c.CreatedAt)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanThread(row rowScanner) (*domain.Thread, error) {
+	var t domain.Thread
+	var rangeStart, rangeEnd sql.NullInt64
+	var resolvedBy, createdBy uuid.NullUUID
+	var resolvedAt sql.NullTime
+	if err := row.Scan(&t.ID, &t.IdeaID, &t.Anchor, &rangeStart, &rangeEnd, &t.QuotedText, &resolvedBy, &resolvedAt, &t.Outdated, &createdBy, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if rangeStart.Valid && rangeEnd.Valid {
+		start, end := int(rangeStart.Int64), int(rangeEnd.Int64)
+		t.RangeStart, t.RangeEnd = &start, &end
+	}
+	if resolvedAt.Valid {
+		t.Resolved = true
+		t.ResolvedAt = &resolvedAt.Time
+		if resolvedBy.Valid {
+			t.ResolvedBy = &resolvedBy.UUID
+		}
+	}
+	t.CreatedBy = createdBy.UUID
+	return &t, nil
+}
+
+func scanComment(row rowScanner) (*domain.Comment, error) {
+	var c domain.Comment
+	var authorID uuid.NullUUID
+	if err := row.Scan(&c.ID, &c.ThreadID, &authorID, &c.AuthorName, &c.Body, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	c.AuthorID = authorID.UUID
+	return &c, nil
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrThreadNotFound  = errors.New("comment thread not found")
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrInvalidAnchor   = errors.New("invalid anchor (expected stage.section, e.g. architecture.database_schema)")
+	ErrInvalidRange    = errors.New("invalid text range for this section")
+	ErrSectionMissing  = errors.New("the section does not exist yet")
+	ErrEmptyBody       = errors.New("comment body is required (max 10000 chars)")
+	ErrNotAuthor       = errors.New("only the author can delete a comment")
+)
+
+// MaxBodyLength bounds the size of a comment
+const MaxBodyLength = 10000
+
+// sectionsByStage lists the sections comments can be anchored to
+var sectionsByStage = map[string][]string{
+	"idea":         {"title", "objective", "problem", "scope"},
+	"action_plan":  {"functional_requirements", "non_functional_requirements", "business_logic_flow"},
+	"architecture": {"user_stories", "database_type", "database_schema", "entities_relationships", "tech_stack", "architecture_pattern", "system_architecture"},
+}
+
+// mentionPattern matches @username (same charset as registration usernames)
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([a-zA-Z0-9_-]{3,50})`)
+
+// Thread is a discussion anchored to a section of a project stage, optionally
+// to a text range inside it. Ranges are character (rune) offsets.
+type Thread struct {
+	ID         uuid.UUID  `json:"id"`
+	IdeaID     uuid.UUID  `json:"idea_id"`
+	Anchor     string     `json:"anchor"` // stage.section
+	RangeStart *int       `json:"range_start,omitempty"`
+	RangeEnd   *int       `json:"range_end,omitempty"`
+	QuotedText string     `json:"quoted_text,omitempty"` // text the range covered when the thread was opened
+	Resolved   bool       `json:"resolved"`
+	ResolvedBy *uuid.UUID `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	Outdated   bool       `json:"outdated"` // the quoted text is no longer in the section
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Comments   []Comment  `json:"comments"`
+}
+
+// Comment is one message of a thread
+type Comment struct {
+	ID         uuid.UUID `json:"id"`
+	ThreadID   uuid.UUID `json:"thread_id"`
+	AuthorID   uuid.UUID `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// User is the subset of a user account the comments need
+type User struct {
+	ID       uuid.UUID
+	Username string
+	Email    string
+}
+
+// ThreadFilter narrows the thread listing
+type ThreadFilter struct {
+	Anchor   string
+	Resolved *bool
+}
+
+// ParseAnchor validates an anchor like "architecture.database_schema"
+func ParseAnchor(anchor string) (stage, section string, err error) {
+	stage, section, ok := strings.Cut(anchor, ".")
+	if !ok || !slices.Contains(sectionsByStage[stage], section) {
+		return "", "", ErrInvalidAnchor
+	}
+	return stage, section, nil
+}
+
+// Mentions returns the distinct usernames mentioned in a comment body
+func Mentions(body string) []string {
+	var usernames []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		if !slices.Contains(usernames, m[1]) {
+			usernames = append(usernames, m[1])
+		}
+	}
+	return usernames
+}
+
+// Reanchor follows the quoted text after the section was edited: the range moves
+// to the nearest occurrence of the quote, or the thread is marked outdated when
+// the quote is gone. Returns whether the thread changed.
+func (t *Thread) Reanchor(content string) bool {
+	if t.RangeStart == nil || t.RangeEnd == nil || t.QuotedText == "" {
+		return false
+	}
+
+	text := []rune(content)
+	quote := []rune(t.QuotedText)
+	start, end := *t.RangeStart, *t.RangeEnd
+	if start >= 0 && end <= len(text) && start < end && string(text[start:end]) == t.QuotedText {
+		if t.Outdated {
+			t.Outdated = false
+			return true
+		}
+		return false
+	}
+
+	best := -1
+	for i := 0; i+len(quote) <= len(text); i++ {
+		if !slices.Equal(text[i:i+len(quote)], quote) {
+			continue
+		}
+		if best < 0 || abs(i-start) < abs(best-start) {
+			best = i
+		}
+	}
+
+	if best < 0 {
+		if t.Outdated {
+			return false
+		}
+		t.Outdated = true
+		return true
+	}
+
+	newStart, newEnd := best, best+len(quote)
+	t.RangeStart, t.RangeEnd = &newStart, &newEnd
+	t.Outdated = false
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,45 @@
+package port
+
+import (
+	"context"
+
+	"github.com/dark/idea-forge/internal/comment/domain"
+	"github.com/google/uuid"
+)
+
+// CommentRepository defines persistence for comment threads
+type CommentRepository interface {
+	// CreateThread stores the thread together with its first comment
+	CreateThread(ctx context.Context, thread *domain.Thread, first *domain.Comment) error
+	// GetThread returns a thread of the idea with its comments
+	GetThread(ctx context.Context, ideaID, threadID uuid.UUID) (*domain.Thread, error)
+	ListThreads(ctx context.Context, ideaID uuid.UUID, filter domain.ThreadFilter) ([]domain.Thread, error)
+	AddComment(ctx context.Context, comment *domain.Comment) error
+	GetComment(ctx context.Context, threadID, commentID uuid.UUID) (*domain.Comment, error)
+	// DeleteComment removes a comment, and the thread when no comments are left
+	DeleteComment(ctx context.Context, threadID, commentID uuid.UUID) error
+	SetResolved(ctx context.Context, threadID uuid.UUID, resolvedBy *uuid.UUID) error
+	UpdateAnchor(ctx context.Context, thread *domain.Thread) error
+}
+
+// SectionSource reads the current content of the sections of a project
+type SectionSource interface {
+	// Sections returns the project title and the content of every existing section keyed by anchor
+	Sections(ctx context.Context, ideaID uuid.UUID) (string, map[string]string, error)
+}
+
+// UserDirectory resolves comment authors and mentioned users
+type UserDirectory interface {
+	GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
+}
+
+// AccessChecker tells whether a user can read a project
+type AccessChecker interface {
+	CanRead(ctx context.Context, userID, ideaID uuid.UUID) (bool, error)
+}
+
+// MentionNotifier tells users they were mentioned in a comment, as their notification preferences say
+type MentionNotifier interface {
+	NotifyMention(ctx context.Context, user *domain.User, ideaID uuid.UUID, authorName, projectTitle, anchor, excerpt, link string) error
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/comment/domain"
+	"github.com/dark/idea-forge/internal/comment/port"
+)
+
+// maxExcerptLength bounds the comment excerpt sent in mention notifications
+const maxExcerptLength = 300
+
+// CommentUsecase manages comment threads anchored to project sections.
+// Callers must already have read access to the idea (checked by the access guard).
+type CommentUsecase struct {
+	repo        port.CommentRepository
+	sections    port.SectionSource
+	users       port.UserDirectory
+	access      port.AccessChecker
+	notifier    port.MentionNotifier
+	frontendURL string
+}
+
+// NewCommentUsecase creates a new comment use case
+func NewCommentUsecase(repo port.CommentRepository, sections port.SectionSource, users port.UserDirectory, access port.AccessChecker, notifier port.MentionNotifier, frontendURL string) *CommentUsecase {
+	return &CommentUsecase{
+		repo:        repo,
+		sections:    sections,
+		users:       users,
+		access:      access,
+		notifier:    notifier,
+		frontendURL: frontendURL,
+	}
+}
+
+// CreateThreadInput opens a thread on a section, optionally on a text range of it
+type CreateThreadInput struct {
+	Anchor     string `json:"anchor"`
+	RangeStart *int   `json:"range_start"`
+	RangeEnd   *int   `json:"range_end"`
+	Body       string `json:"body"`
+}
+
+// CreateThread opens a thread with its first comment
+func (uc *CommentUsecase) CreateThread(ctx context.Context, userID, ideaID uuid.UUID, in CreateThreadInput) (*domain.Thread, error) {
+	if _, _, err := domain.ParseAnchor(in.Anchor); err != nil {
+		return nil, err
+	}
+	body, err := validateBody(in.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	title, sections, err := uc.sections.Sections(ctx, ideaID)
+	if err != nil {
+		return nil, err
+	}
+	content, ok := sections[in.Anchor]
+	if !ok {
+		return nil, domain.ErrSectionMissing
+	}
+
+	thread := &domain.Thread{
+		ID:        uuid.New(),
+		IdeaID:    ideaID,
+		Anchor:    in.Anchor,
+		CreatedBy: userID,
+	}
+	if in.RangeStart != nil || in.RangeEnd != nil {
+		if in.RangeStart == nil || in.RangeEnd == nil {
+			return nil, domain.ErrInvalidRange
+		}
+		start, end := *in.RangeStart, *in.RangeEnd
+		text := []rune(content)
+		if start < 0 || end > len(text) || start >= end {
+			return nil, domain.ErrInvalidRange
+		}
+		thread.RangeStart, thread.RangeEnd = &start, &end
+		thread.QuotedText = string(text[start:end])
+	}
+
+	comment, err := uc.newComment(ctx, userID, thread.ID, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.CreateThread(ctx, thread, comment); err != nil {
+		return nil, err
+	}
+	thread.Comments = []domain.Comment{*comment}
+
+	uc.notifyMentions(ctx, thread, comment, title)
+	return thread, nil
+}
+
+// ListThreads returns the threads of a project. Threads anchored to a text range
+// are re-anchored against the current content first, so edits made anywhere
+// (forms, agents, propagation) are reflected.
+func (uc *CommentUsecase) ListThreads(ctx context.Context, ideaID uuid.UUID, filter domain.ThreadFilter) ([]domain.Thread, error) {
+	if filter.Anchor != "" {
+		if _, _, err := domain.ParseAnchor(filter.Anchor); err != nil {
+			return nil, err
+		}
+	}
+
+	threads, err := uc.repo.ListThreads(ctx, ideaID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(threads) == 0 {
+		return threads, nil
+	}
+
+	_, sections, err := uc.sections.Sections(ctx, ideaID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range threads {
+		if !threads[i].Reanchor(sections[threads[i].Anchor]) {
+			continue
+		}
+		if err := uc.repo.UpdateAnchor(ctx, &threads[i]); err != nil {
+			return nil, err
+		}
+	}
+	return threads, nil
+}
+
+// Reply adds a comment to a thread
+func (uc *CommentUsecase) Reply(ctx context.Context, userID, ideaID, threadID uuid.UUID, body string) (*domain.Comment, error) {
+	body, err := validateBody(body)
+	if err != nil {
+		return nil, err
+	}
+	thread, err := uc.repo.GetThread(ctx, ideaID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := uc.newComment(ctx, userID, thread.ID, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.AddComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	if len(domain.Mentions(body)) > 0 {
+		title, _, err := uc.sections.Sections(ctx, ideaID)
+		if err != nil {
+			log.Printf("error loading project for mentions: %v", err)
+		}
+		uc.notifyMentions(ctx, thread, comment, title)
+	}
+	return comment, nil
+}
+
+// SetResolved resolves or reopens a thread
+func (uc *CommentUsecase) SetResolved(ctx context.Context, userID, ideaID, threadID uuid.UUID, resolved bool) (*domain.Thread, error) {
+	if _, err := uc.repo.GetThread(ctx, ideaID, threadID); err != nil {
+		return nil, err
+	}
+
+	var resolvedBy *uuid.UUID
+	if resolved {
+		resolvedBy = &userID
+	}
+	if err := uc.repo.SetResolved(ctx, threadID, resolvedBy); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetThread(ctx, ideaID, threadID)
+}
+
+// DeleteComment deletes a comment of the user (and the thread if it was the last one)
+func (uc *CommentUsecase) DeleteComment(ctx context.Context, userID, ideaID, threadID, commentID uuid.UUID) error {
+	if _, err := uc.repo.GetThread(ctx, ideaID, threadID); err != nil {
+		return err
+	}
+	comment, err := uc.repo.GetComment(ctx, threadID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID {
+		return domain.ErrNotAuthor
+	}
+	return uc.repo.DeleteComment(ctx, threadID, commentID)
+}
+
+func (uc *CommentUsecase) newComment(ctx context.Context, userID, threadID uuid.UUID, body string) (*domain.Comment, error) {
+	author, err := uc.users.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Comment{
+		ID:         uuid.New(),
+		ThreadID:   threadID,
+		AuthorID:   userID,
+		AuthorName: author.Username,
+		Body:       body,
+	}, nil
+}
+
+// notifyMentions emails every mentioned user that can read the project.
+// Failures are logged: the comment is already stored.
+func (uc *CommentUsecase) notifyMentions(ctx context.Context, thread *domain.Thread, comment *domain.Comment, projectTitle string) {
+	link := uc.frontendURL + "/ideation/" + thread.IdeaID.String() + "?thread=" + thread.ID.String()
+	excerpt := comment.Body
+	if utf8.RuneCountInString(excerpt) > maxExcerptLength {
+		excerpt = string([]rune(excerpt)[:maxExcerptLength]) + "…"
+	}
+
+	for _, username := range domain.Mentions(comment.Body) {
+		user, err := uc.users.GetUserByUsername(ctx, username)
+		if err != nil || user.ID == comment.AuthorID {
+			continue
+		}
+		// No filtrar contenido del proyecto a usuarios sin acceso
+		ok, err := uc.access.CanRead(ctx, user.ID, thread.IdeaID)
+		if err != nil || !ok {
+			continue
+		}
+		if err := uc.notifier.NotifyMention(ctx, user, thread.IdeaID, comment.AuthorName, projectTitle, thread.Anchor, excerpt, link); err != nil {
+			log.Printf("error sending mention to %s: %v", user.ID, err)
+		}
+	}
+}
+
+func validateBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || len(body) > domain.MaxBodyLength {
+		return "", domain.ErrEmptyBody
+	}
+	return body, nil
+}
@@ -3,7 +3,9 @@ package httpadapter
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,9 +16,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/devmodule/domain"
 	"github.com/dark/idea-forge/internal/devmodule/usecase"
+	"github.com/dark/idea-forge/internal/events"
 	actionplandomain "github.com/dark/idea-forge/internal/actionplan/domain"
 	archdomain "github.com/dark/idea-forge/internal/architecture/domain"
 	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+	"github.com/dark/idea-forge/internal/middleware"
 )
 
 type Handlers struct {
@@ -28,17 +32,17 @@ type Handlers struct {
 		Execute(ctx context.Context, id uuid.UUID) (*ideadomain.Idea, error)
 	}
 	IdeaUpdateUsecase interface {
-		Execute(ctx context.Context, id uuid.UUID, title, objective, problem, scope string, validateCompetition, validateMonetization bool, completed *bool) (*ideadomain.Idea, error)
+		Propagate(ctx context.Context, id uuid.UUID, title, objective, problem, scope, source string) (*ideadomain.Idea, error)
 	}
 	ActionPlanUsecase interface {
 		GetActionPlan(ctx context.Context, id uuid.UUID) (*actionplandomain.ActionPlan, error)
 		GetActionPlanByIdeaID(ctx context.Context, ideaID uuid.UUID) (*actionplandomain.ActionPlan, error)
-		UpdateActionPlan(ctx context.Context, plan *actionplandomain.ActionPlan) error
+		ApplyPropagation(ctx context.Context, plan *actionplandomain.ActionPlan, source string) error
 	}
 	ArchitectureUsecase interface {
 		GetArchitecture(ctx context.Context, id uuid.UUID) (*archdomain.Architecture, error)
 		GetArchitectureByActionPlanID(ctx context.Context, actionPlanID uuid.UUID) (*archdomain.Architecture, error)
-		UpdateArchitecture(ctx context.Context, arch *archdomain.Architecture) error
+		ApplyPropagation(ctx context.Context, arch *archdomain.Architecture, source string) error
 	}
 }
 
@@ -59,14 +63,20 @@ func (h *Handlers) getModulesByArchitecture(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Extract architecture ID from path: /dev-modules/by-architecture/{id}
+	// Extract architecture ID from path: /dev-modules/by-architecture/{id}[/export]
 	archIDStr := strings.TrimPrefix(r.URL.Path, "/dev-modules/by-architecture/")
+	archIDStr, isExport := strings.CutSuffix(archIDStr, "/export")
 	archID, err := uuid.Parse(archIDStr)
 	if err != nil {
 		http.Error(w, "invalid architecture_id", http.StatusBadRequest)
 		return
 	}
 
+	if isExport {
+		h.exportModules(w, r, archID)
+		return
+	}
+
 	modules, err := h.Usecase.GetModulesByArchitectureID(r.Context(), archID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -80,6 +90,23 @@ func (h *Handlers) getModulesByArchitecture(w http.ResponseWriter, r *http.Reque
 	writeJSON(w, modules, http.StatusOK)
 }
 
+// exportModules downloads the modules as a GitHub, Jira or generic CSV backlog
+func (h *Handlers) exportModules(w http.ResponseWriter, r *http.Request, archID uuid.UUID) {
+	export, err := h.Usecase.Export(r.Context(), archID, r.URL.Query().Get("format"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidExportFormat) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	w.Write(export.Content)
+}
+
 func (h *Handlers) handleModule(w http.ResponseWriter, r *http.Request) {
 	// Extract module ID from path: /dev-modules/{id}
 	idStr := strings.TrimPrefix(r.URL.Path, "/dev-modules/")
@@ -196,8 +223,9 @@ func (h *Handlers) handleModules(w http.ResponseWriter, r *http.Request) {
 		Status:         "pending",
 	}
 
-	if err := h.Usecase.CreateModule(r.Context(), module); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	if err := h.Usecase.CreateModule(r.Context(), userID, module); err != nil {
+		writeStageError(w, err)
 		return
 	}
 
@@ -253,6 +281,18 @@ func (h *Handlers) handleGlobalChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Save user message first: it checks the user can change the project
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	userMsg := &domain.GlobalChatMessage{
+		IdeaID:  ideaID,
+		Role:    "user",
+		Content: req.Message,
+	}
+	if err := h.Usecase.AddGlobalMessage(r.Context(), userID, userMsg); err != nil {
+		writeStageError(w, err)
+		return
+	}
+
 	// Get full context
 	idea, err := h.IdeaUsecase.Execute(r.Context(), ideaID)
 	if err != nil {
@@ -273,16 +313,6 @@ func (h *Handlers) handleGlobalChat(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Save user message
-	userMsg := &domain.GlobalChatMessage{
-		IdeaID:  ideaID,
-		Role:    "user",
-		Content: req.Message,
-	}
-	if err := h.Usecase.AddGlobalMessage(r.Context(), userMsg); err != nil {
-		log.Printf("error saving user message: %v", err)
-	}
-
 	// Call Genkit global chat
 	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
 	defer cancel()
@@ -308,7 +338,7 @@ func (h *Handlers) handleGlobalChat(w http.ResponseWriter, r *http.Request) {
 				TechnicalDetails: newMod.TechnicalDetails,
 				Status:           "pending",
 			}
-			if err := h.Usecase.CreateModule(r.Context(), module); err != nil {
+			if err := h.Usecase.CreateModule(events.WithSource(r.Context(), propagationSource), uuid.Nil, module); err != nil {
 				log.Printf("error creating new module: %v", err)
 			} else {
 				affectedModules = append(affectedModules, "dev_modules")
@@ -324,7 +354,7 @@ func (h *Handlers) handleGlobalChat(w http.ResponseWriter, r *http.Request) {
 		Content:         genkitResult.Reply,
 		AffectedModules: string(affectedJSON),
 	}
-	if err := h.Usecase.AddGlobalMessage(r.Context(), assistantMsg); err != nil {
+	if err := h.Usecase.AddGlobalMessage(r.Context(), uuid.Nil, assistantMsg); err != nil {
 		log.Printf("error saving assistant message: %v", err)
 	}
 
@@ -398,6 +428,9 @@ func (h *Handlers) callGenkitGlobalChat(ctx context.Context, idea *ideadomain.Id
 	return &result, nil
 }
 
+// propagationSource identifies changes proposed by the global chat agent
+const propagationSource = "global_chat"
+
 func (h *Handlers) applyPropagations(ctx context.Context, idea *ideadomain.Idea, actionPlan *actionplandomain.ActionPlan, architecture *archdomain.Architecture, result *GenkitGlobalChatResult) []string {
 	var affected []string
 
@@ -407,30 +440,14 @@ func (h *Handlers) applyPropagations(ctx context.Context, idea *ideadomain.Idea,
 
 	// Apply ideation updates
 	if ideation, ok := result.Propagation["ideation"].(map[string]interface{}); ok {
-		title := idea.Title
-		objective := idea.Objective
-		problem := idea.Problem
-		scope := idea.Scope
-		updated := false
-
-		if t, ok := ideation["title"].(string); ok && t != "" {
-			title = t
-			updated = true
-		}
-		if o, ok := ideation["objective"].(string); ok && o != "" {
-			objective = o
-			updated = true
-		}
-		if p, ok := ideation["problem"].(string); ok && p != "" {
-			problem = p
-			updated = true
-		}
-		if s, ok := ideation["scope"].(string); ok && s != "" {
-			scope = s
-			updated = true
-		}
+		title, _ := ideation["title"].(string)
+		objective, _ := ideation["objective"].(string)
+		problem, _ := ideation["problem"].(string)
+		scope, _ := ideation["scope"].(string)
+		updated := title != "" || objective != "" || problem != "" || scope != ""
+
 		if updated && h.IdeaUpdateUsecase != nil {
-			if _, err := h.IdeaUpdateUsecase.Execute(ctx, idea.ID, title, objective, problem, scope, idea.ValidateCompetition, idea.ValidateMonetization, nil); err != nil {
+			if _, err := h.IdeaUpdateUsecase.Propagate(ctx, idea.ID, title, objective, problem, scope, propagationSource); err != nil {
 				log.Printf("error updating idea: %v", err)
 			} else {
 				affected = append(affected, "ideation")
@@ -455,7 +472,7 @@ func (h *Handlers) applyPropagations(ctx context.Context, idea *ideadomain.Idea,
 				updated = true
 			}
 			if updated {
-				if err := h.ActionPlanUsecase.UpdateActionPlan(ctx, actionPlan); err != nil {
+				if err := h.ActionPlanUsecase.ApplyPropagation(ctx, actionPlan, propagationSource); err != nil {
 					log.Printf("error updating action plan: %v", err)
 				} else {
 					affected = append(affected, "action_plan")
@@ -497,7 +514,7 @@ func (h *Handlers) applyPropagations(ctx context.Context, idea *ideadomain.Idea,
 				updated = true
 			}
 			if updated {
-				if err := h.ArchitectureUsecase.UpdateArchitecture(ctx, architecture); err != nil {
+				if err := h.ArchitectureUsecase.ApplyPropagation(ctx, architecture, propagationSource); err != nil {
 					log.Printf("error updating architecture: %v", err)
 				} else {
 					affected = append(affected, "architecture")
@@ -509,6 +526,18 @@ func (h *Handlers) applyPropagations(ctx context.Context, idea *ideadomain.Idea,
 	return affected
 }
 
+// writeStageError maps the errors of changing a body-addressed project
+func writeStageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func writeJSON(w http.ResponseWriter, data interface{}, status int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -8,32 +8,35 @@ import (
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/devmodule/domain"
 	"github.com/dark/idea-forge/internal/devmodule/port"
+	"github.com/dark/idea-forge/internal/events"
 )
 
 type repo struct{ db *sql.DB }
 
 func NewRepo(db *sql.DB) port.DevModuleRepository { return &repo{db: db} }
 
-func (r *repo) Save(ctx context.Context, module *domain.DevelopmentModule) error {
+func (r *repo) Save(ctx context.Context, module *domain.DevelopmentModule, evts ...events.Event) error {
 	now := time.Now()
 	module.CreatedAt = now
 	module.UpdatedAt = now
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO development_modules
-		  (id, architecture_id, name, description, functionality, dependencies, technical_details, priority, status, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
-	`, module.ID, module.ArchitectureID, module.Name, module.Description, module.Functionality, module.Dependencies, module.TechnicalDetails, module.Priority, module.Status, module.CreatedAt, module.UpdatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO development_modules
+			  (id, architecture_id, name, description, functionality, dependencies, technical_details, priority, status, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+		`, module.ID, module.ArchitectureID, module.Name, module.Description, module.Functionality, module.Dependencies, module.TechnicalDetails, module.Priority, module.Status, module.CreatedAt, module.UpdatedAt)
+		return err
+	}, evts...)
 }
 
-func (r *repo) SaveBatch(ctx context.Context, modules []domain.DevelopmentModule) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+func (r *repo) SaveBatch(ctx context.Context, modules []domain.DevelopmentModule, evts ...events.Event) error {
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		return insertModules(ctx, tx, modules)
+	}, evts...)
+}
 
+func insertModules(ctx context.Context, tx *sql.Tx, modules []domain.DevelopmentModule) error {
 	stmt, err := tx.PrepareContext(ctx, `
 		INSERT INTO development_modules
 		  (id, architecture_id, name, description, functionality, dependencies, technical_details, priority, status, created_at, updated_at)
@@ -56,8 +59,7 @@ func (r *repo) SaveBatch(ctx context.Context, modules []domain.DevelopmentModule
 			return err
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 func (r *repo) FindByID(ctx context.Context, id uuid.UUID) (*domain.DevelopmentModule, error) {
@@ -97,37 +99,58 @@ func (r *repo) FindByArchitectureID(ctx context.Context, architectureID uuid.UUI
 	return modules, rows.Err()
 }
 
-func (r *repo) Update(ctx context.Context, module *domain.DevelopmentModule) error {
+func (r *repo) Update(ctx context.Context, module *domain.DevelopmentModule, evts ...events.Event) error {
 	module.UpdatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, `
-		UPDATE development_modules
-		   SET name=$2, description=$3, functionality=$4, dependencies=$5, technical_details=$6, priority=$7, status=$8, updated_at=$9
-		 WHERE id=$1
-	`, module.ID, module.Name, module.Description, module.Functionality, module.Dependencies, module.TechnicalDetails, module.Priority, module.Status, module.UpdatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE development_modules
+			   SET name=$2, description=$3, functionality=$4, dependencies=$5, technical_details=$6, priority=$7, status=$8, updated_at=$9
+			 WHERE id=$1
+		`, module.ID, module.Name, module.Description, module.Functionality, module.Dependencies, module.TechnicalDetails, module.Priority, module.Status, module.UpdatedAt)
+		return err
+	}, evts...)
 }
 
-func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM development_modules WHERE id=$1`, id)
-	return err
+func (r *repo) FindIdeaID(ctx context.Context, architectureID uuid.UUID) (uuid.UUID, error) {
+	var ideaID uuid.UUID
+	err := r.db.QueryRowContext(ctx, `
+		SELECT ap.idea_id
+		  FROM architectures a
+		  JOIN action_plans ap ON ap.id = a.action_plan_id
+		 WHERE a.id=$1
+	`, architectureID).Scan(&ideaID)
+	return ideaID, err
 }
 
-func (r *repo) DeleteByArchitectureID(ctx context.Context, architectureID uuid.UUID) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM development_modules WHERE architecture_id=$1`, architectureID)
-	return err
+func (r *repo) Delete(ctx context.Context, id uuid.UUID, evts ...events.Event) error {
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM development_modules WHERE id=$1`, id)
+		return err
+	}, evts...)
+}
+
+func (r *repo) ReplaceByArchitectureID(ctx context.Context, architectureID uuid.UUID, modules []domain.DevelopmentModule, evts ...events.Event) error {
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM development_modules WHERE architecture_id=$1`, architectureID); err != nil {
+			return err
+		}
+		return insertModules(ctx, tx, modules)
+	}, evts...)
 }
 
 // Global Chat Messages
 
-func (r *repo) SaveMessage(ctx context.Context, msg *domain.GlobalChatMessage) error {
+func (r *repo) SaveMessage(ctx context.Context, msg *domain.GlobalChatMessage, evts ...events.Event) error {
 	msg.CreatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO global_chat_messages (id, idea_id, role, content, affected_modules, created_at)
-		VALUES ($1,$2,$3,$4,$5,$6)
-	`, msg.ID, msg.IdeaID, msg.Role, msg.Content, msg.AffectedModules, msg.CreatedAt)
-	return err
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO global_chat_messages (id, idea_id, role, content, affected_modules, created_at)
+			VALUES ($1,$2,$3,$4,$5,$6)
+		`, msg.ID, msg.IdeaID, msg.Role, msg.Content, msg.AffectedModules, msg.CreatedAt)
+		return err
+	}, evts...)
 }
 
 func (r *repo) ListMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]domain.GlobalChatMessage, error) {
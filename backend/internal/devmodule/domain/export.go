@@ -0,0 +1,19 @@
+package domain
+
+import "errors"
+
+// Export formats for the modules of an architecture
+const (
+	ExportGitHub = "github" // JSON array of GitHub issues
+	ExportJira   = "jira"   // CSV for the Jira importer
+	ExportCSV    = "csv"    // generic CSV
+)
+
+var ErrInvalidExportFormat = errors.New("format must be github, jira or csv")
+
+// Export is a rendered module backlog ready to be downloaded
+type Export struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
@@ -1,11 +1,15 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrForbidden is returned when the user cannot change the project
+var ErrForbidden = errors.New("forbidden")
+
 // DevelopmentModule represents a module to be developed for the project
 type DevelopmentModule struct {
 	ID               uuid.UUID `json:"id" db:"id"`
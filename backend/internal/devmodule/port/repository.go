@@ -5,20 +5,30 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/devmodule/domain"
+	"github.com/dark/idea-forge/internal/events"
 )
 
-// DevModuleRepository defines the interface for development module data persistence
+// DevModuleRepository defines the interface for development module data persistence.
+// Mutations take the domain events they produce so both commit together.
 type DevModuleRepository interface {
 	// Development Module operations
-	Save(ctx context.Context, module *domain.DevelopmentModule) error
-	SaveBatch(ctx context.Context, modules []domain.DevelopmentModule) error
+	Save(ctx context.Context, module *domain.DevelopmentModule, evts ...events.Event) error
+	SaveBatch(ctx context.Context, modules []domain.DevelopmentModule, evts ...events.Event) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.DevelopmentModule, error)
 	FindByArchitectureID(ctx context.Context, architectureID uuid.UUID) ([]domain.DevelopmentModule, error)
-	Update(ctx context.Context, module *domain.DevelopmentModule) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	DeleteByArchitectureID(ctx context.Context, architectureID uuid.UUID) error
+	Update(ctx context.Context, module *domain.DevelopmentModule, evts ...events.Event) error
+	Delete(ctx context.Context, id uuid.UUID, evts ...events.Event) error
+	// ReplaceByArchitectureID deletes every module of the architecture and inserts modules
+	ReplaceByArchitectureID(ctx context.Context, architectureID uuid.UUID, modules []domain.DevelopmentModule, evts ...events.Event) error
+	// FindIdeaID returns the idea an architecture belongs to (through its action plan)
+	FindIdeaID(ctx context.Context, architectureID uuid.UUID) (uuid.UUID, error)
 
 	// Global Chat operations
-	SaveMessage(ctx context.Context, msg *domain.GlobalChatMessage) error
+	SaveMessage(ctx context.Context, msg *domain.GlobalChatMessage, evts ...events.Event) error
 	ListMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]domain.GlobalChatMessage, error)
 }
+
+// AccessChecker tells whether a user can change a project
+type AccessChecker interface {
+	CanWrite(ctx context.Context, userID, ideaID uuid.UUID) (bool, error)
+}
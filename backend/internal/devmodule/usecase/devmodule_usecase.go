@@ -6,36 +6,54 @@ import (
 	"github.com/google/uuid"
 	"github.com/dark/idea-forge/internal/devmodule/domain"
 	"github.com/dark/idea-forge/internal/devmodule/port"
+	"github.com/dark/idea-forge/internal/events"
 )
 
 // DevModuleUsecase handles business logic for development modules
 type DevModuleUsecase struct {
-	repo port.DevModuleRepository
+	repo   port.DevModuleRepository
+	access port.AccessChecker
 }
 
 // NewDevModuleUsecase creates a new development module use case
-func NewDevModuleUsecase(repo port.DevModuleRepository) *DevModuleUsecase {
-	return &DevModuleUsecase{repo: repo}
+func NewDevModuleUsecase(repo port.DevModuleRepository, access port.AccessChecker) *DevModuleUsecase {
+	return &DevModuleUsecase{repo: repo, access: access}
 }
 
-// CreateModule creates a new development module
-func (uc *DevModuleUsecase) CreateModule(ctx context.Context, module *domain.DevelopmentModule) error {
+// CreateModule creates a new development module. userID needs write access
+// to the idea (domain.ErrForbidden otherwise); uuid.Nil creates it on behalf
+// of the agent.
+func (uc *DevModuleUsecase) CreateModule(ctx context.Context, userID uuid.UUID, module *domain.DevelopmentModule) error {
+	ideaID, err := uc.repo.FindIdeaID(ctx, module.ArchitectureID)
+	if err != nil {
+		return err
+	}
+	if err := uc.authorize(ctx, userID, ideaID); err != nil {
+		return err
+	}
 	module.ID = uuid.New()
 	if module.Status == "" {
 		module.Status = "pending"
 	}
-	return uc.repo.Save(ctx, module)
+	return uc.repo.Save(ctx, module, events.ModuleCreated{IdeaID: ideaID, Module: summary(module)})
 }
 
-// CreateModules creates multiple development modules at once
+// CreateModules creates the modules generated for an architecture at once
 func (uc *DevModuleUsecase) CreateModules(ctx context.Context, modules []domain.DevelopmentModule) error {
-	for i := range modules {
-		modules[i].ID = uuid.New()
-		if modules[i].Status == "" {
-			modules[i].Status = "pending"
-		}
+	if len(modules) == 0 {
+		return nil
 	}
-	return uc.repo.SaveBatch(ctx, modules)
+	prepare(modules)
+	architectureID := modules[0].ArchitectureID
+	ideaID, err := uc.repo.FindIdeaID(ctx, architectureID)
+	if err != nil {
+		return err
+	}
+	return uc.repo.SaveBatch(ctx, modules, events.ModulesGenerated{
+		IdeaID:         ideaID,
+		ArchitectureID: architectureID,
+		Modules:        summaries(modules),
+	})
 }
 
 // GetModule retrieves a development module by ID
@@ -50,40 +68,116 @@ func (uc *DevModuleUsecase) GetModulesByArchitectureID(ctx context.Context, arch
 
 // UpdateModule updates an existing development module
 func (uc *DevModuleUsecase) UpdateModule(ctx context.Context, module *domain.DevelopmentModule) error {
-	return uc.repo.Update(ctx, module)
+	previous, err := uc.repo.FindByID(ctx, module.ID)
+	if err != nil {
+		return err
+	}
+	ideaID, err := uc.repo.FindIdeaID(ctx, previous.ArchitectureID)
+	if err != nil {
+		return err
+	}
+
+	evts := []events.Event{events.ModuleUpdated{IdeaID: ideaID, Module: summary(module)}}
+	if module.Status != previous.Status {
+		evts = append(evts, events.ModuleStatusChanged{
+			IdeaID:         ideaID,
+			ModuleID:       module.ID,
+			Name:           module.Name,
+			PreviousStatus: previous.Status,
+			Status:         module.Status,
+		})
+	}
+	return uc.repo.Update(ctx, module, evts...)
 }
 
 // DeleteModule deletes a development module
 func (uc *DevModuleUsecase) DeleteModule(ctx context.Context, id uuid.UUID) error {
-	return uc.repo.Delete(ctx, id)
+	module, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	ideaID, err := uc.repo.FindIdeaID(ctx, module.ArchitectureID)
+	if err != nil {
+		return err
+	}
+	return uc.repo.Delete(ctx, id, events.ModuleDeleted{IdeaID: ideaID, ModuleID: id, Name: module.Name})
 }
 
 // ReplaceModules deletes all existing modules for an architecture and creates new ones
 func (uc *DevModuleUsecase) ReplaceModules(ctx context.Context, architectureID uuid.UUID, modules []domain.DevelopmentModule) error {
-	// Delete existing modules
-	if err := uc.repo.DeleteByArchitectureID(ctx, architectureID); err != nil {
+	for i := range modules {
+		modules[i].ArchitectureID = architectureID
+	}
+	prepare(modules)
+
+	ideaID, err := uc.repo.FindIdeaID(ctx, architectureID)
+	if err != nil {
 		return err
 	}
+	return uc.repo.ReplaceByArchitectureID(ctx, architectureID, modules, events.ModulesReplaced{
+		IdeaID:         ideaID,
+		ArchitectureID: architectureID,
+		Modules:        summaries(modules),
+	})
+}
 
-	// Create new modules
-	if len(modules) > 0 {
-		for i := range modules {
-			modules[i].ArchitectureID = architectureID
+// prepare assigns IDs and the default status to new modules
+func prepare(modules []domain.DevelopmentModule) {
+	for i := range modules {
+		modules[i].ID = uuid.New()
+		if modules[i].Status == "" {
+			modules[i].Status = "pending"
 		}
-		return uc.CreateModules(ctx, modules)
 	}
-	return nil
+}
+
+func summary(m *domain.DevelopmentModule) events.Module {
+	return events.Module{ID: m.ID, Name: m.Name, Status: m.Status, Priority: m.Priority}
+}
+
+func summaries(modules []domain.DevelopmentModule) []events.Module {
+	out := make([]events.Module, len(modules))
+	for i := range modules {
+		out[i] = summary(&modules[i])
+	}
+	return out
 }
 
 // Global Chat Messages
 
-// AddGlobalMessage adds a message to the global chat
-func (uc *DevModuleUsecase) AddGlobalMessage(ctx context.Context, msg *domain.GlobalChatMessage) error {
+// AddGlobalMessage adds a message to the global chat. userID is the author
+// and needs write access (domain.ErrForbidden otherwise); uuid.Nil posts on
+// behalf of the agent.
+func (uc *DevModuleUsecase) AddGlobalMessage(ctx context.Context, userID uuid.UUID, msg *domain.GlobalChatMessage) error {
+	if err := uc.authorize(ctx, userID, msg.IdeaID); err != nil {
+		return err
+	}
 	msg.ID = uuid.New()
-	return uc.repo.SaveMessage(ctx, msg)
+	return uc.repo.SaveMessage(ctx, msg, events.ChatMessagePosted{
+		IdeaID:    msg.IdeaID,
+		Stage:     events.StageGlobal,
+		MessageID: msg.ID,
+		Role:      msg.Role,
+		Content:   msg.Content,
+	})
 }
 
 // GetGlobalMessages retrieves global chat messages for an idea
 func (uc *DevModuleUsecase) GetGlobalMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]domain.GlobalChatMessage, error) {
 	return uc.repo.ListMessages(ctx, ideaID, limit)
 }
+
+// authorize checks the user can change the idea; uuid.Nil is the system itself
+func (uc *DevModuleUsecase) authorize(ctx context.Context, userID, ideaID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return nil
+	}
+	ok, err := uc.access.CanWrite(ctx, userID, ideaID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
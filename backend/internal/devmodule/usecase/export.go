@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/devmodule/domain"
+)
+
+// githubIssue is the payload of GitHub's create issue API
+type githubIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+}
+
+// jiraStatuses maps module statuses to Jira's default workflow
+var jiraStatuses = map[string]string{
+	"pending":     "To Do",
+	"in_progress": "In Progress",
+	"completed":   "Done",
+}
+
+// Export renders the modules of an architecture as an issue tracker backlog,
+// ordered by priority. Dependencies become "blocked by" links and the
+// functionality and technical details form the issue body.
+func (uc *DevModuleUsecase) Export(ctx context.Context, architectureID uuid.UUID, format string) (*domain.Export, error) {
+	if format == "" {
+		format = domain.ExportGitHub
+	}
+	if format != domain.ExportGitHub && format != domain.ExportJira && format != domain.ExportCSV {
+		return nil, domain.ErrInvalidExportFormat
+	}
+
+	modules, err := uc.repo.FindByArchitectureID(ctx, architectureID)
+	if err != nil {
+		return nil, err
+	}
+	modules = slices.Clone(modules)
+	slices.SortStableFunc(modules, func(a, b domain.DevelopmentModule) int { return a.Priority - b.Priority })
+
+	switch format {
+	case domain.ExportJira:
+		content, err := jiraCSV(modules)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.Export{Filename: "dev-modules-jira.csv", ContentType: "text/csv; charset=utf-8", Content: content}, nil
+	case domain.ExportCSV:
+		content, err := genericCSV(modules)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.Export{Filename: "dev-modules.csv", ContentType: "text/csv; charset=utf-8", Content: content}, nil
+	}
+
+	issues := make([]githubIssue, len(modules))
+	for i := range modules {
+		m := &modules[i]
+		issues[i] = githubIssue{
+			Title:  m.Name,
+			Body:   issueBody(m, blockedBy(m, modules)),
+			Labels: []string{"status:" + m.Status},
+		}
+	}
+	content, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Export{Filename: "dev-modules-github.json", ContentType: "application/json", Content: content}, nil
+}
+
+// jiraCSV writes the columns the Jira CSV importer maps automatically. Issue
+// ids are the ranks, so the "Inward issue link (Blocks)" columns, one per
+// blocking module, link to rows of the same file; rows keep the rank order.
+func jiraCSV(modules []domain.DevelopmentModule) ([]byte, error) {
+	links := make([][]int, len(modules))
+	width := 0
+	for i := range modules {
+		for _, dep := range blockedBy(&modules[i], modules) {
+			if j := moduleIndex(modules, dep); j >= 0 {
+				links[i] = append(links[i], j+1)
+			}
+		}
+		width = max(width, len(links[i]))
+	}
+
+	header := []string{"Issue id", "Summary", "Issue Type", "Status", "Description"}
+	for range width {
+		header = append(header, "Inward issue link (Blocks)")
+	}
+	rows := [][]string{header}
+	for i := range modules {
+		m := &modules[i]
+		status := jiraStatuses[m.Status]
+		if status == "" {
+			status = jiraStatuses["pending"]
+		}
+		row := []string{strconv.Itoa(i + 1), m.Name, "Task", status, jiraDescription(m, blockedBy(m, modules))}
+		for k := range width {
+			id := ""
+			if k < len(links[i]) {
+				id = strconv.Itoa(links[i][k])
+			}
+			row = append(row, id)
+		}
+		rows = append(rows, row)
+	}
+	return writeCSV(rows)
+}
+
+// genericCSV writes one row per module with its rank and blocking modules
+func genericCSV(modules []domain.DevelopmentModule) ([]byte, error) {
+	rows := [][]string{{"rank", "name", "status", "description", "functionality", "technical_details", "blocked_by"}}
+	for i := range modules {
+		m := &modules[i]
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1), m.Name, m.Status, m.Description, m.Functionality, m.TechnicalDetails,
+			strings.Join(blockedBy(m, modules), "; "),
+		})
+	}
+	return writeCSV(rows)
+}
+
+func writeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// issueBody renders a module as a Markdown issue description
+func issueBody(m *domain.DevelopmentModule, blockers []string) string {
+	var b strings.Builder
+	if d := strings.TrimSpace(m.Description); d != "" {
+		b.WriteString(d + "\n\n")
+	}
+	writeBodySection(&b, "## ", "Funcionalidad", m.Functionality)
+	writeBodySection(&b, "## ", "Detalles técnicos", m.TechnicalDetails)
+	if len(blockers) > 0 {
+		b.WriteString("## Bloqueado por\n\n")
+		for _, dep := range blockers {
+			fmt.Fprintf(&b, "- %s\n", dep)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// jiraDescription renders a module with Jira wiki headings; the blockers are
+// also linked, but the names keep the description readable on its own
+func jiraDescription(m *domain.DevelopmentModule, blockers []string) string {
+	var b strings.Builder
+	if d := strings.TrimSpace(m.Description); d != "" {
+		b.WriteString(d + "\n\n")
+	}
+	writeBodySection(&b, "h3. ", "Funcionalidad", m.Functionality)
+	writeBodySection(&b, "h3. ", "Detalles técnicos", m.TechnicalDetails)
+	if len(blockers) > 0 {
+		b.WriteString("h3. Bloqueado por\n\n")
+		for _, dep := range blockers {
+			fmt.Fprintf(&b, "* %s\n", dep)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func writeBodySection(b *strings.Builder, prefix, title, content string) {
+	if content = strings.TrimSpace(content); content != "" {
+		fmt.Fprintf(b, "%s%s\n\n%s\n\n", prefix, title, content)
+	}
+}
+
+// blockedBy returns the names of the modules m depends on. Dependencies are
+// module names, or IDs in older rows; IDs of known modules become names.
+func blockedBy(m *domain.DevelopmentModule, modules []domain.DevelopmentModule) []string {
+	var names []string
+	for _, dep := range parseDependencies(m.Dependencies) {
+		for _, other := range modules {
+			if other.ID.String() == dep {
+				dep = other.Name
+				break
+			}
+		}
+		if dep != m.Name && !slices.Contains(names, dep) {
+			names = append(names, dep)
+		}
+	}
+	return names
+}
+
+func moduleIndex(modules []domain.DevelopmentModule, name string) int {
+	for i := range modules {
+		if strings.EqualFold(modules[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseDependencies reads the JSON array stored in Dependencies, falling back
+// to the legacy comma separated text
+func parseDependencies(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	var deps []string
+	if err := json.Unmarshal([]byte(raw), &deps); err != nil {
+		deps = strings.Split(raw, ",")
+	}
+	out := deps[:0]
+	for _, d := range deps {
+		if d = strings.TrimSpace(d); d != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/devmodule/domain"
+	"github.com/dark/idea-forge/internal/devmodule/port"
+)
+
+// storedModules serves the modules of one architecture
+type storedModules struct {
+	port.DevModuleRepository
+	modules []domain.DevelopmentModule
+}
+
+func (r storedModules) FindByArchitectureID(ctx context.Context, architectureID uuid.UUID) ([]domain.DevelopmentModule, error) {
+	return r.modules, nil
+}
+
+// backlog returns modules stored out of priority order, with a dependency by
+// ID (older rows), one on an unknown module, a duplicate and a self reference
+func backlog() []domain.DevelopmentModule {
+	auth := uuid.New()
+	return []domain.DevelopmentModule{
+		{ID: uuid.New(), Name: "web", Status: "pending", Priority: 3, Description: "Frontend, \"SPA\"",
+			Functionality: "Pantallas\ncon saltos de línea", Dependencies: `["api", "api", "web", "analytics"]`},
+		{ID: auth, Name: "auth", Status: "completed", Priority: 1, Description: "Login", Dependencies: `[]`},
+		{ID: uuid.New(), Name: "api", Status: "in_progress", Priority: 2, TechnicalDetails: "Go; REST", Dependencies: auth.String()},
+		{ID: uuid.New(), Name: "docs", Status: "archived", Priority: 4},
+	}
+}
+
+func exportBacklog(t *testing.T, format string) *domain.Export {
+	t.Helper()
+	uc := NewDevModuleUsecase(storedModules{modules: backlog()}, nil)
+	export, err := uc.Export(context.Background(), uuid.New(), format)
+	if err != nil {
+		t.Fatalf("Export(%q): %v", format, err)
+	}
+	return export
+}
+
+func readCSV(t *testing.T, content []byte) [][]string {
+	t.Helper()
+	rows, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	return rows
+}
+
+func TestExportFormats(t *testing.T) {
+	tests := []struct {
+		format   string
+		filename string
+		wantErr  error
+	}{
+		{format: "", filename: "dev-modules-github.json"},
+		{format: "github", filename: "dev-modules-github.json"},
+		{format: "jira", filename: "dev-modules-jira.csv"},
+		{format: "csv", filename: "dev-modules.csv"},
+		{format: "trello", wantErr: domain.ErrInvalidExportFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			uc := NewDevModuleUsecase(storedModules{modules: backlog()}, nil)
+			export, err := uc.Export(context.Background(), uuid.New(), tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Export err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && export.Filename != tt.filename {
+				t.Errorf("filename = %q, want %q", export.Filename, tt.filename)
+			}
+		})
+	}
+}
+
+func TestExportEmptyBacklog(t *testing.T) {
+	for _, format := range []string{"github", "jira", "csv"} {
+		uc := NewDevModuleUsecase(storedModules{}, nil)
+		export, err := uc.Export(context.Background(), uuid.New(), format)
+		if err != nil {
+			t.Fatalf("Export(%q): %v", format, err)
+		}
+		if format == "github" && string(export.Content) != "[]" {
+			t.Errorf("github export of no modules = %s, want []", export.Content)
+		}
+		if format != "github" && len(readCSV(t, export.Content)) != 1 {
+			t.Errorf("%s export of no modules should only hold the header", format)
+		}
+	}
+}
+
+func TestGitHubExport(t *testing.T) {
+	var issues []githubIssue
+	if err := json.Unmarshal(exportBacklog(t, "github").Content, &issues); err != nil {
+		t.Fatalf("decoding issues: %v", err)
+	}
+
+	var titles []string
+	for _, issue := range issues {
+		titles = append(titles, issue.Title)
+	}
+	if want := []string{"auth", "api", "web", "docs"}; !reflect.DeepEqual(titles, want) {
+		t.Fatalf("issues = %v, want priority order %v", titles, want)
+	}
+	if !reflect.DeepEqual(issues[1].Labels, []string{"status:in_progress"}) {
+		t.Errorf("labels = %v", issues[1].Labels)
+	}
+	// The dependency stored as an ID is shown by name
+	if !strings.HasSuffix(issues[1].Body, "## Bloqueado por\n\n- auth") {
+		t.Errorf("api body = %q", issues[1].Body)
+	}
+	web := issues[2].Body
+	if strings.Count(web, "- api") != 1 || strings.Contains(web, "- web") || !strings.Contains(web, "- analytics") {
+		t.Errorf("web blockers should list api once, analytics and not itself:\n%s", web)
+	}
+	if issues[3].Body != "" {
+		t.Errorf("module without content has body %q", issues[3].Body)
+	}
+}
+
+func TestJiraExport(t *testing.T) {
+	rows := readCSV(t, exportBacklog(t, "jira").Content)
+
+	// web is blocked by api and analytics, but only api is in the file: one link column
+	wantHeader := []string{"Issue id", "Summary", "Issue Type", "Status", "Description", "Inward issue link (Blocks)"}
+	if !reflect.DeepEqual(rows[0], wantHeader) {
+		t.Fatalf("header = %v", rows[0])
+	}
+	tests := []struct {
+		id, summary, status, blockedBy string
+	}{
+		{"1", "auth", "Done", ""},
+		{"2", "api", "In Progress", "1"},
+		{"3", "web", "To Do", "2"},
+		{"4", "docs", "To Do", ""}, // unknown statuses fall back to To Do
+	}
+	for i, tt := range tests {
+		row := rows[i+1]
+		if len(row) != len(wantHeader) {
+			t.Fatalf("row %d has %d columns, want %d", i+1, len(row), len(wantHeader))
+		}
+		if row[0] != tt.id || row[1] != tt.summary || row[3] != tt.status || row[5] != tt.blockedBy {
+			t.Errorf("row %d = %v, want %+v", i+1, row, tt)
+		}
+	}
+	if desc := rows[3][4]; !strings.Contains(desc, "h3. Funcionalidad\n\nPantallas\ncon saltos de línea") || !strings.Contains(desc, "* analytics") {
+		t.Errorf("web description = %q", desc)
+	}
+}
+
+func TestGenericCSVExport(t *testing.T) {
+	rows := readCSV(t, exportBacklog(t, "csv").Content)
+
+	want := [][]string{
+		{"rank", "name", "status", "description", "functionality", "technical_details", "blocked_by"},
+		{"1", "auth", "completed", "Login", "", "", ""},
+		{"2", "api", "in_progress", "", "", "Go; REST", "auth"},
+		{"3", "web", "pending", "Frontend, \"SPA\"", "Pantallas\ncon saltos de línea", "", "api; analytics"},
+		{"4", "docs", "archived", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows:\n%q\nwant:\n%q", rows, want)
+	}
+}
+
+func TestParseDependencies(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"", nil},
+		{"null", nil},
+		{"[]", []string{}},
+		{`["api", " web ", ""]`, []string{"api", "web"}},
+		{"api, web,,", []string{"api", "web"}},
+		{`["unterminated`, []string{`["unterminated`}},
+	}
+	for _, tt := range tests {
+		got := parseDependencies(tt.raw)
+		if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("parseDependencies(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"slices"
+	"time"
+)
+
+const (
+	pollInterval = time.Second
+	claimBatch   = 100
+	claimLease   = time.Minute
+	maxAttempts  = 10
+)
+
+// Handler processes one event. Events can be delivered more than once, so handlers
+// should tolerate duplicates; returning an error retries the event for that handler only.
+type Handler func(ctx context.Context, r Record) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+type claimed struct {
+	record   Record
+	attempts int
+	pending  []string // subscribers that failed on a previous attempt (nil = all)
+}
+
+// Dispatcher delivers the outbox to in-process subscribers. Every API instance
+// can run one: records are claimed with a lease so each is dispatched once.
+type Dispatcher struct {
+	outbox *pgOutbox
+	subs   []subscriber
+}
+
+func NewDispatcher(db *sql.DB) *Dispatcher {
+	return &Dispatcher{outbox: &pgOutbox{db: db}}
+}
+
+// Subscribe registers a handler under a unique name. Call it before Run.
+func (d *Dispatcher) Subscribe(name string, h Handler) {
+	d.subs = append(d.subs, subscriber{name: name, handler: h})
+}
+
+// Run dispatches new events until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		for d.dispatchBatch(ctx) == claimBatch {
+			// keep draining a backlog without waiting for the ticker
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchBatch returns how many records it claimed
+func (d *Dispatcher) dispatchBatch(ctx context.Context) int {
+	batch, err := d.outbox.claim(ctx, claimBatch, claimLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("events: error claiming outbox: %v", err)
+		}
+		return 0
+	}
+	for _, c := range batch {
+		d.dispatch(ctx, c)
+	}
+	return len(batch)
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, c claimed) {
+	var failed []string
+	var errs []error
+	for _, s := range d.subs {
+		if c.pending != nil && !slices.Contains(c.pending, s.name) {
+			continue
+		}
+		if err := s.handler(ctx, c.record); err != nil {
+			failed = append(failed, s.name)
+			errs = append(errs, errors.New(s.name+": "+err.Error()))
+		}
+	}
+
+	if len(failed) == 0 {
+		if err := d.outbox.markDispatched(ctx, c.record.Seq); err != nil {
+			log.Printf("events: error settling %s %s: %v", c.record.Type, c.record.ID, err)
+		}
+		return
+	}
+
+	lastError := errors.Join(errs...).Error()
+	var retryAt *time.Time
+	if c.attempts < maxAttempts {
+		t := time.Now().Add(retryDelay(c.attempts))
+		retryAt = &t
+	}
+	log.Printf("events: %s %s failed (attempt %d): %s", c.record.Type, c.record.ID, c.attempts, lastError)
+	if err := d.outbox.markFailed(ctx, c.record.Seq, failed, lastError, retryAt); err != nil {
+		log.Printf("events: error settling %s %s: %v", c.record.Type, c.record.ID, err)
+	}
+}
+
+// retryDelay doubles from 5s up to 10m
+func retryDelay(attempts int) time.Duration {
+	delay := 5 * time.Second
+	for i := 1; i < attempts && delay < 10*time.Minute; i++ {
+		delay *= 2
+	}
+	return min(delay, 10*time.Minute)
+}
@@ -0,0 +1,216 @@
+// Package events defines the domain events recorded for every change to a project
+// and the outbox that delivers them.
+//
+// Repositories append events to the outbox table in the same transaction as the
+// change they describe, so an event exists if and only if the change was
+// committed. The Dispatcher then reads the outbox in order and hands every
+// event to the subscribers (real time, webhooks, ...) at least once.
+package events
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Event is a fact about a project
+type Event interface {
+	// EventType is the public name of the event, e.g. "idea.completed"
+	EventType() string
+	// ProjectID is the idea the event belongs to
+	ProjectID() uuid.UUID
+}
+
+// Stages of a project, as used in event names and payloads
+const (
+	StageIdea         = "idea"
+	StageActionPlan   = "action_plan"
+	StageArchitecture = "architecture"
+	StageGlobal       = "global" // global chat
+)
+
+// IdeaUpdated: the idea was edited
+type IdeaUpdated struct {
+	IdeaID    uuid.UUID `json:"idea_id"`
+	Title     string    `json:"title"`
+	Objective string    `json:"objective"`
+	Problem   string    `json:"problem"`
+	Scope     string    `json:"scope"`
+	Completed bool      `json:"completed"`
+}
+
+// ProjectImported: a whole project (idea, stages, modules and chats) was
+// created from an exported bundle
+type ProjectImported struct {
+	IdeaID  uuid.UUID `json:"idea_id"`
+	Title   string    `json:"title"`
+	Modules int       `json:"modules"`
+}
+
+// IdeaCompleted: the idea was marked as completed
+type IdeaCompleted struct {
+	IdeaID uuid.UUID `json:"idea_id"`
+	Title  string    `json:"title"`
+}
+
+// IdeaDeleted: the idea and every stage derived from it were deleted
+type IdeaDeleted struct {
+	IdeaID uuid.UUID `json:"idea_id"`
+}
+
+// StageCreated: an action plan or architecture was started
+type StageCreated struct {
+	IdeaID  uuid.UUID `json:"idea_id"`
+	Stage   string    `json:"stage"`
+	StageID uuid.UUID `json:"stage_id"`
+}
+
+// SectionEdited: the content of a stage section changed
+type SectionEdited struct {
+	IdeaID  uuid.UUID `json:"idea_id"`
+	Stage   string    `json:"stage"`
+	StageID uuid.UUID `json:"stage_id"`
+	Section string    `json:"section"`
+	Content string    `json:"content"`
+}
+
+// StageStatusChanged: the status or completed flag of an action plan or architecture changed
+type StageStatusChanged struct {
+	IdeaID    uuid.UUID `json:"idea_id"`
+	Stage     string    `json:"stage"`
+	StageID   uuid.UUID `json:"stage_id"`
+	Status    string    `json:"status"`
+	Completed bool      `json:"completed"`
+}
+
+// ArchitectureGenerated: the AI filled in a new architecture
+type ArchitectureGenerated struct {
+	IdeaID         uuid.UUID `json:"idea_id"`
+	ArchitectureID uuid.UUID `json:"architecture_id"`
+}
+
+// Module summarizes a development module inside events
+type Module struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Status   string    `json:"status"`
+	Priority int       `json:"priority"`
+}
+
+// ModulesGenerated: the AI proposed the development modules of an architecture
+type ModulesGenerated struct {
+	IdeaID         uuid.UUID `json:"idea_id"`
+	ArchitectureID uuid.UUID `json:"architecture_id"`
+	Modules        []Module  `json:"modules"`
+}
+
+// ModulesReplaced: every module of an architecture was replaced
+type ModulesReplaced struct {
+	IdeaID         uuid.UUID `json:"idea_id"`
+	ArchitectureID uuid.UUID `json:"architecture_id"`
+	Modules        []Module  `json:"modules"`
+}
+
+// ModuleCreated: a module was added by hand
+type ModuleCreated struct {
+	IdeaID uuid.UUID `json:"idea_id"`
+	Module Module    `json:"module"`
+}
+
+// ModuleUpdated: a module was edited
+type ModuleUpdated struct {
+	IdeaID uuid.UUID `json:"idea_id"`
+	Module Module    `json:"module"`
+}
+
+// ModuleDeleted: a module was removed
+type ModuleDeleted struct {
+	IdeaID   uuid.UUID `json:"idea_id"`
+	ModuleID uuid.UUID `json:"module_id"`
+	Name     string    `json:"name"`
+}
+
+// ModuleStatusChanged: a module moved between pending, in_progress and completed
+type ModuleStatusChanged struct {
+	IdeaID         uuid.UUID `json:"idea_id"`
+	ModuleID       uuid.UUID `json:"module_id"`
+	Name           string    `json:"name"`
+	PreviousStatus string    `json:"previous_status"`
+	Status         string    `json:"status"`
+}
+
+// ChatMessagePosted: a message was added to one of the project chats
+type ChatMessagePosted struct {
+	IdeaID    uuid.UUID `json:"idea_id"`
+	Stage     string    `json:"stage"`
+	MessageID uuid.UUID `json:"message_id"`
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+}
+
+// PropagationApplied: a change made elsewhere (global chat, architecture agent)
+// was propagated into the sections of a stage
+type PropagationApplied struct {
+	IdeaID   uuid.UUID `json:"idea_id"`
+	Source   string    `json:"source"`
+	Stage    string    `json:"stage"`
+	StageID  uuid.UUID `json:"stage_id"`
+	Sections []string  `json:"sections"`
+}
+
+func (e IdeaUpdated) EventType() string           { return "idea.updated" }
+func (e ProjectImported) EventType() string       { return "project.imported" }
+func (e IdeaCompleted) EventType() string         { return "idea.completed" }
+func (e IdeaDeleted) EventType() string           { return "idea.deleted" }
+func (e StageCreated) EventType() string          { return e.Stage + ".created" }
+func (e SectionEdited) EventType() string         { return e.Stage + ".section_updated" }
+func (e StageStatusChanged) EventType() string    { return e.Stage + ".updated" }
+func (e ArchitectureGenerated) EventType() string { return "architecture.generated" }
+func (e ModulesGenerated) EventType() string      { return "modules.generated" }
+func (e ModulesReplaced) EventType() string       { return "modules.replaced" }
+func (e ModuleCreated) EventType() string         { return "module.created" }
+func (e ModuleUpdated) EventType() string         { return "module.updated" }
+func (e ModuleDeleted) EventType() string         { return "module.deleted" }
+func (e ModuleStatusChanged) EventType() string   { return "module.status_changed" }
+func (e ChatMessagePosted) EventType() string     { return "chat.message" }
+func (e PropagationApplied) EventType() string    { return "propagation.applied" }
+
+func (e IdeaUpdated) ProjectID() uuid.UUID           { return e.IdeaID }
+func (e ProjectImported) ProjectID() uuid.UUID       { return e.IdeaID }
+func (e IdeaCompleted) ProjectID() uuid.UUID         { return e.IdeaID }
+func (e IdeaDeleted) ProjectID() uuid.UUID           { return e.IdeaID }
+func (e StageCreated) ProjectID() uuid.UUID          { return e.IdeaID }
+func (e SectionEdited) ProjectID() uuid.UUID         { return e.IdeaID }
+func (e StageStatusChanged) ProjectID() uuid.UUID    { return e.IdeaID }
+func (e ArchitectureGenerated) ProjectID() uuid.UUID { return e.IdeaID }
+func (e ModulesGenerated) ProjectID() uuid.UUID      { return e.IdeaID }
+func (e ModulesReplaced) ProjectID() uuid.UUID       { return e.IdeaID }
+func (e ModuleCreated) ProjectID() uuid.UUID         { return e.IdeaID }
+func (e ModuleUpdated) ProjectID() uuid.UUID         { return e.IdeaID }
+func (e ModuleDeleted) ProjectID() uuid.UUID         { return e.IdeaID }
+func (e ModuleStatusChanged) ProjectID() uuid.UUID   { return e.IdeaID }
+func (e ChatMessagePosted) ProjectID() uuid.UUID     { return e.IdeaID }
+func (e PropagationApplied) ProjectID() uuid.UUID    { return e.IdeaID }
+
+// ChangedSections returns a SectionEdited for every section whose content differs
+func ChangedSections(ideaID uuid.UUID, stage string, stageID uuid.UUID, before, after map[string]string) []Event {
+	var evts []Event
+	for _, section := range slices.Sorted(maps.Keys(after)) {
+		if content := after[section]; content != before[section] {
+			evts = append(evts, SectionEdited{IdeaID: ideaID, Stage: stage, StageID: stageID, Section: section, Content: content})
+		}
+	}
+	return evts
+}
+
+// Propagation summarizes the SectionEdited events of one stage produced by a propagation.
+// changed must not be empty.
+func Propagation(source string, changed []Event) PropagationApplied {
+	first := changed[0].(SectionEdited)
+	p := PropagationApplied{IdeaID: first.IdeaID, Source: source, Stage: first.Stage, StageID: first.StageID}
+	for _, e := range changed {
+		p.Sections = append(p.Sections, e.(SectionEdited).Section)
+	}
+	return p
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/middleware"
+)
+
+// Append records events in the outbox. Call it with the transaction of the change
+// the events describe. The actor is the authenticated user of ctx, if any, and
+// the source the one set with WithSource.
+func Append(ctx context.Context, tx *sql.Tx, evts ...Event) error {
+	var actor uuid.NullUUID
+	if userID, ok := middleware.GetUserIDFromContext(ctx); ok {
+		actor = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	source := sourceFrom(ctx, actor.Valid)
+
+	for _, e := range evts {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO outbox_events (event_id, event_type, idea_id, actor_id, source, payload)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.New(), e.EventType(), e.ProjectID(), actor, source, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InTx runs fn in a transaction and appends evts to the outbox before committing
+func InTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error, evts ...Event) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := Append(ctx, tx, evts...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// pgOutbox reads and settles outbox records for the dispatcher
+type pgOutbox struct{ db *sql.DB }
+
+const recordColumns = `seq, event_id, event_type, idea_id, actor_id, source, payload, occurred_at, pending_subscribers`
+
+// claim locks the next due records by pushing their next attempt lease into the
+// future, so other instances skip them while they are dispatched
+func (o *pgOutbox) claim(ctx context.Context, limit int, lease time.Duration) ([]claimed, error) {
+	rows, err := o.db.QueryContext(ctx, `
+		UPDATE outbox_events
+		SET next_attempt_at = NOW() + make_interval(secs => $2), attempts = attempts + 1
+		WHERE seq IN (
+			SELECT seq
+			FROM outbox_events
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY seq
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+recordColumns+`, attempts
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []claimed
+	for rows.Next() {
+		var c claimed
+		var actor uuid.NullUUID
+		var data, pending []byte
+		if err := rows.Scan(&c.record.Seq, &c.record.ID, &c.record.Type, &c.record.IdeaID, &actor, &c.record.Source, &data, &c.record.OccurredAt, &pending, &c.attempts); err != nil {
+			return nil, err
+		}
+		if actor.Valid {
+			c.record.ActorID = &actor.UUID
+		}
+		c.record.Data = data
+		if pending != nil {
+			if err := json.Unmarshal(pending, &c.pending); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING has no order; dispatch in outbox order
+	slices.SortFunc(out, func(a, b claimed) int { return cmp.Compare(a.record.Seq, b.record.Seq) })
+	return out, nil
+}
+
+func (o *pgOutbox) markDispatched(ctx context.Context, seq int64) error {
+	_, err := o.db.ExecContext(ctx, `
+		UPDATE outbox_events
+		SET status = 'dispatched', dispatched_at = NOW(), pending_subscribers = NULL, last_error = ''
+		WHERE seq = $1
+	`, seq)
+	return err
+}
+
+// markFailed keeps the subscribers that still have to handle the record and
+// schedules a retry, or gives up when retryAt is nil
+func (o *pgOutbox) markFailed(ctx context.Context, seq int64, pending []string, lastError string, retryAt *time.Time) error {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	status := "pending"
+	if retryAt == nil {
+		status = "failed"
+	}
+	_, err = o.db.ExecContext(ctx, `
+		UPDATE outbox_events
+		SET status = $2, pending_subscribers = $3, last_error = $4, next_attempt_at = COALESCE($5, next_attempt_at)
+		WHERE seq = $1
+	`, seq, status, string(data), lastError, retryAt)
+	return err
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Record is an event as stored in the outbox
+type Record struct {
+	Seq        int64           `json:"-"` // outbox order
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	IdeaID     uuid.UUID       `json:"idea_id"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty"` // user whose request caused the event
+	Source     string          `json:"source"`             // see WithSource
+	Data       json.RawMessage `json:"data"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// registry maps every event type to its Go type
+var registry = map[string]func() Event{}
+
+func register(newEvent func() Event, types ...string) {
+	for _, t := range types {
+		registry[t] = newEvent
+	}
+}
+
+func init() {
+	register(func() Event { return &IdeaUpdated{} }, "idea.updated")
+	register(func() Event { return &ProjectImported{} }, "project.imported")
+	register(func() Event { return &IdeaCompleted{} }, "idea.completed")
+	register(func() Event { return &IdeaDeleted{} }, "idea.deleted")
+	register(func() Event { return &StageCreated{} }, "action_plan.created", "architecture.created")
+	register(func() Event { return &SectionEdited{} }, "idea.section_updated", "action_plan.section_updated", "architecture.section_updated")
+	register(func() Event { return &StageStatusChanged{} }, "action_plan.updated", "architecture.updated")
+	register(func() Event { return &ArchitectureGenerated{} }, "architecture.generated")
+	register(func() Event { return &ModulesGenerated{} }, "modules.generated")
+	register(func() Event { return &ModulesReplaced{} }, "modules.replaced")
+	register(func() Event { return &ModuleCreated{} }, "module.created")
+	register(func() Event { return &ModuleUpdated{} }, "module.updated")
+	register(func() Event { return &ModuleDeleted{} }, "module.deleted")
+	register(func() Event { return &ModuleStatusChanged{} }, "module.status_changed")
+	register(func() Event { return &ChatMessagePosted{} }, "chat.message")
+	register(func() Event { return &PropagationApplied{} }, "propagation.applied")
+}
+
+// Decode returns the typed event of a record (a pointer to one of the event structs)
+func (r Record) Decode() (Event, error) {
+	newEvent, ok := registry[r.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", r.Type)
+	}
+	e := newEvent()
+	if err := json.Unmarshal(r.Data, e); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", r.Type, err)
+	}
+	return e, nil
+}
@@ -0,0 +1,30 @@
+package events
+
+import "context"
+
+// Sources tell who or what made a change. Propagations use their own source,
+// e.g. "global_chat".
+const (
+	SourceUser   = "user"   // a user through a form or the API
+	SourceAgent  = "agent"  // an AI agent (chats, section edits, generation)
+	SourceSystem = "system" // no user involved
+)
+
+type sourceKey struct{}
+
+// WithSource marks the changes made with ctx as coming from source
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// sourceFrom returns the source set with WithSource, defaulting to the user
+// when the request is authenticated and to the system otherwise
+func sourceFrom(ctx context.Context, hasActor bool) string {
+	if s, ok := ctx.Value(sourceKey{}).(string); ok && s != "" {
+		return s
+	}
+	if hasActor {
+		return SourceUser
+	}
+	return SourceSystem
+}
@@ -0,0 +1,257 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxDocumentSize limita el documento subido (1MB)
+	maxDocumentSize = 1 << 20
+	// maxDocumentPrompt es la parte del documento que se envía al agente
+	maxDocumentPrompt = 32 << 10
+)
+
+// documentExtensions son los formatos aceptados: Markdown o texto plano
+var documentExtensions = []string{"", ".md", ".markdown", ".txt", ".text"}
+
+// documentExtraction es la respuesta del agente al leer un documento
+type documentExtraction struct {
+	Title      string             `json:"title"`
+	Objective  string             `json:"objective"`
+	Problem    string             `json:"problem"`
+	Scope      string             `json:"scope"`
+	Confidence map[string]float64 `json:"confidence"`
+	Summary    string             `json:"summary"`
+}
+
+// createIdeaFromDocument crea una idea a partir de un README o documento existente.
+// Acepta multipart (campo "file") o el texto directamente en el cuerpo; el
+// workspace va en la query porque el cuerpo es el documento.
+func (h *Handlers) createIdeaFromDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxDocumentSize+64<<10)
+
+	filename, content, err := readDocument(r)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, "document too large (max 1MB)", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+
+	// El permiso de escritura en el workspace lo valida el guard de acceso
+	workspaceID := uuid.Nil
+	if raw := r.URL.Query().Get("workspace_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+			return
+		}
+		workspaceID = id
+	} else if h.Workspaces != nil {
+		id, err := h.Workspaces.PersonalWorkspaceID(r.Context(), userID)
+		if err != nil {
+			log.Printf("error resolving personal workspace: %v", err)
+			http.Error(w, "error resolving workspace", http.StatusInternalServerError)
+			return
+		}
+		workspaceID = id
+	}
+
+	extracted, err := h.extractIdeaFromDocument(r.Context(), filename, content)
+	if err != nil {
+		log.Printf("error extracting idea from document: %v", err)
+		http.Error(w, "error extracting idea from document", http.StatusBadGateway)
+		return
+	}
+
+	idea, err := h.Create.Execute(
+		r.Context(),
+		userID,
+		workspaceID,
+		strings.TrimSpace(extracted.Title),
+		strings.TrimSpace(extracted.Objective),
+		strings.TrimSpace(extracted.Problem),
+		strings.TrimSpace(extracted.Scope),
+		r.URL.Query().Get("validate_competition") == "true",
+		r.URL.Query().Get("validate_monetization") == "true",
+	)
+	if err != nil {
+		log.Printf("Error creating idea from document: %v", err)
+		http.Error(w, "could not extract the idea fields from the document", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Sembrar el chat de ideación con el contexto del documento
+	msg, err := h.Append.Execute(r.Context(), uuid.Nil, idea.ID, "system", sourceSummary(filename, extracted))
+	if err != nil {
+		log.Printf("error seeding ideation chat: %v", err)
+		http.Error(w, "error seeding ideation chat", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"idea":       idea,
+		"confidence": extracted.Confidence,
+		"message":    msg,
+	}, http.StatusCreated)
+}
+
+// readDocument devuelve el nombre y el texto del documento subido
+func readDocument(r *http.Request) (string, string, error) {
+	filename, data, err := readUpload(r, maxDocumentSize)
+	if err != nil {
+		return "", "", err
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !slices.Contains(documentExtensions, ext) || !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+		return "", "", errors.New("document must be markdown or plain text")
+	}
+
+	content := strings.TrimSpace(strings.TrimPrefix(string(data), "\ufeff"))
+	if content == "" {
+		return "", "", errors.New("document is empty")
+	}
+	return filename, content, nil
+}
+
+// readUpload lee un archivo subido como multipart (campo "file") o directamente
+// en el cuerpo, con el nombre en la query ("filename"). El nombre se reduce a su base.
+func readUpload(r *http.Request, limit int64) (string, []byte, error) {
+	var filename string
+	var data []byte
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				return "", nil, err
+			}
+			return "", nil, errors.New("file is required")
+		}
+		defer file.Close()
+		filename = header.Filename
+		data, err = io.ReadAll(io.LimitReader(file, limit+1))
+		if err != nil {
+			return "", nil, err
+		}
+	} else {
+		filename = r.URL.Query().Get("filename")
+		var err error
+		data, err = io.ReadAll(r.Body)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if int64(len(data)) > limit {
+		return "", nil, &http.MaxBytesError{Limit: limit}
+	}
+
+	filename = filepath.Base(filename)
+	if filename == "." || filename == "/" {
+		filename = ""
+	}
+	return filename, data, nil
+}
+
+// extractIdeaFromDocument pide al agente los campos de la idea, con su confianza
+func (h *Handlers) extractIdeaFromDocument(ctx context.Context, filename, content string) (*documentExtraction, error) {
+	if len(content) > maxDocumentPrompt {
+		// Cortar en un límite de carácter para no enviar UTF-8 inválido
+		cut := maxDocumentPrompt
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		content = content[:cut]
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"filename": filename,
+		"content":  content,
+	})
+
+	url := os.Getenv("GENKIT_BASE_URL") + "/ideation/from-document"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if tok := os.Getenv("GENKIT_TOKEN"); tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+
+	resp, err := h.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling genkit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("genkit returned status %d", resp.StatusCode)
+	}
+
+	var out documentExtraction
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if out.Confidence == nil {
+		out.Confidence = map[string]float64{}
+	}
+	return &out, nil
+}
+
+// sourceSummary redacta el mensaje de sistema que abre el chat de ideación
+func sourceSummary(filename string, ex *documentExtraction) string {
+	var b strings.Builder
+	if filename != "" {
+		fmt.Fprintf(&b, "Idea creada a partir del documento %q.", filename)
+	} else {
+		b.WriteString("Idea creada a partir de un documento.")
+	}
+	if s := strings.TrimSpace(ex.Summary); s != "" {
+		b.WriteString("\n\nResumen del documento: ")
+		b.WriteString(s)
+	}
+
+	// Señalar los campos que el agente tuvo que inferir para revisarlos en la conversación
+	labels := []struct{ field, label string }{
+		{"title", "título"}, {"objective", "objetivo"}, {"problem", "problema"}, {"scope", "alcance"},
+	}
+	var inferred []string
+	for _, l := range labels {
+		if ex.Confidence[l.field] < 0.5 {
+			inferred = append(inferred, l.label)
+		}
+	}
+	if len(inferred) > 0 {
+		fmt.Fprintf(&b, "\n\nCampos con baja confianza, a confirmar con el usuario: %s.", strings.Join(inferred, ", "))
+	}
+	return b.String()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,7 +12,10 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/dark/idea-forge/internal/events"
+	"github.com/dark/idea-forge/internal/ideation/domain"
 	"github.com/dark/idea-forge/internal/ideation/usecase"
+	"github.com/dark/idea-forge/internal/middleware"
 	"github.com/google/uuid"
 )
 
@@ -22,7 +26,13 @@ type Handlers struct {
 	Update     *usecase.UpdateIdea
 	Delete     *usecase.DeleteIdea
 	Append     *usecase.AppendMessage
+	Import     *usecase.ImportIdeas
 	HTTPClient *http.Client
+
+	// Workspaces resuelve el workspace personal cuando la idea no indica uno
+	Workspaces interface {
+		PersonalWorkspaceID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	}
 }
 
 func (h *Handlers) Register(mux *http.ServeMux) {
@@ -37,6 +47,14 @@ func (h *Handlers) Register(mux *http.ServeMux) {
 
 	// /ideation/ideas/{id}  y  /ideation/ideas/{id}/messages  y  /ideation/ideas/{id}/edit-section
 	mux.HandleFunc("/ideation/ideas/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ideation/ideas/from-document" {
+			h.createIdeaFromDocument(w, r)
+			return
+		}
+		if r.URL.Path == "/ideation/ideas/import-csv" {
+			h.importCSV(w, r)
+			return
+		}
 		if strings.HasSuffix(r.URL.Path, "/messages") {
 			h.getMessages(w, r)
 			return
@@ -81,6 +99,7 @@ func (h *Handlers) createIdea(w http.ResponseWriter, r *http.Request) {
 		Scope                string `json:"scope"`
 		ValidateCompetition  bool   `json:"validate_competition"`
 		ValidateMonetization bool   `json:"validate_monetization"`
+		WorkspaceID          string `json:"workspace_id"` // opcional: por defecto el workspace personal
 	}
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
 		log.Printf("Error decoding JSON: %v", err)
@@ -88,6 +107,34 @@ func (h *Handlers) createIdea(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// El propietario es el usuario autenticado
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+
+	// Resolver el workspace antes de llamar a la IA (el permiso de escritura lo valida el guard de acceso)
+	workspaceID := uuid.Nil
+	if in.WorkspaceID != "" {
+		id, err := uuid.Parse(in.WorkspaceID)
+		if err != nil {
+			http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+			return
+		}
+		workspaceID = id
+	} else if h.Workspaces != nil {
+		id, err := h.Workspaces.PersonalWorkspaceID(r.Context(), userID)
+		if err != nil {
+			log.Printf("error resolving personal workspace: %v", err)
+			http.Error(w, "error resolving workspace", http.StatusInternalServerError)
+			return
+		}
+		workspaceID = id
+	}
+
+	// Se comprueba antes de mejorar la idea con IA para no gastar la llamada
+	if err := h.Create.Authorize(r.Context(), userID, workspaceID); err != nil {
+		writeCreateError(w, err)
+		return
+	}
+
 	log.Printf("Received idea data - title: %q, objective: %q, problem: %q, scope: %q",
 		in.Title, in.Objective, in.Problem, in.Scope)
 
@@ -110,6 +157,8 @@ func (h *Handlers) createIdea(w http.ResponseWriter, r *http.Request) {
 	// Crear idea con valores mejorados
 	idea, err := h.Create.Execute(
 		r.Context(),
+		userID,
+		workspaceID,
 		improved["title"],
 		improved["objective"],
 		improved["problem"],
@@ -119,13 +168,22 @@ func (h *Handlers) createIdea(w http.ResponseWriter, r *http.Request) {
 	)
 	if err != nil {
 		log.Printf("Error creating idea: %v", err)
-		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		writeCreateError(w, err)
 		return
 	}
 
 	writeJSON(w, idea, http.StatusOK)
 }
 
+// writeCreateError responde 403 si el usuario no puede crear en el workspace y 422 en otro caso
+func writeCreateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrForbidden) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+}
+
 func (h *Handlers) getIdea(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -150,7 +208,19 @@ func (h *Handlers) listIdeas(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	ideas, err := h.List.Execute(r.Context(), 50)
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+
+	var workspaceID *uuid.UUID
+	if v := r.URL.Query().Get("workspace_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+			return
+		}
+		workspaceID = &id
+	}
+
+	ideas, err := h.List.Execute(r.Context(), userID, workspaceID, 50)
 	if err != nil {
 		http.Error(w, "error listing ideas", http.StatusInternalServerError)
 		return
@@ -258,7 +328,12 @@ func (h *Handlers) chat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 1) Guarda mensaje del usuario
-	if _, err := h.Append.Execute(r.Context(), ideaID, "user", in.Message); err != nil {
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	if _, err := h.Append.Execute(r.Context(), userID, ideaID, "user", in.Message); err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		return
 	}
@@ -296,7 +371,7 @@ func (h *Handlers) chat(w http.ResponseWriter, r *http.Request) {
 
 	// 4) Llama al agente en :3001
 	url := os.Getenv("GENKIT_BASE_URL") + "/flows/ideationAgent"
-	req, _ := http.NewRequest("POST", url, bytes.NewReader(payload))
+	req, _ := http.NewRequestWithContext(r.Context(), "POST", url, bytes.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
 	if tok := os.Getenv("GENKIT_TOKEN"); tok != "" {
 		req.Header.Set("Authorization", "Bearer "+tok)
@@ -330,7 +405,7 @@ func (h *Handlers) chat(w http.ResponseWriter, r *http.Request) {
 
 		// Actualizar idea con los campos sugeridos por el agente
 		_, err := h.Update.Execute(
-			r.Context(),
+			events.WithSource(r.Context(), events.SourceAgent),
 			ideaID,
 			out.Updates["title"],
 			out.Updates["objective"],
@@ -348,7 +423,7 @@ func (h *Handlers) chat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 6) Guarda respuesta del asistente
-	if _, err := h.Append.Execute(r.Context(), ideaID, "assistant", out.Reply); err != nil {
+	if _, err := h.Append.Execute(r.Context(), uuid.Nil, ideaID, "assistant", out.Reply); err != nil {
 		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		return
 	}
@@ -595,54 +670,21 @@ func (h *Handlers) propagateChanges(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Cargar idea actual
-	idea, err := h.Get.Execute(r.Context(), ideaID)
-	if err != nil {
+	// Verificar que la idea existe
+	if _, err := h.Get.Execute(r.Context(), ideaID); err != nil {
 		http.Error(w, "idea not found", http.StatusNotFound)
 		return
 	}
 
 	// Actualizar solo los campos que vienen en la propagación
-	updated := false
-	title := idea.Title
-	objective := idea.Objective
-	problem := idea.Problem
-	scope := idea.Scope
-
-	if in.Title != nil && *in.Title != "" {
-		title = *in.Title
-		updated = true
-	}
-	if in.Objective != nil && *in.Objective != "" {
-		objective = *in.Objective
-		updated = true
-	}
-	if in.Problem != nil && *in.Problem != "" {
-		problem = *in.Problem
-		updated = true
-	}
-	if in.Scope != nil && *in.Scope != "" {
-		scope = *in.Scope
-		updated = true
-	}
+	title, objective, problem, scope := deref(in.Title), deref(in.Objective), deref(in.Problem), deref(in.Scope)
+	updated := title != "" || objective != "" || problem != "" || scope != ""
 
 	if updated {
-		_, err := h.Update.Execute(
-			r.Context(),
-			ideaID,
-			title,
-			objective,
-			problem,
-			scope,
-			idea.ValidateCompetition,
-			idea.ValidateMonetization,
-			nil, // No cambiar completed
-		)
-		if err != nil {
+		if _, err := h.Update.Propagate(r.Context(), ideaID, title, objective, problem, scope, in.Source); err != nil {
 			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 			return
 		}
-		log.Printf("[propagate] Idea %s updated from %s", ideaID, in.Source)
 	}
 
 	writeJSON(w, map[string]interface{}{
@@ -650,3 +692,10 @@ func (h *Handlers) propagateChanges(w http.ResponseWriter, r *http.Request) {
 		"updated": updated,
 	}, http.StatusOK)
 }
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,92 @@
+package httpadapter
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/ideation/usecase"
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// maxCSVSize limita el CSV subido (5MB)
+const maxCSVSize = 5 << 20
+
+// importCSV crea ideas en bloque desde un CSV (multipart "file" o el cuerpo).
+// Query: workspace_id, dry_run=true para solo validar, improve=true para
+// mejorar cada idea con IA y columns=campo:Encabezado,... para mapear columnas.
+func (h *Handlers) importCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCSVSize+64<<10)
+
+	_, data, err := readUpload(r, maxCSVSize)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, "file too large (max 5MB)", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	opts := usecase.ImportOptions{
+		DryRun:  q.Get("dry_run") == "true",
+		Columns: map[string]string{},
+	}
+	if q.Get("improve") == "true" {
+		opts.Improve = h.improveIdeaWithAI
+	}
+	if raw := q.Get("columns"); raw != "" {
+		for _, pair := range strings.Split(raw, ",") {
+			field, header, ok := strings.Cut(pair, ":")
+			if !ok || strings.TrimSpace(field) == "" || strings.TrimSpace(header) == "" {
+				http.Error(w, "columns must be field:header pairs", http.StatusBadRequest)
+				return
+			}
+			opts.Columns[strings.TrimSpace(field)] = strings.TrimSpace(header)
+		}
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+
+	// El permiso de escritura en el workspace lo valida el guard de acceso
+	workspaceID := uuid.Nil
+	if raw := q.Get("workspace_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+			return
+		}
+		workspaceID = id
+	} else if h.Workspaces != nil {
+		id, err := h.Workspaces.PersonalWorkspaceID(r.Context(), userID)
+		if err != nil {
+			log.Printf("error resolving personal workspace: %v", err)
+			http.Error(w, "error resolving workspace", http.StatusInternalServerError)
+			return
+		}
+		workspaceID = id
+	}
+
+	report, err := h.Import.Execute(r.Context(), userID, workspaceID, bytes.NewReader(data), opts)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCSV) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("error importing ideas: %v", err)
+		http.Error(w, "error importing ideas", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, report, http.StatusOK)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/dark/idea-forge/internal/events"
 	"github.com/dark/idea-forge/internal/ideation/domain"
 	"github.com/dark/idea-forge/internal/ideation/port"
 )
@@ -13,85 +14,132 @@ type repo struct{ db *sql.DB }
 
 func NewRepo(db *sql.DB) port.IdeaRepository { return &repo{db: db} }
 
+// ideaColumns son las columnas leídas por scanIdea
+const ideaColumns = `id, title, objective, problem, scope, validate_competition, validate_monetization, completed, user_id, workspace_id, created_at`
+
 func (r *repo) Save(ctx context.Context, i *domain.Idea) error {
 	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO ideation_ideas
-		  (id, title, objective, problem, scope, validate_competition, validate_monetization, completed, created_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
-	`, i.ID, i.Title, i.Objective, i.Problem, i.Scope, i.ValidateCompetition, i.ValidateMonetization, i.Completed, i.CreatedAt)
+		  (id, title, objective, problem, scope, validate_competition, validate_monetization, completed, user_id, workspace_id, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+	`, i.ID, i.Title, i.Objective, i.Problem, i.Scope, i.ValidateCompetition, i.ValidateMonetization, i.Completed, i.UserID, i.WorkspaceID, i.CreatedAt)
 	return err
 }
 
 func (r *repo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Idea, error) {
-	var i domain.Idea
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, title, objective, problem, scope, validate_competition, validate_monetization, completed, created_at
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+ideaColumns+`
 		  FROM ideation_ideas
 		 WHERE id=$1
-	`, id).
-		Scan(&i.ID, &i.Title, &i.Objective, &i.Problem, &i.Scope, &i.ValidateCompetition, &i.ValidateMonetization, &i.Completed, &i.CreatedAt)
+	`, id)
+	return scanIdea(row)
+}
+
+func (r *repo) FindAccessible(ctx context.Context, userID uuid.UUID, workspaceID *uuid.UUID, limit int) ([]domain.Idea, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+ideaColumns+`
+		  FROM ideation_ideas
+		 WHERE (workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id=$1)
+		        OR id IN (SELECT idea_id FROM project_collaborators WHERE user_id=$1))
+		   AND ($2::uuid IS NULL OR workspace_id=$2)
+		 ORDER BY created_at DESC
+		 LIMIT $3
+	`, userID, workspaceID, limit)
 	if err != nil {
 		return nil, err
 	}
-	return &i, nil
+	return scanIdeas(rows)
 }
 
-func (r *repo) FindAll(ctx context.Context, limit int) ([]domain.Idea, error) {
+func (r *repo) FindByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Idea, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, title, objective, problem, scope, validate_competition, validate_monetization, completed, created_at
+		SELECT `+ideaColumns+`
 		  FROM ideation_ideas
+		 WHERE user_id=$1
 		 ORDER BY created_at DESC
-		 LIMIT $1
-	`, limit)
+		 LIMIT $2
+	`, userID, limit)
 	if err != nil {
 		return nil, err
 	}
+	return scanIdeas(rows)
+}
+
+func scanIdeas(rows *sql.Rows) ([]domain.Idea, error) {
 	defer rows.Close()
 
 	var ideas []domain.Idea
 	for rows.Next() {
-		var i domain.Idea
-		if err := rows.Scan(&i.ID, &i.Title, &i.Objective, &i.Problem, &i.Scope, &i.ValidateCompetition, &i.ValidateMonetization, &i.Completed, &i.CreatedAt); err != nil {
+		i, err := scanIdea(rows)
+		if err != nil {
 			return nil, err
 		}
-		ideas = append(ideas, i)
+		ideas = append(ideas, *i)
 	}
 	return ideas, rows.Err()
 }
 
-func (r *repo) UpdateIdea(ctx context.Context, i *domain.Idea) error {
-	_, err := r.db.ExecContext(ctx, `
-		UPDATE ideation_ideas
-		   SET title = $2,
-		       objective = $3,
-		       problem = $4,
-		       scope = $5,
-		       validate_competition = $6,
-		       validate_monetization = $7,
-		       completed = $8
-		 WHERE id = $1
-	`, i.ID, i.Title, i.Objective, i.Problem, i.Scope, i.ValidateCompetition, i.ValidateMonetization, i.Completed)
-	return err
+type scanner interface {
+	Scan(dest ...any) error
 }
 
-func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
-	// Eliminar primero los mensajes relacionados (CASCADE)
-	_, err := r.db.ExecContext(ctx, `DELETE FROM ideation_messages WHERE idea_id = $1`, id)
-	if err != nil {
-		return err
+func scanIdea(s scanner) (*domain.Idea, error) {
+	var i domain.Idea
+	var userID, workspaceID uuid.NullUUID
+	if err := s.Scan(&i.ID, &i.Title, &i.Objective, &i.Problem, &i.Scope, &i.ValidateCompetition, &i.ValidateMonetization, &i.Completed, &userID, &workspaceID, &i.CreatedAt); err != nil {
+		return nil, err
 	}
+	i.UserID = uuidPtr(userID)
+	i.WorkspaceID = uuidPtr(workspaceID)
+	return &i, nil
+}
 
-	// Eliminar la idea
-	_, err = r.db.ExecContext(ctx, `DELETE FROM ideation_ideas WHERE id = $1`, id)
-	return err
+func uuidPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
 }
 
-func (r *repo) AppendMessage(ctx context.Context, m *domain.Message) error {
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO ideation_messages (id, idea_id, role, content, created_at)
-		VALUES ($1,$2,$3,$4, now())
-	`, m.ID, m.IdeaID, m.Role, m.Content)
-	return err
+func (r *repo) UpdateIdea(ctx context.Context, i *domain.Idea, evts ...events.Event) error {
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE ideation_ideas
+			   SET title = $2,
+			       objective = $3,
+			       problem = $4,
+			       scope = $5,
+			       validate_competition = $6,
+			       validate_monetization = $7,
+			       completed = $8
+			 WHERE id = $1
+		`, i.ID, i.Title, i.Objective, i.Problem, i.Scope, i.ValidateCompetition, i.ValidateMonetization, i.Completed)
+		return err
+	}, evts...)
+}
+
+func (r *repo) Delete(ctx context.Context, id uuid.UUID, evts ...events.Event) error {
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Eliminar primero los mensajes relacionados (CASCADE)
+		_, err := tx.ExecContext(ctx, `DELETE FROM ideation_messages WHERE idea_id = $1`, id)
+		if err != nil {
+			return err
+		}
+
+		// Eliminar la idea
+		_, err = tx.ExecContext(ctx, `DELETE FROM ideation_ideas WHERE id = $1`, id)
+		return err
+	}, evts...)
+}
+
+func (r *repo) AppendMessage(ctx context.Context, m *domain.Message, evts ...events.Event) error {
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO ideation_messages (id, idea_id, role, content, created_at)
+			VALUES ($1,$2,$3,$4, now())
+		`, m.ID, m.IdeaID, m.Role, m.Content)
+		return err
+	}, evts...)
 }
 
 func (r *repo) ListMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]domain.Message, error) {
@@ -7,6 +7,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrForbidden: el usuario no puede escribir en la idea o en el workspace
+var ErrForbidden = errors.New("forbidden")
+
 type Idea struct {
 	ID                   uuid.UUID
 	Title                string
@@ -16,6 +19,8 @@ type Idea struct {
 	ValidateCompetition  bool
 	ValidateMonetization bool
 	Completed            bool
+	UserID               *uuid.UUID // Propietario (nil en ideas creadas antes de la autenticación)
+	WorkspaceID          *uuid.UUID // Workspace al que pertenece (obligatorio al guardarla)
 	CreatedAt            time.Time
 }
 
@@ -4,16 +4,26 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/dark/idea-forge/internal/events"
 	"github.com/dark/idea-forge/internal/ideation/domain"
 )
 
+// Las mutaciones reciben los eventos de dominio para registrarlos en el outbox en la misma transacción
 type IdeaRepository interface {
 	Save(ctx context.Context, idea *domain.Idea) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Idea, error)
-	FindAll(ctx context.Context, limit int) ([]domain.Idea, error)
-	UpdateIdea(ctx context.Context, idea *domain.Idea) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	// FindAccessible devuelve las ideas visibles para el usuario (membresía de workspace, colaborador o propias)
+	FindAccessible(ctx context.Context, userID uuid.UUID, workspaceID *uuid.UUID, limit int) ([]domain.Idea, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Idea, error)
+	UpdateIdea(ctx context.Context, idea *domain.Idea, evts ...events.Event) error
+	Delete(ctx context.Context, id uuid.UUID, evts ...events.Event) error
 
-	AppendMessage(ctx context.Context, msg *domain.Message) error
+	AppendMessage(ctx context.Context, msg *domain.Message, evts ...events.Event) error
 	ListMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]domain.Message, error)
 }
+
+// AccessChecker decide si el usuario puede escribir en una idea o crear ideas en un workspace
+type AccessChecker interface {
+	CanWrite(ctx context.Context, userID, ideaID uuid.UUID) (bool, error)
+	CanWriteWorkspace(ctx context.Context, userID, workspaceID uuid.UUID) (bool, error)
+}
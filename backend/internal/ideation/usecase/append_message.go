@@ -4,29 +4,47 @@ import (
 	"context"
 	"errors"
 
+	"github.com/dark/idea-forge/internal/events"
 	"github.com/dark/idea-forge/internal/ideation/domain"
 	"github.com/dark/idea-forge/internal/ideation/port"
 	"github.com/google/uuid"
 )
 
-type AppendMessage struct{ repo port.IdeaRepository }
+type AppendMessage struct {
+	repo   port.IdeaRepository
+	access port.AccessChecker
+}
 
-func NewAppendMessage(r port.IdeaRepository) *AppendMessage { return &AppendMessage{repo: r} }
+func NewAppendMessage(r port.IdeaRepository, a port.AccessChecker) *AppendMessage {
+	return &AppendMessage{repo: r, access: a}
+}
 
 // Repo expone el repositorio subyacente (para lecturas puntuales desde el handler)
 func (uc *AppendMessage) Repo() port.IdeaRepository { return uc.repo }
 
-func (uc *AppendMessage) Execute(ctx context.Context, ideaID uuid.UUID, role, content string) (*domain.Message, error) {
+// Execute guarda un mensaje en la conversación de la idea. userID es quien escribe y necesita
+// permiso de escritura (domain.ErrForbidden si no); uuid.Nil para mensajes del sistema o del agente.
+func (uc *AppendMessage) Execute(ctx context.Context, userID, ideaID uuid.UUID, role, content string) (*domain.Message, error) {
 	if role == "" || content == "" {
 		return nil, errors.New("invalid message")
 	}
+	if userID != uuid.Nil {
+		ok, err := uc.access.CanWrite(ctx, userID, ideaID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, domain.ErrForbidden
+		}
+	}
 	msg := &domain.Message{
 		ID:      uuid.New(),
 		IdeaID:  ideaID,
 		Role:    role,
 		Content: content,
 	}
-	if err := uc.repo.AppendMessage(ctx, msg); err != nil {
+	posted := events.ChatMessagePosted{IdeaID: ideaID, Stage: events.StageIdea, MessageID: msg.ID, Role: role, Content: content}
+	if err := uc.repo.AppendMessage(ctx, msg, posted); err != nil {
 		return nil, err
 	}
 	return msg, nil
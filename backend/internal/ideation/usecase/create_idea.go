@@ -3,17 +3,48 @@ package usecase
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/dark/idea-forge/internal/ideation/domain"
 	"github.com/dark/idea-forge/internal/ideation/port"
 )
 
-type CreateIdea struct{ repo port.IdeaRepository }
+type CreateIdea struct {
+	repo   port.IdeaRepository
+	access port.AccessChecker
+}
 
-func NewCreateIdea(r port.IdeaRepository) *CreateIdea { return &CreateIdea{repo: r} }
+func NewCreateIdea(r port.IdeaRepository, a port.AccessChecker) *CreateIdea {
+	return &CreateIdea{repo: r, access: a}
+}
 
-func (uc *CreateIdea) Execute(ctx context.Context, title, objective, problem, scope string, comp, monet bool) (*domain.Idea, error) {
+// Execute crea la idea; userID y workspaceID pueden ser uuid.Nil si no hay propietario o workspace.
+// Crear en un workspace exige permiso de escritura en él (domain.ErrForbidden si no).
+func (uc *CreateIdea) Execute(ctx context.Context, userID, workspaceID uuid.UUID, title, objective, problem, scope string, comp, monet bool) (*domain.Idea, error) {
 	idea, err := domain.NewIdea(title, objective, problem, scope, comp, monet)
 	if err != nil { return nil, err }
+	if err := uc.Authorize(ctx, userID, workspaceID); err != nil { return nil, err }
+	if userID != uuid.Nil {
+		idea.UserID = &userID
+	}
+	if workspaceID != uuid.Nil {
+		idea.WorkspaceID = &workspaceID
+	}
 	if err := uc.repo.Save(ctx, idea); err != nil { return nil, err }
 	return idea, nil
+}
+
+// Authorize devuelve domain.ErrForbidden si el usuario no puede crear ideas en el workspace
+func (uc *CreateIdea) Authorize(ctx context.Context, userID, workspaceID uuid.UUID) error {
+	if userID == uuid.Nil || workspaceID == uuid.Nil {
+		return nil
+	}
+	ok, err := uc.access.CanWriteWorkspace(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
 }
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/dark/idea-forge/internal/events"
 	"github.com/dark/idea-forge/internal/ideation/port"
 )
 
@@ -12,5 +13,5 @@ type DeleteIdea struct{ repo port.IdeaRepository }
 func NewDeleteIdea(r port.IdeaRepository) *DeleteIdea { return &DeleteIdea{repo: r} }
 
 func (uc *DeleteIdea) Execute(ctx context.Context, id uuid.UUID) error {
-	return uc.repo.Delete(ctx, id)
+	return uc.repo.Delete(ctx, id, events.IdeaDeleted{IdeaID: id})
 }
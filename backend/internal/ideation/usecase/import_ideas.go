@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/ideation/domain"
+	"github.com/dark/idea-forge/internal/ideation/port"
+)
+
+// MaxImportRows limita las filas de datos de un CSV
+const MaxImportRows = 1000
+
+// ErrInvalidCSV indica que el archivo no se puede leer como CSV de ideas
+var ErrInvalidCSV = errors.New("invalid csv")
+
+// Estados de cada fila en el informe
+const (
+	RowCreated = "created"
+	RowValid   = "valid" // dry-run: se crearía
+	RowSkipped = "skipped"
+	RowError   = "error"
+)
+
+// importFields son los campos de la idea que se pueden mapear, con los
+// encabezados que se reconocen sin mapeo explícito
+var importFields = []struct {
+	name    string
+	aliases []string
+}{
+	{"title", []string{"title", "titulo", "título", "name", "nombre", "idea"}},
+	{"objective", []string{"objective", "objetivo", "goal", "meta"}},
+	{"problem", []string{"problem", "problema"}},
+	{"scope", []string{"scope", "alcance"}},
+	{"validate_competition", []string{"validate_competition", "competition", "competencia"}},
+	{"validate_monetization", []string{"validate_monetization", "monetization", "monetizacion", "monetización"}},
+}
+
+// ImportOptions configura una importación
+type ImportOptions struct {
+	// Columns asigna encabezados del CSV a campos de la idea (p. ej. "title": "Nombre");
+	// los campos no indicados se buscan por sus nombres habituales
+	Columns map[string]string
+	// DryRun valida las filas sin crear ideas
+	DryRun bool
+	// Improve, si no es nil, mejora cada idea con IA antes de guardarla (se omite en dry-run)
+	Improve func(ctx context.Context, title, objective, problem, scope string) (map[string]string, error)
+}
+
+// ImportRow es el resultado de una fila; Line es la línea del archivo
+type ImportRow struct {
+	Line   int        `json:"line"`
+	Status string     `json:"status"`
+	Title  string     `json:"title,omitempty"`
+	IdeaID *uuid.UUID `json:"idea_id,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// ImportReport resume la importación fila a fila
+type ImportReport struct {
+	DryRun         bool              `json:"dry_run"`
+	Created        int               `json:"created"`
+	Valid          int               `json:"valid"`
+	Skipped        int               `json:"skipped"`
+	Errored        int               `json:"errored"`
+	Columns        map[string]string `json:"columns"`
+	IgnoredColumns []string          `json:"ignored_columns"`
+	Rows           []ImportRow       `json:"rows"`
+}
+
+type ImportIdeas struct{ repo port.IdeaRepository }
+
+func NewImportIdeas(r port.IdeaRepository) *ImportIdeas { return &ImportIdeas{repo: r} }
+
+// Execute crea una idea por fila del CSV. Cada fila se valida con domain.NewIdea
+// y se guarda por separado: un error en una fila no detiene las demás. Se
+// omiten las filas vacías y los títulos repetidos en el archivo o ya existentes
+// en el workspace. userID y workspaceID pueden ser uuid.Nil.
+func (uc *ImportIdeas) Execute(ctx context.Context, userID, workspaceID uuid.UUID, data io.Reader, opts ImportOptions) (*ImportReport, error) {
+	r, err := newCSVReader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidCSV)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	columns, ignored, err := mapColumns(header, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	titles, err := uc.existingTitles(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{DryRun: opts.DryRun, Columns: map[string]string{}, IgnoredColumns: ignored, Rows: []ImportRow{}}
+	for field, i := range columns {
+		report.Columns[field] = strings.TrimSpace(header[i])
+	}
+
+	for rows := 0; ; rows++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		if rows == MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidCSV, MaxImportRows)
+		}
+		line, _ := r.FieldPos(0)
+
+		row := uc.importRow(ctx, userID, workspaceID, columns, record, titles, opts)
+		row.Line = line
+		switch row.Status {
+		case RowCreated:
+			report.Created++
+		case RowValid:
+			report.Valid++
+		case RowSkipped:
+			report.Skipped++
+		default:
+			report.Errored++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+func (uc *ImportIdeas) importRow(ctx context.Context, userID, workspaceID uuid.UUID, columns map[string]int, record []string, titles map[string]string, opts ImportOptions) ImportRow {
+	value := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	blank := true
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			blank = false
+			break
+		}
+	}
+	if blank {
+		return ImportRow{Status: RowSkipped, Reason: "empty row"}
+	}
+
+	title := value("title")
+	row := ImportRow{Title: title}
+	key := strings.ToLower(title)
+	if reason, dup := titles[key]; title != "" && dup {
+		row.Status, row.Reason = RowSkipped, reason
+		return row
+	}
+
+	comp, err := parseBool(value("validate_competition"))
+	if err != nil {
+		row.Status, row.Reason = RowError, "validate_competition: "+err.Error()
+		return row
+	}
+	monet, err := parseBool(value("validate_monetization"))
+	if err != nil {
+		row.Status, row.Reason = RowError, "validate_monetization: "+err.Error()
+		return row
+	}
+
+	idea, err := domain.NewIdea(title, value("objective"), value("problem"), value("scope"), comp, monet)
+	if err != nil {
+		row.Status, row.Reason = RowError, err.Error()
+		return row
+	}
+	titles[key] = "duplicate title in the file"
+
+	if opts.DryRun {
+		row.Status = RowValid
+		return row
+	}
+
+	if opts.Improve != nil {
+		if improved, err := opts.Improve(ctx, idea.Title, idea.Objective, idea.Problem, idea.Scope); err == nil {
+			// Solo se aceptan mejoras completas; si no, se mantienen los valores del CSV
+			if better, err := domain.NewIdea(improved["title"], improved["objective"], improved["problem"], improved["scope"], comp, monet); err == nil {
+				idea.Title, idea.Objective, idea.Problem, idea.Scope = better.Title, better.Objective, better.Problem, better.Scope
+			}
+		}
+	}
+
+	if userID != uuid.Nil {
+		idea.UserID = &userID
+	}
+	if workspaceID != uuid.Nil {
+		idea.WorkspaceID = &workspaceID
+	}
+	if err := uc.repo.Save(ctx, idea); err != nil {
+		delete(titles, key)
+		row.Status, row.Reason = RowError, "could not save the idea"
+		return row
+	}
+	row.Status, row.Title, row.IdeaID = RowCreated, idea.Title, &idea.ID
+	return row
+}
+
+// existingTitles devuelve los títulos (en minúsculas) de las ideas del destino,
+// con el motivo para omitir las filas que los repitan
+func (uc *ImportIdeas) existingTitles(ctx context.Context, userID, workspaceID uuid.UUID) (map[string]string, error) {
+	titles := map[string]string{}
+	if userID == uuid.Nil {
+		return titles, nil
+	}
+	var ideas []domain.Idea
+	var err error
+	if workspaceID != uuid.Nil {
+		ideas, err = uc.repo.FindAccessible(ctx, userID, &workspaceID, 10000)
+	} else {
+		ideas, err = uc.repo.FindByUserID(ctx, userID, 10000)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range ideas {
+		titles[strings.ToLower(strings.TrimSpace(i.Title))] = "an idea with this title already exists"
+	}
+	return titles, nil
+}
+
+// newCSVReader detecta el separador: las hojas de cálculo en español suelen exportar con ';'
+func newCSVReader(data io.Reader) (*csv.Reader, error) {
+	br := bufio.NewReader(data)
+	if bom, _ := br.Peek(3); bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+	first, err := br.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	if i := bytes.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+
+	r := csv.NewReader(br)
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		r.Comma = ';'
+	}
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	return r, nil
+}
+
+// mapColumns devuelve la posición de cada campo en el encabezado y los
+// encabezados que no corresponden a ningún campo
+func mapColumns(header []string, explicit map[string]string) (map[string]int, []string, error) {
+	positions := map[string]int{}
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(h))
+		if _, dup := positions[key]; !dup && key != "" {
+			positions[key] = i
+		}
+	}
+
+	columns := map[string]int{}
+	for field, h := range explicit {
+		known := false
+		for _, f := range importFields {
+			known = known || f.name == field
+		}
+		if !known {
+			return nil, nil, fmt.Errorf("%w: unknown field %q", ErrInvalidCSV, field)
+		}
+		i, ok := positions[strings.ToLower(strings.TrimSpace(h))]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: column %q not found", ErrInvalidCSV, h)
+		}
+		columns[field] = i
+	}
+	for _, f := range importFields {
+		if _, ok := columns[f.name]; ok {
+			continue
+		}
+		for _, alias := range f.aliases {
+			if i, ok := positions[alias]; ok {
+				columns[f.name] = i
+				break
+			}
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, nil, fmt.Errorf("%w: no title column", ErrInvalidCSV)
+	}
+
+	used := map[int]bool{}
+	for _, i := range columns {
+		used[i] = true
+	}
+	ignored := []string{}
+	for i, h := range header {
+		if !used[i] && strings.TrimSpace(h) != "" {
+			ignored = append(ignored, strings.TrimSpace(h))
+		}
+	}
+	return columns, ignored, nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "", "false", "0", "no", "n":
+		return false, nil
+	case "true", "1", "yes", "y", "si", "sí", "s", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("%q is not a boolean", s)
+}
@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/dark/idea-forge/internal/ideation/domain"
 	"github.com/dark/idea-forge/internal/ideation/port"
 )
@@ -15,9 +17,10 @@ func NewListIdeas(repo port.IdeaRepository) *ListIdeas {
 	return &ListIdeas{repo: repo}
 }
 
-func (uc *ListIdeas) Execute(ctx context.Context, limit int) ([]domain.Idea, error) {
+// Execute lista las ideas a las que el usuario tiene acceso; workspaceID opcional filtra por workspace
+func (uc *ListIdeas) Execute(ctx context.Context, userID uuid.UUID, workspaceID *uuid.UUID, limit int) ([]domain.Idea, error) {
 	if limit <= 0 {
 		limit = 50 // Default limit
 	}
-	return uc.repo.FindAll(ctx, limit)
+	return uc.repo.FindAccessible(ctx, userID, workspaceID, limit)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/dark/idea-forge/internal/events"
 	"github.com/dark/idea-forge/internal/ideation/domain"
 	"github.com/dark/idea-forge/internal/ideation/port"
 	"github.com/google/uuid"
@@ -24,39 +25,95 @@ func (uc *UpdateIdea) Execute(
 	validateCompetition, validateMonetization bool,
 	completed *bool,
 ) (*domain.Idea, error) {
+	return uc.update(ctx, id, "", func(existing *domain.Idea) {
+		// Actualizar solo los campos que no estén vacíos
+		applyText(existing, title, objective, problem, scope)
+
+		// Las validaciones siempre se actualizan (pueden cambiar a false)
+		existing.ValidateCompetition = validateCompetition
+		existing.ValidateMonetization = validateMonetization
+
+		// Actualizar completed si se proporcionó
+		if completed != nil {
+			existing.Completed = *completed
+		}
+	})
+}
+
+// Propagate aplica a la idea un cambio originado en otra etapa (source) y
+// registra qué campos cambiaron. Los campos vacíos se dejan como están.
+func (uc *UpdateIdea) Propagate(
+	ctx context.Context,
+	id uuid.UUID,
+	title, objective, problem, scope, source string,
+) (*domain.Idea, error) {
+	return uc.update(ctx, id, source, func(existing *domain.Idea) {
+		applyText(existing, title, objective, problem, scope)
+	})
+}
+
+func (uc *UpdateIdea) update(ctx context.Context, id uuid.UUID, source string, apply func(*domain.Idea)) (*domain.Idea, error) {
+	if source != "" {
+		ctx = events.WithSource(ctx, source)
+	}
+
 	// 1. Verificar que la idea existe
 	existing, err := uc.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("idea no encontrada")
 	}
 
-	// 2. Actualizar solo los campos que no estén vacíos
+	wasCompleted := existing.Completed
+	before := sections(existing)
+
+	// 2. Aplicar los cambios
+	apply(existing)
+
+	// 3. Guardar cambios junto con sus eventos
+	evts := []events.Event{events.IdeaUpdated{
+		IdeaID:    existing.ID,
+		Title:     existing.Title,
+		Objective: existing.Objective,
+		Problem:   existing.Problem,
+		Scope:     existing.Scope,
+		Completed: existing.Completed,
+	}}
+	changed := events.ChangedSections(existing.ID, events.StageIdea, existing.ID, before, sections(existing))
+	evts = append(evts, changed...)
+	if existing.Completed && !wasCompleted {
+		evts = append(evts, events.IdeaCompleted{IdeaID: existing.ID, Title: existing.Title})
+	}
+	if source != "" && len(changed) > 0 {
+		evts = append(evts, events.Propagation(source, changed))
+	}
+	if err := uc.repo.UpdateIdea(ctx, existing, evts...); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func applyText(i *domain.Idea, title, objective, problem, scope string) {
 	if title != "" {
-		existing.Title = title
+		i.Title = title
 	}
 	if objective != "" {
-		existing.Objective = objective
+		i.Objective = objective
 	}
 	if problem != "" {
-		existing.Problem = problem
+		i.Problem = problem
 	}
 	if scope != "" {
-		existing.Scope = scope
+		i.Scope = scope
 	}
+}
 
-	// Las validaciones siempre se actualizan (pueden cambiar a false)
-	existing.ValidateCompetition = validateCompetition
-	existing.ValidateMonetization = validateMonetization
-
-	// Actualizar completed si se proporcionó
-	if completed != nil {
-		existing.Completed = *completed
+// sections returns the editable text of the idea keyed like the other stages
+func sections(i *domain.Idea) map[string]string {
+	return map[string]string{
+		"title":     i.Title,
+		"objective": i.Objective,
+		"problem":   i.Problem,
+		"scope":     i.Scope,
 	}
-
-	// 3. Guardar cambios
-	if err := uc.repo.UpdateIdea(ctx, existing); err != nil {
-		return nil, err
-	}
-
-	return existing, nil
 }
@@ -0,0 +1,79 @@
+// Package mailbox provides transports that keep emails instead of sending
+// them, for local development and tests
+package mailbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dark/idea-forge/internal/mail/domain"
+)
+
+// File writes every message as an .eml file in a directory (open them with any
+// mail client) and logs its plain-text body
+type File struct {
+	dir  string
+	from string
+}
+
+func NewFile(dir, from string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &File{dir: dir, from: from}, nil
+}
+
+func (f *File) Send(ctx context.Context, m *domain.Message) error {
+	name := filepath.Join(f.dir, fmt.Sprintf("%s-%s.eml", m.CreatedAt.Format("20060102-150405"), m.ID))
+	if err := os.WriteFile(name, m.MIME(f.from), 0o644); err != nil {
+		return err
+	}
+	log.Printf("[EMAIL] To: %s\nSubject: %s\nSaved: %s\n%s", m.To, m.Subject, name, m.Text)
+	return nil
+}
+
+// Memory keeps messages in memory
+type Memory struct {
+	mu       sync.Mutex
+	messages []domain.Message
+}
+
+func NewMemory() *Memory { return &Memory{} }
+
+func (mb *Memory) Send(ctx context.Context, m *domain.Message) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.messages = append(mb.messages, *m)
+	return nil
+}
+
+// Messages returns the messages received so far, oldest first
+func (mb *Memory) Messages() []domain.Message {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	return append([]domain.Message(nil), mb.messages...)
+}
+
+// Last returns the latest message sent to the address, if any
+func (mb *Memory) Last(to string) (*domain.Message, bool) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	for i := len(mb.messages) - 1; i >= 0; i-- {
+		if mb.messages[i].To == to {
+			m := mb.messages[i]
+			return &m, true
+		}
+	}
+	return nil, false
+}
+
+// Reset drops every message
+func (mb *Memory) Reset() {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	mb.messages = nil
+}
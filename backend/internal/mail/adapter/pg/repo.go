@@ -0,0 +1,68 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dark/idea-forge/internal/mail/domain"
+	"github.com/dark/idea-forge/internal/mail/port"
+)
+
+type repo struct{ db *sql.DB }
+
+func NewRepo(db *sql.DB) port.OutboxRepository { return &repo{db: db} }
+
+const messageColumns = `id, to_address, template, locale, subject, text_body, html_body, status, attempts, next_attempt_at, last_error, created_at, sent_at`
+
+func (r *repo) Enqueue(ctx context.Context, m *domain.Message) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO mail_outbox (`+messageColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, m.ID, m.To, m.Template, m.Locale, m.Subject, m.Text, m.HTML, m.Status, m.Attempts, m.NextAttemptAt, m.LastError, m.CreatedAt, m.SentAt)
+	return err
+}
+
+func (r *repo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.Message, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE mail_outbox
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id
+			FROM mail_outbox
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+messageColumns, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		if err := rows.Scan(&m.ID, &m.To, &m.Template, &m.Locale, &m.Subject, &m.Text, &m.HTML,
+			&m.Status, &m.Attempts, &m.NextAttemptAt, &m.LastError, &m.CreatedAt, &m.SentAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (r *repo) RecordAttempt(ctx context.Context, m *domain.Message) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE mail_outbox
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, sent_at = $6
+		WHERE id = $1
+	`, m.ID, m.Status, m.Attempts, m.NextAttemptAt, m.LastError, m.SentAt)
+	return err
+}
+
+func (r *repo) PurgeSent(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM mail_outbox WHERE status = 'sent' AND sent_at < $1`, before)
+	return err
+}
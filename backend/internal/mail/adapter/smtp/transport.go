@@ -0,0 +1,97 @@
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"github.com/dark/idea-forge/internal/mail/domain"
+)
+
+// Transport sends messages through an SMTP server, upgrading to TLS when the
+// server offers STARTTLS
+type Transport struct {
+	host    string
+	port    string
+	user    string
+	pass    string
+	from    string
+	timeout time.Duration
+}
+
+func NewTransport(host, port, user, pass, from string, timeout time.Duration) *Transport {
+	return &Transport{host: host, port: port, user: user, pass: pass, from: from, timeout: timeout}
+}
+
+func (t *Transport) Send(ctx context.Context, m *domain.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	from, err := mail.ParseAddress(t.from)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP_FROM: %w", err)
+	}
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return fmt.Errorf("%w: invalid recipient: %v", domain.ErrPermanent, err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(t.host, t.port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: t.host}); err != nil {
+			return err
+		}
+	}
+	if t.user != "" {
+		if err := c.Auth(smtp.PlainAuth("", t.user, t.pass, t.host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	// Rejections of the recipient or the content will not change on retry
+	if err := c.Rcpt(to.Address); err != nil {
+		return permanent(err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.MIME(t.from)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return permanent(err)
+	}
+	return c.Quit()
+}
+
+// permanent marks 5xx replies as permanent errors
+func permanent(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return fmt.Errorf("%w: %v", domain.ErrPermanent, err)
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message statuses
+const (
+	StatusPending = "pending" // waiting for its next attempt
+	StatusSent    = "sent"    // accepted by the transport
+	StatusDead    = "dead"    // gave up: MaxAttempts reached or permanent error
+)
+
+// MaxAttempts is how many times a message is tried before it is marked dead
+const MaxAttempts = 8
+
+// ErrPermanent is wrapped by transports when retrying cannot help (e.g. a 5xx
+// SMTP reply for a rejected recipient); the message goes straight to dead
+var ErrPermanent = errors.New("permanent delivery error")
+
+// Message is an email queued in the outbox. It is rendered when queued so the
+// worker only has to send it.
+type Message struct {
+	ID            uuid.UUID  `json:"id"`
+	To            string     `json:"to"`
+	Template      string     `json:"template"`
+	Locale        string     `json:"locale"`
+	Subject       string     `json:"subject"`
+	Text          string     `json:"text"`
+	HTML          string     `json:"html"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// RetryDelay is the wait before the attempt that follows a failed one:
+// 30s, 1m, 2m, ... doubling up to 1h
+func RetryDelay(failedAttempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < failedAttempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		failedAttempts int
+		want           time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{MaxAttempts + 10, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := RetryDelay(tt.failedAttempts); got != tt.want {
+			t.Errorf("RetryDelay(%d) = %v, want %v", tt.failedAttempts, got, tt.want)
+		}
+	}
+}
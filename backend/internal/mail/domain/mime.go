@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"strings"
+	"time"
+)
+
+// MIME builds the RFC 5322 message: multipart/alternative with the plain-text
+// and HTML bodies, quoted-printable encoded
+func (m *Message) MIME(from string) []byte {
+	boundary := newBoundary()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@%s>\r\n", m.ID, domainOf(from))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n", boundary)
+	b.WriteString("\r\n")
+
+	writePart(&b, boundary, "text/plain", m.Text)
+	if m.HTML != "" {
+		writePart(&b, boundary, "text/html", m.HTML)
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes()
+}
+
+func writePart(b *bytes.Buffer, boundary, contentType, body string) {
+	fmt.Fprintf(b, "--%s\r\n", boundary)
+	fmt.Fprintf(b, "Content-Type: %s; charset=UTF-8\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	w := quotedprintable.NewWriter(b)
+	w.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	w.Close()
+	b.WriteString("\r\n")
+}
+
+func newBoundary() string {
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return "ideaforge-" + hex.EncodeToString(buf)
+}
+
+// domainOf returns the domain of the address in from ("Name <user@host>" or "user@host")
+func domainOf(from string) string {
+	addr := strings.TrimSuffix(from, ">")
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		return addr[i+1:]
+	}
+	return "localhost"
+}
@@ -0,0 +1,7 @@
+package port
+
+// Renderer renders an email template in a locale, falling back to the default
+// locale when the template is not translated. It returns the locale used.
+type Renderer interface {
+	Render(locale, name string, data any) (subject, text, html, used string, err error)
+}
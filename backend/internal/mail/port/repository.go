@@ -0,0 +1,30 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/dark/idea-forge/internal/mail/domain"
+)
+
+// OutboxRepository persists queued emails
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, m *domain.Message) error
+	// ClaimDue locks pending messages whose next attempt is due by pushing its lease into the
+	// future, so other instances skip them while they are being sent
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.Message, error)
+	// RecordAttempt stores the outcome of an attempt (status, attempts, next attempt and error)
+	RecordAttempt(ctx context.Context, m *domain.Message) error
+	// PurgeSent deletes sent messages older than before; they may hold codes and sign-in links
+	PurgeSent(ctx context.Context, before time.Time) error
+}
+
+// Transport hands a rendered message to the mail system
+type Transport interface {
+	Send(ctx context.Context, m *domain.Message) error
+}
+
+// LocaleResolver returns the preferred locale of a recipient ("" when unknown)
+type LocaleResolver interface {
+	LocaleFor(ctx context.Context, email string) string
+}
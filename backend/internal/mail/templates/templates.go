@@ -0,0 +1,100 @@
+// Package templates holds the email templates, one directory per locale.
+//
+// Every <name>.tmpl defines "subject" and "text" (rendered with text/template)
+// and "content" (rendered with html/template inside the "layout" of
+// layout.tmpl). A locale may omit templates; DefaultLocale is used instead.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// DefaultLocale is used for recipients without a locale and templates missing in theirs
+const DefaultLocale = "es"
+
+//go:embed */*.tmpl
+var files embed.FS
+
+type set struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer renders the embedded templates
+type Renderer struct {
+	sets map[string]map[string]*set // locale -> name -> templates
+}
+
+// Load parses every template so broken ones fail at startup
+func Load() (*Renderer, error) {
+	r := &Renderer{sets: map[string]map[string]*set{}}
+	locales, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range locales {
+		locale := dir.Name()
+		names, err := fs.Glob(files, locale+"/*.tmpl")
+		if err != nil {
+			return nil, err
+		}
+		r.sets[locale] = map[string]*set{}
+		for _, file := range names {
+			name := strings.TrimSuffix(path.Base(file), ".tmpl")
+			if name == "layout" {
+				continue
+			}
+			layout := locale + "/layout.tmpl"
+			text, err := texttemplate.ParseFS(files, layout, file)
+			if err != nil {
+				return nil, err
+			}
+			html, err := htmltemplate.ParseFS(files, layout, file)
+			if err != nil {
+				return nil, err
+			}
+			r.sets[locale][name] = &set{text: text, html: html}
+		}
+	}
+	if _, ok := r.sets[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("missing templates for default locale %q", DefaultLocale)
+	}
+	return r, nil
+}
+
+// Render returns the subject, plain-text and HTML bodies of template name in
+// locale, and the locale actually used
+func (r *Renderer) Render(locale, name string, data any) (subject, text, html, used string, err error) {
+	s, ok := r.sets[locale][name]
+	if !ok {
+		locale = DefaultLocale
+		if s, ok = r.sets[locale][name]; !ok {
+			return "", "", "", "", fmt.Errorf("unknown email template %q", name)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := s.text.ExecuteTemplate(&b, "subject", data); err != nil {
+		return "", "", "", "", err
+	}
+	subject = strings.TrimSpace(b.String())
+
+	b.Reset()
+	if err := s.text.ExecuteTemplate(&b, "text", data); err != nil {
+		return "", "", "", "", err
+	}
+	text = b.String()
+
+	b.Reset()
+	if err := s.html.ExecuteTemplate(&b, "layout", data); err != nil {
+		return "", "", "", "", err
+	}
+	return subject, text, b.String(), locale, nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import "context"
+
+// The methods below implement the email ports of the other modules
+// (auth, workspace, notification) on top of Queue.
+
+func (uc *MailUsecase) SendVerificationCode(ctx context.Context, email, username, code string) error {
+	return uc.Queue(ctx, email, "verification_code", map[string]any{"Username": username, "Code": code})
+}
+
+func (uc *MailUsecase) SendPasswordResetLink(ctx context.Context, email, username, resetLink string) error {
+	return uc.Queue(ctx, email, "password_reset", map[string]any{"Username": username, "Link": resetLink})
+}
+
+func (uc *MailUsecase) SendMagicLink(ctx context.Context, email, username, magicLink string) error {
+	return uc.Queue(ctx, email, "magic_link", map[string]any{"Username": username, "Link": magicLink})
+}
+
+func (uc *MailUsecase) SendEmailChangeCode(ctx context.Context, newEmail, username, code string) error {
+	return uc.Queue(ctx, newEmail, "email_change_code", map[string]any{"Username": username, "Code": code})
+}
+
+func (uc *MailUsecase) SendDataExportLink(ctx context.Context, email, username, downloadLink string) error {
+	return uc.Queue(ctx, email, "data_export", map[string]any{"Username": username, "Link": downloadLink})
+}
+
+func (uc *MailUsecase) SendWorkspaceInvitation(ctx context.Context, email, inviterName, workspaceName, acceptLink string) error {
+	return uc.Queue(ctx, email, "workspace_invitation", map[string]any{
+		"InviterName":   inviterName,
+		"WorkspaceName": workspaceName,
+		"Link":          acceptLink,
+	})
+}
+
+func (uc *MailUsecase) SendCommentMention(ctx context.Context, email, username, authorName, projectTitle, anchor, excerpt, link string) error {
+	return uc.Queue(ctx, email, "comment_mention", map[string]any{
+		"Username":     username,
+		"AuthorName":   authorName,
+		"ProjectTitle": projectTitle,
+		"Anchor":       anchor,
+		"Excerpt":      excerpt,
+		"Link":         link,
+	})
+}
+
+func (uc *MailUsecase) SendCollaboratorAdded(ctx context.Context, email, username, inviterName, projectTitle, role, link string) error {
+	return uc.Queue(ctx, email, "collaborator_added", map[string]any{
+		"Username":     username,
+		"InviterName":  inviterName,
+		"ProjectTitle": projectTitle,
+		"Role":         role,
+		"Link":         link,
+	})
+}
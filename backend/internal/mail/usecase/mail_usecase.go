@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/mail/domain"
+	"github.com/dark/idea-forge/internal/mail/port"
+)
+
+const (
+	pollInterval   = 5 * time.Second
+	claimBatch     = 20
+	claimLease     = 2 * time.Minute
+	purgeInterval  = time.Hour
+	sentRetention  = 7 * 24 * time.Hour
+	maxErrorLength = 1000
+)
+
+// MailUsecase renders emails, queues them in the outbox and sends them from a
+// background worker with retries
+type MailUsecase struct {
+	repo      port.OutboxRepository
+	renderer  port.Renderer
+	transport port.Transport
+	locales   port.LocaleResolver
+	wake      chan struct{}
+}
+
+// NewMailUsecase creates a new mail use case
+func NewMailUsecase(repo port.OutboxRepository, renderer port.Renderer, transport port.Transport, locales port.LocaleResolver) *MailUsecase {
+	return &MailUsecase{
+		repo:      repo,
+		renderer:  renderer,
+		transport: transport,
+		locales:   locales,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Queue renders template in the recipient's locale and queues it. The email is
+// sent by Run, so a failing mail server never fails the caller.
+func (uc *MailUsecase) Queue(ctx context.Context, to, template string, data map[string]any) error {
+	subject, text, html, locale, err := uc.renderer.Render(uc.locales.LocaleFor(ctx, to), template, data)
+	if err != nil {
+		return fmt.Errorf("render %s email: %w", template, err)
+	}
+	now := time.Now()
+	m := &domain.Message{
+		ID:            uuid.New(),
+		To:            to,
+		Template:      template,
+		Locale:        locale,
+		Subject:       subject,
+		Text:          text,
+		HTML:          html,
+		Status:        domain.StatusPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+	if err := uc.repo.Enqueue(ctx, m); err != nil {
+		return err
+	}
+	uc.kick()
+	return nil
+}
+
+// Run sends due emails until ctx is done. Every API instance can run it:
+// messages are claimed with a lease so each one is sent by a single instance.
+func (uc *MailUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	var purgedAt time.Time
+	for {
+		uc.sendDue(ctx)
+		if time.Since(purgedAt) >= purgeInterval {
+			if err := uc.repo.PurgeSent(ctx, time.Now().Add(-sentRetention)); err != nil {
+				log.Printf("mail: error purging sent messages: %v", err)
+			}
+			purgedAt = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-uc.wake:
+		}
+	}
+}
+
+func (uc *MailUsecase) sendDue(ctx context.Context) {
+	messages, err := uc.repo.ClaimDue(ctx, claimBatch, claimLease)
+	if err != nil {
+		log.Printf("mail: error claiming messages: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := range messages {
+		wg.Add(1)
+		go func(m *domain.Message) {
+			defer wg.Done()
+			uc.send(ctx, m)
+		}(&messages[i])
+	}
+	wg.Wait()
+}
+
+func (uc *MailUsecase) send(ctx context.Context, m *domain.Message) {
+	err := uc.transport.Send(ctx, m)
+
+	m.Attempts++
+	switch {
+	case err == nil:
+		now := time.Now()
+		m.Status, m.NextAttemptAt, m.SentAt, m.LastError = domain.StatusSent, nil, &now, ""
+	case errors.Is(err, domain.ErrPermanent) || m.Attempts >= domain.MaxAttempts:
+		m.Status, m.NextAttemptAt, m.LastError = domain.StatusDead, nil, truncate(err.Error())
+		log.Printf("mail: giving up on message %s (%s) after %d attempts: %v", m.ID, m.Template, m.Attempts, err)
+	default:
+		next := time.Now().Add(domain.RetryDelay(m.Attempts))
+		m.Status, m.NextAttemptAt, m.LastError = domain.StatusPending, &next, truncate(err.Error())
+	}
+
+	if err := uc.repo.RecordAttempt(ctx, m); err != nil {
+		log.Printf("mail: error recording attempt of message %s: %v", m.ID, err)
+	}
+}
+
+func (uc *MailUsecase) kick() {
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
+	return s
+}
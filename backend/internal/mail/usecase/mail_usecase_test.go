@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/mail/adapter/mailbox"
+	"github.com/dark/idea-forge/internal/mail/domain"
+	"github.com/dark/idea-forge/internal/mail/port"
+	"github.com/dark/idea-forge/internal/mail/templates"
+)
+
+// memoryOutbox is an in-memory OutboxRepository
+type memoryOutbox struct {
+	mu       sync.Mutex
+	messages map[uuid.UUID]domain.Message
+	last     uuid.UUID
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{messages: map[uuid.UUID]domain.Message{}}
+}
+
+func (o *memoryOutbox) Enqueue(ctx context.Context, m *domain.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages[m.ID] = *m
+	o.last = m.ID
+	return nil
+}
+
+func (o *memoryOutbox) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.Message, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var due []domain.Message
+	for id, m := range o.messages {
+		if m.Status != domain.StatusPending || m.NextAttemptAt.After(time.Now()) || len(due) == limit {
+			continue
+		}
+		due = append(due, m)
+		leased := time.Now().Add(lease)
+		m.NextAttemptAt = &leased
+		o.messages[id] = m
+	}
+	return due, nil
+}
+
+func (o *memoryOutbox) RecordAttempt(ctx context.Context, m *domain.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages[m.ID] = *m
+	return nil
+}
+
+func (o *memoryOutbox) PurgeSent(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func (o *memoryOutbox) get(id uuid.UUID) domain.Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.messages[id]
+}
+
+// fixedLocales resolves every recipient to the same locale
+type fixedLocales string
+
+func (l fixedLocales) LocaleFor(ctx context.Context, email string) string { return string(l) }
+
+// failingTransport fails every send with err
+type failingTransport struct{ err error }
+
+func (t failingTransport) Send(ctx context.Context, m *domain.Message) error { return t.err }
+
+func newTestUsecase(t *testing.T, transport port.Transport, locale string) (*MailUsecase, *memoryOutbox) {
+	t.Helper()
+	renderer, err := templates.Load()
+	if err != nil {
+		t.Fatalf("loading templates: %v", err)
+	}
+	outbox := newMemoryOutbox()
+	return NewMailUsecase(outbox, renderer, transport, fixedLocales(locale)), outbox
+}
+
+func TestSendRecordsOutcome(t *testing.T) {
+	transient := errors.New("connection refused")
+	permanent := fmt.Errorf("550 mailbox unavailable: %w", domain.ErrPermanent)
+
+	tests := []struct {
+		name          string
+		err           error
+		attemptsSoFar int
+		wantStatus    string
+		wantRetryIn   time.Duration // 0 means no next attempt
+		wantLastError string
+	}{
+		{name: "sent", wantStatus: domain.StatusSent},
+		{name: "transient error is retried", err: transient, wantStatus: domain.StatusPending, wantRetryIn: domain.RetryDelay(1), wantLastError: transient.Error()},
+		{name: "retries back off", err: transient, attemptsSoFar: 3, wantStatus: domain.StatusPending, wantRetryIn: domain.RetryDelay(4), wantLastError: transient.Error()},
+		{name: "permanent error goes straight to dead", err: permanent, wantStatus: domain.StatusDead, wantLastError: permanent.Error()},
+		{name: "last attempt goes to dead", err: transient, attemptsSoFar: domain.MaxAttempts - 1, wantStatus: domain.StatusDead, wantLastError: transient.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var transport port.Transport = mailbox.NewMemory()
+			if tt.err != nil {
+				transport = failingTransport{err: tt.err}
+			}
+			uc, outbox := newTestUsecase(t, transport, "es")
+
+			ctx := context.Background()
+			if err := uc.SendVerificationCode(ctx, "ada@example.com", "ada", "123456"); err != nil {
+				t.Fatalf("queue: %v", err)
+			}
+			id := outbox.last
+			queued := outbox.get(id)
+			queued.Attempts = tt.attemptsSoFar
+			outbox.RecordAttempt(ctx, &queued)
+
+			before := time.Now()
+			uc.sendDue(ctx)
+			m := outbox.get(id)
+
+			if m.Status != tt.wantStatus {
+				t.Fatalf("status = %q, want %q", m.Status, tt.wantStatus)
+			}
+			if m.Attempts != tt.attemptsSoFar+1 {
+				t.Errorf("attempts = %d, want %d", m.Attempts, tt.attemptsSoFar+1)
+			}
+			if m.LastError != tt.wantLastError {
+				t.Errorf("last error = %q, want %q", m.LastError, tt.wantLastError)
+			}
+			switch {
+			case tt.wantRetryIn == 0 && m.NextAttemptAt != nil:
+				t.Errorf("next attempt = %v, want none", m.NextAttemptAt)
+			case tt.wantRetryIn != 0 && (m.NextAttemptAt == nil || m.NextAttemptAt.Before(before.Add(tt.wantRetryIn))):
+				t.Errorf("next attempt = %v, want %v from now", m.NextAttemptAt, tt.wantRetryIn)
+			}
+			if (m.SentAt != nil) != (tt.wantStatus == domain.StatusSent) {
+				t.Errorf("sent at = %v with status %q", m.SentAt, m.Status)
+			}
+		})
+	}
+}
+
+func TestQueuedEmailIsMultipartInRecipientLocale(t *testing.T) {
+	tests := []struct {
+		locale      string
+		wantLocale  string
+		wantSubject string
+		wantText    string
+		wantHTML    string
+	}{
+		{locale: "es", wantLocale: "es", wantSubject: "Verifica tu cuenta en Idea Forge", wantText: "Tu código de verificación es: 123456", wantHTML: "<p>Hola ada &lt;script&gt;,</p>"},
+		{locale: "en", wantLocale: "en", wantSubject: "Verify your Idea Forge account", wantText: "Your verification code is: 123456", wantHTML: "<p>Hi ada &lt;script&gt;,</p>"},
+		{locale: "fr", wantLocale: templates.DefaultLocale, wantSubject: "Verifica tu cuenta en Idea Forge", wantText: "Tu código de verificación es: 123456", wantHTML: "<p>Hola ada &lt;script&gt;,</p>"},
+		{locale: "", wantLocale: templates.DefaultLocale, wantSubject: "Verifica tu cuenta en Idea Forge", wantText: "Tu código de verificación es: 123456", wantHTML: "<p>Hola ada &lt;script&gt;,</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			box := mailbox.NewMemory()
+			uc, _ := newTestUsecase(t, box, tt.locale)
+
+			ctx := context.Background()
+			if err := uc.SendVerificationCode(ctx, "ada@example.com", "ada <script>", "123456"); err != nil {
+				t.Fatalf("queue: %v", err)
+			}
+			uc.sendDue(ctx)
+
+			m, ok := box.Last("ada@example.com")
+			if !ok {
+				t.Fatal("no message in the mailbox")
+			}
+			if m.Locale != tt.wantLocale {
+				t.Errorf("locale = %q, want %q", m.Locale, tt.wantLocale)
+			}
+
+			subject, parts := parseMIME(t, m.MIME("Idea Forge <noreply@ideaforge.dev>"))
+			if subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", subject, tt.wantSubject)
+			}
+			if !strings.Contains(parts["text/plain"], tt.wantText) {
+				t.Errorf("text part does not contain %q:\n%s", tt.wantText, parts["text/plain"])
+			}
+			if !strings.Contains(parts["text/html"], tt.wantHTML) {
+				t.Errorf("html part does not contain %q:\n%s", tt.wantHTML, parts["text/html"])
+			}
+		})
+	}
+}
+
+// parseMIME returns the decoded subject and the decoded body of each part by content type
+func parseMIME(t *testing.T, raw []byte) (string, map[string]string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("reading message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decoding subject: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q (%v), want multipart/alternative", mediaType, err)
+	}
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading part: %v", err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		body, err := io.ReadAll(part) // quoted-printable is decoded by the reader
+		if err != nil {
+			t.Fatalf("reading %s part: %v", contentType, err)
+		}
+		parts[contentType] = strings.ReplaceAll(string(body), "\r\n", "\n")
+	}
+	if len(parts) != 2 {
+		t.Fatalf("got %d parts, want text/plain and text/html", len(parts))
+	}
+	return subject, parts
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const UserRoleKey contextKey = "user_role"
+
+// UserAccess es el estado de acceso vigente de un usuario (rol y estado de la cuenta)
+type UserAccess struct {
+	Role   string
+	Status string
+}
+
+// UserAccessLookup obtiene el acceso vigente de un usuario desde la base de datos
+type UserAccessLookup interface {
+	GetUserAccess(ctx context.Context, userID uuid.UUID) (*UserAccess, error)
+}
+
+// LoadUserAccess debe ir después de AuthMiddleware. Consulta el rol y estado actuales
+// para que una suspensión o un cambio de rol apliquen sin esperar a que expire el JWT.
+func LoadUserAccess(lookup UserAccessLookup) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserIDFromContext(r.Context())
+			if !ok {
+				respondError(w, http.StatusUnauthorized, "Usuario no autenticado")
+				return
+			}
+
+			access, err := lookup.GetUserAccess(r.Context(), userID)
+			if err != nil {
+				respondError(w, http.StatusUnauthorized, "Usuario no encontrado")
+				return
+			}
+
+			if access.Status == "suspended" {
+				respondError(w, http.StatusForbidden, "usuario suspendido")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserRoleKey, access.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole permite el acceso solo a usuarios con alguno de los roles indicados.
+// Debe ir después de LoadUserAccess.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := GetUserRoleFromContext(r.Context())
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			respondError(w, http.StatusForbidden, "Permisos insuficientes")
+		})
+	}
+}
+
+// GetUserRoleFromContext obtiene el rol del usuario del contexto
+func GetUserRoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(UserRoleKey).(string)
+	return role, ok
+}
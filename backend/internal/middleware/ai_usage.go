@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AIUsageRecorder registra una llamada al servicio de IA atribuida a un usuario
+type AIUsageRecorder interface {
+	RecordAICall(ctx context.Context, userID uuid.UUID, endpoint string) error
+}
+
+// AIUsageTransport cuenta las llamadas salientes a Genkit por usuario.
+// El usuario se toma del contexto de la request saliente, así que las llamadas
+// deben construirse con http.NewRequestWithContext a partir del contexto del handler.
+type AIUsageTransport struct {
+	Base     http.RoundTripper
+	Recorder AIUsageRecorder
+}
+
+func (t *AIUsageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+
+	if userID, ok := GetUserIDFromContext(req.Context()); ok {
+		// Contexto propio: el de la request puede estar por cancelarse
+		recordCtx, cancel := context.WithTimeout(context.WithoutCancel(req.Context()), 5*time.Second)
+		defer cancel()
+		if recErr := t.Recorder.RecordAICall(recordCtx, userID, req.URL.Path); recErr != nil {
+			log.Printf("error recording AI usage for user %s: %v", userID, recErr)
+		}
+	}
+
+	return resp, err
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -14,6 +15,9 @@ type contextKey string
 
 const UserIDKey contextKey = "user_id"
 
+// AuthenticatedAtKey guarda cuándo se emitió el token (claim iat), es decir, cuándo inició sesión el usuario
+const AuthenticatedAtKey contextKey = "authenticated_at"
+
 // AuthMiddleware valida el JWT token
 func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -75,6 +79,9 @@ func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 
 			// Agregar user_id al contexto
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+				ctx = context.WithValue(ctx, AuthenticatedAtKey, iat.Time)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -86,6 +93,12 @@ func GetUserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	return userID, ok
 }
 
+// GetAuthenticatedAtFromContext obtiene cuándo se emitió el token de la sesión
+func GetAuthenticatedAtFromContext(ctx context.Context) (time.Time, bool) {
+	authenticatedAt, ok := ctx.Value(AuthenticatedAtKey).(time.Time)
+	return authenticatedAt, ok
+}
+
 func respondError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,76 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/dark/idea-forge/internal/notification/domain"
+	"github.com/dark/idea-forge/internal/notification/usecase"
+)
+
+// Handlers exposes the notification preferences of the current user. Must be registered behind the auth middleware.
+type Handlers struct {
+	Usecase *usecase.NotificationUsecase
+}
+
+func (h *Handlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /notifications/preferences", h.getPreferences)
+	mux.HandleFunc("PUT /notifications/preferences", h.updatePreferences)
+}
+
+func (h *Handlers) getPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.Usecase.GetPreferences(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, prefs, http.StatusOK)
+}
+
+func (h *Handlers) updatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in usecase.UpdatePreferencesInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.Usecase.UpdatePreferences(r.Context(), userID, in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, prefs, http.StatusOK)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMode),
+		errors.Is(err, domain.ErrInvalidFrequency),
+		errors.Is(err, domain.ErrInvalidStaleDays):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("notification error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,216 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dark/idea-forge/internal/notification/domain"
+	"github.com/dark/idea-forge/internal/notification/port"
+	"github.com/google/uuid"
+)
+
+type repo struct{ db *sql.DB }
+
+func NewRepo(db *sql.DB) port.NotificationRepository { return &repo{db: db} }
+
+const preferenceColumns = `comment_mentions, collaborator_added, stale_stages, stale_modules, stale_after_days, digest, last_digest_at, updated_at`
+
+func (r *repo) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.Preferences, error) {
+	p := &domain.Preferences{UserID: userID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT `+preferenceColumns+`
+		FROM notification_preferences WHERE user_id = $1
+	`, userID).Scan(&p.CommentMentions, &p.CollaboratorAdded, &p.StaleStages, &p.StaleModules, &p.StaleAfterDays, &p.Digest, &p.LastDigestAt, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *repo) SavePreferences(ctx context.Context, p *domain.Preferences) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notification_preferences (user_id, `+preferenceColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE SET
+			comment_mentions = EXCLUDED.comment_mentions,
+			collaborator_added = EXCLUDED.collaborator_added,
+			stale_stages = EXCLUDED.stale_stages,
+			stale_modules = EXCLUDED.stale_modules,
+			stale_after_days = EXCLUDED.stale_after_days,
+			digest = EXCLUDED.digest,
+			updated_at = EXCLUDED.updated_at
+	`, p.UserID, p.CommentMentions, p.CollaboratorAdded, p.StaleStages, p.StaleModules, p.StaleAfterDays, p.Digest, p.LastDigestAt, p.UpdatedAt)
+	return err
+}
+
+func (r *repo) ListRecipients(ctx context.Context) ([]domain.Recipient, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.email, u.username, p.user_id IS NOT NULL,
+			COALESCE(p.comment_mentions, ''), COALESCE(p.collaborator_added, ''),
+			COALESCE(p.stale_stages, FALSE), COALESCE(p.stale_modules, FALSE),
+			COALESCE(p.stale_after_days, 0), COALESCE(p.digest, ''), p.last_digest_at
+		FROM users u
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE u.status = 'active' AND (p.user_id IS NULL OR p.digest <> 'off')
+		ORDER BY u.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []domain.Recipient
+	for rows.Next() {
+		var rc domain.Recipient
+		var saved bool
+		var p domain.Preferences
+		if err := rows.Scan(&rc.UserID, &rc.Email, &rc.Username, &saved,
+			&p.CommentMentions, &p.CollaboratorAdded, &p.StaleStages, &p.StaleModules,
+			&p.StaleAfterDays, &p.Digest, &p.LastDigestAt); err != nil {
+			return nil, err
+		}
+		if saved {
+			p.UserID = rc.UserID
+			rc.Preferences = &p
+		} else {
+			rc.Preferences = domain.DefaultPreferences(rc.UserID)
+			rc.Preferences.LastDigestAt = p.LastDigestAt
+		}
+		recipients = append(recipients, rc)
+	}
+	return recipients, rows.Err()
+}
+
+func (r *repo) ClaimDigest(ctx context.Context, userID uuid.UUID, previous *time.Time, now time.Time) (bool, error) {
+	// Users on the defaults get their row here; the column defaults match domain.DefaultPreferences
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO notification_preferences (user_id, last_digest_at, updated_at)
+		VALUES ($1, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE SET last_digest_at = EXCLUDED.last_digest_at
+		WHERE notification_preferences.last_digest_at IS NOT DISTINCT FROM $2
+	`, userID, previous, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *repo) Enqueue(ctx context.Context, n *domain.Notification) error {
+	data, err := json.Marshal(n.Data)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO notifications (id, user_id, kind, idea_id, data, link, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, n.ID, n.UserID, n.Kind, n.IdeaID, data, n.Link, n.CreatedAt)
+	return err
+}
+
+func (r *repo) ListPending(ctx context.Context, userID uuid.UUID, until time.Time) ([]domain.Notification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, kind, idea_id, data, link, created_at
+		FROM notifications
+		WHERE user_id = $1 AND created_at <= $2
+		ORDER BY created_at, id
+	`, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []domain.Notification
+	for rows.Next() {
+		var n domain.Notification
+		var data []byte
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.IdeaID, &data, &n.Link, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &n.Data); err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+	return list, rows.Err()
+}
+
+func (r *repo) DeletePending(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM notifications WHERE user_id = $1 AND created_at <= $2`, userID, until)
+	return err
+}
+
+func (r *repo) StaleStages(ctx context.Context, userID uuid.UUID, before time.Time) ([]domain.StaleStage, error) {
+	// The current stage is the first one not completed; its last change is the latest
+	// write to the project or activity recorded on it
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, title, stage, last_change FROM (
+			SELECT i.id, i.title,
+				CASE
+					WHEN NOT COALESCE(i.completed, FALSE) THEN 'idea'
+					WHEN ap.id IS NULL OR NOT COALESCE(ap.completed, FALSE) THEN 'action_plan'
+					WHEN a.id IS NULL OR NOT COALESCE(a.completed, FALSE) THEN 'architecture'
+				END AS stage,
+				GREATEST(i.created_at, ap.updated_at, a.updated_at,
+					(SELECT MAX(pa.occurred_at) FROM project_activity pa WHERE pa.idea_id = i.id)) AS last_change
+			FROM ideation_ideas i
+			LEFT JOIN action_plans ap ON ap.idea_id = i.id
+			LEFT JOIN architectures a ON a.action_plan_id = ap.id
+			WHERE i.user_id = $1
+		) p
+		WHERE stage IS NOT NULL AND last_change < $2
+		ORDER BY last_change
+	`, userID, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []domain.StaleStage
+	for rows.Next() {
+		var s domain.StaleStage
+		if err := rows.Scan(&s.IdeaID, &s.ProjectTitle, &s.Stage, &s.LastChange); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+func (r *repo) StaleModules(ctx context.Context, userID uuid.UUID, before time.Time) ([]domain.StaleModule, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.id, i.title, m.name, m.updated_at
+		FROM development_modules m
+		JOIN architectures a ON a.id = m.architecture_id
+		JOIN action_plans ap ON ap.id = a.action_plan_id
+		JOIN ideation_ideas i ON i.id = ap.idea_id
+		WHERE i.user_id = $1 AND m.status = 'in_progress' AND m.updated_at < $2
+		ORDER BY m.updated_at
+	`, userID, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []domain.StaleModule
+	for rows.Next() {
+		var m domain.StaleModule
+		if err := rows.Scan(&m.IdeaID, &m.ProjectTitle, &m.ModuleName, &m.Since); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+func (r *repo) ProjectTitle(ctx context.Context, ideaID uuid.UUID) (string, error) {
+	var title string
+	err := r.db.QueryRowContext(ctx, `SELECT title FROM ideation_ideas WHERE id = $1`, ideaID).Scan(&title)
+	return title, err
+}
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification kinds
+const (
+	KindCommentMention  = "comment_mention"
+	KindCollaboratorAdd = "collaborator_added"
+	KindStaleStage      = "stale_stage"
+	KindStaleModule     = "stale_module"
+)
+
+// Delivery modes of the event notifications (mentions, collaborator grants)
+const (
+	ModeInstant = "instant" // one email right away
+	ModeDigest  = "digest"  // batched into the next digest
+	ModeOff     = "off"
+)
+
+// Digest frequencies
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+	DigestOff    = "off"
+)
+
+// MaxStaleAfterDays bounds the stale threshold
+const MaxStaleAfterDays = 90
+
+var (
+	ErrInvalidMode      = errors.New("mode must be instant, digest or off")
+	ErrInvalidFrequency = errors.New("digest must be daily, weekly or off")
+	ErrInvalidStaleDays = errors.New("stale_after_days must be between 1 and 90")
+	ErrNotFound         = errors.New("notification preferences not found")
+)
+
+// Preferences are the notification settings of a user. Stale alerts are only
+// sent in digests.
+type Preferences struct {
+	UserID            uuid.UUID  `json:"user_id"`
+	CommentMentions   string     `json:"comment_mentions"`
+	CollaboratorAdded string     `json:"collaborator_added"`
+	StaleStages       bool       `json:"stale_stages"`  // projects whose current stage has not changed in StaleAfterDays
+	StaleModules      bool       `json:"stale_modules"` // modules left in_progress for more than StaleAfterDays
+	StaleAfterDays    int        `json:"stale_after_days"`
+	Digest            string     `json:"digest"`
+	LastDigestAt      *time.Time `json:"last_digest_at,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// DefaultPreferences apply to users who never changed their settings
+// (keep in sync with the column defaults of notification_preferences)
+func DefaultPreferences(userID uuid.UUID) *Preferences {
+	return &Preferences{
+		UserID:            userID,
+		CommentMentions:   ModeInstant,
+		CollaboratorAdded: ModeInstant,
+		StaleStages:       true,
+		StaleModules:      true,
+		StaleAfterDays:    7,
+		Digest:            DigestWeekly,
+	}
+}
+
+// Validate checks the user-editable fields
+func (p *Preferences) Validate() error {
+	for _, mode := range []string{p.CommentMentions, p.CollaboratorAdded} {
+		if mode != ModeInstant && mode != ModeDigest && mode != ModeOff {
+			return ErrInvalidMode
+		}
+	}
+	if p.Digest != DigestDaily && p.Digest != DigestWeekly && p.Digest != DigestOff {
+		return ErrInvalidFrequency
+	}
+	if p.StaleAfterDays < 1 || p.StaleAfterDays > MaxStaleAfterDays {
+		return ErrInvalidStaleDays
+	}
+	return nil
+}
+
+// ModeFor returns how notifications of kind are delivered
+func (p *Preferences) ModeFor(kind string) string {
+	mode := ModeOff
+	switch kind {
+	case KindCommentMention:
+		mode = p.CommentMentions
+	case KindCollaboratorAdd:
+		mode = p.CollaboratorAdded
+	}
+	// Without digests, batched notifications would never be sent
+	if mode == ModeDigest && p.Digest == DigestOff {
+		return ModeInstant
+	}
+	return mode
+}
+
+// DigestDue tells whether the next digest should go out at now. A small slack
+// keeps an hourly job from drifting a day later every time.
+func (p *Preferences) DigestDue(now time.Time) bool {
+	var period time.Duration
+	switch p.Digest {
+	case DigestDaily:
+		period = 24 * time.Hour
+	case DigestWeekly:
+		period = 7 * 24 * time.Hour
+	default:
+		return false
+	}
+	return p.LastDigestAt == nil || now.Sub(*p.LastDigestAt) >= period-time.Hour
+}
+
+// Notification is an event notification waiting for the next digest. Data holds
+// the fields the digest template shows for its kind.
+type Notification struct {
+	ID        uuid.UUID         `json:"id"`
+	UserID    uuid.UUID         `json:"user_id"`
+	Kind      string            `json:"kind"`
+	IdeaID    *uuid.UUID        `json:"idea_id,omitempty"`
+	Data      map[string]string `json:"data"`
+	Link      string            `json:"link"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Mention is a user mentioned in a comment
+type Mention struct {
+	UserID       uuid.UUID
+	Email        string
+	Username     string
+	IdeaID       uuid.UUID
+	AuthorName   string
+	ProjectTitle string
+	Anchor       string
+	Excerpt      string
+	Link         string
+}
+
+// CollaboratorGrant is a user given access to a project
+type CollaboratorGrant struct {
+	UserID      uuid.UUID
+	Email       string
+	Username    string
+	IdeaID      uuid.UUID
+	InviterName string
+	Role        string
+}
+
+// StaleStage is a project whose current stage has not changed for a while
+type StaleStage struct {
+	IdeaID       uuid.UUID
+	ProjectTitle string
+	Stage        string // idea, action_plan or architecture
+	LastChange   time.Time
+	Days         int
+	Link         string
+}
+
+// StaleModule is a development module left in progress
+type StaleModule struct {
+	IdeaID       uuid.UUID
+	ProjectTitle string
+	ModuleName   string
+	Since        time.Time
+	Days         int
+}
+
+// Digest batches the notifications of a user for one period
+type Digest struct {
+	Frequency    string // DigestDaily or DigestWeekly
+	Mentions     []Notification
+	Shared       []Notification
+	StaleStages  []StaleStage
+	StaleModules []StaleModule
+}
+
+// Empty reports whether there is nothing to send
+func (d *Digest) Empty() bool {
+	return len(d.Mentions) == 0 && len(d.Shared) == 0 && len(d.StaleStages) == 0 && len(d.StaleModules) == 0
+}
+
+// Recipient is a user the digest job considers, with their preferences
+// (defaults when they never changed them)
+type Recipient struct {
+	UserID      uuid.UUID
+	Email       string
+	Username    string
+	Preferences *Preferences
+}
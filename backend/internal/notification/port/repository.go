@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/notification/domain"
+)
+
+// NotificationRepository persists notification preferences and the digest queue
+type NotificationRepository interface {
+	// GetPreferences returns domain.ErrNotFound when the user never saved preferences
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.Preferences, error)
+	SavePreferences(ctx context.Context, prefs *domain.Preferences) error
+	// ListRecipients returns the active users with digests enabled, with their preferences
+	ListRecipients(ctx context.Context) ([]domain.Recipient, error)
+	// ClaimDigest sets last_digest_at to now if it still holds previous, so only one
+	// instance sends each digest
+	ClaimDigest(ctx context.Context, userID uuid.UUID, previous *time.Time, now time.Time) (bool, error)
+
+	Enqueue(ctx context.Context, n *domain.Notification) error
+	// ListPending returns the notifications queued for the user up to now, oldest first
+	ListPending(ctx context.Context, userID uuid.UUID, until time.Time) ([]domain.Notification, error)
+	DeletePending(ctx context.Context, userID uuid.UUID, until time.Time) error
+
+	// StaleStages returns the projects of the user whose current stage has not changed since before
+	StaleStages(ctx context.Context, userID uuid.UUID, before time.Time) ([]domain.StaleStage, error)
+	// StaleModules returns the modules of the user's projects in progress since before
+	StaleModules(ctx context.Context, userID uuid.UUID, before time.Time) ([]domain.StaleModule, error)
+	ProjectTitle(ctx context.Context, ideaID uuid.UUID) (string, error)
+}
+
+// Mailer sends the notification emails
+type Mailer interface {
+	SendCommentMention(ctx context.Context, email, username, authorName, projectTitle, anchor, excerpt, link string) error
+	SendCollaboratorAdded(ctx context.Context, email, username, inviterName, projectTitle, role, link string) error
+	SendDigest(ctx context.Context, email, username string, digest *domain.Digest) error
+}
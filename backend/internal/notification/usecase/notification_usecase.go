@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/notification/domain"
+	"github.com/dark/idea-forge/internal/notification/port"
+)
+
+// digestInterval is how often the digest job looks for users whose digest is due
+const digestInterval = time.Hour
+
+// NotificationUsecase sends notification emails right away or batches them into
+// daily or weekly digests, following the preferences of each user
+type NotificationUsecase struct {
+	repo        port.NotificationRepository
+	mailer      port.Mailer
+	frontendURL string
+}
+
+// NewNotificationUsecase creates a new notification use case
+func NewNotificationUsecase(repo port.NotificationRepository, mailer port.Mailer, frontendURL string) *NotificationUsecase {
+	return &NotificationUsecase{repo: repo, mailer: mailer, frontendURL: frontendURL}
+}
+
+// UpdatePreferencesInput holds the fields to change; nil fields are left as they are
+type UpdatePreferencesInput struct {
+	CommentMentions   *string `json:"comment_mentions"`
+	CollaboratorAdded *string `json:"collaborator_added"`
+	StaleStages       *bool   `json:"stale_stages"`
+	StaleModules      *bool   `json:"stale_modules"`
+	StaleAfterDays    *int    `json:"stale_after_days"`
+	Digest            *string `json:"digest"`
+}
+
+// GetPreferences returns the preferences of the user, or the defaults
+func (uc *NotificationUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.Preferences, error) {
+	prefs, err := uc.repo.GetPreferences(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultPreferences(userID), nil
+	}
+	return prefs, err
+}
+
+// UpdatePreferences changes the preferences of the user
+func (uc *NotificationUsecase) UpdatePreferences(ctx context.Context, userID uuid.UUID, in UpdatePreferencesInput) (*domain.Preferences, error) {
+	prefs, err := uc.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if in.CommentMentions != nil {
+		prefs.CommentMentions = *in.CommentMentions
+	}
+	if in.CollaboratorAdded != nil {
+		prefs.CollaboratorAdded = *in.CollaboratorAdded
+	}
+	if in.StaleStages != nil {
+		prefs.StaleStages = *in.StaleStages
+	}
+	if in.StaleModules != nil {
+		prefs.StaleModules = *in.StaleModules
+	}
+	if in.StaleAfterDays != nil {
+		prefs.StaleAfterDays = *in.StaleAfterDays
+	}
+	if in.Digest != nil {
+		prefs.Digest = *in.Digest
+	}
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+	prefs.UpdatedAt = time.Now()
+	if err := uc.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// NotifyMention tells a user they were mentioned in a comment
+func (uc *NotificationUsecase) NotifyMention(ctx context.Context, m domain.Mention) error {
+	mode, err := uc.mode(ctx, m.UserID, domain.KindCommentMention)
+	if err != nil {
+		return err
+	}
+	switch mode {
+	case domain.ModeInstant:
+		return uc.mailer.SendCommentMention(ctx, m.Email, m.Username, m.AuthorName, m.ProjectTitle, m.Anchor, m.Excerpt, m.Link)
+	case domain.ModeDigest:
+		return uc.enqueue(ctx, m.UserID, domain.KindCommentMention, m.IdeaID, m.Link, map[string]string{
+			"Author":  m.AuthorName,
+			"Project": m.ProjectTitle,
+			"Anchor":  m.Anchor,
+			"Excerpt": m.Excerpt,
+		})
+	}
+	return nil
+}
+
+// NotifyCollaboratorAdded tells a user they were given access to a project
+func (uc *NotificationUsecase) NotifyCollaboratorAdded(ctx context.Context, g domain.CollaboratorGrant) error {
+	mode, err := uc.mode(ctx, g.UserID, domain.KindCollaboratorAdd)
+	if err != nil || mode == domain.ModeOff {
+		return err
+	}
+	title, err := uc.repo.ProjectTitle(ctx, g.IdeaID)
+	if err != nil {
+		return err
+	}
+	link := uc.projectLink(g.IdeaID)
+	if mode == domain.ModeInstant {
+		return uc.mailer.SendCollaboratorAdded(ctx, g.Email, g.Username, g.InviterName, title, g.Role, link)
+	}
+	return uc.enqueue(ctx, g.UserID, domain.KindCollaboratorAdd, g.IdeaID, link, map[string]string{
+		"Inviter": g.InviterName,
+		"Project": title,
+		"Role":    g.Role,
+	})
+}
+
+// Run sends the digests that are due until ctx is done. Every API instance can run it:
+// each digest is claimed before being sent so it goes out once.
+func (uc *NotificationUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(digestInterval)
+	defer ticker.Stop()
+	for {
+		uc.sendDue(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *NotificationUsecase) sendDue(ctx context.Context, now time.Time) {
+	recipients, err := uc.repo.ListRecipients(ctx)
+	if err != nil {
+		log.Printf("error listing digest recipients: %v", err)
+		return
+	}
+	for i := range recipients {
+		r := &recipients[i]
+		if !r.Preferences.DigestDue(now) {
+			continue
+		}
+		if err := uc.sendDigest(ctx, r, now); err != nil {
+			log.Printf("error sending digest to %s: %v", r.UserID, err)
+		}
+	}
+}
+
+func (uc *NotificationUsecase) sendDigest(ctx context.Context, r *domain.Recipient, now time.Time) error {
+	prefs := r.Preferences
+	claimed, err := uc.repo.ClaimDigest(ctx, r.UserID, prefs.LastDigestAt, now)
+	if err != nil || !claimed {
+		return err
+	}
+
+	digest := &domain.Digest{Frequency: prefs.Digest}
+	pending, err := uc.repo.ListPending(ctx, r.UserID, now)
+	if err != nil {
+		return err
+	}
+	for _, n := range pending {
+		if n.Kind == domain.KindCommentMention {
+			digest.Mentions = append(digest.Mentions, n)
+		} else {
+			digest.Shared = append(digest.Shared, n)
+		}
+	}
+
+	cutoff := now.AddDate(0, 0, -prefs.StaleAfterDays)
+	if prefs.StaleStages {
+		if digest.StaleStages, err = uc.repo.StaleStages(ctx, r.UserID, cutoff); err != nil {
+			return err
+		}
+		for i := range digest.StaleStages {
+			s := &digest.StaleStages[i]
+			s.Days, s.Link = days(now, s.LastChange), uc.projectLink(s.IdeaID)
+		}
+	}
+	if prefs.StaleModules {
+		if digest.StaleModules, err = uc.repo.StaleModules(ctx, r.UserID, cutoff); err != nil {
+			return err
+		}
+		for i := range digest.StaleModules {
+			digest.StaleModules[i].Days = days(now, digest.StaleModules[i].Since)
+		}
+	}
+	if digest.Empty() {
+		return nil
+	}
+
+	if err := uc.mailer.SendDigest(ctx, r.Email, r.Username, digest); err != nil {
+		return err
+	}
+	return uc.repo.DeletePending(ctx, r.UserID, now)
+}
+
+func (uc *NotificationUsecase) mode(ctx context.Context, userID uuid.UUID, kind string) (string, error) {
+	prefs, err := uc.GetPreferences(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return prefs.ModeFor(kind), nil
+}
+
+func (uc *NotificationUsecase) enqueue(ctx context.Context, userID uuid.UUID, kind string, ideaID uuid.UUID, link string, data map[string]string) error {
+	return uc.repo.Enqueue(ctx, &domain.Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Kind:      kind,
+		IdeaID:    &ideaID,
+		Data:      data,
+		Link:      link,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (uc *NotificationUsecase) projectLink(ideaID uuid.UUID) string {
+	return uc.frontendURL + "/ideation/" + ideaID.String()
+}
+
+func days(now, since time.Time) int {
+	return int(now.Sub(since).Hours() / 24)
+}
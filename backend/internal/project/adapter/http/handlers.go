@@ -0,0 +1,199 @@
+package httpadapter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/usecase"
+	"github.com/google/uuid"
+)
+
+// maxImportBody bounds the size of an uploaded project bundle
+const maxImportBody = 32 << 20
+
+// Handlers exposes project-level endpoints. Access to {ideaID} is checked by the
+// access guard: listing needs read access, changes need the owner role.
+type Handlers struct {
+	Projects      *usecase.ProjectUsecase
+	Imports       *usecase.ImportUsecase
+	Collaborators *usecase.CollaboratorUsecase
+	ShareLinks    *usecase.ShareLinkUsecase
+
+	// Workspaces resolves the personal workspace when an import names none
+	Workspaces interface {
+		PersonalWorkspaceID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	}
+}
+
+func (h *Handlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /projects/{ideaID}/export", h.export)
+	mux.HandleFunc("POST /projects/import", h.importProject)
+
+	mux.HandleFunc("GET /projects/{ideaID}/collaborators", h.listCollaborators)
+	mux.HandleFunc("POST /projects/{ideaID}/collaborators", h.addCollaborator)
+	mux.HandleFunc("DELETE /projects/{ideaID}/collaborators/{userID}", h.removeCollaborator)
+
+	mux.HandleFunc("GET /projects/{ideaID}/share-links", h.listShareLinks)
+	mux.HandleFunc("POST /projects/{ideaID}/share-links", h.createShareLink)
+	mux.HandleFunc("DELETE /projects/{ideaID}/share-links/{linkID}", h.revokeShareLink)
+}
+
+func (h *Handlers) export(w http.ResponseWriter, r *http.Request) {
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.Projects.Export(r.Context(), ideaID, r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	w.Write(export.Content)
+}
+
+// importProject takes a project.json document or a zip bundle as the body. The
+// workspace goes in the query string (write access is checked by the guard).
+func (h *Handlers) importProject(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	workspaceID := uuid.Nil
+	if raw := r.URL.Query().Get("workspace_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+			return
+		}
+		workspaceID = id
+	} else if h.Workspaces != nil {
+		id, err := h.Workspaces.PersonalWorkspaceID(r.Context(), userID)
+		if err != nil {
+			log.Printf("error resolving personal workspace: %v", err)
+			http.Error(w, "error resolving workspace", http.StatusInternalServerError)
+			return
+		}
+		workspaceID = id
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBody))
+	if err != nil {
+		http.Error(w, "bundle too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	project, err := h.Imports.Import(r.Context(), userID, workspaceID, data)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, project, http.StatusCreated)
+}
+
+func (h *Handlers) listCollaborators(w http.ResponseWriter, r *http.Request) {
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+
+	collaborators, err := h.Collaborators.List(r.Context(), ideaID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, collaborators, http.StatusOK)
+}
+
+func (h *Handlers) addCollaborator(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+
+	var in usecase.AddCollaboratorInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	collaborator, err := h.Collaborators.Add(r.Context(), userID, ideaID, in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, collaborator, http.StatusCreated)
+}
+
+func (h *Handlers) removeCollaborator(w http.ResponseWriter, r *http.Request) {
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Collaborators.Remove(r.Context(), ideaID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCollaboratorNotFound),
+		errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrShareLinkNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidCollaborator),
+		errors.Is(err, domain.ErrInvalidRole),
+		errors.Is(err, domain.ErrSelfCollaborator),
+		errors.Is(err, domain.ErrInvalidExpiry),
+		errors.Is(err, domain.ErrInvalidExportFormat),
+		errors.Is(err, domain.ErrInvalidBundle):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrSharePasswordRequired),
+		errors.Is(err, domain.ErrInvalidSharePassword):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, domain.ErrShareLinkExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+	case errors.Is(err, domain.ErrTooManyShareAttempts):
+		setRetryAfter(w)
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		log.Printf("project error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
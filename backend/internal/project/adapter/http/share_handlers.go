@@ -0,0 +1,153 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/usecase"
+	"github.com/google/uuid"
+)
+
+// RegisterPublic registers the public share link routes (no authentication)
+func (h *Handlers) RegisterPublic(mux *http.ServeMux) {
+	mux.HandleFunc("GET /share/{token}", h.openShareLink)
+	mux.HandleFunc("POST /share/{token}", h.openShareLink)
+}
+
+func (h *Handlers) listShareLinks(w http.ResponseWriter, r *http.Request) {
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+
+	links, err := h.ShareLinks.List(r.Context(), ideaID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, links, http.StatusOK)
+}
+
+func (h *Handlers) createShareLink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+
+	var in usecase.CreateShareLinkInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.ShareLinks.Create(r.Context(), userID, ideaID, in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, link, http.StatusCreated)
+}
+
+func (h *Handlers) revokeShareLink(w http.ResponseWriter, r *http.Request) {
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+	linkID, err := uuid.Parse(r.PathValue("linkID"))
+	if err != nil {
+		http.Error(w, "invalid link id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ShareLinks.Revoke(r.Context(), ideaID, linkID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// openShareLink serves the shared project as JSON, or as an HTML page for browsers
+// (Accept: text/html or ?format=html). Protected links take the password from the
+// X-Share-Password header or from the form posted by the HTML page.
+func (h *Handlers) openShareLink(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	password := r.Header.Get("X-Share-Password")
+	if r.Method == http.MethodPost {
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<16)
+		password = r.PostFormValue("password")
+	}
+
+	html := wantsHTML(r)
+	project, err := h.ShareLinks.Open(r.Context(), r.PathValue("token"), password, clientIP(r))
+	if err != nil {
+		if html && (errors.Is(err, domain.ErrSharePasswordRequired) || errors.Is(err, domain.ErrInvalidSharePassword) ||
+			errors.Is(err, domain.ErrTooManyShareAttempts)) {
+			var data struct{ Error string }
+			status := http.StatusUnauthorized
+			switch {
+			case errors.Is(err, domain.ErrInvalidSharePassword):
+				data.Error = "Contraseña incorrecta"
+			case errors.Is(err, domain.ErrTooManyShareAttempts):
+				data.Error = "Demasiados intentos. Prueba de nuevo en unos minutos."
+				status = http.StatusTooManyRequests
+				setRetryAfter(w)
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(status)
+			sharePasswordPage.Execute(w, data)
+			return
+		}
+		writeError(w, err)
+		return
+	}
+
+	if !html {
+		writeJSON(w, project, http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := sharePage.Execute(w, project); err != nil {
+		log.Printf("error rendering share page: %v", err)
+	}
+}
+
+// clientIP is the address of the caller. Forwarding headers are ignored since
+// any client can set them to dodge the per-IP limit.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func setRetryAfter(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(usecase.PasswordAttemptWindow.Seconds())))
+}
+
+func wantsHTML(r *http.Request) bool {
+	switch r.URL.Query().Get("format") {
+	case "html":
+		return true
+	case "json":
+		return false
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
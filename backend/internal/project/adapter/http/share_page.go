@@ -0,0 +1,136 @@
+package httpadapter
+
+import (
+	"html/template"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/usecase"
+)
+
+type transcript struct {
+	Title    string
+	Messages []domain.ChatMessage
+}
+
+// transcripts lists the non-empty chat histories in pipeline order
+func transcripts(c *domain.Chats) []transcript {
+	var out []transcript
+	for _, t := range []transcript{
+		{"Ideación", c.Ideation},
+		{"Plan de acción", c.ActionPlan},
+		{"Arquitectura", c.Architecture},
+		{"Chat global", c.Global},
+	} {
+		if len(t.Messages) > 0 {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// sharePage renders a shared project as a standalone read-only HTML page
+var sharePage = template.Must(template.New("share").Funcs(template.FuncMap{
+	"deps":        usecase.ParseDependencies,
+	"transcripts": transcripts,
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Idea.Title}} · Idea Forge</title>
+<style>
+  body { font-family: system-ui, -apple-system, sans-serif; max-width: 860px; margin: 2rem auto; padding: 0 1rem; color: #1f2937; line-height: 1.55; }
+  h1 { margin-bottom: .25rem; }
+  h2 { border-bottom: 1px solid #e5e7eb; padding-bottom: .25rem; margin-top: 2.5rem; }
+  h3 { margin-bottom: .25rem; }
+  .meta { color: #6b7280; font-size: .9rem; }
+  .content { white-space: pre-wrap; }
+  .module { border: 1px solid #e5e7eb; border-radius: 8px; padding: .75rem 1rem; margin: 1rem 0; }
+  .message { border-left: 3px solid #e5e7eb; padding-left: .75rem; margin: .75rem 0; }
+  footer { margin: 3rem 0 1rem; color: #9ca3af; font-size: .8rem; text-align: center; }
+</style>
+</head>
+<body>
+<h1>{{.Idea.Title}}</h1>
+<p class="meta">Proyecto compartido en modo lectura · creado el {{.Idea.CreatedAt.Format "2006-01-02"}}</p>
+
+<h2>Idea</h2>
+<h3>Objetivo</h3><div class="content">{{.Idea.Objective}}</div>
+<h3>Problema</h3><div class="content">{{.Idea.Problem}}</div>
+<h3>Alcance</h3><div class="content">{{.Idea.Scope}}</div>
+
+{{with .ActionPlan}}
+<h2>Plan de acción</h2>
+<h3>Requerimientos funcionales</h3><div class="content">{{.FunctionalRequirements}}</div>
+<h3>Requerimientos no funcionales</h3><div class="content">{{.NonFunctionalRequirements}}</div>
+<h3>Flujo de lógica de negocio</h3><div class="content">{{.BusinessLogicFlow}}</div>
+{{end}}
+
+{{with .Architecture}}
+<h2>Arquitectura</h2>
+<h3>Historias de usuario</h3><div class="content">{{.UserStories}}</div>
+<h3>Tipo de base de datos</h3><div class="content">{{.DatabaseType}}</div>
+<h3>Esquema de base de datos</h3><div class="content">{{.DatabaseSchema}}</div>
+<h3>Entidades y relaciones</h3><div class="content">{{.EntitiesRelationships}}</div>
+<h3>Stack tecnológico</h3><div class="content">{{.TechStack}}</div>
+<h3>Patrón de arquitectura</h3><div class="content">{{.ArchitecturePattern}}</div>
+<h3>Arquitectura del sistema</h3><div class="content">{{.SystemArchitecture}}</div>
+{{end}}
+
+{{if .Modules}}
+<h2>Módulos de desarrollo</h2>
+{{range $i, $m := .Modules}}
+<div class="module">
+  <h3>{{$m.Name}}</h3>
+  <p class="meta">Prioridad {{$m.Priority}} · {{$m.Status}}{{with deps $m.Dependencies}} · depende de {{range $j, $d := .}}{{if $j}}, {{end}}{{$d}}{{end}}{{end}}</p>
+  <div class="content">{{$m.Description}}</div>
+  {{with $m.Functionality}}<h4>Funcionalidad</h4><div class="content">{{.}}</div>{{end}}
+  {{with $m.TechnicalDetails}}<h4>Detalles técnicos</h4><div class="content">{{.}}</div>{{end}}
+</div>
+{{end}}
+{{end}}
+
+{{with .Chats}}
+<h2>Conversaciones</h2>
+{{range transcripts .}}
+<h3>{{.Title}}</h3>
+{{range .Messages}}
+<div class="message">
+  <p class="meta">{{.Role}} · {{.CreatedAt.Format "2006-01-02 15:04"}}</p>
+  <div class="content">{{.Content}}</div>
+</div>
+{{end}}
+{{end}}
+{{end}}
+
+<footer>Generado con Idea Forge</footer>
+</body>
+</html>
+`))
+
+// sharePasswordPage asks for the password of a protected share link
+var sharePasswordPage = template.Must(template.New("share-password").Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Proyecto protegido · Idea Forge</title>
+<style>
+  body { font-family: system-ui, -apple-system, sans-serif; max-width: 380px; margin: 6rem auto; padding: 0 1rem; color: #1f2937; }
+  input, button { width: 100%; box-sizing: border-box; padding: .6rem; margin-top: .5rem; font-size: 1rem; }
+  .error { color: #b91c1c; }
+</style>
+</head>
+<body>
+<h1>Proyecto protegido</h1>
+<p>Ingresa la contraseña para ver este proyecto.</p>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<form method="post">
+  <input type="password" name="password" placeholder="Contraseña" autofocus required>
+  <button type="submit">Ver proyecto</button>
+</form>
+</body>
+</html>
+`))
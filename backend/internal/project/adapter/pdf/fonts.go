@@ -0,0 +1,164 @@
+package pdf
+
+import "unicode/utf8"
+
+// style selects one of the standard fonts used by the typesetter
+type style int
+
+const (
+	regular style = iota
+	bold
+	italic
+	boldItalic
+	code
+)
+
+// font is one of the 14 standard Type 1 fonts, which every PDF reader ships,
+// so nothing has to be embedded. Text is written in WinAnsiEncoding.
+type font struct {
+	resource string
+	base     string
+	widths   *[256]int // glyph widths in 1/1000 em; nil for monospaced fonts
+}
+
+var (
+	helveticaWidths     [256]int
+	helveticaBoldWidths [256]int
+)
+
+var fonts = [...]font{
+	regular:    {"F1", "Helvetica", &helveticaWidths},
+	bold:       {"F2", "Helvetica-Bold", &helveticaBoldWidths},
+	italic:     {"F3", "Helvetica-Oblique", &helveticaWidths},
+	boldItalic: {"F4", "Helvetica-BoldOblique", &helveticaBoldWidths},
+	code:       {"F5", "Courier", nil},
+}
+
+// courierWidth is the advance of every Courier glyph
+const courierWidth = 600
+
+// width returns the advance of WinAnsi encoded text at the given size
+func (f *font) width(text []byte, size float64) float64 {
+	if f.widths == nil {
+		return float64(len(text)*courierWidth) * size / 1000
+	}
+	w := 0
+	for _, c := range text {
+		w += f.widths[c]
+	}
+	return float64(w) * size / 1000
+}
+
+// ASCII widths (32-126) from the Adobe font metrics
+var (
+	helveticaASCII = [...]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space - /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 - ?
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ - O
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P - _
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` - o
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p - ~
+	}
+	helveticaBoldASCII = [...]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// symbolWidths covers the non-letter glyphs of WinAnsi 0x80-0xBF, shared by
+// both weights (the differences are negligible for line breaking)
+var symbolWidths = map[byte]int{
+	0x80: 556, 0x82: 222, 0x83: 556, 0x84: 333, 0x85: 1000, 0x86: 556, 0x87: 556, 0x88: 333,
+	0x89: 1000, 0x8A: 667, 0x8B: 333, 0x8C: 1000, 0x8E: 611, 0x91: 222, 0x92: 222, 0x93: 333,
+	0x94: 333, 0x95: 350, 0x96: 556, 0x97: 1000, 0x98: 333, 0x99: 1000, 0x9A: 500, 0x9B: 333,
+	0x9C: 944, 0x9E: 500, 0x9F: 667,
+	0xA0: 278, 0xA1: 333, 0xA2: 556, 0xA3: 556, 0xA4: 556, 0xA5: 556, 0xA6: 260, 0xA7: 556,
+	0xA8: 333, 0xA9: 737, 0xAA: 370, 0xAB: 556, 0xAC: 584, 0xAD: 333, 0xAE: 737, 0xAF: 333,
+	0xB0: 400, 0xB1: 584, 0xB2: 333, 0xB3: 333, 0xB4: 333, 0xB5: 556, 0xB6: 537, 0xB7: 278,
+	0xB8: 333, 0xB9: 333, 0xBA: 365, 0xBB: 556, 0xBC: 834, 0xBD: 834, 0xBE: 834, 0xBF: 611,
+	0xC6: 1000, 0xD7: 584, 0xDF: 611, 0xE6: 889, 0xF7: 584,
+}
+
+// latinBase maps the accented letters of WinAnsi 0xC0-0xFF to the ASCII
+// letter whose width they share
+const latinBase = "AAAAAA_CEEEEIIIIDNOOOOO_OUUUUYP_aaaaaa_ceeeeiiiionooooo_ouuuuypy"
+
+func init() {
+	fill := func(widths *[256]int, ascii []int) {
+		for i := range widths {
+			widths[i] = 556
+		}
+		for i, w := range ascii {
+			widths[32+i] = w
+		}
+		for c, w := range symbolWidths {
+			widths[c] = w
+		}
+		for i := 0; i < len(latinBase); i++ {
+			if base := latinBase[i]; base != '_' {
+				widths[0xC0+i] = widths[base]
+			}
+		}
+	}
+	fill(&helveticaWidths, helveticaASCII[:])
+	fill(&helveticaBoldWidths, helveticaBoldASCII[:])
+}
+
+// winAnsi maps the Unicode characters of the 0x80-0x9F WinAnsi block
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// substitutes approximates common characters outside WinAnsi, keeping the
+// column alignment of text diagrams in code blocks
+var substitutes = map[rune]string{
+	'→': "->", '←': "<-", '⇒': "=>", '↔': "<->", '≥': ">=", '≤': "<=", '≠': "!=",
+	'↓': "v", '↑': "^", '▼': "v", '▲': "^", '►': ">", '▶': ">", '◄': "<", '◀': "<",
+	'✓': "v", '✔': "v", '✗': "x", '✘': "x", '☐': "[ ]", '☑': "[x]",
+}
+
+// encode converts UTF-8 text to WinAnsiEncoding. Box drawing characters
+// become ASCII, emoji are dropped and anything else unknown becomes '?'.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r < 0x20:
+		case r < 0x80, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		case winAnsi[r] != 0:
+			out = append(out, winAnsi[r])
+		case substitutes[r] != "":
+			out = append(out, substitutes[r]...)
+		case r >= 0x2500 && r <= 0x257F:
+			out = append(out, boxDrawing(r))
+		case r >= 0x2600 && r <= 0x27BF, r >= 0x1F000, r == 0xFE0F, r == 0x200B, r == 0x200D:
+		case r == 0x2002, r == 0x2003, r == 0x2009, r == 0x202F:
+			out = append(out, ' ')
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func boxDrawing(r rune) byte {
+	switch r {
+	case '─', '━', '═', '┄', '┅', '┈', '┉', '╌', '╍':
+		return '-'
+	case '│', '┃', '║', '┆', '┇', '┊', '┋', '╎', '╏':
+		return '|'
+	}
+	return '+'
+}
@@ -0,0 +1,546 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A4 page in points, with the text area inside the margins
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	marginX      = 56.0
+	marginTop    = 64.0
+	marginBottom = 64.0
+	textWidth    = pageWidth - 2*marginX
+)
+
+const (
+	bodySize    = 10.0
+	bodyLeading = 14.0
+	codeSize    = 8.0
+	codeLeading = 10.5
+	tableSize   = 8.5
+	cellLeading = 11.0
+	cellPadding = 4.0
+	listIndent  = 16.0
+)
+
+type color struct{ r, g, b float64 }
+
+var (
+	textColor     = color{0.13, 0.13, 0.13}
+	headingColor  = color{0.09, 0.22, 0.40}
+	mutedColor    = color{0.45, 0.45, 0.45}
+	ruleColor     = color{0.78, 0.78, 0.78}
+	codeBgColor   = color{0.95, 0.95, 0.96}
+	headerBgColor = color{0.90, 0.93, 0.97}
+)
+
+// page collects the content stream and links of one PDF page
+type page struct {
+	content strings.Builder
+	links   []link
+}
+
+// link is a clickable area pointing to a position on another page
+type link struct {
+	x, y, w, h float64
+	page       int
+	top        float64
+}
+
+func (p *page) fill(c color, x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s rg %s %s %s %s re f\n", num(c.r), num(c.g), num(c.b), num(x), num(y), num(w), num(h))
+}
+
+func (p *page) stroke(c color, width, x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s RG %s w %s %s %s %s re S\n", num(c.r), num(c.g), num(c.b), num(width), num(x), num(y), num(w), num(h))
+}
+
+func (p *page) line(c color, width, x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%s %s %s RG %s w %s %s m %s %s l S\n", num(c.r), num(c.g), num(c.b), num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// text draws runs one after the other, starting at the baseline (x, y)
+func (p *page) text(c color, x, y float64, runs []run) {
+	fmt.Fprintf(&p.content, "BT %s %s %s rg %s %s Td", num(c.r), num(c.g), num(c.b), num(x), num(y))
+	for _, r := range runs {
+		fmt.Fprintf(&p.content, " /%s %s Tf (%s) Tj", fonts[r.style].resource, num(r.size), escape(r.text))
+	}
+	p.content.WriteString(" ET\n")
+}
+
+// run is WinAnsi encoded text in a single font
+type run struct {
+	text  []byte
+	style style
+	size  float64
+}
+
+func (r run) width() float64 {
+	return fonts[r.style].width(r.text, r.size)
+}
+
+// word is an unbreakable piece of a paragraph; space tells whether a
+// breakable space follows it
+type word struct {
+	run
+	space bool
+}
+
+// words splits inline spans into words. Inline code is set slightly smaller
+// so Courier matches the x-height of the surrounding text.
+func words(spans []span, size float64) []word {
+	var out []word
+	for _, sp := range spans {
+		sz := size
+		if sp.style == code {
+			sz = size * 0.9
+		}
+		var cur []byte
+		for _, c := range encode(sp.text) {
+			if c != ' ' {
+				cur = append(cur, c)
+				continue
+			}
+			if len(cur) > 0 {
+				out = append(out, word{run{cur, sp.style, sz}, true})
+				cur = nil
+			} else if len(out) > 0 {
+				out[len(out)-1].space = true
+			}
+		}
+		if len(cur) > 0 {
+			out = append(out, word{run{cur, sp.style, sz}, false})
+		}
+	}
+	return out
+}
+
+// wrap breaks words into lines no wider than width, splitting words that do
+// not fit on a line of their own
+func wrap(ws []word, width float64) [][]word {
+	// chunks are sequences of words glued without spaces, like "**bold**,"
+	var chunks [][]word
+	start := 0
+	for i, w := range ws {
+		if w.space || i == len(ws)-1 {
+			chunks = append(chunks, ws[start:i+1])
+			start = i + 1
+		}
+	}
+
+	var lines [][]word
+	var line []word
+	lineWidth := 0.0
+	for _, chunk := range chunks {
+		cw := 0.0
+		for _, w := range chunk {
+			cw += w.width()
+		}
+		if cw > width {
+			if len(line) > 0 {
+				lines = append(lines, line)
+				line, lineWidth = nil, 0
+			}
+			pieces := splitChunk(chunk, width)
+			lines = append(lines, pieces[:len(pieces)-1]...)
+			line = pieces[len(pieces)-1]
+			for _, w := range line {
+				lineWidth += w.width()
+			}
+		} else if len(line) > 0 && lineWidth+spaceWidth(line)+cw > width {
+			lines = append(lines, line)
+			line, lineWidth = append([]word(nil), chunk...), cw
+		} else {
+			lineWidth += spaceWidth(line) + cw
+			line = append(line, chunk...)
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// spaceWidth is the width of the space that ends a line, if any
+func spaceWidth(line []word) float64 {
+	if len(line) == 0 || !line[len(line)-1].space {
+		return 0
+	}
+	last := line[len(line)-1]
+	return fonts[last.style].width([]byte{' '}, last.size)
+}
+
+// splitChunk breaks a chunk wider than width at character boundaries
+func splitChunk(chunk []word, width float64) [][]word {
+	var lines [][]word
+	var line []word
+	used := 0.0
+	for _, w := range chunk {
+		f := fonts[w.style]
+		start := 0
+		for i := range w.text {
+			cw := f.width(w.text[i:i+1], w.size)
+			if used+cw > width && (i > start || len(line) > 0) {
+				if i > start {
+					line = append(line, word{run{w.text[start:i], w.style, w.size}, false})
+				}
+				lines = append(lines, line)
+				line, used, start = nil, 0, i
+			}
+			used += cw
+		}
+		line = append(line, word{run{w.text[start:], w.style, w.size}, w.space})
+	}
+	return append(lines, line)
+}
+
+// lineRuns joins the words of a line, merging neighbours in the same font
+func lineRuns(line []word) []run {
+	var runs []run
+	for _, w := range line {
+		text := w.text
+		if w.space {
+			text = append(text[:len(text):len(text)], ' ')
+		}
+		if n := len(runs); n > 0 && runs[n-1].style == w.style && runs[n-1].size == w.size {
+			runs[n-1].text = append(runs[n-1].text, text...)
+			continue
+		}
+		runs = append(runs, run{append([]byte(nil), text...), w.style, w.size})
+	}
+	return runs
+}
+
+// heading is a typeset heading, kept for the table of contents and outline
+type heading struct {
+	level int
+	text  string
+	page  int
+	top   float64
+}
+
+// typesetter lays out blocks top to bottom, opening pages as needed
+type typesetter struct {
+	pages    []*page
+	page     *page
+	y        float64 // top of the free space on the current page
+	topLevel int     // level of the highest heading of the document
+	headings []heading
+}
+
+func newTypesetter() *typesetter {
+	t := &typesetter{}
+	t.newPage()
+	return t
+}
+
+func (t *typesetter) newPage() {
+	t.page = &page{}
+	t.pages = append(t.pages, t.page)
+	t.y = pageHeight - marginTop
+}
+
+func (t *typesetter) atTop() bool {
+	return t.y >= pageHeight-marginTop
+}
+
+// need opens a new page unless height fits above the bottom margin
+func (t *typesetter) need(height float64) {
+	if t.y-height < marginBottom && !t.atTop() {
+		t.newPage()
+	}
+}
+
+// skip adds vertical space, which is dropped at the top of a page
+func (t *typesetter) skip(height float64) {
+	if !t.atTop() {
+		t.y = max(t.y-height, marginBottom)
+	}
+}
+
+func (t *typesetter) render(blocks []block) {
+	t.topLevel = 6
+	for _, b := range blocks {
+		if b.kind == blockHeading {
+			t.topLevel = min(t.topLevel, b.level)
+		}
+	}
+
+	for i, b := range blocks {
+		switch b.kind {
+		case blockHeading:
+			t.heading(b.level, b.text)
+		case blockParagraph:
+			t.paragraph(parseInline(b.text), paragraphStyle{size: bodySize, leading: bodyLeading, color: textColor})
+			t.skip(6)
+		case blockListItem:
+			marker := encode(b.marker)
+			indent := float64(b.level) * listIndent
+			t.paragraph(parseInline(b.text), paragraphStyle{
+				indent: indent + listIndent, size: bodySize, leading: bodyLeading, color: textColor,
+				marker: run{marker, regular, bodySize}, markerX: indent + 4,
+			})
+			if i+1 == len(blocks) || blocks[i+1].kind != blockListItem {
+				t.skip(6)
+			} else {
+				t.skip(2)
+			}
+		case blockQuote:
+			t.paragraph(parseInline(b.text), paragraphStyle{indent: 12, size: bodySize, leading: bodyLeading, color: mutedColor, bar: true})
+			t.skip(6)
+		case blockCode:
+			t.code(b.lines)
+			t.skip(8)
+		case blockTable:
+			t.table(b.rows)
+			t.skip(8)
+		case blockRule:
+			t.need(12)
+			t.page.line(ruleColor, 0.75, marginX, t.y-6, pageWidth-marginX, t.y-6)
+			t.y -= 12
+		}
+	}
+}
+
+var headingSizes = [...]float64{18, 14.5, 12, 10.5}
+
+// heading sets a heading relative to the top level of the document. Top
+// level headings open a page; every heading is kept with the text after it.
+func (t *typesetter) heading(level int, text string) {
+	rel := min(level-t.topLevel, len(headingSizes)-1)
+	size := headingSizes[rel]
+	leading := size * 1.3
+
+	if rel == 0 && !t.atTop() {
+		t.newPage()
+	}
+	t.skip(size * 0.8)
+	t.need(leading + 2*bodyLeading)
+
+	plain := strings.TrimSpace(plainText(text))
+	t.headings = append(t.headings, heading{level: rel, text: plain, page: len(t.pages) - 1, top: t.y})
+	st := bold
+	if rel == 3 {
+		st = boldItalic
+	}
+	t.paragraph([]span{{plain, st}}, paragraphStyle{size: size, leading: leading, color: headingColor})
+	if rel == 0 {
+		t.page.line(headingColor, 1, marginX, t.y-2, pageWidth-marginX, t.y-2)
+		t.y -= 6
+	}
+	t.skip(size * 0.4)
+}
+
+type paragraphStyle struct {
+	indent  float64
+	size    float64
+	leading float64
+	color   color
+	marker  run     // drawn on the first line, e.g. a list bullet
+	markerX float64 // offset of the marker from the margin
+	bar     bool    // draw a quote bar on the left
+}
+
+func (t *typesetter) paragraph(spans []span, st paragraphStyle) {
+	x := marginX + st.indent
+	for i, line := range wrap(words(spans, st.size), textWidth-st.indent) {
+		t.need(st.leading)
+		baseline := t.y - st.size
+		if i == 0 && len(st.marker.text) > 0 {
+			t.page.text(st.color, marginX+st.markerX, baseline, []run{st.marker})
+		}
+		if st.bar {
+			t.page.fill(ruleColor, marginX, t.y-st.leading, 3, st.leading)
+		}
+		t.page.text(st.color, x, baseline, lineRuns(line))
+		t.y -= st.leading
+	}
+}
+
+// code sets a code block on a shaded background, wrapping long lines and
+// splitting the block across pages
+func (t *typesetter) code(src []string) {
+	const padding = 6.0
+	width := textWidth - 2*padding
+	perLine := int(width / (codeSize * courierWidth / 1000))
+	var lines [][]byte
+	for _, l := range src {
+		text := encode(strings.TrimRight(l, " "))
+		for len(text) > perLine {
+			lines = append(lines, text[:perLine])
+			text = text[perLine:]
+		}
+		lines = append(lines, text)
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	for len(lines) > 0 {
+		t.need(2*padding + codeLeading)
+		fit := min(int((t.y-marginBottom-2*padding)/codeLeading), len(lines))
+		if fit < 1 {
+			t.newPage()
+			continue
+		}
+		height := 2*padding + float64(fit)*codeLeading
+		t.page.fill(codeBgColor, marginX, t.y-height, textWidth, height)
+		y := t.y - padding
+		for _, l := range lines[:fit] {
+			y -= codeLeading
+			t.page.text(textColor, marginX+padding, y+2.5, []run{{l, code, codeSize}})
+		}
+		t.y -= height
+		lines = lines[fit:]
+		if len(lines) > 0 {
+			t.newPage()
+		}
+	}
+}
+
+// table sets a pipe table with a shaded header that is repeated on every page
+func (t *typesetter) table(rows [][]string) {
+	cols := 0
+	for _, r := range rows {
+		cols = max(cols, len(r))
+	}
+	if cols == 0 {
+		return
+	}
+
+	cells := make([][][]word, len(rows))
+	natural := make([]float64, cols)
+	minimum := make([]float64, cols)
+	for i, r := range rows {
+		cells[i] = make([][]word, cols)
+		for j := 0; j < cols; j++ {
+			text := ""
+			if j < len(r) {
+				text = r[j]
+			}
+			spans := parseInline(text)
+			if i == 0 {
+				for k := range spans {
+					spans[k].style = bold
+				}
+			}
+			ws := words(spans, tableSize)
+			cells[i][j] = ws
+			total := 0.0
+			for _, w := range ws {
+				total += w.width() + spaceWidth([]word{w})
+				minimum[j] = max(minimum[j], w.width())
+			}
+			natural[j] = max(natural[j], total)
+		}
+	}
+	widths := columnWidths(natural, minimum, textWidth-float64(cols)*2*cellPadding)
+	for j := range widths {
+		widths[j] += 2 * cellPadding
+	}
+
+	wrapped := make([][][][]word, len(rows))
+	for i := range cells {
+		wrapped[i] = make([][][]word, cols)
+		for j, ws := range cells[i] {
+			wrapped[i][j] = wrap(ws, widths[j]-2*cellPadding)
+		}
+	}
+
+	t.tableRow(wrapped[0], widths, true, nil)
+	for _, row := range wrapped[1:] {
+		t.tableRow(row, widths, false, wrapped[0])
+	}
+}
+
+// columnWidths fits the natural column widths into available space: narrow
+// columns keep their width and the rest share what is left
+func columnWidths(natural, minimum []float64, available float64) []float64 {
+	widths := make([]float64, len(natural))
+	total := 0.0
+	for _, w := range natural {
+		total += w
+	}
+	if total <= available {
+		copy(widths, natural)
+		return widths
+	}
+
+	fair := available / float64(len(natural))
+	remaining, wide := available, 0.0
+	for j, w := range natural {
+		if w <= fair {
+			widths[j] = w
+			remaining -= w
+		} else {
+			wide += w
+		}
+	}
+	for j, w := range natural {
+		if w > fair {
+			widths[j] = max(remaining*w/wide, min(minimum[j], fair))
+		}
+	}
+	return widths
+}
+
+// tableRow draws a row, continuing it on the next page (after the repeated
+// header) when it does not fit. The header is only repeated when it leaves
+// room for a line of the row, and a fresh page always takes at least one
+// line, so every page makes progress.
+func (t *typesetter) tableRow(row [][][]word, widths []float64, isHeader bool, header [][][]word) {
+	lines := 1
+	for _, c := range row {
+		lines = max(lines, len(c))
+	}
+	height := func(n int) float64 { return float64(n)*cellLeading + 2*cellPadding }
+	usable := pageHeight - marginTop - marginBottom - 2*cellPadding
+
+	repeatHeader := false
+	if !isHeader {
+		headerLines := 1
+		for _, c := range header {
+			headerLines = max(headerLines, len(c))
+		}
+		if height(headerLines)+height(1) <= pageHeight-marginTop-marginBottom {
+			repeatHeader = true
+			usable -= height(headerLines)
+		}
+	}
+	pageCapacity := int(usable / cellLeading)
+
+	fresh := false
+	for start := 0; start < lines; {
+		fit := int((t.y - marginBottom - 2*cellPadding) / cellLeading)
+		if !fresh && (fit < 1 || (start == 0 && fit < lines && lines <= pageCapacity)) {
+			t.newPage()
+			if repeatHeader {
+				t.tableRow(header, widths, true, nil)
+			}
+			fresh = true
+			continue
+		}
+		fit = max(fit, 1)
+		fresh = false
+		n := min(fit, lines-start)
+		h := height(n)
+		x := marginX
+		for j, c := range row {
+			if isHeader {
+				t.page.fill(headerBgColor, x, t.y-h, widths[j], h)
+			}
+			t.page.stroke(ruleColor, 0.5, x, t.y-h, widths[j], h)
+			y := t.y - cellPadding
+			for k := start; k < min(start+n, len(c)); k++ {
+				y -= cellLeading
+				t.page.text(textColor, x+cellPadding, y+2.5, lineRuns(c[k]))
+			}
+			x += widths[j]
+		}
+		t.y -= h
+		start += n
+	}
+}
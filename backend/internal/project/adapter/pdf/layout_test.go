@@ -0,0 +1,227 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// headerFill is the content stream operator that shades a table header cell
+var headerFill = fmt.Sprintf("%s %s %s rg", num(headerBgColor.r), num(headerBgColor.g), num(headerBgColor.b))
+
+// layout typesets markdown, failing the test if it does not finish in time
+func layout(t *testing.T, markdown string) *typesetter {
+	t.Helper()
+	done := make(chan *typesetter, 1)
+	go func() {
+		ts := newTypesetter()
+		ts.render(parseMarkdown(markdown))
+		done <- ts
+	}()
+	select {
+	case ts := <-done:
+		return ts
+	case <-time.After(10 * time.Second):
+		t.Fatal("layout did not finish")
+		return nil
+	}
+}
+
+func tableMarkdown(rows ...string) string {
+	var b strings.Builder
+	for i, r := range rows {
+		fmt.Fprintf(&b, "| %s |\n", r)
+		if i == 0 {
+			b.WriteString("| --- |\n")
+		}
+	}
+	return b.String()
+}
+
+func headerPages(ts *typesetter) int {
+	n := 0
+	for _, p := range ts.pages {
+		if strings.Contains(p.content.String(), headerFill) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestTableLayoutTerminates(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		rows   []string
+		// repeated tells whether the header should be shaded again on the pages of the rows
+		repeated bool
+	}{
+		{name: "unbroken header of 6000 characters", header: strings.Repeat("x", 6000), rows: []string{strings.Repeat("data ", 100)}, repeated: true},
+		{name: "header that leaves no room for a row", header: strings.Repeat("x", 6300), rows: []string{"data"}},
+		{name: "unbroken header of 5000 characters", header: strings.Repeat("x", 5000), rows: []string{"data", "more"}, repeated: true},
+		{name: "header taller than a page", header: strings.Repeat("cabecera ", 1500), rows: []string{"data"}},
+		{name: "header and row that do not fit together", header: strings.Repeat("cabecera ", 400), rows: []string{strings.Repeat("dato ", 800)}, repeated: true},
+		{name: "row taller than a page", header: "Name", rows: []string{strings.Repeat("y", 20000)}, repeated: true},
+		{name: "header and row taller than a page", header: strings.Repeat("x", 9000), rows: []string{strings.Repeat("y", 9000)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := layout(t, tableMarkdown(append([]string{tt.header}, tt.rows...)...))
+			if len(ts.pages) > 20 {
+				t.Fatalf("table took %d pages", len(ts.pages))
+			}
+			last := ts.pages[len(ts.pages)-1].content.String()
+			if !strings.Contains(last, "(y") && !strings.Contains(last, "(d") && !strings.Contains(last, "(more") {
+				t.Errorf("the last page does not hold the end of the table:\n%s", last)
+			}
+
+			alone := layout(t, tableMarkdown(tt.header))
+			switch got, own := headerPages(ts), headerPages(alone); {
+			case tt.repeated && got <= own && len(ts.pages) > len(alone.pages):
+				t.Errorf("header shaded on %d pages, want it repeated beyond its own %d", got, own)
+			case !tt.repeated && got != own:
+				t.Errorf("header shaded on %d pages, want only its own %d", got, own)
+			}
+		})
+	}
+}
+
+func TestTableRepeatsHeaderOnEveryPage(t *testing.T) {
+	rows := []string{"Module | Status"}
+	for i := 0; i < 200; i++ {
+		rows = append(rows, fmt.Sprintf("module-%d | pending", i))
+	}
+	ts := layout(t, tableMarkdown(rows...))
+
+	if len(ts.pages) < 3 {
+		t.Fatalf("200 rows took %d pages, want at least 3", len(ts.pages))
+	}
+	for i, p := range ts.pages {
+		if !strings.Contains(p.content.String(), headerFill) {
+			t.Errorf("page %d has no header", i+1)
+		}
+	}
+	if !strings.Contains(ts.pages[len(ts.pages)-1].content.String(), "(module-199") {
+		t.Error("last row missing from the last page")
+	}
+}
+
+func TestRowIsKeptTogetherWhenItFitsAPage(t *testing.T) {
+	ts := newTypesetter()
+	ts.y = marginBottom + 120
+	ts.table([][]string{{"Name"}, {strings.Repeat("dato ", 300)}})
+
+	if len(ts.pages) != 2 {
+		t.Fatalf("table took %d pages, want 2", len(ts.pages))
+	}
+	if strings.Contains(ts.pages[0].content.String(), "(dato") {
+		t.Error("row started at the bottom of the first page, want it moved whole to the next one")
+	}
+	if second := ts.pages[1].content.String(); !strings.Contains(second, headerFill) || !strings.Contains(second, "(dato") {
+		t.Error("second page should repeat the header above the row")
+	}
+}
+
+func TestCodeBlockSplitsAcrossPages(t *testing.T) {
+	var md strings.Builder
+	md.WriteString("```\n")
+	for i := 0; i < 150; i++ {
+		fmt.Fprintf(&md, "line %d\n", i)
+	}
+	md.WriteString("```\n")
+	ts := layout(t, md.String())
+
+	if len(ts.pages) != 3 {
+		t.Fatalf("150 code lines took %d pages, want 3", len(ts.pages))
+	}
+	for i := 0; i < 150; i++ {
+		found := 0
+		for _, p := range ts.pages {
+			found += strings.Count(p.content.String(), fmt.Sprintf("(line %d)", i))
+		}
+		if found != 1 {
+			t.Fatalf("line %d drawn %d times", i, found)
+		}
+	}
+}
+
+func TestHeadingsOpenPagesAndAreIndexed(t *testing.T) {
+	ts := layout(t, "# One\n\ntext\n\n## One.a\n\ntext\n\n# Two\n\ntext\n")
+
+	want := []heading{{level: 0, text: "One", page: 0}, {level: 1, text: "One.a", page: 0}, {level: 0, text: "Two", page: 1}}
+	if len(ts.headings) != len(want) {
+		t.Fatalf("headings = %+v", ts.headings)
+	}
+	for i, h := range ts.headings {
+		if h.level != want[i].level || h.text != want[i].text || h.page != want[i].page {
+			t.Errorf("heading %d = %+v, want %+v", i, h, want[i])
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width float64
+		lines int
+	}{
+		{name: "empty", text: "", width: 100, lines: 0},
+		{name: "fits", text: "a short line", width: 200, lines: 1},
+		{name: "breaks at spaces", text: "one two three four five six", width: 60, lines: 3},
+		{name: "splits an unbroken word", text: strings.Repeat("x", 100), width: 100, lines: 5},
+		{name: "narrower than a character", text: "abc", width: 1, lines: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := wrap(words([]span{{tt.text, regular}}, bodySize), tt.width)
+			if len(lines) != tt.lines {
+				t.Fatalf("%d lines, want %d", len(lines), tt.lines)
+			}
+			var joined strings.Builder
+			for _, l := range lines {
+				width := 0.0
+				for _, w := range l {
+					width += w.width()
+					joined.Write(w.text)
+					if w.space {
+						joined.WriteByte(' ')
+					}
+				}
+				if width > tt.width && len(l) > 1 {
+					t.Errorf("line %q is %.1f wide, want at most %.1f", lineRuns(l)[0].text, width, tt.width)
+				}
+			}
+			if got := strings.TrimSpace(joined.String()); strings.ReplaceAll(got, " ", "") != strings.ReplaceAll(tt.text, " ", "") {
+				t.Errorf("wrapped text = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestColumnWidths(t *testing.T) {
+	tests := []struct {
+		name             string
+		natural, minimum []float64
+		available        float64
+		want             []float64
+	}{
+		{name: "fits", natural: []float64{50, 100}, minimum: []float64{20, 40}, available: 300, want: []float64{50, 100}},
+		{name: "narrow column keeps its width", natural: []float64{50, 400, 200}, minimum: []float64{20, 40, 40}, available: 350, want: []float64{50, 200, 100}},
+		{name: "wide columns share the space", natural: []float64{400, 400}, minimum: []float64{10, 250}, available: 300, want: []float64{150, 150}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := columnWidths(tt.natural, tt.minimum, tt.available)
+			for j := range got {
+				if fmt.Sprintf("%.2f", got[j]) != fmt.Sprintf("%.2f", tt.want[j]) {
+					t.Fatalf("widths = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
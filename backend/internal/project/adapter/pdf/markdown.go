@@ -0,0 +1,294 @@
+package pdf
+
+import (
+	"regexp"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockListItem
+	blockCode
+	blockTable
+	blockQuote
+	blockRule
+)
+
+// block is a Markdown block element
+type block struct {
+	kind   blockKind
+	level  int    // heading level or list nesting depth
+	marker string // list item marker
+	text   string
+	lines  []string   // code block lines
+	rows   [][]string // table rows, the first one is the header
+}
+
+var (
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	listRe     = regexp.MustCompile(`^(\s*)([-*+]|\d{1,3}[.)])\s+(.*)$`)
+	tableSepRe = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// parseMarkdown splits Markdown into blocks. It covers what the generated
+// specifications use: headings, paragraphs, nested lists, fenced code,
+// pipe tables, block quotes and horizontal rules.
+func parseMarkdown(src string) []block {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var blocks []block
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, block{kind: blockParagraph, text: strings.Join(para, " ")})
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+
+		case isFence(trimmed):
+			flush()
+			fence := trimmed[:3]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, strings.ReplaceAll(lines[i], "\t", "    "))
+			}
+			blocks = append(blocks, block{kind: blockCode, lines: code})
+
+		case headingRe.MatchString(trimmed):
+			flush()
+			m := headingRe.FindStringSubmatch(trimmed)
+			blocks = append(blocks, block{kind: blockHeading, level: len(m[1]), text: m[2]})
+
+		case isRule(trimmed):
+			flush()
+			blocks = append(blocks, block{kind: blockRule})
+
+		case strings.Contains(trimmed, "|") && i+1 < len(lines) && isTableSeparator(lines[i+1]):
+			flush()
+			rows := [][]string{splitRow(trimmed)}
+			i++
+			for i+1 < len(lines) && strings.Contains(lines[i+1], "|") && strings.TrimSpace(lines[i+1]) != "" {
+				i++
+				rows = append(rows, splitRow(strings.TrimSpace(lines[i])))
+			}
+			blocks = append(blocks, block{kind: blockTable, rows: rows})
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			quote := []string{strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))}
+			for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), ">") {
+				i++
+				quote = append(quote, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")))
+			}
+			blocks = append(blocks, block{kind: blockQuote, text: strings.Join(quote, " ")})
+
+		case listRe.MatchString(line):
+			flush()
+			m := listRe.FindStringSubmatch(line)
+			text := []string{m[3]}
+			for i+1 < len(lines) && isContinuation(lines[i+1]) {
+				i++
+				text = append(text, strings.TrimSpace(lines[i]))
+			}
+			indent := len(strings.ReplaceAll(m[1], "\t", "  "))
+			blocks = append(blocks, listItem(min(indent/2, 3), m[2], strings.Join(text, " ")))
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return blocks
+}
+
+func listItem(level int, marker, text string) block {
+	switch {
+	case strings.HasPrefix(text, "[ ] "):
+		marker, text = "[ ]", text[4:]
+	case strings.HasPrefix(text, "[x] "), strings.HasPrefix(text, "[X] "):
+		marker, text = "[x]", text[4:]
+	case marker == "-" || marker == "*" || marker == "+":
+		marker = "•"
+		if level%2 == 1 {
+			marker = "–"
+		}
+	}
+	return block{kind: blockListItem, level: level, marker: marker, text: text}
+}
+
+// isContinuation reports whether line continues the previous list item
+func isContinuation(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || line == trimmed || listRe.MatchString(line) {
+		return false
+	}
+	return !isFence(trimmed) && !headingRe.MatchString(trimmed) && !strings.HasPrefix(trimmed, "|")
+}
+
+func isFence(line string) bool {
+	return strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~")
+}
+
+func isRule(line string) bool {
+	line = strings.ReplaceAll(line, " ", "")
+	if len(line) < 3 || !strings.Contains("-*_", line[:1]) {
+		return false
+	}
+	return strings.Count(line, line[:1]) == len(line)
+}
+
+func isTableSeparator(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.Contains(line, "-") && tableSepRe.MatchString(line)
+}
+
+func splitRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// span is a piece of inline text in a single style
+type span struct {
+	text  string
+	style style
+}
+
+// parseInline resolves emphasis, inline code and links. Markers without a
+// closing counterpart are kept as text.
+func parseInline(s string) []span {
+	var spans []span
+	var cur strings.Builder
+	strong, emphasis := false, false
+	var emphasisMarker byte
+
+	current := func() style {
+		switch {
+		case strong && emphasis:
+			return boldItalic
+		case strong:
+			return bold
+		case emphasis:
+			return italic
+		}
+		return regular
+	}
+	flush := func() {
+		if cur.Len() > 0 {
+			spans = append(spans, span{cur.String(), current()})
+			cur.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		rest := s[i:]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_[]()#+-.!|<>", s[i+1]) >= 0:
+			cur.WriteByte(s[i+1])
+			i += 2
+
+		case c == '`':
+			end := strings.IndexByte(s[i+1:], '`')
+			if end < 0 {
+				cur.WriteByte(c)
+				i++
+				continue
+			}
+			flush()
+			spans = append(spans, span{s[i+1 : i+1+end], code})
+			i += end + 2
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if !strong && !strings.Contains(s[i+2:], rest[:2]) {
+				cur.WriteString(rest[:2])
+				i += 2
+				continue
+			}
+			flush()
+			strong = !strong
+			i += 2
+
+		case c == '*' || c == '_':
+			opens := !emphasis && i+1 < len(s) && s[i+1] != ' ' && strings.IndexByte(s[i+1:], c) >= 0 &&
+				(c == '*' || i == 0 || !isWordByte(s[i-1]))
+			closes := emphasis && c == emphasisMarker && (c == '*' || i+1 == len(s) || !isWordByte(s[i+1]))
+			if !opens && !closes {
+				cur.WriteByte(c)
+				i++
+				continue
+			}
+			flush()
+			emphasis = !emphasis
+			emphasisMarker = c
+			i++
+
+		case c == '[' || (c == '!' && strings.HasPrefix(rest, "![")):
+			if c == '!' {
+				rest = rest[1:]
+			}
+			label, ok := linkLabel(rest)
+			if !ok {
+				cur.WriteByte(c)
+				i++
+				continue
+			}
+			cur.WriteString(label)
+			i += len(s[i:]) - len(rest) + strings.IndexByte(rest, ')') + 1
+
+		case strings.HasPrefix(rest, "<br>") || strings.HasPrefix(rest, "<br/>") || strings.HasPrefix(rest, "<br />"):
+			cur.WriteByte(' ')
+			i += strings.IndexByte(rest, '>') + 1
+
+		default:
+			cur.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return spans
+}
+
+// linkLabel returns the text of a "[label](url)" link at the start of s
+func linkLabel(s string) (string, bool) {
+	end := strings.Index(s, "](")
+	if end < 0 || strings.IndexByte(s[end:], ')') < 0 || strings.ContainsAny(s[1:end], "[]") {
+		return "", false
+	}
+	return s[1:end], true
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// plainText drops the inline markup of s
+func plainText(s string) string {
+	var b strings.Builder
+	for _, sp := range parseInline(s) {
+		b.WriteString(sp.text)
+	}
+	return b.String()
+}
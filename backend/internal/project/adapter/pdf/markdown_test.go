@@ -0,0 +1,129 @@
+package pdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []block
+	}{
+		{
+			name: "empty",
+			src:  "",
+			want: nil,
+		},
+		{
+			name: "heading and paragraph joined across lines",
+			src:  "## Title ##\nfirst line\nsecond line\n\nnext",
+			want: []block{
+				{kind: blockHeading, level: 2, text: "Title"},
+				{kind: blockParagraph, text: "first line second line"},
+				{kind: blockParagraph, text: "next"},
+			},
+		},
+		{
+			name: "hash without space is text",
+			src:  "#hashtag",
+			want: []block{{kind: blockParagraph, text: "#hashtag"}},
+		},
+		{
+			name: "nested lists, tasks and continuations",
+			src:  "- one\n  continued\n  - nested\n1. first\n- [ ] todo\n- [x] done",
+			want: []block{
+				{kind: blockListItem, level: 0, marker: "•", text: "one continued"},
+				{kind: blockListItem, level: 1, marker: "–", text: "nested"},
+				{kind: blockListItem, level: 0, marker: "1.", text: "first"},
+				{kind: blockListItem, level: 0, marker: "[ ]", text: "todo"},
+				{kind: blockListItem, level: 0, marker: "[x]", text: "done"},
+			},
+		},
+		{
+			name: "fenced code keeps blank lines and expands tabs",
+			src:  "```go\nfunc main() {\n\n\treturn\n}\n```\nafter",
+			want: []block{
+				{kind: blockCode, lines: []string{"func main() {", "", "    return", "}"}},
+				{kind: blockParagraph, text: "after"},
+			},
+		},
+		{
+			name: "unterminated fence runs to the end",
+			src:  "~~~\n# not a heading\n| a | b |",
+			want: []block{{kind: blockCode, lines: []string{"# not a heading", "| a | b |"}}},
+		},
+		{
+			name: "table with escaped pipe and ragged rows",
+			src:  "| Name | Notes |\n|:---|---:|\n| a \\| b | x |\n| only |\n\nafter",
+			want: []block{
+				{kind: blockTable, rows: [][]string{{"Name", "Notes"}, {"a | b", "x"}, {"only"}}},
+				{kind: blockParagraph, text: "after"},
+			},
+		},
+		{
+			name: "pipe without separator is a paragraph",
+			src:  "a | b\nc | d",
+			want: []block{{kind: blockParagraph, text: "a | b c | d"}},
+		},
+		{
+			name: "quote and rules",
+			src:  "> quoted\n> more\n---\n* * *\n--",
+			want: []block{
+				{kind: blockQuote, text: "quoted more"},
+				{kind: blockRule},
+				{kind: blockRule},
+				{kind: blockParagraph, text: "--"},
+			},
+		},
+		{
+			name: "windows line endings",
+			src:  "# A\r\ntext\r\n",
+			want: []block{{kind: blockHeading, level: 1, text: "A"}, {kind: blockParagraph, text: "text"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMarkdown(tt.src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMarkdown(%q)\n got  %+v\n want %+v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []span
+	}{
+		{"plain", []span{{"plain", regular}}},
+		{"**bold** and *italic*", []span{{"bold", bold}, {" and ", regular}, {"italic", italic}}},
+		{"***both***", []span{{"both", boldItalic}}},
+		{"use `go test` here", []span{{"use ", regular}, {"go test", code}, {" here", regular}}},
+		{"snake_case_name stays", []span{{"snake_case_name stays", regular}}},
+		{"_under_ score", []span{{"under", italic}, {" score", regular}}},
+		{"unclosed **bold and `code and *star", []span{{"unclosed **bold and `code and *star", regular}}},
+		{"see [the docs](https://example.com) now", []span{{"see the docs now", regular}}},
+		{"![diagram](img.png)", []span{{"diagram", regular}}},
+		{"[not a link] (x)", []span{{"[not a link] (x)", regular}}},
+		{`\*literal\* and \| pipe`, []span{{"*literal* and | pipe", regular}}},
+		{"line<br>break<br />again", []span{{"line break again", regular}}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			if got := parseInline(tt.src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInline(%q) = %+v, want %+v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	if got := plainText("**Módulo** `auth` ([spec](x))"); got != "Módulo auth (spec)" {
+		t.Errorf("plainText = %q", got)
+	}
+}
@@ -0,0 +1,139 @@
+// Package pdf typesets Markdown documents as A4 PDF files with a cover page,
+// a linked table of contents and page numbers. It only uses the standard
+// PDF fonts, so the output needs no embedded font files.
+package pdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+)
+
+// tocTitle heads the table of contents; documents are written in Spanish
+const tocTitle = "Índice"
+
+type renderer struct{}
+
+// NewRenderer creates a PDF document renderer
+func NewRenderer() port.DocumentRenderer {
+	return &renderer{}
+}
+
+// Render lays out the document body first so the table of contents can show
+// the page of every heading, then assembles cover, contents and body
+func (r *renderer) Render(doc *domain.Document) ([]byte, error) {
+	body := newTypesetter()
+	body.render(parseMarkdown(doc.Markdown))
+
+	// The table of contents lists the two highest heading levels
+	var entries []heading
+	for _, h := range body.headings {
+		if h.level <= 1 {
+			entries = append(entries, h)
+		}
+	}
+
+	var toc []*page
+	if len(entries) > 0 {
+		toc = contents(entries, 0)
+		toc = contents(entries, 1+len(toc))
+	}
+	offset := 1 + len(toc)
+
+	pages := append([]*page{cover(doc)}, toc...)
+	pages = append(pages, body.pages...)
+	for i, p := range pages[1:] {
+		footer(p, doc.Title, i+2, len(pages))
+	}
+
+	var bookmarks []bookmark
+	if len(toc) > 0 {
+		bookmarks = append(bookmarks, bookmark{tocTitle, 1, pageHeight - marginTop})
+	}
+	for _, h := range entries {
+		if h.level == 0 {
+			bookmarks = append(bookmarks, bookmark{h.text, offset + h.page, h.top})
+		}
+	}
+	return writePDF(pages, doc.Title, doc.GeneratedAt, bookmarks)
+}
+
+// cover draws the title page
+func cover(doc *domain.Document) *page {
+	t := newTypesetter()
+	t.y = pageHeight * 0.62
+	t.page.fill(headingColor, marginX, t.y+24, 64, 4)
+	t.paragraph([]span{{doc.Title, bold}}, paragraphStyle{size: 28, leading: 34, color: headingColor})
+	if doc.Subtitle != "" {
+		t.y -= 10
+		t.paragraph([]span{{doc.Subtitle, regular}}, paragraphStyle{size: 14, leading: 19, color: mutedColor})
+	}
+	if !doc.GeneratedAt.IsZero() {
+		t.y -= 24
+		t.paragraph([]span{{doc.GeneratedAt.Format("02/01/2006 15:04"), regular}}, paragraphStyle{size: 10, leading: 14, color: mutedColor})
+	}
+	return t.page
+}
+
+// contents typesets the table of contents; body pages start after offset
+// pages. Titles are cut to one line so the page count does not depend on
+// the numbers.
+func contents(entries []heading, offset int) []*page {
+	t := newTypesetter()
+	t.paragraph([]span{{tocTitle, bold}}, paragraphStyle{size: headingSizes[0], leading: headingSizes[0] * 1.3, color: headingColor})
+	t.page.line(headingColor, 1, marginX, t.y-2, pageWidth-marginX, t.y-2)
+	t.y -= 18
+
+	for _, e := range entries {
+		size, st, indent := bodySize, regular, listIndent
+		if e.level == 0 {
+			size, st, indent = 11, bold, 0
+			t.skip(6)
+		}
+		leading := size * 1.6
+		t.need(leading)
+
+		number := run{encode(fmt.Sprint(offset + e.page + 1)), st, size}
+		dot := run{[]byte("."), regular, size}
+		available := textWidth - indent - number.width() - 4*dot.width()
+		title := run{fitText(encode(e.text), st, size, available), st, size}
+		dots := int((textWidth - indent - title.width() - number.width()) / dot.width())
+
+		baseline := t.y - size
+		x := marginX + indent
+		t.page.text(textColor, x, baseline, []run{title})
+		if dots > 2 {
+			leader := run{[]byte(" " + strings.Repeat(".", dots-2)), regular, size}
+			t.page.text(mutedColor, pageWidth-marginX-number.width()-leader.width()-dot.width(), baseline, []run{leader})
+		}
+		t.page.text(textColor, pageWidth-marginX-number.width(), baseline, []run{number})
+		t.page.links = append(t.page.links, link{x: x, y: t.y - leading, w: textWidth - indent, h: leading, page: offset + e.page, top: e.top})
+		t.y -= leading
+	}
+	return t.pages
+}
+
+// fitText cuts text with an ellipsis so it is no wider than width
+func fitText(text []byte, st style, size, width float64) []byte {
+	f := fonts[st]
+	if f.width(text, size) <= width {
+		return text
+	}
+	ellipsis := []byte{0x85}
+	for len(text) > 0 && f.width(text, size)+f.width(ellipsis, size) > width {
+		text = text[:len(text)-1]
+	}
+	return append(text, ellipsis...)
+}
+
+// footer draws the running title and the page number
+func footer(p *page, title string, number, total int) {
+	const size = 8.0
+	y := marginBottom - 28
+	p.line(ruleColor, 0.5, marginX, y+12, pageWidth-marginX, y+12)
+	label := run{encode(fmt.Sprintf("%d / %d", number, total)), regular, size}
+	p.text(mutedColor, pageWidth-marginX-label.width(), y, []run{label})
+	p.text(mutedColor, marginX, y, []run{{fitText(encode(title), regular, size, textWidth*0.7), regular, size}})
+}
@@ -0,0 +1,141 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+)
+
+var (
+	pageCountRe = regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`)
+	startxrefRe = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+)
+
+func TestRender(t *testing.T) {
+	long := strings.Repeat("Un párrafo largo con acentos, comillas “tipográficas” y (paréntesis). ", 400)
+	tests := []struct {
+		name      string
+		markdown  string
+		pages     int  // expected page count, 0 when the body spans several pages
+		outline   bool // whether the document has bookmarks
+		wantTexts []string
+	}{
+		{name: "empty document", markdown: "", pages: 2},
+		{name: "text without headings", markdown: "just text", pages: 2, wantTexts: []string{"(just text)"}},
+		{name: "sections with contents", markdown: "# Idea\n\ntext\n\n# Plan\n\n## Steps\n\n- one", pages: 4, outline: true, wantTexts: []string{"(Idea)", "(Plan)", "(Steps)"}},
+		{name: "long section", markdown: "# Idea\n\n" + long, outline: true},
+		{name: "escapes pdf string delimiters", markdown: `a (b) \ c`, pages: 2, wantTexts: []string{`(a \(b\) \\ c)`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := NewRenderer().Render(&domain.Document{
+				Title:       "Proyecto (demo)",
+				Subtitle:    "Especificación",
+				GeneratedAt: time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC),
+				Markdown:    tt.markdown,
+			})
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) {
+				t.Fatalf("missing PDF header: %q", out[:min(len(out), 16)])
+			}
+
+			// startxref must point at the cross-reference table
+			m := startxrefRe.FindSubmatch(out)
+			if m == nil {
+				t.Fatal("missing startxref trailer")
+			}
+			xref, _ := strconv.Atoi(string(m[1]))
+			if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+				t.Fatalf("startxref %d does not point at the xref table", xref)
+			}
+
+			count := pageCountRe.FindSubmatch(out)
+			if count == nil {
+				t.Fatal("missing page tree")
+			}
+			pages, _ := strconv.Atoi(string(count[1]))
+			if pages != bytes.Count(out, []byte("/Type /Page /Parent")) {
+				t.Errorf("page tree counts %d pages, file holds %d", pages, bytes.Count(out, []byte("/Type /Page /Parent")))
+			}
+			if tt.pages > 0 && pages != tt.pages {
+				t.Errorf("%d pages, want %d", pages, tt.pages)
+			}
+			if tt.pages == 0 && pages < 3 {
+				t.Errorf("%d pages, want the long section to span several", pages)
+			}
+			if got := bytes.Contains(out, []byte("/Outlines")); got != tt.outline {
+				t.Errorf("outline = %v, want %v", got, tt.outline)
+			}
+
+			content := pageContents(t, tt.markdown)
+			for _, want := range tt.wantTexts {
+				if !strings.Contains(content, want) {
+					t.Errorf("content does not draw %s", want)
+				}
+			}
+		})
+	}
+}
+
+// pageContents returns the uncompressed content streams of the body pages
+func pageContents(t *testing.T, markdown string) string {
+	t.Helper()
+	ts := newTypesetter()
+	ts.render(parseMarkdown(markdown))
+	var b strings.Builder
+	for i, p := range ts.pages {
+		fmt.Fprintf(&b, "%% page %d\n%s", i+1, p.content.String())
+	}
+	return b.String()
+}
+
+func TestContentsLinksToHeadingPages(t *testing.T) {
+	entries := []heading{{level: 0, text: "Idea", page: 0}, {level: 1, text: "Detalle", page: 0}, {level: 0, text: "Plan", page: 3}}
+	pages := contents(entries, 2)
+
+	if len(pages) != 1 {
+		t.Fatalf("contents took %d pages", len(pages))
+	}
+	links := pages[0].links
+	if len(links) != len(entries) {
+		t.Fatalf("%d links, want %d", len(links), len(entries))
+	}
+	for i, l := range links {
+		if l.page != 2+entries[i].page {
+			t.Errorf("link %d goes to page %d, want %d", i, l.page, 2+entries[i].page)
+		}
+	}
+	if !strings.Contains(pages[0].content.String(), "(6)") {
+		t.Error("contents should print the page number of Plan as 6")
+	}
+}
+
+func TestFitText(t *testing.T) {
+	tests := []struct {
+		text     string
+		width    float64
+		ellipsis bool
+	}{
+		{"short", 100, false},
+		{strings.Repeat("long ", 40), 100, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		got := fitText(encode(tt.text), regular, 10, tt.width)
+		if hasEllipsis := bytes.HasSuffix(got, []byte{0x85}); hasEllipsis != tt.ellipsis {
+			t.Errorf("fitText(%q, %v) = %q", tt.text, tt.width, got)
+		}
+		if tt.ellipsis && len(got) > 1 && fonts[regular].width(got, 10) > tt.width {
+			t.Errorf("fitText(%q) is %.1f wide, want at most %v", tt.text, fonts[regular].width(got, 10), tt.width)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// bookmark is a top-level entry of the document outline
+type bookmark struct {
+	title string
+	page  int
+	top   float64
+}
+
+// writePDF serializes pages into a PDF 1.4 file. Objects are numbered as
+// catalog, page tree, info, fonts, then a page and its content stream per
+// page, then the outline.
+func writePDF(pages []*page, title string, created time.Time, bookmarks []bookmark) ([]byte, error) {
+	const (
+		catalogObj = 1
+		pagesObj   = 2
+		infoObj    = 3
+		firstFont  = 4
+	)
+	firstPage := firstFont + len(fonts)
+	pageObj := func(i int) int { return firstPage + 2*i }
+	outlineObj := firstPage + 2*len(pages)
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	dest := func(page int, top float64) string {
+		return fmt.Sprintf("[%d 0 R /XYZ 0 %s null]", pageObj(page), num(top+8))
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	catalog := fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R", pagesObj)
+	if len(bookmarks) > 0 {
+		catalog += fmt.Sprintf(" /Outlines %d 0 R /PageMode /UseOutlines", outlineObj)
+	}
+	object(catalog + " >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageObj(i))
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	object(fmt.Sprintf("<< /Title %s /Producer (Idea Forge) /CreationDate (D:%s) >>", textString(title), created.UTC().Format("20060102150405Z")))
+
+	var fontRefs strings.Builder
+	for i, f := range fonts {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.base))
+		fmt.Fprintf(&fontRefs, " /%s %d 0 R", f.resource, firstFont+i)
+	}
+
+	for i, p := range pages {
+		var annots strings.Builder
+		for _, l := range p.links {
+			fmt.Fprintf(&annots, " << /Type /Annot /Subtype /Link /Border [0 0 0] /Rect [%s %s %s %s] /Dest %s >>",
+				num(l.x), num(l.y), num(l.x+l.w), num(l.y+l.h), dest(l.page, l.top))
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font <<%s >> >> /Contents %d 0 R /Annots [%s ] >>",
+			pagesObj, num(pageWidth), num(pageHeight), fontRefs.String(), pageObj(i)+1, annots.String()))
+
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		if _, err := zw.Write([]byte(p.content.String())); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	if len(bookmarks) > 0 {
+		first, last := outlineObj+1, outlineObj+len(bookmarks)
+		object(fmt.Sprintf("<< /Type /Outlines /First %d 0 R /Last %d 0 R /Count %d >>", first, last, len(bookmarks)))
+		for i, b := range bookmarks {
+			item := fmt.Sprintf("<< /Title %s /Parent %d 0 R /Dest %s", textString(b.title), outlineObj, dest(b.page, b.top))
+			if i > 0 {
+				item += fmt.Sprintf(" /Prev %d 0 R", first+i-1)
+			}
+			if i < len(bookmarks)-1 {
+				item += fmt.Sprintf(" /Next %d 0 R", first+i+1)
+			}
+			object(item + " >>")
+		}
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogObj, infoObj, xref)
+	return buf.Bytes(), nil
+}
+
+// escape quotes WinAnsi text for a PDF literal string
+func escape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// textString encodes metadata text as a UTF-16 hex string, which readers
+// show without depending on the font encoding
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+func num(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/events"
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+)
+
+func NewImportRepo(db *sql.DB) port.ImportRepository { return &repo{db: db} }
+
+func (r *repo) ImportProject(ctx context.Context, p *domain.Project, evts ...events.Event) error {
+	return events.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		i := p.Idea
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO ideation_ideas
+			  (id, title, objective, problem, scope, validate_competition, validate_monetization, completed, user_id, workspace_id, created_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+		`, i.ID, i.Title, i.Objective, i.Problem, i.Scope, i.ValidateCompetition, i.ValidateMonetization, i.Completed, i.UserID, i.WorkspaceID, i.CreatedAt); err != nil {
+			return err
+		}
+
+		if plan := p.ActionPlan; plan != nil {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO action_plans
+				  (id, idea_id, status, functional_requirements, non_functional_requirements, business_logic_flow, completed, created_at, updated_at)
+				VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+			`, plan.ID, plan.IdeaID, plan.Status, plan.FunctionalRequirements, plan.NonFunctionalRequirements, plan.BusinessLogicFlow, plan.Completed, plan.CreatedAt, plan.UpdatedAt); err != nil {
+				return err
+			}
+		}
+
+		if arch := p.Architecture; arch != nil {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO architectures
+				  (id, action_plan_id, status, user_stories, database_type, database_schema, entities_relationships, tech_stack, architecture_pattern, system_architecture, completed, created_at, updated_at)
+				VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+			`, arch.ID, arch.ActionPlanID, arch.Status, arch.UserStories, arch.DatabaseType, arch.DatabaseSchema, arch.EntitiesRelationships, arch.TechStack, arch.ArchitecturePattern, arch.SystemArchitecture, arch.Completed, arch.CreatedAt, arch.UpdatedAt); err != nil {
+				return err
+			}
+		}
+
+		for _, m := range p.Modules {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO development_modules
+				  (id, architecture_id, name, description, functionality, dependencies, technical_details, priority, status, created_at, updated_at)
+				VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+			`, m.ID, m.ArchitectureID, m.Name, m.Description, m.Functionality, m.Dependencies, m.TechnicalDetails, m.Priority, m.Status, m.CreatedAt, m.UpdatedAt); err != nil {
+				return err
+			}
+		}
+
+		if p.Chats == nil {
+			return nil
+		}
+		for _, m := range p.Chats.Ideation {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO ideation_messages (id, idea_id, role, content, created_at)
+				VALUES ($1,$2,$3,$4,$5)
+			`, uuid.New(), i.ID, m.Role, m.Content, m.CreatedAt); err != nil {
+				return err
+			}
+		}
+		for _, m := range p.Chats.ActionPlan {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO action_plan_messages (id, action_plan_id, role, content, created_at)
+				VALUES ($1,$2,$3,$4,$5)
+			`, uuid.New(), p.ActionPlan.ID, m.Role, m.Content, m.CreatedAt); err != nil {
+				return err
+			}
+		}
+		for _, m := range p.Chats.Architecture {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO architecture_messages (id, architecture_id, role, content, created_at)
+				VALUES ($1,$2,$3,$4,$5)
+			`, uuid.New(), p.Architecture.ID, m.Role, m.Content, m.CreatedAt); err != nil {
+				return err
+			}
+		}
+		for _, m := range p.Chats.Global {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO global_chat_messages (id, idea_id, role, content, affected_modules, created_at)
+				VALUES ($1,$2,$3,$4,$5,$6)
+			`, uuid.New(), i.ID, m.Role, m.Content, m.AffectedModules, m.CreatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, evts...)
+}
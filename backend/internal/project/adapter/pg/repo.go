@@ -0,0 +1,64 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+	"github.com/google/uuid"
+)
+
+type repo struct{ db *sql.DB }
+
+func NewCollaboratorRepo(db *sql.DB) port.CollaboratorRepository { return &repo{db: db} }
+
+func (r *repo) UpsertCollaborator(ctx context.Context, c *domain.Collaborator) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO project_collaborators (idea_id, user_id, role, added_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (idea_id, user_id) DO UPDATE SET role = EXCLUDED.role, added_by = EXCLUDED.added_by
+		RETURNING created_at
+	`, c.IdeaID, c.UserID, c.Role, c.AddedBy).Scan(&c.CreatedAt)
+}
+
+func (r *repo) ListCollaborators(ctx context.Context, ideaID uuid.UUID) ([]domain.Collaborator, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.idea_id, c.user_id, u.username, u.email, c.role, c.added_by, c.created_at
+		FROM project_collaborators c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.idea_id = $1
+		ORDER BY c.created_at
+	`, ideaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collaborators := []domain.Collaborator{}
+	for rows.Next() {
+		var c domain.Collaborator
+		var addedBy uuid.NullUUID
+		if err := rows.Scan(&c.IdeaID, &c.UserID, &c.Username, &c.Email, &c.Role, &addedBy, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.AddedBy = addedBy.UUID
+		collaborators = append(collaborators, c)
+	}
+	return collaborators, rows.Err()
+}
+
+func (r *repo) DeleteCollaborator(ctx context.Context, ideaID, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM project_collaborators WHERE idea_id = $1 AND user_id = $2`, ideaID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrCollaboratorNotFound
+	}
+	return nil
+}
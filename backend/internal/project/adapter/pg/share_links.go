@@ -0,0 +1,121 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+	"github.com/google/uuid"
+)
+
+func NewShareLinkRepo(db *sql.DB) port.ShareLinkRepository { return &repo{db: db} }
+
+const shareLinkColumns = `id, idea_id, created_by, password_hash, include_chats, expires_at, revoked_at, created_at`
+
+func (r *repo) CreateShareLink(ctx context.Context, link *domain.ShareLink) error {
+	var passwordHash sql.NullString
+	if link.PasswordHash != "" {
+		passwordHash = sql.NullString{String: link.PasswordHash, Valid: true}
+	}
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO share_links (id, idea_id, created_by, password_hash, include_chats, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, link.ID, link.IdeaID, link.CreatedBy, passwordHash, link.IncludeChats, link.ExpiresAt).Scan(&link.CreatedAt)
+}
+
+func (r *repo) GetShareLink(ctx context.Context, id uuid.UUID) (*domain.ShareLink, error) {
+	link, err := scanShareLink(r.db.QueryRowContext(ctx, `SELECT `+shareLinkColumns+` FROM share_links WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrShareLinkNotFound
+	}
+	return link, err
+}
+
+func (r *repo) ListShareLinks(ctx context.Context, ideaID uuid.UUID) ([]domain.ShareLink, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+shareLinkColumns+`
+		FROM share_links
+		WHERE idea_id = $1
+		ORDER BY created_at DESC
+	`, ideaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []domain.ShareLink{}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+	return links, rows.Err()
+}
+
+func (r *repo) RevokeShareLink(ctx context.Context, ideaID, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE share_links SET revoked_at = NOW()
+		WHERE id = $1 AND idea_id = $2 AND revoked_at IS NULL
+	`, id, ideaID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrShareLinkNotFound
+	}
+	return nil
+}
+
+func (r *repo) CountPasswordFailures(ctx context.Context, linkID uuid.UUID, ip string, since time.Time) (int, int, error) {
+	var byLink, byIP int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE link_id = $1), COUNT(*) FILTER (WHERE ip = $2)
+		FROM share_link_password_failures
+		WHERE (link_id = $1 OR ip = $2) AND failed_at > $3
+	`, linkID, ip, since).Scan(&byLink, &byIP)
+	return byLink, byIP, err
+}
+
+func (r *repo) RecordPasswordFailure(ctx context.Context, linkID uuid.UUID, ip string, forgetBefore time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM share_link_password_failures WHERE failed_at < $1`, forgetBefore); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO share_link_password_failures (link_id, ip) VALUES ($1, $2)
+	`, linkID, ip)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanShareLink(row rowScanner) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+	var createdBy uuid.NullUUID
+	var passwordHash sql.NullString
+	var expiresAt, revokedAt sql.NullTime
+	if err := row.Scan(&link.ID, &link.IdeaID, &createdBy, &passwordHash, &link.IncludeChats, &expiresAt, &revokedAt, &link.CreatedAt); err != nil {
+		return nil, err
+	}
+	link.CreatedBy = createdBy.UUID
+	link.PasswordHash = passwordHash.String
+	link.HasPassword = passwordHash.Valid
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+	return &link, nil
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collaborator roles on a single project
+const (
+	CollaboratorViewer = "viewer"
+	CollaboratorEditor = "editor"
+)
+
+var (
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	ErrInvalidCollaborator  = errors.New("email or user_id is required")
+	ErrInvalidRole          = errors.New("role must be viewer or editor")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrSelfCollaborator     = errors.New("you cannot add yourself as collaborator")
+)
+
+// Collaborator is a user granted access to one project (idea, plan, architecture and modules)
+// outside of the workspace membership
+type Collaborator struct {
+	IdeaID    uuid.UUID `json:"idea_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	AddedBy   uuid.UUID `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// User is the subset of a user account needed to grant access
+type User struct {
+	ID       uuid.UUID
+	Username string
+	Email    string
+}
+
+// IsValidCollaboratorRole reports whether role can be granted on a project
+func IsValidCollaboratorRole(role string) bool {
+	return role == CollaboratorViewer || role == CollaboratorEditor
+}
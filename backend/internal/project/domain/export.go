@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Export formats for a whole project
+const (
+	ExportMarkdown = "md"  // single Markdown specification
+	ExportZip      = "zip" // one file per stage plus chat transcripts
+	ExportPDF      = "pdf" // printable specification
+)
+
+var (
+	ErrInvalidExportFormat = errors.New("format must be md, zip or pdf")
+	// ErrInvalidBundle is returned for bundles that cannot be imported; the
+	// wrapping error tells what is wrong
+	ErrInvalidBundle = errors.New("invalid project bundle")
+)
+
+// Export is a rendered project ready to be downloaded
+type Export struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Document is a titled Markdown document to be typeset for print
+type Document struct {
+	Title       string
+	Subtitle    string
+	GeneratedAt time.Time
+	Markdown    string
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	actionplandomain "github.com/dark/idea-forge/internal/actionplan/domain"
+	archdomain "github.com/dark/idea-forge/internal/architecture/domain"
+	devmoduledomain "github.com/dark/idea-forge/internal/devmodule/domain"
+	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+)
+
+// Project aggregates every stage of an idea pipeline
+type Project struct {
+	Idea         *ideadomain.Idea                    `json:"idea"`
+	ActionPlan   *actionplandomain.ActionPlan        `json:"action_plan,omitempty"`
+	Architecture *archdomain.Architecture            `json:"architecture,omitempty"`
+	Modules      []devmoduledomain.DevelopmentModule `json:"modules"`
+	Chats        *Chats                              `json:"chats,omitempty"`
+}
+
+// Chats holds the four chat histories of a project
+type Chats struct {
+	Ideation     []ChatMessage `json:"ideation"`
+	ActionPlan   []ChatMessage `json:"action_plan"`
+	Architecture []ChatMessage `json:"architecture"`
+	Global       []ChatMessage `json:"global"`
+}
+
+// ChatMessage is a stage-agnostic view of a chat message
+type ChatMessage struct {
+	Role            string    `json:"role"` // user, assistant, system
+	Content         string    `json:"content"`
+	AffectedModules string    `json:"affected_modules,omitempty"` // only for global chat
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// File is a named document produced from project data
+type File struct {
+	Path    string
+	Content []byte
+}
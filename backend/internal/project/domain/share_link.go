@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrShareLinkNotFound     = errors.New("share link not found")
+	ErrShareLinkExpired      = errors.New("share link expired")
+	ErrSharePasswordRequired = errors.New("password required")
+	ErrInvalidSharePassword  = errors.New("invalid password")
+	ErrTooManyShareAttempts  = errors.New("too many password attempts, try again later")
+	ErrInvalidExpiry         = errors.New("expires_at must be in the future")
+)
+
+// ShareLink grants read-only public access to a project through a signed token
+type ShareLink struct {
+	ID           uuid.UUID  `json:"id"`
+	IdeaID       uuid.UUID  `json:"idea_id"`
+	CreatedBy    uuid.UUID  `json:"created_by"`
+	PasswordHash string     `json:"-"`
+	HasPassword  bool       `json:"has_password"`
+	IncludeChats bool       `json:"include_chats"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	URL          string     `json:"url,omitempty"` // only returned when the link is created
+}
+
+// IsActive reports whether the link can still be opened
+func (l *ShareLink) IsActive() bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	return l.ExpiresAt == nil || time.Now().Before(*l.ExpiresAt)
+}
@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+)
+
+// CollaboratorRepository persists per-project access grants
+type CollaboratorRepository interface {
+	// UpsertCollaborator grants the role, replacing any previous grant of the user on the idea
+	UpsertCollaborator(ctx context.Context, c *domain.Collaborator) error
+	ListCollaborators(ctx context.Context, ideaID uuid.UUID) ([]domain.Collaborator, error)
+	DeleteCollaborator(ctx context.Context, ideaID, userID uuid.UUID) error
+}
+
+// UserLookup finds user accounts to share projects with
+type UserLookup interface {
+	GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+}
+
+// CollaboratorNotifier tells users they were given access to a project
+type CollaboratorNotifier interface {
+	NotifyCollaboratorAdded(ctx context.Context, c *domain.Collaborator, inviterName string) error
+}
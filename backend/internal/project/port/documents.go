@@ -0,0 +1,8 @@
+package port
+
+import "github.com/dark/idea-forge/internal/project/domain"
+
+// DocumentRenderer typesets Markdown documents into a printable format
+type DocumentRenderer interface {
+	Render(doc *domain.Document) ([]byte, error)
+}
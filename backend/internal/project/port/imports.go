@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/dark/idea-forge/internal/events"
+	"github.com/dark/idea-forge/internal/project/domain"
+)
+
+// ImportRepository persists imported projects
+type ImportRepository interface {
+	// ImportProject inserts the idea, its stages, modules and chats in one transaction
+	ImportProject(ctx context.Context, p *domain.Project, evts ...events.Event) error
+}
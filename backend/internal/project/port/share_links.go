@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+)
+
+// ShareLinkRepository persists public share links
+type ShareLinkRepository interface {
+	CreateShareLink(ctx context.Context, link *domain.ShareLink) error
+	GetShareLink(ctx context.Context, id uuid.UUID) (*domain.ShareLink, error)
+	ListShareLinks(ctx context.Context, ideaID uuid.UUID) ([]domain.ShareLink, error)
+	RevokeShareLink(ctx context.Context, ideaID, id uuid.UUID) error
+	// CountPasswordFailures returns the failed password attempts since the given time on the link and from the IP
+	CountPasswordFailures(ctx context.Context, linkID uuid.UUID, ip string, since time.Time) (byLink, byIP int, err error)
+	// RecordPasswordFailure stores a failed password attempt and forgets the ones before forgetBefore
+	RecordPasswordFailure(ctx context.Context, linkID uuid.UUID, ip string, forgetBefore time.Time) error
+}
@@ -0,0 +1,37 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	actionplandomain "github.com/dark/idea-forge/internal/actionplan/domain"
+	archdomain "github.com/dark/idea-forge/internal/architecture/domain"
+	devmoduledomain "github.com/dark/idea-forge/internal/devmodule/domain"
+	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+)
+
+// IdeaSource reads ideas and their ideation chat
+type IdeaSource interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*ideadomain.Idea, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]ideadomain.Idea, error)
+	ListMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]ideadomain.Message, error)
+}
+
+// ActionPlanSource reads action plans and their chat
+type ActionPlanSource interface {
+	GetActionPlanByIdeaID(ctx context.Context, ideaID uuid.UUID) (*actionplandomain.ActionPlan, error)
+	GetMessages(ctx context.Context, actionPlanID uuid.UUID, limit int) ([]actionplandomain.ActionPlanMessage, error)
+}
+
+// ArchitectureSource reads architectures and their chat
+type ArchitectureSource interface {
+	GetArchitectureByActionPlanID(ctx context.Context, actionPlanID uuid.UUID) (*archdomain.Architecture, error)
+	GetMessages(ctx context.Context, architectureID uuid.UUID, limit int) ([]archdomain.ArchitectureMessage, error)
+}
+
+// DevModuleSource reads development modules and the global chat
+type DevModuleSource interface {
+	GetModulesByArchitectureID(ctx context.Context, architectureID uuid.UUID) ([]devmoduledomain.DevelopmentModule, error)
+	GetGlobalMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]devmoduledomain.GlobalChatMessage, error)
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+)
+
+// CollaboratorUsecase shares a single project with specific users.
+// Callers must already hold the owner role on the idea.
+type CollaboratorUsecase struct {
+	repo     port.CollaboratorRepository
+	users    port.UserLookup
+	notifier port.CollaboratorNotifier
+}
+
+// NewCollaboratorUsecase creates a new collaborator use case
+func NewCollaboratorUsecase(repo port.CollaboratorRepository, users port.UserLookup, notifier port.CollaboratorNotifier) *CollaboratorUsecase {
+	return &CollaboratorUsecase{repo: repo, users: users, notifier: notifier}
+}
+
+// AddCollaboratorInput identifies the user by id or email
+type AddCollaboratorInput struct {
+	UserID *uuid.UUID `json:"user_id"`
+	Email  string     `json:"email"`
+	Role   string     `json:"role"`
+}
+
+// Add grants (or changes) access to a project
+func (uc *CollaboratorUsecase) Add(ctx context.Context, actorID, ideaID uuid.UUID, in AddCollaboratorInput) (*domain.Collaborator, error) {
+	if in.Role == "" {
+		in.Role = domain.CollaboratorViewer
+	}
+	if !domain.IsValidCollaboratorRole(in.Role) {
+		return nil, domain.ErrInvalidRole
+	}
+
+	var user *domain.User
+	var err error
+	switch {
+	case in.UserID != nil:
+		user, err = uc.users.GetUserByID(ctx, *in.UserID)
+	case strings.TrimSpace(in.Email) != "":
+		user, err = uc.users.GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(in.Email)))
+	default:
+		return nil, domain.ErrInvalidCollaborator
+	}
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.ID == actorID {
+		return nil, domain.ErrSelfCollaborator
+	}
+
+	c := &domain.Collaborator{
+		IdeaID:   ideaID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     in.Role,
+		AddedBy:  actorID,
+	}
+	if err := uc.repo.UpsertCollaborator(ctx, c); err != nil {
+		return nil, err
+	}
+
+	inviterName := "Alguien"
+	if actor, err := uc.users.GetUserByID(ctx, actorID); err == nil {
+		inviterName = actor.Username
+	}
+	if err := uc.notifier.NotifyCollaboratorAdded(ctx, c, inviterName); err != nil {
+		log.Printf("error notifying collaborator %s: %v", c.UserID, err)
+	}
+	return c, nil
+}
+
+// List returns the collaborators of a project
+func (uc *CollaboratorUsecase) List(ctx context.Context, ideaID uuid.UUID) ([]domain.Collaborator, error) {
+	return uc.repo.ListCollaborators(ctx, ideaID)
+}
+
+// Remove revokes the access of a collaborator
+func (uc *CollaboratorUsecase) Remove(ctx context.Context, ideaID, userID uuid.UUID) error {
+	return uc.repo.DeleteCollaborator(ctx, ideaID, userID)
+}
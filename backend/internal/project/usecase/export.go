@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+)
+
+// Export renders a project as a single Markdown specification, as a printable
+// PDF of it, or as a zip with one file per stage, the specification and the
+// chat transcripts
+func (uc *ProjectUsecase) Export(ctx context.Context, ideaID uuid.UUID, format string) (*domain.Export, error) {
+	if format == "" {
+		format = domain.ExportMarkdown
+	}
+	if format != domain.ExportMarkdown && format != domain.ExportZip && format != domain.ExportPDF {
+		return nil, domain.ErrInvalidExportFormat
+	}
+
+	p, err := uc.GetProject(ctx, ideaID, format == domain.ExportZip)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	name := exportName(p)
+
+	if format == domain.ExportPDF {
+		content, err := uc.documents.Render(&domain.Document{
+			Title:       strings.TrimSpace(p.Idea.Title),
+			Subtitle:    "Especificación del proyecto",
+			GeneratedAt: now,
+			Markdown:    renderSpecificationBody(p),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &domain.Export{
+			Filename:    name + ".pdf",
+			ContentType: "application/pdf",
+			Content:     content,
+		}, nil
+	}
+
+	spec := RenderSpecificationMarkdown(p, now)
+	if format == domain.ExportMarkdown {
+		return &domain.Export{
+			Filename:    name + ".md",
+			ContentType: "text/markdown; charset=utf-8",
+			Content:     []byte(spec),
+		}, nil
+	}
+
+	files, err := ProjectFiles(p, name+"/")
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, domain.File{Path: name + "/specification.md", Content: []byte(spec)})
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &domain.Export{
+		Filename:    name + ".zip",
+		ContentType: "application/zip",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// exportName turns the idea title into an ASCII file name, falling back to the idea id
+func exportName(p *domain.Project) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(p.Idea.Title) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case strings.ContainsRune("áàä", r):
+			b.WriteByte('a')
+			dash = false
+		case strings.ContainsRune("éèë", r):
+			b.WriteByte('e')
+			dash = false
+		case strings.ContainsRune("íìï", r):
+			b.WriteByte('i')
+			dash = false
+		case strings.ContainsRune("óòö", r):
+			b.WriteByte('o')
+			dash = false
+		case strings.ContainsRune("úùü", r):
+			b.WriteByte('u')
+			dash = false
+		case r == 'ñ':
+			b.WriteByte('n')
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= 60 {
+			break
+		}
+	}
+	name := strings.TrimRight(b.String(), "-")
+	if name == "" {
+		return p.Idea.ID.String()
+	}
+	return name
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	actionplandomain "github.com/dark/idea-forge/internal/actionplan/domain"
+	archdomain "github.com/dark/idea-forge/internal/architecture/domain"
+	devmoduledomain "github.com/dark/idea-forge/internal/devmodule/domain"
+	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+)
+
+// storedProject is a complete project with its chats, served through the stage sources
+type storedProject struct {
+	idea        *ideadomain.Idea
+	plan        *actionplandomain.ActionPlan
+	arch        *archdomain.Architecture
+	modules     []devmoduledomain.DevelopmentModule
+	ideaChat    []ideadomain.Message
+	planChat    []actionplandomain.ActionPlanMessage
+	archChat    []archdomain.ArchitectureMessage
+	globalChat  []devmoduledomain.GlobalChatMessage
+	renderCalls int
+}
+
+type ideaSource struct {
+	port.IdeaSource
+	p *storedProject
+}
+
+func (s ideaSource) FindByID(ctx context.Context, id uuid.UUID) (*ideadomain.Idea, error) {
+	copied := *s.p.idea
+	return &copied, nil
+}
+
+func (s ideaSource) ListMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]ideadomain.Message, error) {
+	return s.p.ideaChat, nil
+}
+
+type planSource struct{ p *storedProject }
+
+func (s planSource) GetActionPlanByIdeaID(ctx context.Context, ideaID uuid.UUID) (*actionplandomain.ActionPlan, error) {
+	if s.p.plan == nil {
+		return nil, sql.ErrNoRows
+	}
+	return s.p.plan, nil
+}
+
+func (s planSource) GetMessages(ctx context.Context, actionPlanID uuid.UUID, limit int) ([]actionplandomain.ActionPlanMessage, error) {
+	return s.p.planChat, nil
+}
+
+type archSource struct{ p *storedProject }
+
+func (s archSource) GetArchitectureByActionPlanID(ctx context.Context, actionPlanID uuid.UUID) (*archdomain.Architecture, error) {
+	if s.p.arch == nil {
+		return nil, sql.ErrNoRows
+	}
+	return s.p.arch, nil
+}
+
+func (s archSource) GetMessages(ctx context.Context, architectureID uuid.UUID, limit int) ([]archdomain.ArchitectureMessage, error) {
+	return s.p.archChat, nil
+}
+
+type moduleSource struct{ p *storedProject }
+
+func (s moduleSource) GetModulesByArchitectureID(ctx context.Context, architectureID uuid.UUID) ([]devmoduledomain.DevelopmentModule, error) {
+	return s.p.modules, nil
+}
+
+func (s moduleSource) GetGlobalMessages(ctx context.Context, ideaID uuid.UUID, limit int) ([]devmoduledomain.GlobalChatMessage, error) {
+	return s.p.globalChat, nil
+}
+
+// markdownDocuments stands in for the PDF renderer and returns the Markdown it was given
+type markdownDocuments struct{ p *storedProject }
+
+func (d markdownDocuments) Render(doc *domain.Document) ([]byte, error) {
+	d.p.renderCalls++
+	return []byte(doc.Title + "\n" + doc.Markdown), nil
+}
+
+func newProjectUsecase(p *storedProject) *ProjectUsecase {
+	return NewProjectUsecase(ideaSource{p: p}, planSource{p}, archSource{p}, moduleSource{p}, markdownDocuments{p})
+}
+
+func fullProject() *storedProject {
+	owner := uuid.New()
+	idea := &ideadomain.Idea{ID: uuid.New(), UserID: &owner, Title: "Gestor de Tareas: Versión 2", Objective: "Organizar", Problem: "Caos", Scope: "Equipos"}
+	plan := &actionplandomain.ActionPlan{ID: uuid.New(), IdeaID: idea.ID, Status: "completed",
+		FunctionalRequirements: "# Login\n\nUsuarios con email", NonFunctionalRequirements: "Rápido", BusinessLogicFlow: "1. Crear tarea"}
+	arch := &archdomain.Architecture{ID: uuid.New(), ActionPlanID: plan.ID, Status: "completed",
+		UserStories: "Como usuario...", DatabaseType: "PostgreSQL", DatabaseSchema: "CREATE TABLE tasks (id uuid);",
+		TechStack: "Go", ArchitecturePattern: "Hexagonal", SystemArchitecture: "## API\n\nREST"}
+	api := devmoduledomain.DevelopmentModule{ID: uuid.New(), ArchitectureID: arch.ID, Name: "api", Status: "pending", Priority: 1, Dependencies: `[]`}
+	web := devmoduledomain.DevelopmentModule{ID: uuid.New(), ArchitectureID: arch.ID, Name: "web | ui", Status: "pending", Priority: 2, Dependencies: `["api"]`}
+	return &storedProject{
+		idea: idea, plan: plan, arch: arch, modules: []devmoduledomain.DevelopmentModule{api, web},
+		ideaChat:   []ideadomain.Message{{Role: "user", Content: "Quiero un gestor de tareas"}},
+		planChat:   []actionplandomain.ActionPlanMessage{{Role: "assistant", Content: "Plan listo"}},
+		archChat:   []archdomain.ArchitectureMessage{{Role: "user", Content: "¿Go o Node?"}},
+		globalChat: []devmoduledomain.GlobalChatMessage{{Role: "user", Content: "Renombra web"}},
+	}
+}
+
+func TestExportFormats(t *testing.T) {
+	tests := []struct {
+		format      string
+		filename    string
+		contentType string
+		wantErr     error
+	}{
+		{format: "", filename: "gestor-de-tareas-version-2.md", contentType: "text/markdown; charset=utf-8"},
+		{format: "md", filename: "gestor-de-tareas-version-2.md", contentType: "text/markdown; charset=utf-8"},
+		{format: "pdf", filename: "gestor-de-tareas-version-2.pdf", contentType: "application/pdf"},
+		{format: "zip", filename: "gestor-de-tareas-version-2.zip", contentType: "application/zip"},
+		{format: "docx", wantErr: domain.ErrInvalidExportFormat},
+		{format: "MD", wantErr: domain.ErrInvalidExportFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			p := fullProject()
+			export, err := newProjectUsecase(p).Export(context.Background(), p.idea.ID, tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Export err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if export.Filename != tt.filename || export.ContentType != tt.contentType {
+				t.Errorf("export = %s (%s), want %s (%s)", export.Filename, export.ContentType, tt.filename, tt.contentType)
+			}
+			if len(export.Content) == 0 {
+				t.Error("empty export")
+			}
+			// The PDF typesets the specification body, without its Markdown title and index
+			if tt.format == "pdf" && (p.renderCalls != 1 || bytes.Contains(export.Content, []byte("## Índice"))) {
+				t.Errorf("pdf rendered %d times with content:\n%s", p.renderCalls, export.Content)
+			}
+		})
+	}
+}
+
+func TestExportZipRoundTrip(t *testing.T) {
+	p := fullProject()
+	export, err := newProjectUsecase(p).Export(context.Background(), p.idea.ID, domain.ExportZip)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(export.Content), int64(len(export.Content)))
+	if err != nil {
+		t.Fatalf("reading zip: %v", err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	want := []string{
+		"gestor-de-tareas-version-2/action_plan.md",
+		"gestor-de-tareas-version-2/architecture.md",
+		"gestor-de-tareas-version-2/chats/action_plan.json",
+		"gestor-de-tareas-version-2/chats/action_plan.md",
+		"gestor-de-tareas-version-2/chats/architecture.json",
+		"gestor-de-tareas-version-2/chats/architecture.md",
+		"gestor-de-tareas-version-2/chats/global.json",
+		"gestor-de-tareas-version-2/chats/global.md",
+		"gestor-de-tareas-version-2/chats/ideation.json",
+		"gestor-de-tareas-version-2/chats/ideation.md",
+		"gestor-de-tareas-version-2/idea.md",
+		"gestor-de-tareas-version-2/modules.md",
+		"gestor-de-tareas-version-2/project.json",
+		"gestor-de-tareas-version-2/specification.md",
+	}
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("zip entries:\n%s\nwant:\n%s", strings.Join(names, "\n"), strings.Join(want, "\n"))
+	}
+
+	// The bundle is what the importer reads back
+	doc, err := bundleDocument(export.Content)
+	if err != nil {
+		t.Fatalf("bundleDocument: %v", err)
+	}
+	var back domain.Project
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&back); err != nil {
+		t.Fatalf("decoding project.json: %v", err)
+	}
+	if err := validateBundle(&back); err != nil {
+		t.Fatalf("exported bundle does not validate: %v", err)
+	}
+	if back.Idea.Title != p.idea.Title || back.ActionPlan.FunctionalRequirements != p.plan.FunctionalRequirements ||
+		back.Architecture.DatabaseSchema != p.arch.DatabaseSchema || len(back.Modules) != 2 {
+		t.Errorf("round trip lost data: %+v", back)
+	}
+	if back.Modules[1].Dependencies != `["api"]` {
+		t.Errorf("dependencies after round trip = %s", back.Modules[1].Dependencies)
+	}
+	if back.Chats == nil || len(back.Chats.Ideation) != 1 || len(back.Chats.Global) != 1 || back.Chats.Architecture[0].Content != "¿Go o Node?" {
+		t.Errorf("chats after round trip = %+v", back.Chats)
+	}
+}
+
+var indexLinkRe = regexp.MustCompile(`(?m)^- \[([^\]]+)\]\(#([^)]+)\)$`)
+
+func TestRenderSpecificationMarkdown(t *testing.T) {
+	generated := time.Date(2025, 12, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("index anchors match the chapters", func(t *testing.T) {
+		spec := RenderSpecificationMarkdown(&domain.Project{Idea: fullProject().idea}, generated)
+		links := indexLinkRe.FindAllStringSubmatch(spec, -1)
+		if len(links) != len(specChapters) {
+			t.Fatalf("index has %d entries, want %d", len(links), len(specChapters))
+		}
+		for _, l := range links {
+			if !strings.Contains(spec, "\n## "+l[1]+"\n") || headingAnchor(l[1]) != l[2] {
+				t.Errorf("index entry %q -> #%s has no matching heading", l[1], l[2])
+			}
+		}
+		if !strings.Contains(spec, "el 2025-12-01 09:30") {
+			t.Error("generation date missing")
+		}
+	})
+
+	t.Run("missing stages", func(t *testing.T) {
+		spec := RenderSpecificationMarkdown(&domain.Project{Idea: &ideadomain.Idea{Title: "Solo idea"}}, generated)
+		if got := strings.Count(spec, stageMissing); got != 6 {
+			t.Errorf("%d chapters marked as not generated, want 6", got)
+		}
+		if !strings.Contains(spec, "_Sin módulos._") || strings.Count(spec, "_Sin contenido._") != 3 {
+			t.Errorf("empty idea fields and modules should be marked:\n%s", spec)
+		}
+	})
+
+	t.Run("modules and nested headings", func(t *testing.T) {
+		p := fullProject()
+		spec := RenderSpecificationMarkdown(&domain.Project{Idea: p.idea, ActionPlan: p.plan, Architecture: p.arch, Modules: p.modules}, generated)
+		for _, want := range []string{
+			"| 1 | api | pending | 1 | — |",
+			`| 2 | web \| ui | pending | 2 | api |`,
+			"### 8.2 web | ui\n\nDepende de: api",
+			// Headings inside generated content stay below their chapter
+			"### Funcionales\n\n#### Login",
+			"## 7. Arquitectura del sistema\n\n### API",
+		} {
+			if !strings.Contains(spec, want) {
+				t.Errorf("specification does not contain %q", want)
+			}
+		}
+	})
+}
+
+func TestDemoteHeadings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		level   int
+		want    string
+	}{
+		{name: "no headings", content: "text", level: 2, want: "text"},
+		{name: "already below", content: "#### Deep\ntext", level: 3, want: "#### Deep\ntext"},
+		{name: "top heading moves under the level", content: "# A\n## B", level: 2, want: "### A\n#### B"},
+		{name: "capped at six", content: "# A\n###### F", level: 3, want: "#### A\n###### F"},
+		{name: "fenced code untouched", content: "```\n# comment\n```\n# A", level: 2, want: "```\n# comment\n```\n### A"},
+		{name: "hashtags are not headings", content: "#tag\n# A", level: 1, want: "#tag\n## A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := demoteHeadings(tt.content, tt.level); got != tt.want {
+				t.Errorf("demoteHeadings(%q, %d) = %q, want %q", tt.content, tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportName(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Idea Forge", "idea-forge"},
+		{"  ¿Añadir Módulos?  ", "anadir-modulos"},
+		{"API v2 / REST", "api-v2-rest"},
+		{"日本語", id.String()},
+		{"", id.String()},
+		{strings.Repeat("a", 100), strings.Repeat("a", 60)},
+	}
+	for _, tt := range tests {
+		if got := exportName(&domain.Project{Idea: &ideadomain.Idea{ID: id, Title: tt.title}}); got != tt.want {
+			t.Errorf("exportName(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/events"
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+)
+
+// maxBundleDocument bounds the size of the project.json read from a zip bundle
+const maxBundleDocument = 32 << 20
+
+var (
+	stageStatuses  = []string{"draft", "in_progress", "completed"}
+	moduleStatuses = []string{"pending", "in_progress", "completed"}
+	chatRoles      = []string{"user", "assistant", "system"}
+)
+
+// ImportUsecase recreates projects from the bundles produced by Export
+type ImportUsecase struct {
+	repo port.ImportRepository
+}
+
+// NewImportUsecase creates a new import use case
+func NewImportUsecase(repo port.ImportRepository) *ImportUsecase {
+	return &ImportUsecase{repo: repo}
+}
+
+// Import reads a project.json document, or a zip bundle containing one, and
+// recreates the project with new IDs, owned by userID in workspaceID
+// (uuid.Nil for none). Module dependencies keep pointing at the same modules.
+func (uc *ImportUsecase) Import(ctx context.Context, userID, workspaceID uuid.UUID, data []byte) (*domain.Project, error) {
+	doc, err := bundleDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var p domain.Project
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidBundle, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%w: unexpected data after the project", domain.ErrInvalidBundle)
+	}
+	if err := validateBundle(&p); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidBundle, err)
+	}
+
+	assignNewIDs(&p, userID, workspaceID, time.Now().UTC())
+
+	evt := events.ProjectImported{IdeaID: p.Idea.ID, Title: p.Idea.Title, Modules: len(p.Modules)}
+	if err := uc.repo.ImportProject(ctx, &p, evt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// bundleDocument returns the project document: data itself, or the
+// project.json closest to the root of a zip bundle
+func bundleDocument(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return data, nil
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidBundle, err)
+	}
+
+	var found *zip.File
+	depth := 0
+	for _, f := range zr.File {
+		if path.Base(f.Name) != "project.json" {
+			continue
+		}
+		d := strings.Count(f.Name, "/")
+		switch {
+		case found == nil || d < depth:
+			found, depth = f, d
+		case d == depth:
+			return nil, fmt.Errorf("%w: the zip contains more than one project", domain.ErrInvalidBundle)
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: project.json not found in the zip", domain.ErrInvalidBundle)
+	}
+
+	rc, err := found.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidBundle, err)
+	}
+	defer rc.Close()
+	doc, err := io.ReadAll(io.LimitReader(rc, maxBundleDocument+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidBundle, err)
+	}
+	if len(doc) > maxBundleDocument {
+		return nil, fmt.Errorf("%w: project.json is too large", domain.ErrInvalidBundle)
+	}
+	return doc, nil
+}
+
+// validateBundle checks the project is complete and consistent, filling in
+// default statuses and rewriting module dependencies as module names
+func validateBundle(p *domain.Project) error {
+	idea := p.Idea
+	if idea == nil {
+		return fmt.Errorf("idea is required")
+	}
+	required := []struct{ field, value string }{
+		{"title", idea.Title}, {"objective", idea.Objective}, {"problem", idea.Problem}, {"scope", idea.Scope},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			return fmt.Errorf("idea.%s is required", r.field)
+		}
+	}
+
+	if plan := p.ActionPlan; plan != nil {
+		status, err := checkStatus("action_plan.status", plan.Status, stageStatuses)
+		if err != nil {
+			return err
+		}
+		plan.Status = status
+	}
+
+	if arch := p.Architecture; arch != nil {
+		if p.ActionPlan == nil {
+			return fmt.Errorf("architecture requires an action_plan")
+		}
+		status, err := checkStatus("architecture.status", arch.Status, stageStatuses)
+		if err != nil {
+			return err
+		}
+		arch.Status = status
+	}
+
+	if len(p.Modules) > 0 && p.Architecture == nil {
+		return fmt.Errorf("modules require an architecture")
+	}
+	if err := validateModules(p); err != nil {
+		return err
+	}
+
+	if p.Chats != nil {
+		if len(p.Chats.ActionPlan) > 0 && p.ActionPlan == nil {
+			return fmt.Errorf("chats.action_plan requires an action_plan")
+		}
+		if len(p.Chats.Architecture) > 0 && p.Architecture == nil {
+			return fmt.Errorf("chats.architecture requires an architecture")
+		}
+		histories := []struct {
+			name     string
+			messages []domain.ChatMessage
+		}{
+			{"ideation", p.Chats.Ideation},
+			{"action_plan", p.Chats.ActionPlan},
+			{"architecture", p.Chats.Architecture},
+			{"global", p.Chats.Global},
+		}
+		for _, h := range histories {
+			if len(h.messages) > maxChatMessages {
+				return fmt.Errorf("chats.%s has more than %d messages", h.name, maxChatMessages)
+			}
+			for i, m := range h.messages {
+				if !slices.Contains(chatRoles, m.Role) {
+					return fmt.Errorf("chats.%s[%d].role must be one of %s", h.name, i, strings.Join(chatRoles, ", "))
+				}
+				if strings.TrimSpace(m.Content) == "" {
+					return fmt.Errorf("chats.%s[%d].content is required", h.name, i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateModules checks module names are unique. Dependencies are module
+// names; the ones given as IDs of modules in the bundle are rewritten as
+// names, since IDs change on import.
+func validateModules(p *domain.Project) error {
+	names := make(map[string]bool, len(p.Modules))
+	byID := make(map[string]string, len(p.Modules))
+	for i := range p.Modules {
+		m := &p.Modules[i]
+		m.Name = strings.TrimSpace(m.Name)
+		if m.Name == "" {
+			return fmt.Errorf("modules[%d].name is required", i)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("module %q appears more than once", m.Name)
+		}
+		names[m.Name] = true
+		if m.ID != uuid.Nil {
+			byID[m.ID.String()] = m.Name
+		}
+
+		status, err := checkStatus(fmt.Sprintf("modules[%d].status", i), m.Status, moduleStatuses)
+		if err != nil {
+			return err
+		}
+		m.Status = status
+	}
+
+	for i := range p.Modules {
+		m := &p.Modules[i]
+		deps := []string{}
+		for _, dep := range ParseDependencies(m.Dependencies) {
+			if name, ok := byID[dep]; ok {
+				dep = name
+			}
+			deps = append(deps, dep)
+		}
+		raw, err := json.Marshal(deps)
+		if err != nil {
+			return err
+		}
+		m.Dependencies = string(raw)
+	}
+	return nil
+}
+
+func checkStatus(field, status string, allowed []string) (string, error) {
+	if status == "" {
+		return allowed[0], nil
+	}
+	if !slices.Contains(allowed, status) {
+		return "", fmt.Errorf("%s must be one of %s", field, strings.Join(allowed, ", "))
+	}
+	return status, nil
+}
+
+// assignNewIDs gives the project fresh IDs and ownership. Stages count as
+// created now; chat messages keep their timestamps so the history reads the same.
+func assignNewIDs(p *domain.Project, userID, workspaceID uuid.UUID, now time.Time) {
+	idea := p.Idea
+	idea.ID = uuid.New()
+	idea.UserID, idea.WorkspaceID = nil, nil
+	if userID != uuid.Nil {
+		idea.UserID = &userID
+	}
+	if workspaceID != uuid.Nil {
+		idea.WorkspaceID = &workspaceID
+	}
+	idea.CreatedAt = now
+
+	if plan := p.ActionPlan; plan != nil {
+		plan.ID, plan.IdeaID = uuid.New(), idea.ID
+		plan.CreatedAt, plan.UpdatedAt = now, now
+	}
+	if arch := p.Architecture; arch != nil {
+		arch.ID, arch.ActionPlanID = uuid.New(), p.ActionPlan.ID
+		arch.CreatedAt, arch.UpdatedAt = now, now
+	}
+	for i := range p.Modules {
+		m := &p.Modules[i]
+		m.ID, m.ArchitectureID = uuid.New(), p.Architecture.ID
+		m.CreatedAt, m.UpdatedAt = now, now
+	}
+
+	if p.Chats != nil {
+		for _, messages := range [][]domain.ChatMessage{p.Chats.Ideation, p.Chats.ActionPlan, p.Chats.Architecture, p.Chats.Global} {
+			for i := range messages {
+				if messages[i].CreatedAt.IsZero() {
+					messages[i].CreatedAt = now
+				}
+			}
+		}
+	}
+}
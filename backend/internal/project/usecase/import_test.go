@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	actionplandomain "github.com/dark/idea-forge/internal/actionplan/domain"
+	archdomain "github.com/dark/idea-forge/internal/architecture/domain"
+	devmoduledomain "github.com/dark/idea-forge/internal/devmodule/domain"
+	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+	"github.com/dark/idea-forge/internal/project/domain"
+)
+
+// validProject returns a complete bundle; tests break one thing at a time
+func validProject() *domain.Project {
+	return &domain.Project{
+		Idea:         &ideadomain.Idea{Title: "Idea Forge", Objective: "o", Problem: "p", Scope: "s"},
+		ActionPlan:   &actionplandomain.ActionPlan{},
+		Architecture: &archdomain.Architecture{Status: "completed"},
+		Modules: []devmoduledomain.DevelopmentModule{
+			{Name: "api"},
+			{Name: "web", Dependencies: `["api"]`},
+		},
+		Chats: &domain.Chats{
+			Ideation: []domain.ChatMessage{{Role: "user", Content: "hello"}},
+		},
+	}
+}
+
+func TestValidateBundle(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(p *domain.Project)
+		wantErr string // "" means valid
+	}{
+		{name: "complete project", modify: func(p *domain.Project) {}},
+		{name: "idea only", modify: func(p *domain.Project) {
+			p.ActionPlan, p.Architecture, p.Modules, p.Chats = nil, nil, nil, nil
+		}},
+		{name: "missing idea", modify: func(p *domain.Project) { p.Idea = nil }, wantErr: "idea is required"},
+		{name: "blank title", modify: func(p *domain.Project) { p.Idea.Title = "  " }, wantErr: "idea.title is required"},
+		{name: "missing scope", modify: func(p *domain.Project) { p.Idea.Scope = "" }, wantErr: "idea.scope is required"},
+		{name: "unknown plan status", modify: func(p *domain.Project) { p.ActionPlan.Status = "archived" }, wantErr: "action_plan.status must be one of"},
+		{name: "architecture without plan", modify: func(p *domain.Project) { p.ActionPlan = nil }, wantErr: "architecture requires an action_plan"},
+		{name: "unknown architecture status", modify: func(p *domain.Project) { p.Architecture.Status = "pending" }, wantErr: "architecture.status must be one of"},
+		{name: "modules without architecture", modify: func(p *domain.Project) { p.Architecture = nil }, wantErr: "modules require an architecture"},
+		{name: "unnamed module", modify: func(p *domain.Project) { p.Modules[1].Name = " " }, wantErr: "modules[1].name is required"},
+		{name: "duplicated module", modify: func(p *domain.Project) { p.Modules[1].Name = " api " }, wantErr: `module "api" appears more than once`},
+		{name: "unknown module status", modify: func(p *domain.Project) { p.Modules[0].Status = "draft" }, wantErr: "modules[0].status must be one of"},
+		{name: "plan chat without plan", modify: func(p *domain.Project) {
+			p.ActionPlan, p.Architecture, p.Modules = nil, nil, nil
+			p.Chats.ActionPlan = []domain.ChatMessage{{Role: "user", Content: "hi"}}
+		}, wantErr: "chats.action_plan requires an action_plan"},
+		{name: "architecture chat without architecture", modify: func(p *domain.Project) {
+			p.Architecture, p.Modules = nil, nil
+			p.Chats.Architecture = []domain.ChatMessage{{Role: "user", Content: "hi"}}
+		}, wantErr: "chats.architecture requires an architecture"},
+		{name: "unknown chat role", modify: func(p *domain.Project) { p.Chats.Ideation[0].Role = "admin" }, wantErr: "chats.ideation[0].role must be one of"},
+		{name: "empty chat message", modify: func(p *domain.Project) { p.Chats.Global = []domain.ChatMessage{{Role: "assistant", Content: " "}} }, wantErr: "chats.global[0].content is required"},
+		{name: "too many chat messages", modify: func(p *domain.Project) {
+			p.Chats.Ideation = make([]domain.ChatMessage, maxChatMessages+1)
+		}, wantErr: "chats.ideation has more than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := validProject()
+			tt.modify(p)
+			err := validateBundle(p)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateBundle = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateBundle = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBundleNormalizes(t *testing.T) {
+	p := validProject()
+	apiID := uuid.New()
+	p.Modules[0].ID = apiID
+	p.Modules[0].Name = " api "
+	p.Modules[1].Dependencies = `["` + apiID.String() + `", "external"]`
+
+	if err := validateBundle(p); err != nil {
+		t.Fatalf("validateBundle: %v", err)
+	}
+	if p.ActionPlan.Status != "draft" || p.Architecture.Status != "completed" {
+		t.Errorf("stage statuses = %q, %q; want draft, completed", p.ActionPlan.Status, p.Architecture.Status)
+	}
+	if p.Modules[0].Name != "api" || p.Modules[0].Status != "pending" {
+		t.Errorf("module = %q/%q, want api/pending", p.Modules[0].Name, p.Modules[0].Status)
+	}
+	// Dependencies given as module IDs become names, since IDs change on import
+	if got := p.Modules[1].Dependencies; got != `["api","external"]` {
+		t.Errorf("dependencies = %s, want [\"api\",\"external\"]", got)
+	}
+	if got := p.Modules[0].Dependencies; got != `[]` {
+		t.Errorf("dependencies without value = %s, want []", got)
+	}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	actionplandomain "github.com/dark/idea-forge/internal/actionplan/domain"
+	archdomain "github.com/dark/idea-forge/internal/architecture/domain"
+	devmoduledomain "github.com/dark/idea-forge/internal/devmodule/domain"
+	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+	"github.com/dark/idea-forge/internal/project/domain"
+)
+
+// RenderIdeaMarkdown renders the ideation stage
+func RenderIdeaMarkdown(idea *ideadomain.Idea) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", idea.Title)
+	writeSection(&b, "##", "Objetivo", idea.Objective)
+	writeSection(&b, "##", "Problema", idea.Problem)
+	writeSection(&b, "##", "Alcance", idea.Scope)
+	fmt.Fprintf(&b, "- Validar competencia: %s\n", yesNo(idea.ValidateCompetition))
+	fmt.Fprintf(&b, "- Validar monetización: %s\n", yesNo(idea.ValidateMonetization))
+	fmt.Fprintf(&b, "- Completada: %s\n", yesNo(idea.Completed))
+	fmt.Fprintf(&b, "- Creada: %s\n", idea.CreatedAt.Format("2006-01-02 15:04"))
+	return b.String()
+}
+
+// RenderActionPlanMarkdown renders the action plan stage
+func RenderActionPlanMarkdown(plan *actionplandomain.ActionPlan) string {
+	var b strings.Builder
+	b.WriteString("# Plan de acción\n\n")
+	writeSection(&b, "##", "Requerimientos funcionales", plan.FunctionalRequirements)
+	writeSection(&b, "##", "Requerimientos no funcionales", plan.NonFunctionalRequirements)
+	writeSection(&b, "##", "Flujo de lógica de negocio", plan.BusinessLogicFlow)
+	fmt.Fprintf(&b, "- Estado: %s\n", plan.Status)
+	fmt.Fprintf(&b, "- Completado: %s\n", yesNo(plan.Completed))
+	return b.String()
+}
+
+// RenderArchitectureMarkdown renders the architecture stage
+func RenderArchitectureMarkdown(arch *archdomain.Architecture) string {
+	var b strings.Builder
+	b.WriteString("# Arquitectura\n\n")
+	writeSection(&b, "##", "Historias de usuario", arch.UserStories)
+	writeSection(&b, "##", "Tipo de base de datos", arch.DatabaseType)
+	writeSection(&b, "##", "Esquema de base de datos", arch.DatabaseSchema)
+	writeSection(&b, "##", "Entidades y relaciones", arch.EntitiesRelationships)
+	writeSection(&b, "##", "Stack tecnológico", arch.TechStack)
+	writeSection(&b, "##", "Patrón de arquitectura", arch.ArchitecturePattern)
+	writeSection(&b, "##", "Arquitectura del sistema", arch.SystemArchitecture)
+	fmt.Fprintf(&b, "- Estado: %s\n", arch.Status)
+	fmt.Fprintf(&b, "- Completada: %s\n", yesNo(arch.Completed))
+	return b.String()
+}
+
+// RenderModulesMarkdown renders the development modules in priority order
+func RenderModulesMarkdown(modules []devmoduledomain.DevelopmentModule) string {
+	var b strings.Builder
+	b.WriteString("# Módulos de desarrollo\n\n")
+	if len(modules) == 0 {
+		b.WriteString("_Sin módulos._\n")
+		return b.String()
+	}
+	for i, m := range modules {
+		fmt.Fprintf(&b, "## %d. %s\n\n", i+1, m.Name)
+		fmt.Fprintf(&b, "- Estado: %s\n", m.Status)
+		fmt.Fprintf(&b, "- Prioridad: %d\n", m.Priority)
+		if deps := ParseDependencies(m.Dependencies); len(deps) > 0 {
+			fmt.Fprintf(&b, "- Depende de: %s\n", strings.Join(deps, ", "))
+		}
+		b.WriteString("\n")
+		writeSection(&b, "###", "Descripción", m.Description)
+		writeSection(&b, "###", "Funcionalidad", m.Functionality)
+		writeSection(&b, "###", "Detalles técnicos", m.TechnicalDetails)
+	}
+	return b.String()
+}
+
+// RenderTranscriptMarkdown renders a chat history
+func RenderTranscriptMarkdown(title string, messages []domain.ChatMessage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	for _, m := range messages {
+		fmt.Fprintf(&b, "**%s** · %s\n\n", roleLabel(m.Role), m.CreatedAt.Format("2006-01-02 15:04"))
+		b.WriteString(strings.TrimSpace(m.Content))
+		b.WriteString("\n\n---\n\n")
+	}
+	return b.String()
+}
+
+// ParseDependencies decodes the JSON array of module names stored in DevelopmentModule.Dependencies
+func ParseDependencies(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	var deps []string
+	if err := json.Unmarshal([]byte(raw), &deps); err != nil {
+		// Valor legado en texto plano separado por comas
+		for _, d := range strings.Split(raw, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				deps = append(deps, d)
+			}
+		}
+	}
+	return deps
+}
+
+func writeSection(b *strings.Builder, level, title, content string) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		content = "_Sin contenido._"
+	}
+	fmt.Fprintf(b, "%s %s\n\n%s\n\n", level, title, content)
+}
+
+func roleLabel(role string) string {
+	switch role {
+	case "user":
+		return "Usuario"
+	case "assistant":
+		return "Asistente"
+	case "system":
+		return "Sistema"
+	default:
+		return role
+	}
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "sí"
+	}
+	return "no"
+}
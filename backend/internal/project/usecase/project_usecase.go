@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+)
+
+// maxChatMessages bounds how many messages are read per chat history
+const maxChatMessages = 10000
+
+// maxUserProjects bounds how many projects are read for a single user
+const maxUserProjects = 1000
+
+// ProjectUsecase assembles full projects from the stage modules
+type ProjectUsecase struct {
+	ideas         port.IdeaSource
+	actionPlans   port.ActionPlanSource
+	architectures port.ArchitectureSource
+	modules       port.DevModuleSource
+	documents     port.DocumentRenderer
+}
+
+// NewProjectUsecase creates a new project use case
+func NewProjectUsecase(ideas port.IdeaSource, actionPlans port.ActionPlanSource, architectures port.ArchitectureSource, modules port.DevModuleSource, documents port.DocumentRenderer) *ProjectUsecase {
+	return &ProjectUsecase{
+		ideas:         ideas,
+		actionPlans:   actionPlans,
+		architectures: architectures,
+		modules:       modules,
+		documents:     documents,
+	}
+}
+
+// GetProject loads an idea and every stage derived from it
+func (uc *ProjectUsecase) GetProject(ctx context.Context, ideaID uuid.UUID, withChats bool) (*domain.Project, error) {
+	idea, err := uc.ideas.FindByID(ctx, ideaID)
+	if err != nil {
+		return nil, err
+	}
+	project := &domain.Project{Idea: idea}
+
+	plan, err := uc.actionPlans.GetActionPlanByIdeaID(ctx, idea.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("loading action plan: %w", err)
+	}
+	project.ActionPlan = plan
+
+	if plan != nil {
+		arch, err := uc.architectures.GetArchitectureByActionPlanID(ctx, plan.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("loading architecture: %w", err)
+		}
+		project.Architecture = arch
+	}
+
+	if project.Architecture != nil {
+		modules, err := uc.modules.GetModulesByArchitectureID(ctx, project.Architecture.ID)
+		if err != nil {
+			return nil, fmt.Errorf("loading modules: %w", err)
+		}
+		project.Modules = modules
+	}
+
+	if withChats {
+		chats, err := uc.loadChats(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		project.Chats = chats
+	}
+
+	return project, nil
+}
+
+// ListUserProjects loads every project owned by a user
+func (uc *ProjectUsecase) ListUserProjects(ctx context.Context, userID uuid.UUID, withChats bool) ([]domain.Project, error) {
+	ideas, err := uc.ideas.FindByUserID(ctx, userID, maxUserProjects)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]domain.Project, 0, len(ideas))
+	for _, idea := range ideas {
+		project, err := uc.GetProject(ctx, idea.ID, withChats)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *project)
+	}
+	return projects, nil
+}
+
+// UserArchiveFiles renders every project of a user as JSON plus Markdown files
+func (uc *ProjectUsecase) UserArchiveFiles(ctx context.Context, userID uuid.UUID) ([]domain.File, error) {
+	projects, err := uc.ListUserProjects(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []domain.File
+	for i := range projects {
+		projectFiles, err := ProjectFiles(&projects[i], fmt.Sprintf("projects/%s/", projects[i].Idea.ID))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, projectFiles...)
+	}
+	return files, nil
+}
+
+// ProjectFiles renders one project as JSON documents and Markdown renderings under prefix
+func ProjectFiles(p *domain.Project, prefix string) ([]domain.File, error) {
+	var files []domain.File
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		files = append(files, domain.File{Path: prefix + name, Content: data})
+		return nil
+	}
+	addMarkdown := func(name, content string) {
+		files = append(files, domain.File{Path: prefix + name, Content: []byte(content)})
+	}
+
+	if err := addJSON("project.json", p); err != nil {
+		return nil, err
+	}
+
+	addMarkdown("idea.md", RenderIdeaMarkdown(p.Idea))
+	if p.ActionPlan != nil {
+		addMarkdown("action_plan.md", RenderActionPlanMarkdown(p.ActionPlan))
+	}
+	if p.Architecture != nil {
+		addMarkdown("architecture.md", RenderArchitectureMarkdown(p.Architecture))
+		addMarkdown("modules.md", RenderModulesMarkdown(p.Modules))
+	}
+
+	if p.Chats != nil {
+		transcripts := []struct {
+			name     string
+			title    string
+			messages []domain.ChatMessage
+		}{
+			{"ideation", "Chat de ideación", p.Chats.Ideation},
+			{"action_plan", "Chat del plan de acción", p.Chats.ActionPlan},
+			{"architecture", "Chat de arquitectura", p.Chats.Architecture},
+			{"global", "Chat global", p.Chats.Global},
+		}
+		for _, t := range transcripts {
+			if len(t.messages) == 0 {
+				continue
+			}
+			if err := addJSON("chats/"+t.name+".json", t.messages); err != nil {
+				return nil, err
+			}
+			addMarkdown("chats/"+t.name+".md", RenderTranscriptMarkdown(t.title, t.messages))
+		}
+	}
+
+	return files, nil
+}
+
+func (uc *ProjectUsecase) loadChats(ctx context.Context, p *domain.Project) (*domain.Chats, error) {
+	chats := &domain.Chats{}
+
+	ideaMsgs, err := uc.ideas.ListMessages(ctx, p.Idea.ID, maxChatMessages)
+	if err != nil {
+		return nil, fmt.Errorf("loading ideation chat: %w", err)
+	}
+	for _, m := range ideaMsgs {
+		chats.Ideation = append(chats.Ideation, domain.ChatMessage{Role: m.Role, Content: m.Content, CreatedAt: m.CreatedAt})
+	}
+
+	if p.ActionPlan != nil {
+		planMsgs, err := uc.actionPlans.GetMessages(ctx, p.ActionPlan.ID, maxChatMessages)
+		if err != nil {
+			return nil, fmt.Errorf("loading action plan chat: %w", err)
+		}
+		for _, m := range planMsgs {
+			chats.ActionPlan = append(chats.ActionPlan, domain.ChatMessage{Role: m.Role, Content: m.Content, CreatedAt: m.CreatedAt})
+		}
+	}
+
+	if p.Architecture != nil {
+		archMsgs, err := uc.architectures.GetMessages(ctx, p.Architecture.ID, maxChatMessages)
+		if err != nil {
+			return nil, fmt.Errorf("loading architecture chat: %w", err)
+		}
+		for _, m := range archMsgs {
+			chats.Architecture = append(chats.Architecture, domain.ChatMessage{Role: m.Role, Content: m.Content, CreatedAt: m.CreatedAt})
+		}
+	}
+
+	globalMsgs, err := uc.modules.GetGlobalMessages(ctx, p.Idea.ID, maxChatMessages)
+	if err != nil {
+		return nil, fmt.Errorf("loading global chat: %w", err)
+	}
+	for _, m := range globalMsgs {
+		chats.Global = append(chats.Global, domain.ChatMessage{Role: m.Role, Content: m.Content, AffectedModules: m.AffectedModules, CreatedAt: m.CreatedAt})
+	}
+
+	return chats, nil
+}
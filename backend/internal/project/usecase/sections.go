@@ -0,0 +1,25 @@
+package usecase
+
+import "github.com/dark/idea-forge/internal/project/domain"
+
+// Sections returns the editable text sections of a project keyed by "stage.section"
+// (e.g. "architecture.database_schema"). Stages that do not exist yet are omitted.
+func Sections(p *domain.Project) map[string]string {
+	sections := map[string]string{
+		"idea.title":     p.Idea.Title,
+		"idea.objective": p.Idea.Objective,
+		"idea.problem":   p.Idea.Problem,
+		"idea.scope":     p.Idea.Scope,
+	}
+	if p.ActionPlan != nil {
+		for name, content := range p.ActionPlan.Sections() {
+			sections["action_plan."+name] = content
+		}
+	}
+	if p.Architecture != nil {
+		for name, content := range p.Architecture.Sections() {
+			sections["architecture."+name] = content
+		}
+	}
+	return sections
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+)
+
+// Password attempts allowed on protected links within PasswordAttemptWindow:
+// per link, against guessing one link from many addresses, and per client IP,
+// against one client guessing many links
+const (
+	PasswordAttemptWindow = 15 * time.Minute
+	maxFailuresPerLink    = 10
+	maxFailuresPerIP      = 20
+)
+
+// ShareLinkUsecase creates and opens public read-only links to a project.
+// Tokens are the link ID signed with HMAC-SHA256, so forged tokens are
+// rejected without a database lookup; revocation and expiry live in the database.
+type ShareLinkUsecase struct {
+	repo     port.ShareLinkRepository
+	projects *ProjectUsecase
+	secret   []byte
+	apiURL   string
+}
+
+// NewShareLinkUsecase creates a new share link use case
+func NewShareLinkUsecase(repo port.ShareLinkRepository, projects *ProjectUsecase, secret, apiURL string) *ShareLinkUsecase {
+	return &ShareLinkUsecase{repo: repo, projects: projects, secret: []byte(secret), apiURL: apiURL}
+}
+
+// CreateShareLinkInput configures a new share link
+type CreateShareLinkInput struct {
+	ExpiresAt    *time.Time `json:"expires_at"`
+	Password     string     `json:"password"`
+	IncludeChats bool       `json:"include_chats"`
+}
+
+// Create creates a share link for the idea. The URL is only returned here.
+func (uc *ShareLinkUsecase) Create(ctx context.Context, actorID, ideaID uuid.UUID, in CreateShareLinkInput) (*domain.ShareLink, error) {
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return nil, domain.ErrInvalidExpiry
+	}
+
+	link := &domain.ShareLink{
+		ID:           uuid.New(),
+		IdeaID:       ideaID,
+		CreatedBy:    actorID,
+		IncludeChats: in.IncludeChats,
+		ExpiresAt:    in.ExpiresAt,
+	}
+	if in.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		link.PasswordHash = string(hash)
+		link.HasPassword = true
+	}
+
+	if err := uc.repo.CreateShareLink(ctx, link); err != nil {
+		return nil, err
+	}
+	link.URL = uc.apiURL + "/share/" + uc.sign(link.ID)
+	return link, nil
+}
+
+// List returns the share links of an idea (without their URLs)
+func (uc *ShareLinkUsecase) List(ctx context.Context, ideaID uuid.UUID) ([]domain.ShareLink, error) {
+	return uc.repo.ListShareLinks(ctx, ideaID)
+}
+
+// Revoke disables a share link immediately
+func (uc *ShareLinkUsecase) Revoke(ctx context.Context, ideaID, linkID uuid.UUID) error {
+	return uc.repo.RevokeShareLink(ctx, ideaID, linkID)
+}
+
+// Open validates the token (and password) and returns the read-only project.
+// clientIP identifies the caller for the password attempt limits.
+func (uc *ShareLinkUsecase) Open(ctx context.Context, token, password, clientIP string) (*domain.Project, error) {
+	linkID, ok := uc.verify(token)
+	if !ok {
+		return nil, domain.ErrShareLinkNotFound
+	}
+
+	link, err := uc.repo.GetShareLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil {
+		return nil, domain.ErrShareLinkNotFound
+	}
+	if !link.IsActive() {
+		return nil, domain.ErrShareLinkExpired
+	}
+
+	if link.HasPassword {
+		if password == "" {
+			return nil, domain.ErrSharePasswordRequired
+		}
+		if err := uc.checkPassword(ctx, link, password, clientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	project, err := uc.projects.GetProject(ctx, link.IdeaID, link.IncludeChats)
+	if err != nil {
+		return nil, err
+	}
+	// No exponer a quién pertenece el proyecto
+	idea := *project.Idea
+	idea.UserID, idea.WorkspaceID = nil, nil
+	project.Idea = &idea
+	return project, nil
+}
+
+// checkPassword compares the password unless the link or the client ran out of
+// attempts; failures are recorded so every instance sees them
+func (uc *ShareLinkUsecase) checkPassword(ctx context.Context, link *domain.ShareLink, password, clientIP string) error {
+	since := time.Now().Add(-PasswordAttemptWindow)
+	byLink, byIP, err := uc.repo.CountPasswordFailures(ctx, link.ID, clientIP, since)
+	if err != nil {
+		return err
+	}
+	if byLink >= maxFailuresPerLink || byIP >= maxFailuresPerIP {
+		return domain.ErrTooManyShareAttempts
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)); err != nil {
+		if err := uc.repo.RecordPasswordFailure(ctx, link.ID, clientIP, since); err != nil {
+			return err
+		}
+		return domain.ErrInvalidSharePassword
+	}
+	return nil
+}
+
+func (uc *ShareLinkUsecase) sign(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:]) + "." + base64.RawURLEncoding.EncodeToString(uc.mac(id))
+}
+
+func (uc *ShareLinkUsecase) verify(token string) (uuid.UUID, bool) {
+	rawID, rawSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, false
+	}
+	idBytes, err := base64.RawURLEncoding.DecodeString(rawID)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	id, err := uuid.FromBytes(idBytes)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(rawSig)
+	if err != nil || !hmac.Equal(sig, uc.mac(id)) {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (uc *ShareLinkUsecase) mac(id uuid.UUID) []byte {
+	m := hmac.New(sha256.New, uc.secret)
+	m.Write([]byte("share-link:"))
+	m.Write(id[:])
+	return m.Sum(nil)
+}
@@ -0,0 +1,294 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	actionplandomain "github.com/dark/idea-forge/internal/actionplan/domain"
+	ideadomain "github.com/dark/idea-forge/internal/ideation/domain"
+	"github.com/dark/idea-forge/internal/project/domain"
+	"github.com/dark/idea-forge/internal/project/port"
+)
+
+type memoryShareLinks struct {
+	links    map[uuid.UUID]*domain.ShareLink
+	failures []passwordFailure
+}
+
+type passwordFailure struct {
+	linkID uuid.UUID
+	ip     string
+	at     time.Time
+}
+
+func (r *memoryShareLinks) CreateShareLink(ctx context.Context, link *domain.ShareLink) error {
+	stored := *link
+	r.links[link.ID] = &stored
+	return nil
+}
+
+func (r *memoryShareLinks) GetShareLink(ctx context.Context, id uuid.UUID) (*domain.ShareLink, error) {
+	link, ok := r.links[id]
+	if !ok {
+		return nil, domain.ErrShareLinkNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (r *memoryShareLinks) ListShareLinks(ctx context.Context, ideaID uuid.UUID) ([]domain.ShareLink, error) {
+	return nil, nil
+}
+
+func (r *memoryShareLinks) RevokeShareLink(ctx context.Context, ideaID, id uuid.UUID) error {
+	now := time.Now()
+	r.links[id].RevokedAt = &now
+	return nil
+}
+
+func (r *memoryShareLinks) CountPasswordFailures(ctx context.Context, linkID uuid.UUID, ip string, since time.Time) (int, int, error) {
+	var byLink, byIP int
+	for _, f := range r.failures {
+		if !f.at.After(since) {
+			continue
+		}
+		if f.linkID == linkID {
+			byLink++
+		}
+		if f.ip == ip {
+			byIP++
+		}
+	}
+	return byLink, byIP, nil
+}
+
+func (r *memoryShareLinks) RecordPasswordFailure(ctx context.Context, linkID uuid.UUID, ip string, forgetBefore time.Time) error {
+	r.failures = append(r.failures, passwordFailure{linkID: linkID, ip: ip, at: time.Now()})
+	return nil
+}
+
+func newMemoryShareLinks() *memoryShareLinks {
+	return &memoryShareLinks{links: map[uuid.UUID]*domain.ShareLink{}}
+}
+
+// singleIdea serves one idea without later stages
+type singleIdea struct {
+	port.IdeaSource
+	idea *ideadomain.Idea
+}
+
+func (s singleIdea) FindByID(ctx context.Context, id uuid.UUID) (*ideadomain.Idea, error) {
+	if id != s.idea.ID {
+		return nil, sql.ErrNoRows
+	}
+	copied := *s.idea
+	return &copied, nil
+}
+
+type noActionPlan struct{ port.ActionPlanSource }
+
+func (noActionPlan) GetActionPlanByIdeaID(ctx context.Context, ideaID uuid.UUID) (*actionplandomain.ActionPlan, error) {
+	return nil, sql.ErrNoRows
+}
+
+func newShareLinkUsecase(secret string, repo *memoryShareLinks, idea *ideadomain.Idea) *ShareLinkUsecase {
+	projects := NewProjectUsecase(singleIdea{idea: idea}, noActionPlan{}, nil, nil, nil)
+	return NewShareLinkUsecase(repo, projects, secret, "https://api.example.com")
+}
+
+func tokenOf(t *testing.T, link *domain.ShareLink) string {
+	t.Helper()
+	token, ok := strings.CutPrefix(link.URL, "https://api.example.com/share/")
+	if !ok {
+		t.Fatalf("unexpected share URL %q", link.URL)
+	}
+	return token
+}
+
+func TestShareLinkTokenVerification(t *testing.T) {
+	uc := NewShareLinkUsecase(nil, nil, "secret", "")
+	other := NewShareLinkUsecase(nil, nil, "another-secret", "")
+	id := uuid.New()
+	token := uc.sign(id)
+	rawID, rawSig, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{name: "valid", token: token, ok: true},
+		{name: "signed with another secret", token: other.sign(id)},
+		{name: "signature of another link", token: rawID + "." + strings.SplitN(uc.sign(uuid.New()), ".", 2)[1]},
+		{name: "truncated signature", token: rawID + "." + rawSig[:len(rawSig)-2]},
+		{name: "no signature", token: rawID},
+		{name: "empty signature", token: rawID + "."},
+		{name: "id is not base64url", token: "!!!." + rawSig},
+		{name: "id is not a uuid", token: "YWJj." + rawSig},
+		{name: "empty", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := uc.verify(tt.token)
+			if ok != tt.ok {
+				t.Fatalf("verify ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && got != id {
+				t.Fatalf("verify id = %s, want %s", got, id)
+			}
+		})
+	}
+}
+
+func TestOpenShareLink(t *testing.T) {
+	owner, workspace := uuid.New(), uuid.New()
+	idea := &ideadomain.Idea{ID: uuid.New(), Title: "Shared idea", UserID: &owner, WorkspaceID: &workspace}
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		input    CreateShareLinkInput
+		revoke   bool
+		expire   bool
+		password string
+		forge    bool
+		wantErr  error
+	}{
+		{name: "open link"},
+		{name: "password required", input: CreateShareLinkInput{Password: "hunter2"}, wantErr: domain.ErrSharePasswordRequired},
+		{name: "wrong password", input: CreateShareLinkInput{Password: "hunter2"}, password: "hunter3", wantErr: domain.ErrInvalidSharePassword},
+		{name: "right password", input: CreateShareLinkInput{Password: "hunter2"}, password: "hunter2"},
+		{name: "revoked", revoke: true, wantErr: domain.ErrShareLinkNotFound},
+		{name: "expired", expire: true, wantErr: domain.ErrShareLinkExpired},
+		{name: "forged token", forge: true, wantErr: domain.ErrShareLinkNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryShareLinks()
+			uc := newShareLinkUsecase("secret", repo, idea)
+
+			link, err := uc.Create(ctx, owner, idea.ID, tt.input)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			token := tokenOf(t, link)
+			if tt.revoke {
+				uc.Revoke(ctx, idea.ID, link.ID)
+			}
+			if tt.expire {
+				repo.links[link.ID].ExpiresAt = &past
+			}
+			if tt.forge {
+				token = newShareLinkUsecase("guessed", repo, idea).sign(link.ID)
+			}
+
+			project, err := uc.Open(ctx, token, tt.password, "203.0.113.7")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Open err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if project.Idea.Title != idea.Title {
+				t.Errorf("title = %q, want %q", project.Idea.Title, idea.Title)
+			}
+			if project.Idea.UserID != nil || project.Idea.WorkspaceID != nil {
+				t.Error("shared project exposes its owner")
+			}
+		})
+	}
+}
+
+func TestCreateShareLinkRejectsPastExpiry(t *testing.T) {
+	repo := newMemoryShareLinks()
+	uc := NewShareLinkUsecase(repo, nil, "secret", "")
+	past := time.Now().Add(-time.Minute)
+
+	if _, err := uc.Create(context.Background(), uuid.New(), uuid.New(), CreateShareLinkInput{ExpiresAt: &past}); !errors.Is(err, domain.ErrInvalidExpiry) {
+		t.Fatalf("Create err = %v, want ErrInvalidExpiry", err)
+	}
+}
+
+func TestOpenShareLinkLimitsPasswordAttempts(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+	idea := &ideadomain.Idea{ID: uuid.New(), Title: "Shared idea"}
+
+	t.Run("per link", func(t *testing.T) {
+		uc := newShareLinkUsecase("secret", newMemoryShareLinks(), idea)
+		link, err := uc.Create(ctx, owner, idea.ID, CreateShareLinkInput{Password: "hunter2"})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		token := tokenOf(t, link)
+
+		// Guesses spread over many addresses still exhaust the link
+		for i := 0; i < maxFailuresPerLink; i++ {
+			ip := fmt.Sprintf("198.51.100.%d", i)
+			if _, err := uc.Open(ctx, token, "guess", ip); !errors.Is(err, domain.ErrInvalidSharePassword) {
+				t.Fatalf("attempt %d: err = %v, want ErrInvalidSharePassword", i, err)
+			}
+		}
+		if _, err := uc.Open(ctx, token, "hunter2", "203.0.113.7"); !errors.Is(err, domain.ErrTooManyShareAttempts) {
+			t.Fatalf("right password after the limit: err = %v, want ErrTooManyShareAttempts", err)
+		}
+		// Asking for the password page does not count and is not blocked
+		if _, err := uc.Open(ctx, token, "", "203.0.113.7"); !errors.Is(err, domain.ErrSharePasswordRequired) {
+			t.Fatalf("no password: err = %v, want ErrSharePasswordRequired", err)
+		}
+	})
+
+	t.Run("per ip", func(t *testing.T) {
+		repo := newMemoryShareLinks()
+		uc := newShareLinkUsecase("secret", repo, idea)
+
+		// One client guessing across many links exhausts its address
+		var tokens []string
+		for i := 0; i <= maxFailuresPerIP/maxFailuresPerLink; i++ {
+			link, err := uc.Create(ctx, owner, idea.ID, CreateShareLinkInput{Password: "hunter2"})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			tokens = append(tokens, tokenOf(t, link))
+		}
+		for i := 0; i < maxFailuresPerIP; i++ {
+			token := tokens[i/maxFailuresPerLink]
+			if _, err := uc.Open(ctx, token, "guess", "203.0.113.7"); !errors.Is(err, domain.ErrInvalidSharePassword) {
+				t.Fatalf("attempt %d: err = %v, want ErrInvalidSharePassword", i, err)
+			}
+		}
+		last := tokens[len(tokens)-1]
+		if _, err := uc.Open(ctx, last, "hunter2", "203.0.113.7"); !errors.Is(err, domain.ErrTooManyShareAttempts) {
+			t.Fatalf("fresh link from the same address: err = %v, want ErrTooManyShareAttempts", err)
+		}
+		if _, err := uc.Open(ctx, last, "hunter2", "198.51.100.1"); err != nil {
+			t.Fatalf("fresh link from another address: %v", err)
+		}
+	})
+
+	t.Run("failures expire", func(t *testing.T) {
+		repo := newMemoryShareLinks()
+		uc := newShareLinkUsecase("secret", repo, idea)
+		link, err := uc.Create(ctx, owner, idea.ID, CreateShareLinkInput{Password: "hunter2"})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		old := time.Now().Add(-PasswordAttemptWindow - time.Minute)
+		for i := 0; i < maxFailuresPerLink; i++ {
+			repo.failures = append(repo.failures, passwordFailure{linkID: link.ID, ip: "198.51.100.1", at: old})
+		}
+		if _, err := uc.Open(ctx, tokenOf(t, link), "hunter2", "203.0.113.7"); err != nil {
+			t.Fatalf("Open after the window: %v", err)
+		}
+	})
+}
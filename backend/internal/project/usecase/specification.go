@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dark/idea-forge/internal/project/domain"
+)
+
+// specChapters are the top-level sections of a project specification
+var specChapters = []string{
+	"1. Idea",
+	"2. Requerimientos",
+	"3. Flujo de lógica de negocio",
+	"4. Historias de usuario",
+	"5. Diseño de base de datos",
+	"6. Stack tecnológico",
+	"7. Arquitectura del sistema",
+	"8. Módulos de desarrollo",
+}
+
+// RenderSpecificationMarkdown renders the whole project as a single document:
+// idea, requirements, business logic, user stories, database design, tech
+// stack, system architecture and the ordered module list
+func RenderSpecificationMarkdown(p *domain.Project, generatedAt time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", strings.TrimSpace(p.Idea.Title))
+	fmt.Fprintf(&b, "> Especificación generada por Idea Forge el %s.\n\n", generatedAt.Format("2006-01-02 15:04"))
+
+	b.WriteString("## Índice\n\n")
+	for _, c := range specChapters {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", c, headingAnchor(c))
+	}
+	b.WriteString("\n")
+	b.WriteString(renderSpecificationBody(p))
+	return b.String()
+}
+
+// renderSpecificationBody renders the specification chapters, without title or index
+func renderSpecificationBody(p *domain.Project) string {
+	var b strings.Builder
+	chapters := specChapters
+	fmt.Fprintf(&b, "## %s\n\n", chapters[0])
+	writeSpecSection(&b, 3, "Objetivo", p.Idea.Objective)
+	writeSpecSection(&b, 3, "Problema", p.Idea.Problem)
+	writeSpecSection(&b, 3, "Alcance", p.Idea.Scope)
+
+	plan := p.ActionPlan
+	fmt.Fprintf(&b, "## %s\n\n", chapters[1])
+	if plan == nil {
+		b.WriteString(stageMissing)
+	} else {
+		writeSpecSection(&b, 3, "Funcionales", plan.FunctionalRequirements)
+		writeSpecSection(&b, 3, "No funcionales", plan.NonFunctionalRequirements)
+	}
+	fmt.Fprintf(&b, "## %s\n\n", chapters[2])
+	if plan == nil {
+		b.WriteString(stageMissing)
+	} else {
+		writeSpecContent(&b, 2, plan.BusinessLogicFlow)
+	}
+
+	arch := p.Architecture
+	fmt.Fprintf(&b, "## %s\n\n", chapters[3])
+	if arch == nil {
+		b.WriteString(stageMissing)
+	} else {
+		writeSpecContent(&b, 2, arch.UserStories)
+	}
+	fmt.Fprintf(&b, "## %s\n\n", chapters[4])
+	if arch == nil {
+		b.WriteString(stageMissing)
+	} else {
+		writeSpecSection(&b, 3, "Tipo de base de datos", arch.DatabaseType)
+		writeSpecSection(&b, 3, "Esquema", arch.DatabaseSchema)
+		writeSpecSection(&b, 3, "Entidades y relaciones", arch.EntitiesRelationships)
+	}
+	fmt.Fprintf(&b, "## %s\n\n", chapters[5])
+	if arch == nil {
+		b.WriteString(stageMissing)
+	} else {
+		writeSpecContent(&b, 2, arch.TechStack)
+		writeSpecSection(&b, 3, "Patrón de arquitectura", arch.ArchitecturePattern)
+	}
+	fmt.Fprintf(&b, "## %s\n\n", chapters[6])
+	if arch == nil {
+		b.WriteString(stageMissing)
+	} else {
+		writeSpecContent(&b, 2, arch.SystemArchitecture)
+	}
+
+	fmt.Fprintf(&b, "## %s\n\n", chapters[7])
+	if len(p.Modules) == 0 {
+		b.WriteString("_Sin módulos._\n\n")
+		return b.String()
+	}
+	// Los módulos vienen ordenados por prioridad (orden de desarrollo)
+	b.WriteString("| # | Módulo | Estado | Prioridad | Depende de |\n|---|--------|--------|-----------|------------|\n")
+	for i, m := range p.Modules {
+		deps := strings.Join(ParseDependencies(m.Dependencies), ", ")
+		if deps == "" {
+			deps = "—"
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %d | %s |\n", i+1, tableCell(m.Name), m.Status, m.Priority, tableCell(deps))
+	}
+	b.WriteString("\n")
+	for i, m := range p.Modules {
+		fmt.Fprintf(&b, "### 8.%d %s\n\n", i+1, m.Name)
+		if deps := ParseDependencies(m.Dependencies); len(deps) > 0 {
+			fmt.Fprintf(&b, "Depende de: %s\n\n", strings.Join(deps, ", "))
+		}
+		writeSpecSection(&b, 4, "Descripción", m.Description)
+		writeSpecSection(&b, 4, "Funcionalidad", m.Functionality)
+		writeSpecSection(&b, 4, "Detalles técnicos", m.TechnicalDetails)
+	}
+	return b.String()
+}
+
+const stageMissing = "_Etapa aún no generada._\n\n"
+
+func writeSpecSection(b *strings.Builder, level int, title, content string) {
+	fmt.Fprintf(b, "%s %s\n\n", strings.Repeat("#", level), title)
+	writeSpecContent(b, level, content)
+}
+
+// writeSpecContent writes generated content nested under a heading of the given
+// level: its own headings are pushed below it so they do not break the outline
+func writeSpecContent(b *strings.Builder, level int, content string) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		b.WriteString("_Sin contenido._\n\n")
+		return
+	}
+	b.WriteString(demoteHeadings(content, level))
+	b.WriteString("\n\n")
+}
+
+// demoteHeadings shifts the ATX headings of content so the top one is one level
+// below level, leaving fenced code blocks untouched
+func demoteHeadings(content string, level int) string {
+	lines := strings.Split(content, "\n")
+	top := 0
+	inFence := false
+	for _, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+		} else if n := headingLevel(line); !inFence && n > 0 && (top == 0 || n < top) {
+			top = n
+		}
+	}
+	if top == 0 || top > level {
+		return content
+	}
+	shift := level + 1 - top
+
+	inFence = false
+	for i, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if n := headingLevel(line); !inFence && n > 0 {
+			lines[i] = strings.Repeat("#", min(n+shift, 6)) + line[n:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func headingLevel(line string) int {
+	n := 0
+	for n < len(line) && line[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || (n < len(line) && line[n] != ' ') {
+		return 0
+	}
+	return n
+}
+
+func isFence(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~")
+}
+
+// headingAnchor returns the anchor GitHub-style renderers give a heading
+func headingAnchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+func tableCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
@@ -0,0 +1,85 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// heartbeat keeps idle streams open through proxies
+const heartbeat = 25 * time.Second
+
+// accessRecheck bounds how long a stream outlives the user's access to the project
+// (removed from the workspace or as a collaborator, or the project was deleted)
+const accessRecheck = time.Minute
+
+// ServeEvents streams the events of /projects/{ideaID}/events as Server-Sent Events.
+// Read access to the project is checked by the access guard when the stream opens and
+// again every accessRecheck; once it is lost the stream sends "access_revoked" and closes.
+func (h *Hub) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	ideaID, err := uuid.Parse(r.PathValue("ideaID"))
+	if err != nil {
+		http.Error(w, "invalid idea id", http.StatusBadRequest)
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := h.Subscribe(ideaID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	recheck := time.NewTicker(h.recheck)
+	defer recheck.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-recheck.C:
+			ok, err := h.access.CanRead(r.Context(), userID, ideaID)
+			if err != nil {
+				// A transient failure keeps the stream; the next check decides
+				log.Printf("realtime: checking access of %s to idea %s: %v", userID, ideaID, err)
+				continue
+			}
+			if !ok {
+				fmt.Fprint(w, "event: access_revoked\ndata: {}\n\n")
+				flusher.Flush()
+				return
+			}
+		case e := <-events:
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package realtime
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// fakeAccess answers each CanRead with the next entry of results, repeating the last one
+type fakeAccess struct {
+	mu      sync.Mutex
+	results []accessResult
+	calls   int
+}
+
+type accessResult struct {
+	ok  bool
+	err error
+}
+
+func (a *fakeAccess) CanRead(ctx context.Context, userID, ideaID uuid.UUID) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	r := a.results[min(a.calls, len(a.results)-1)]
+	a.calls++
+	return r.ok, r.err
+}
+
+// openStream serves the hub as the authenticated user and returns the open stream of the idea
+func openStream(t *testing.T, hub *Hub, ideaID uuid.UUID) *bufio.Reader {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /projects/{ideaID}/events", hub.ServeEvents)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), middleware.UserIDKey, uuid.New())
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/projects/"+ideaID.String()+"/events", nil)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("opening stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvents returns the event names sent until the stream closes
+func readEvents(t *testing.T, stream *bufio.Reader) []string {
+	t.Helper()
+	var names []string
+	for {
+		line, err := stream.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			return names
+		}
+		if err != nil {
+			t.Fatalf("reading stream: %v", err)
+		}
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "event: "); ok {
+			names = append(names, name)
+		}
+	}
+}
+
+func TestServeEventsClosesWhenAccessIsLost(t *testing.T) {
+	transient := errors.New("connection reset")
+	tests := []struct {
+		name    string
+		results []accessResult
+		checks  int
+	}{
+		{name: "removed from the project", results: []accessResult{{ok: false}}, checks: 1},
+		{name: "removed after a while", results: []accessResult{{ok: true}, {ok: true}, {ok: false}}, checks: 3},
+		{name: "transient error keeps the stream", results: []accessResult{{err: transient}, {ok: true}, {ok: false}}, checks: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access := &fakeAccess{results: tt.results}
+			hub := NewHub(access)
+			hub.recheck = 10 * time.Millisecond
+
+			stream := openStream(t, hub, uuid.New())
+			names := readEvents(t, stream)
+
+			if len(names) != 1 || names[0] != "access_revoked" {
+				t.Fatalf("events = %v, want [access_revoked]", names)
+			}
+			access.mu.Lock()
+			defer access.mu.Unlock()
+			if access.calls != tt.checks {
+				t.Errorf("access checked %d times, want %d", access.calls, tt.checks)
+			}
+		})
+	}
+}
+
+func TestServeEventsKeepsStreamingWhileAccessHolds(t *testing.T) {
+	hub := NewHub(&fakeAccess{results: []accessResult{{ok: true}}})
+	hub.recheck = 10 * time.Millisecond
+	ideaID := uuid.New()
+
+	stream := openStream(t, hub, ideaID)
+	if line, _ := stream.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("first line = %q", line)
+	}
+	// Let a few access checks pass before publishing
+	time.Sleep(50 * time.Millisecond)
+	hub.Broadcast(Event{ID: uuid.New(), Type: "idea.updated", IdeaID: ideaID})
+
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("stream closed before the event: %v", err)
+		}
+		if strings.TrimSpace(line) == "event: idea.updated" {
+			return
+		}
+	}
+}
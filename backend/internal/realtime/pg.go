@@ -0,0 +1,89 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Channel is the Postgres NOTIFY channel shared by all API instances
+const Channel = "project_events"
+
+// maxPayload stays under the 8000 byte NOTIFY limit
+const maxPayload = 7900
+
+// Publisher sends project events to every API instance
+type Publisher struct{ db *sql.DB }
+
+func NewPublisher(db *sql.DB) *Publisher { return &Publisher{db: db} }
+
+// Publish notifies an event to every instance. Events too large for a
+// notification are sent without their data and flagged as truncated.
+func (p *Publisher) Publish(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayload {
+		e.Data, e.Truncated = nil, true
+		if payload, err = json.Marshal(e); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err = p.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, Channel, string(payload))
+	return err
+}
+
+// Listen forwards the notifications of every instance to the hub until ctx is done,
+// reconnecting with backoff when the connection drops.
+func Listen(ctx context.Context, dsn string, hub *Hub) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := listen(ctx, dsn, hub, func() { backoff = time.Second })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("realtime: listener disconnected: %v (retrying in %s)", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func listen(ctx context.Context, dsn string, hub *Hub, connected func()) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	connected()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			log.Printf("realtime: invalid notification: %v", err)
+			continue
+		}
+		hub.Broadcast(e)
+	}
+}
@@ -0,0 +1,92 @@
+// Package realtime pushes project events to the clients that have the project open.
+//
+// The events dispatcher publishes every recorded event through Postgres NOTIFY. Every API instance LISTENs
+// on the same channel and fans the events out to the Server-Sent Events streams
+// open on that instance, so a change made through any instance (a form, an
+// agent chat or a global chat propagation) reaches every collaborator.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a change to a project, e.g. "idea.updated" or "action_plan.section_updated"
+type Event struct {
+	ID     uuid.UUID       `json:"id"`
+	Type   string          `json:"type"`
+	IdeaID uuid.UUID       `json:"idea_id"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	// Truncated means Data did not fit in a notification; clients should refetch
+	Truncated bool      `json:"truncated,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// subscriberBuffer is how many events a slow stream may lag behind before events are dropped
+const subscriberBuffer = 64
+
+// AccessChecker decides whether a user can still read a project (false if it no longer exists)
+type AccessChecker interface {
+	CanRead(ctx context.Context, userID, ideaID uuid.UUID) (bool, error)
+}
+
+// Hub keeps the event streams open on this instance, grouped by project
+type Hub struct {
+	access AccessChecker
+	// recheck is how often an open stream re-checks that its user can still read the project
+	recheck time.Duration
+
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[chan Event]struct{}
+}
+
+func NewHub(access AccessChecker) *Hub {
+	return &Hub{
+		access:  access,
+		recheck: accessRecheck,
+		subs:    make(map[uuid.UUID]map[chan Event]struct{}),
+	}
+}
+
+// Subscribe returns the events of a project until cancel is called
+func (h *Hub) Subscribe(ideaID uuid.UUID) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[ideaID] == nil {
+		h.subs[ideaID] = make(map[chan Event]struct{})
+	}
+	h.subs[ideaID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[ideaID], ch)
+			if len(h.subs[ideaID]) == 0 {
+				delete(h.subs, ideaID)
+			}
+			h.mu.Unlock()
+		})
+	}
+	return ch, cancel
+}
+
+// Broadcast delivers an event to the streams of its project without blocking
+func (h *Hub) Broadcast(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[e.IdeaID] {
+		select {
+		case ch <- e:
+		default:
+			log.Printf("realtime: stream of idea %s is lagging, dropped %s", e.IdeaID, e.Type)
+		}
+	}
+}
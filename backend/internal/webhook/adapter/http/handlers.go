@@ -0,0 +1,243 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/dark/idea-forge/internal/webhook/domain"
+	"github.com/dark/idea-forge/internal/webhook/usecase"
+	"github.com/google/uuid"
+)
+
+// Handlers exposes webhook subscriptions and their delivery log. Must be registered behind the auth middleware.
+type Handlers struct {
+	Usecase *usecase.WebhookUsecase
+}
+
+func (h *Handlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /webhooks", h.listWebhooks)
+	mux.HandleFunc("POST /webhooks", h.createWebhook)
+	mux.HandleFunc("GET /webhooks/events", h.listEvents)
+	mux.HandleFunc("GET /webhooks/{id}", h.getWebhook)
+	mux.HandleFunc("PUT /webhooks/{id}", h.updateWebhook)
+	mux.HandleFunc("DELETE /webhooks/{id}", h.deleteWebhook)
+	mux.HandleFunc("POST /webhooks/{id}/ping", h.ping)
+
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", h.listDeliveries)
+	mux.HandleFunc("GET /webhooks/{id}/deliveries/{deliveryID}", h.getDelivery)
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{deliveryID}/redeliver", h.redeliver)
+}
+
+func (h *Handlers) listEvents(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, domain.Events, http.StatusOK)
+}
+
+func (h *Handlers) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var workspaceID *uuid.UUID
+	if v := r.URL.Query().Get("workspace_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+			return
+		}
+		workspaceID = &id
+	}
+
+	hooks, err := h.Usecase.List(r.Context(), userID, workspaceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, hooks, http.StatusOK)
+}
+
+func (h *Handlers) createWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in usecase.CreateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.Usecase.Create(r.Context(), userID, in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, hook, http.StatusCreated)
+}
+
+func (h *Handlers) getWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	hook, err := h.Usecase.Get(r.Context(), userID, webhookID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, hook, http.StatusOK)
+}
+
+func (h *Handlers) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var in usecase.UpdateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.Usecase.Update(r.Context(), userID, webhookID, in)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, hook, http.StatusOK)
+}
+
+func (h *Handlers) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Usecase.Delete(r.Context(), userID, webhookID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ping(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.Usecase.Ping(r.Context(), userID, webhookID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, delivery, http.StatusAccepted)
+}
+
+func (h *Handlers) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	filter := domain.DeliveryFilter{Status: r.URL.Query().Get("status")}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	deliveries, err := h.Usecase.ListDeliveries(r.Context(), userID, webhookID, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, deliveries, http.StatusOK)
+}
+
+func (h *Handlers) getDelivery(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := uuid.Parse(r.PathValue("deliveryID"))
+	if err != nil {
+		http.Error(w, "invalid delivery id", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.Usecase.GetDelivery(r.Context(), userID, webhookID, deliveryID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, delivery, http.StatusOK)
+}
+
+func (h *Handlers) redeliver(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := uuid.Parse(r.PathValue("deliveryID"))
+	if err != nil {
+		http.Error(w, "invalid delivery id", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.Usecase.Redeliver(r.Context(), userID, webhookID, deliveryID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, delivery, http.StatusAccepted)
+}
+
+func parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	webhookID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid webhook id", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, webhookID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound),
+		errors.Is(err, domain.ErrDeliveryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidURL),
+		errors.Is(err, domain.ErrPrivateTarget),
+		errors.Is(err, domain.ErrInvalidEvent):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("webhook error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
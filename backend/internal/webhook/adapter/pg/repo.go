@@ -0,0 +1,272 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dark/idea-forge/internal/webhook/domain"
+	"github.com/dark/idea-forge/internal/webhook/port"
+	"github.com/google/uuid"
+)
+
+type repo struct{ db *sql.DB }
+
+func NewRepo(db *sql.DB) port.WebhookRepository { return &repo{db: db} }
+
+const webhookColumns = `id, user_id, workspace_id, url, events, secret, active, created_at, updated_at`
+
+const deliveryColumns = `id, webhook_id, event_id, event, payload, status, attempts, next_attempt_at, delivered_at, created_at`
+
+func (r *repo) Create(ctx context.Context, hook *domain.Webhook) error {
+	events, err := json.Marshal(hook.Events)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO webhooks (id, user_id, workspace_id, url, events, secret, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`, hook.ID, hook.UserID, hook.WorkspaceID, hook.URL, string(events), hook.Secret, hook.Active).
+		Scan(&hook.CreatedAt, &hook.UpdatedAt)
+}
+
+func (r *repo) Get(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	hook, err := scanWebhook(r.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	return hook, err
+}
+
+func (r *repo) ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	return r.listWebhooks(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE user_id = $1 ORDER BY created_at`, userID)
+}
+
+func (r *repo) ListForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]domain.Webhook, error) {
+	return r.listWebhooks(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE workspace_id = $1 ORDER BY created_at`, workspaceID)
+}
+
+func (r *repo) FindSubscribed(ctx context.Context, event string, ownerID, workspaceID *uuid.UUID) ([]domain.Webhook, error) {
+	return r.listWebhooks(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE active
+		  AND (events = '[]'::jsonb OR events @> jsonb_build_array($1::text))
+		  AND (user_id = $2 OR workspace_id = $3)
+	`, event, ownerID, workspaceID)
+}
+
+func (r *repo) Update(ctx context.Context, hook *domain.Webhook) error {
+	events, err := json.Marshal(hook.Events)
+	if err != nil {
+		return err
+	}
+	err = r.db.QueryRowContext(ctx, `
+		UPDATE webhooks SET url = $2, events = $3, active = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, hook.ID, hook.URL, string(events), hook.Active).Scan(&hook.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrWebhookNotFound
+	}
+	return err
+}
+
+func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *repo) CreateDeliveries(ctx context.Context, deliveries []domain.Delivery) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range deliveries {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (id, webhook_id, event_id, event, payload, status, attempts, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, d.ID, d.WebhookID, d.EventID, d.Event, string(d.Payload), d.Status, d.Attempts, d.NextAttemptAt, d.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *repo) HasDeliveries(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM webhook_deliveries WHERE event_id = $1)`, eventID).Scan(&exists)
+	return exists, err
+}
+
+func (r *repo) GetDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.Delivery, error) {
+	d, err := scanDelivery(r.db.QueryRowContext(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1 AND webhook_id = $2
+	`, deliveryID, webhookID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, delivery_id, status_code, error, response_body, duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempted_at
+	`, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	d.AttemptLog = []domain.Attempt{}
+	for rows.Next() {
+		var a domain.Attempt
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.StatusCode, &a.Error, &a.ResponseBody, &a.DurationMs, &a.AttemptedAt); err != nil {
+			return nil, err
+		}
+		d.AttemptLog = append(d.AttemptLog, a)
+	}
+	return d, rows.Err()
+}
+
+func (r *repo) ListDeliveries(ctx context.Context, webhookID uuid.UUID, filter domain.DeliveryFilter) ([]domain.Delivery, error) {
+	return r.listDeliveries(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, webhookID, filter.Status, filter.Limit)
+}
+
+func (r *repo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.Delivery, error) {
+	return r.listDeliveries(ctx, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns, limit, lease.Seconds())
+}
+
+func (r *repo) RecordAttempt(ctx context.Context, d *domain.Delivery, a *domain.Attempt) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO webhook_delivery_attempts (id, delivery_id, status_code, error, response_body, duration_ms, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, a.ID, a.DeliveryID, a.StatusCode, a.Error, a.ResponseBody, a.DurationMs, a.AttemptedAt); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, delivered_at = $5
+		WHERE id = $1
+	`, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.DeliveredAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repo) listWebhooks(ctx context.Context, query string, args ...any) ([]domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []domain.Webhook{}
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, *hook)
+	}
+	return hooks, rows.Err()
+}
+
+func (r *repo) listDeliveries(ctx context.Context, query string, args ...any) ([]domain.Delivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []domain.Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(row rowScanner) (*domain.Webhook, error) {
+	var hook domain.Webhook
+	var userID, workspaceID uuid.NullUUID
+	var events []byte
+	if err := row.Scan(&hook.ID, &userID, &workspaceID, &hook.URL, &events, &hook.Secret, &hook.Active, &hook.CreatedAt, &hook.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		hook.UserID = &userID.UUID
+	}
+	if workspaceID.Valid {
+		hook.WorkspaceID = &workspaceID.UUID
+	}
+	if err := json.Unmarshal(events, &hook.Events); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func scanDelivery(row rowScanner) (*domain.Delivery, error) {
+	var d domain.Delivery
+	var payload []byte
+	var nextAttemptAt, deliveredAt sql.NullTime
+	if err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Event, &payload, &d.Status, &d.Attempts, &nextAttemptAt, &deliveredAt, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	if nextAttemptAt.Valid {
+		d.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
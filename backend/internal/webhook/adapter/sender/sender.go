@@ -0,0 +1,79 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/dark/idea-forge/internal/webhook/domain"
+	"github.com/dark/idea-forge/internal/webhook/port"
+)
+
+// maxResponseBody is how much of the endpoint's answer is kept in the delivery log
+const maxResponseBody = 1024
+
+type httpSender struct{ client *http.Client }
+
+// NewHTTPSender creates a Sender that gives each endpoint timeout to answer.
+// Redirects are not followed so a delivery always hits the configured URL.
+// Unless allowPrivate is set, connections to addresses inside the network are
+// refused with domain.ErrPrivateTarget. The check runs on the resolved address
+// at dial time, so a hostname that later resolves to an internal IP (DNS
+// rebinding) is refused too.
+func NewHTTPSender(timeout time.Duration, allowPrivate bool) port.Sender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = refusePrivate
+	}
+	return &httpSender{client: &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// No proxy: the address check must see the real target
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        20,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// refusePrivate is a net.Dialer Control that rejects non-public addresses
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrPrivateTarget, address)
+	}
+	if !domain.IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", domain.ErrPrivateTarget, addrPort.Addr())
+	}
+	return nil
+}
+
+func (s *httpSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, string(respBody), nil
+}
@@ -0,0 +1,74 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dark/idea-forge/internal/webhook/domain"
+)
+
+func TestSendRefusesPrivateTargets(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("internal secret"))
+	}))
+	defer srv.Close()
+
+	status, body, err := NewHTTPSender(time.Second, false).Send(context.Background(), srv.URL, nil, []byte("{}"))
+	if !errors.Is(err, domain.ErrPrivateTarget) {
+		t.Fatalf("err = %v, want ErrPrivateTarget", err)
+	}
+	if status != 0 || body != "" || hits != 0 {
+		t.Fatalf("got status %d body %q hits %d, want nothing sent", status, body, hits)
+	}
+}
+
+func TestSendAllowsPrivateTargetsWhenConfigured(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	status, body, err := NewHTTPSender(time.Second, true).Send(context.Background(), srv.URL, nil, []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusAccepted || body != "ok" {
+		t.Fatalf("got status %d body %q", status, body)
+	}
+}
+
+func TestRefusePrivate(t *testing.T) {
+	tests := []struct {
+		address string
+		refused bool
+	}{
+		{"127.0.0.1:80", true},
+		{"[::1]:443", true},
+		{"10.1.2.3:80", true},
+		{"172.16.0.1:80", true},
+		{"192.168.1.10:8080", true},
+		{"169.254.169.254:80", true},
+		{"[fe80::1]:80", true},
+		{"[fd00::1]:80", true},
+		{"0.0.0.0:80", true},
+		{"[::]:80", true},
+		{"224.0.0.1:80", true},
+		{"[::ffff:127.0.0.1]:80", true},
+		{"not-an-address", true},
+		{"93.184.216.34:443", false},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", false},
+	}
+	for _, tt := range tests {
+		err := refusePrivate("tcp", tt.address, nil)
+		if got := errors.Is(err, domain.ErrPrivateTarget); got != tt.refused {
+			t.Errorf("refusePrivate(%q) refused = %v, want %v", tt.address, got, tt.refused)
+		}
+	}
+}
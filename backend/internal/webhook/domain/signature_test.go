@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"idea.created"}`)
+	now := time.Now()
+	valid := Sign("secret", now, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		header    string
+		body      []byte
+		tolerance time.Duration
+		wantErr   error
+	}{
+		{name: "valid", secret: "secret", header: valid, body: body, tolerance: 5 * time.Minute},
+		{name: "extra spaces and parts", secret: "secret", header: " v0=old, " + strings.ReplaceAll(valid, ",", " , "), body: body, tolerance: 5 * time.Minute},
+		{name: "wrong secret", secret: "other", header: valid, body: body, tolerance: 5 * time.Minute, wantErr: ErrInvalidSignature},
+		{name: "tampered body", secret: "secret", header: valid, body: []byte(`{"event":"idea.deleted"}`), tolerance: 5 * time.Minute, wantErr: ErrInvalidSignature},
+		{name: "replayed after tolerance", secret: "secret", header: Sign("secret", now.Add(-10*time.Minute), body), body: body, tolerance: 5 * time.Minute, wantErr: ErrInvalidSignature},
+		{name: "timestamp in the future", secret: "secret", header: Sign("secret", now.Add(10*time.Minute), body), body: body, tolerance: 5 * time.Minute, wantErr: ErrInvalidSignature},
+		{name: "old signature without tolerance", secret: "secret", header: Sign("secret", now.Add(-24*time.Hour), body), body: body},
+		{name: "timestamp swapped", secret: "secret", header: "t=" + "1" + valid[strings.Index(valid, ","):], body: body, wantErr: ErrInvalidSignature},
+		{name: "missing signature", secret: "secret", header: valid[:strings.Index(valid, ",")], body: body, wantErr: ErrInvalidSignature},
+		{name: "missing timestamp", secret: "secret", header: valid[strings.Index(valid, ",")+1:], body: body, wantErr: ErrInvalidSignature},
+		{name: "empty header", secret: "secret", body: body, wantErr: ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.secret, tt.header, tt.body, tt.tolerance); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignFormat(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	got := Sign("secret", at, []byte("{}"))
+	if !strings.HasPrefix(got, "t=1700000000,v1=") || len(got) != len("t=1700000000,v1=")+64 {
+		t.Fatalf("Sign = %q, want t=<unix>,v1=<64 hex chars>", got)
+	}
+	if got != Sign("secret", at, []byte("{}")) {
+		t.Fatal("Sign is not deterministic")
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"errors"
+	"net/netip"
+)
+
+// ErrPrivateTarget is returned when a webhook would reach an address inside the
+// server's network (loopback, private, link-local, unspecified or multicast)
+var ErrPrivateTarget = errors.New("webhook url points to a private or local address")
+
+// IsPublicAddr reports whether deliveries may be sent to addr. Targets inside
+// the network are refused so a webhook cannot be used to reach internal
+// services (SSRF) unless private targets are explicitly allowed.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events that can be subscribed to
+const (
+	EventIdeaCompleted         = "idea.completed"
+	EventArchitectureGenerated = "architecture.generated"
+	EventModuleStatusChanged   = "module.status_changed"
+)
+
+// EventPing is sent on demand to test an endpoint; it is delivered regardless of the filter
+const EventPing = "ping"
+
+// Events lists every event a webhook can subscribe to
+var Events = []string{EventIdeaCompleted, EventArchitectureGenerated, EventModuleStatusChanged}
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"   // waiting for its next attempt
+	StatusSucceeded = "succeeded" // the endpoint answered 2xx
+	StatusFailed    = "failed"    // gave up after MaxAttempts
+)
+
+// MaxAttempts is how many times a delivery is tried before it is marked failed
+const MaxAttempts = 8
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrInvalidURL       = errors.New("url must be an absolute http or https url")
+	ErrInvalidEvent     = errors.New("unknown event")
+	ErrForbidden        = errors.New("you cannot manage this webhook")
+)
+
+// Webhook subscribes an HTTP endpoint to project events. A user-level webhook
+// receives the events of the projects the user created; a workspace-level one
+// receives the events of every project in the workspace.
+type Webhook struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty"`
+	URL         string     `json:"url"`
+	Events      []string   `json:"events"`           // empty means every event
+	Secret      string     `json:"secret,omitempty"` // only returned when the webhook is created
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Subscribes reports whether the webhook receives the event
+func (w *Webhook) Subscribes(event string) bool {
+	if event == EventPing || len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one event sent (or to be sent) to one webhook
+type Delivery struct {
+	ID            uuid.UUID       `json:"id"`
+	WebhookID     uuid.UUID       `json:"webhook_id"`
+	EventID       uuid.UUID       `json:"event_id"` // shared by the deliveries of the same event
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	AttemptLog    []Attempt       `json:"attempt_log,omitempty"`
+}
+
+// Attempt is the outcome of one HTTP request of a delivery
+type Attempt struct {
+	ID           uuid.UUID `json:"id"`
+	DeliveryID   uuid.UUID `json:"delivery_id"`
+	StatusCode   int       `json:"status_code,omitempty"` // 0 when no response was received
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"` // truncated
+	DurationMs   int64     `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
+
+// Succeeded reports whether the endpoint accepted the delivery
+func (a *Attempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// DeliveryFilter narrows the delivery log
+type DeliveryFilter struct {
+	Status string
+	Limit  int
+}
+
+// IsEvent reports whether event can be subscribed to
+func IsEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryDelay is the wait before the attempt that follows a failed one:
+// 30s, 1m, 2m, ... doubling up to 6h
+func RetryDelay(failedAttempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < failedAttempts && delay < 6*time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > 6*time.Hour {
+		delay = 6 * time.Hour
+	}
+	return delay
+}
@@ -0,0 +1,50 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/dark/idea-forge/internal/webhook/domain"
+	"github.com/google/uuid"
+)
+
+// WebhookRepository defines persistence for webhooks and their deliveries
+type WebhookRepository interface {
+	Create(ctx context.Context, hook *domain.Webhook) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error)
+	ListForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]domain.Webhook, error)
+	Update(ctx context.Context, hook *domain.Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// FindSubscribed returns the active webhooks of the project's creator or workspace that receive the event
+	FindSubscribed(ctx context.Context, event string, ownerID, workspaceID *uuid.UUID) ([]domain.Webhook, error)
+
+	CreateDeliveries(ctx context.Context, deliveries []domain.Delivery) error
+	// HasDeliveries reports whether deliveries of the event were already queued
+	HasDeliveries(ctx context.Context, eventID uuid.UUID) (bool, error)
+	// GetDelivery returns a delivery of the webhook with its attempt log
+	GetDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.Delivery, error)
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, filter domain.DeliveryFilter) ([]domain.Delivery, error)
+	// ClaimDue locks pending deliveries whose next attempt is due by pushing its lease into the
+	// future, so other instances skip them while they are being sent
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.Delivery, error)
+	// RecordAttempt stores the attempt and the resulting delivery state together
+	RecordAttempt(ctx context.Context, delivery *domain.Delivery, attempt *domain.Attempt) error
+}
+
+// ProjectDirectory tells which user created a project and which workspace it belongs to.
+// Scope returns domain.ErrProjectNotFound when the project no longer exists.
+type ProjectDirectory interface {
+	Scope(ctx context.Context, ideaID uuid.UUID) (ownerID, workspaceID *uuid.UUID, err error)
+}
+
+// AccessChecker answers authorization questions owned by the access module
+type AccessChecker interface {
+	CanRead(ctx context.Context, userID, ideaID uuid.UUID) (bool, error)
+	CanManageWorkspace(ctx context.Context, userID, workspaceID uuid.UUID) (bool, error)
+}
+
+// Sender performs the HTTP request of a delivery
+type Sender interface {
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (statusCode int, responseBody string, err error)
+}
@@ -0,0 +1,442 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/webhook/domain"
+	"github.com/dark/idea-forge/internal/webhook/port"
+)
+
+const (
+	pollInterval  = 5 * time.Second
+	claimBatch    = 20
+	claimLease    = 2 * time.Minute
+	maxLogEntries = 100
+)
+
+// WebhookUsecase manages webhook subscriptions and delivers project events to them
+type WebhookUsecase struct {
+	repo     port.WebhookRepository
+	projects port.ProjectDirectory
+	access   port.AccessChecker
+	sender   port.Sender
+	wake     chan struct{}
+
+	// allowPrivate accepts URLs on loopback or private addresses (local receivers)
+	allowPrivate bool
+}
+
+// NewWebhookUsecase creates a new webhook use case. Unless allowPrivate is
+// set, URLs naming a loopback or private address are rejected; the sender
+// enforces the same rule on the resolved address.
+func NewWebhookUsecase(repo port.WebhookRepository, projects port.ProjectDirectory, access port.AccessChecker, sender port.Sender, allowPrivate bool) *WebhookUsecase {
+	return &WebhookUsecase{
+		repo:         repo,
+		projects:     projects,
+		access:       access,
+		sender:       sender,
+		wake:         make(chan struct{}, 1),
+		allowPrivate: allowPrivate,
+	}
+}
+
+// CreateWebhookInput holds the fields of a new webhook; without WorkspaceID the webhook is user-level
+type CreateWebhookInput struct {
+	URL         string     `json:"url"`
+	Events      []string   `json:"events"`
+	WorkspaceID *uuid.UUID `json:"workspace_id"`
+}
+
+// UpdateWebhookInput holds the fields to change; nil fields are left as they are
+type UpdateWebhookInput struct {
+	URL    *string   `json:"url"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
+
+// Create subscribes a URL and returns the webhook with its signing secret
+func (uc *WebhookUsecase) Create(ctx context.Context, userID uuid.UUID, in CreateWebhookInput) (*domain.Webhook, error) {
+	hook := &domain.Webhook{ID: uuid.New(), Active: true}
+	if in.WorkspaceID != nil {
+		if err := uc.checkWorkspace(ctx, userID, *in.WorkspaceID); err != nil {
+			return nil, err
+		}
+		hook.WorkspaceID = in.WorkspaceID
+	} else {
+		hook.UserID = &userID
+	}
+
+	var err error
+	if hook.URL, err = uc.validateURL(in.URL); err != nil {
+		return nil, err
+	}
+	if hook.Events, err = validateEvents(in.Events); err != nil {
+		return nil, err
+	}
+	if hook.Secret, err = newSecret(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// List returns the user's webhooks, or the workspace's when workspaceID is set (owners only)
+func (uc *WebhookUsecase) List(ctx context.Context, userID uuid.UUID, workspaceID *uuid.UUID) ([]domain.Webhook, error) {
+	if workspaceID != nil {
+		if err := uc.checkWorkspace(ctx, userID, *workspaceID); err != nil {
+			return nil, err
+		}
+		return uc.repo.ListForWorkspace(ctx, *workspaceID)
+	}
+	return uc.repo.ListForUser(ctx, userID)
+}
+
+// Get returns a webhook the user manages
+func (uc *WebhookUsecase) Get(ctx context.Context, userID, webhookID uuid.UUID) (*domain.Webhook, error) {
+	return uc.authorize(ctx, userID, webhookID)
+}
+
+// Update changes the URL, the event filter or the active flag of a webhook
+func (uc *WebhookUsecase) Update(ctx context.Context, userID, webhookID uuid.UUID, in UpdateWebhookInput) (*domain.Webhook, error) {
+	hook, err := uc.authorize(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.URL != nil {
+		if hook.URL, err = uc.validateURL(*in.URL); err != nil {
+			return nil, err
+		}
+	}
+	if in.Events != nil {
+		if hook.Events, err = validateEvents(*in.Events); err != nil {
+			return nil, err
+		}
+	}
+	if in.Active != nil {
+		hook.Active = *in.Active
+	}
+
+	if err := uc.repo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Delete removes a webhook and its delivery log
+func (uc *WebhookUsecase) Delete(ctx context.Context, userID, webhookID uuid.UUID) error {
+	if _, err := uc.authorize(ctx, userID, webhookID); err != nil {
+		return err
+	}
+	return uc.repo.Delete(ctx, webhookID)
+}
+
+// Ping queues a "ping" delivery to test the endpoint
+func (uc *WebhookUsecase) Ping(ctx context.Context, userID, webhookID uuid.UUID) (*domain.Delivery, error) {
+	hook, err := uc.authorize(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := uuid.New()
+	payload, err := envelope(eventID, domain.EventPing, nil, time.Now(), map[string]any{"webhook_id": hook.ID})
+	if err != nil {
+		return nil, err
+	}
+	delivery := newDelivery(hook.ID, eventID, domain.EventPing, payload)
+	if err := uc.repo.CreateDeliveries(ctx, []domain.Delivery{delivery}); err != nil {
+		return nil, err
+	}
+	uc.kick()
+	return &delivery, nil
+}
+
+// ListDeliveries returns the most recent deliveries of a webhook
+func (uc *WebhookUsecase) ListDeliveries(ctx context.Context, userID, webhookID uuid.UUID, filter domain.DeliveryFilter) ([]domain.Delivery, error) {
+	if _, err := uc.authorize(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+	if filter.Limit <= 0 || filter.Limit > maxLogEntries {
+		filter.Limit = maxLogEntries
+	}
+	return uc.repo.ListDeliveries(ctx, webhookID, filter)
+}
+
+// GetDelivery returns a delivery with every attempt made
+func (uc *WebhookUsecase) GetDelivery(ctx context.Context, userID, webhookID, deliveryID uuid.UUID) (*domain.Delivery, error) {
+	if _, err := uc.authorize(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetDelivery(ctx, webhookID, deliveryID)
+}
+
+// Redeliver queues a new delivery with the same payload as a previous one
+func (uc *WebhookUsecase) Redeliver(ctx context.Context, userID, webhookID, deliveryID uuid.UUID) (*domain.Delivery, error) {
+	if _, err := uc.authorize(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+	previous, err := uc.repo.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := newDelivery(webhookID, previous.EventID, previous.Event, previous.Payload)
+	if err := uc.repo.CreateDeliveries(ctx, []domain.Delivery{delivery}); err != nil {
+		return nil, err
+	}
+	uc.kick()
+	return &delivery, nil
+}
+
+// Enqueue queues a delivery of a project event for every subscribed webhook.
+// Events that cannot be subscribed to are ignored. It is called by the event
+// dispatcher, which may hand the same event over again: an event that already
+// has deliveries is not queued twice.
+func (uc *WebhookUsecase) Enqueue(ctx context.Context, eventID, ideaID uuid.UUID, eventType string, occurredAt time.Time, data json.RawMessage) error {
+	if !domain.IsEvent(eventType) {
+		return nil
+	}
+	queued, err := uc.repo.HasDeliveries(ctx, eventID)
+	if err != nil || queued {
+		return err
+	}
+
+	ownerID, workspaceID, err := uc.projects.Scope(ctx, ideaID)
+	if errors.Is(err, domain.ErrProjectNotFound) {
+		// Deleted since the event happened: nobody can read it anymore
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	hooks, err := uc.repo.FindSubscribed(ctx, eventType, ownerID, workspaceID)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	payload, err := envelope(eventID, eventType, &ideaID, occurredAt, data)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []domain.Delivery
+	for _, hook := range hooks {
+		// A creator who lost access to the project stops receiving its events
+		if hook.UserID != nil {
+			ok, err := uc.access.CanRead(ctx, *hook.UserID, ideaID)
+			if err != nil || !ok {
+				continue
+			}
+		}
+		deliveries = append(deliveries, newDelivery(hook.ID, eventID, eventType, payload))
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := uc.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+	uc.kick()
+	return nil
+}
+
+// Run sends due deliveries until ctx is done. Every API instance can run it:
+// deliveries are claimed with a lease so each one is sent by a single instance.
+func (uc *WebhookUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		uc.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-uc.wake:
+		}
+	}
+}
+
+func (uc *WebhookUsecase) deliverDue(ctx context.Context) {
+	deliveries, err := uc.repo.ClaimDue(ctx, claimBatch, claimLease)
+	if err != nil {
+		log.Printf("webhook: error claiming deliveries: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(d *domain.Delivery) {
+			defer wg.Done()
+			uc.deliver(ctx, d)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+}
+
+func (uc *WebhookUsecase) deliver(ctx context.Context, d *domain.Delivery) {
+	attempt := &domain.Attempt{ID: uuid.New(), DeliveryID: d.ID, AttemptedAt: time.Now()}
+	refused := false
+
+	hook, err := uc.repo.Get(ctx, d.WebhookID)
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case !hook.Active:
+		attempt.Error = "webhook is disabled"
+	default:
+		headers := map[string]string{
+			"Content-Type":         "application/json",
+			"User-Agent":           "IdeaForge-Webhooks/1.0",
+			domain.HeaderEvent:     d.Event,
+			domain.HeaderDelivery:  d.ID.String(),
+			domain.HeaderSignature: domain.Sign(hook.Secret, attempt.AttemptedAt, d.Payload),
+		}
+		status, body, err := uc.sender.Send(ctx, hook.URL, headers, d.Payload)
+		attempt.StatusCode, attempt.ResponseBody = status, body
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		if errors.Is(err, domain.ErrPrivateTarget) {
+			// Nothing from an internal address reaches the log, and retrying will not help
+			attempt.StatusCode, attempt.ResponseBody = 0, ""
+			refused = true
+		}
+	}
+	attempt.DurationMs = time.Since(attempt.AttemptedAt).Milliseconds()
+
+	d.Attempts++
+	switch {
+	case attempt.Succeeded():
+		d.Status, d.NextAttemptAt = domain.StatusSucceeded, nil
+		d.DeliveredAt = &attempt.AttemptedAt
+	case refused || d.Attempts >= domain.MaxAttempts:
+		d.Status, d.NextAttemptAt = domain.StatusFailed, nil
+	default:
+		next := time.Now().Add(domain.RetryDelay(d.Attempts))
+		d.Status, d.NextAttemptAt = domain.StatusPending, &next
+	}
+
+	if err := uc.repo.RecordAttempt(ctx, d, attempt); err != nil {
+		log.Printf("webhook: error recording attempt of delivery %s: %v", d.ID, err)
+	}
+}
+
+func (uc *WebhookUsecase) kick() {
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+}
+
+// authorize returns the webhook if the user manages it: its owner, or a workspace owner
+func (uc *WebhookUsecase) authorize(ctx context.Context, userID, webhookID uuid.UUID) (*domain.Webhook, error) {
+	hook, err := uc.repo.Get(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if hook.WorkspaceID != nil {
+		if err := uc.checkWorkspace(ctx, userID, *hook.WorkspaceID); err != nil {
+			return nil, err
+		}
+	} else if hook.UserID == nil || *hook.UserID != userID {
+		return nil, domain.ErrWebhookNotFound
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+func (uc *WebhookUsecase) checkWorkspace(ctx context.Context, userID, workspaceID uuid.UUID) error {
+	ok, err := uc.access.CanManageWorkspace(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func newDelivery(webhookID, eventID uuid.UUID, event string, payload json.RawMessage) domain.Delivery {
+	now := time.Now()
+	return domain.Delivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		Event:         event,
+		Payload:       payload,
+		Status:        domain.StatusPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+}
+
+// envelope builds the JSON body sent to the endpoints
+func envelope(eventID uuid.UUID, event string, ideaID *uuid.UUID, createdAt time.Time, data any) (json.RawMessage, error) {
+	return json.Marshal(struct {
+		ID        uuid.UUID  `json:"id"`
+		Event     string     `json:"event"`
+		IdeaID    *uuid.UUID `json:"idea_id,omitempty"`
+		CreatedAt time.Time  `json:"created_at"`
+		Data      any        `json:"data"`
+	}{eventID, event, ideaID, createdAt.UTC(), data})
+}
+
+// validateURL rejects URLs that are not absolute http(s) and, unless private
+// targets are allowed, those naming localhost or a non-public IP. Hostnames
+// are checked again on the resolved address by the sender.
+func (uc *WebhookUsecase) validateURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", domain.ErrInvalidURL
+	}
+	if !uc.allowPrivate {
+		host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return "", domain.ErrPrivateTarget
+		}
+		if addr, err := netip.ParseAddr(host); err == nil && !domain.IsPublicAddr(addr) {
+			return "", domain.ErrPrivateTarget
+		}
+	}
+	return u.String(), nil
+}
+
+func validateEvents(events []string) ([]string, error) {
+	seen := make(map[string]bool)
+	out := []string{}
+	for _, e := range events {
+		if !domain.IsEvent(e) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidEvent, e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,306 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/dark/idea-forge/internal/middleware"
+	"github.com/dark/idea-forge/internal/workspace/domain"
+	"github.com/dark/idea-forge/internal/workspace/usecase"
+	"github.com/google/uuid"
+)
+
+// Handlers exposes workspaces, members and invitations. Must be registered behind the auth middleware.
+type Handlers struct {
+	Usecase *usecase.WorkspaceUsecase
+}
+
+func (h *Handlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /workspaces", h.listWorkspaces)
+	mux.HandleFunc("POST /workspaces", h.createWorkspace)
+	mux.HandleFunc("GET /workspaces/{id}", h.getWorkspace)
+	mux.HandleFunc("PUT /workspaces/{id}", h.renameWorkspace)
+	mux.HandleFunc("DELETE /workspaces/{id}", h.deleteWorkspace)
+
+	mux.HandleFunc("GET /workspaces/{id}/members", h.listMembers)
+	mux.HandleFunc("PUT /workspaces/{id}/members/{userID}", h.changeMemberRole)
+	mux.HandleFunc("DELETE /workspaces/{id}/members/{userID}", h.removeMember)
+
+	mux.HandleFunc("GET /workspaces/{id}/invitations", h.listInvitations)
+	mux.HandleFunc("POST /workspaces/{id}/invitations", h.invite)
+	mux.HandleFunc("DELETE /workspaces/{id}/invitations/{invitationID}", h.revokeInvitation)
+	mux.HandleFunc("POST /workspaces/invitations/accept", h.acceptInvitation)
+}
+
+func (h *Handlers) listWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	workspaces, err := h.Usecase.List(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, workspaces, http.StatusOK)
+}
+
+func (h *Handlers) createWorkspace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := h.Usecase.Create(r.Context(), userID, in.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, ws, http.StatusCreated)
+}
+
+func (h *Handlers) getWorkspace(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	ws, err := h.Usecase.Get(r.Context(), userID, workspaceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, ws, http.StatusOK)
+}
+
+func (h *Handlers) renameWorkspace(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := h.Usecase.Rename(r.Context(), userID, workspaceID, in.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, ws, http.StatusOK)
+}
+
+func (h *Handlers) deleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Usecase.Delete(r.Context(), userID, workspaceID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) listMembers(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.Usecase.ListMembers(r.Context(), userID, workspaceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, members, http.StatusOK)
+}
+
+func (h *Handlers) changeMemberRole(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	memberID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	var in struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Usecase.ChangeMemberRole(r.Context(), userID, workspaceID, memberID, in.Role); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) removeMember(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	memberID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Usecase.RemoveMember(r.Context(), userID, workspaceID, memberID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) listInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.Usecase.ListInvitations(r.Context(), userID, workspaceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, invitations, http.StatusOK)
+}
+
+func (h *Handlers) invite(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	inv, err := h.Usecase.Invite(r.Context(), userID, workspaceID, in.Email, in.Role)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, inv, http.StatusCreated)
+}
+
+func (h *Handlers) revokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, workspaceID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	invitationID, err := uuid.Parse(r.PathValue("invitationID"))
+	if err != nil {
+		http.Error(w, "invalid invitation id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Usecase.RevokeInvitation(r.Context(), userID, workspaceID, invitationID); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) acceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.Token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := h.Usecase.AcceptInvitation(r.Context(), userID, in.Token)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, ws, http.StatusOK)
+}
+
+// parseIDs returns the authenticated user and the workspace from the path
+func parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	workspaceID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid workspace id", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, workspaceID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWorkspaceNotFound),
+		errors.Is(err, domain.ErrMemberNotFound),
+		errors.Is(err, domain.ErrInvitationNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidName),
+		errors.Is(err, domain.ErrInvalidRole),
+		errors.Is(err, domain.ErrInvalidEmail):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrInvitationForAnother):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrLastOwner),
+		errors.Is(err, domain.ErrPersonalWorkspace),
+		errors.Is(err, domain.ErrWorkspaceNotEmpty),
+		errors.Is(err, domain.ErrAlreadyMember),
+		errors.Is(err, domain.ErrInvitationAccepted):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrInvitationExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+	default:
+		log.Printf("workspace error: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,288 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dark/idea-forge/internal/workspace/domain"
+	"github.com/dark/idea-forge/internal/workspace/port"
+	"github.com/google/uuid"
+)
+
+type repo struct{ db *sql.DB }
+
+func NewRepo(db *sql.DB) port.WorkspaceRepository { return &repo{db: db} }
+
+const workspaceColumns = `w.id, w.name, w.personal, w.created_by, w.created_at, w.updated_at`
+
+const invitationColumns = `id, workspace_id, email, role, token, invited_by, expires_at, accepted_at, created_at`
+
+func (r *repo) Create(ctx context.Context, ws *domain.Workspace) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO workspaces (id, name, personal, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, updated_at
+	`, ws.ID, ws.Name, ws.Personal, ws.CreatedBy).Scan(&ws.CreatedAt, &ws.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`, ws.ID, ws.CreatedBy, domain.RoleOwner); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Workspace, error) {
+	return r.getWorkspace(ctx, `SELECT `+workspaceColumns+` FROM workspaces w WHERE w.id = $1`, id)
+}
+
+func (r *repo) GetPersonal(ctx context.Context, userID uuid.UUID) (*domain.Workspace, error) {
+	return r.getWorkspace(ctx, `SELECT `+workspaceColumns+` FROM workspaces w WHERE w.created_by = $1 AND w.personal`, userID)
+}
+
+func (r *repo) getWorkspace(ctx context.Context, query string, arg any) (*domain.Workspace, error) {
+	var ws domain.Workspace
+	var createdBy uuid.NullUUID
+	err := r.db.QueryRowContext(ctx, query, arg).
+		Scan(&ws.ID, &ws.Name, &ws.Personal, &createdBy, &ws.CreatedAt, &ws.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	ws.CreatedBy = createdBy.UUID
+	return &ws, nil
+}
+
+func (r *repo) ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Workspace, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+workspaceColumns+`, m.role
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1
+		ORDER BY w.personal DESC, w.name
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workspaces := []domain.Workspace{}
+	for rows.Next() {
+		var ws domain.Workspace
+		var createdBy uuid.NullUUID
+		if err := rows.Scan(&ws.ID, &ws.Name, &ws.Personal, &createdBy, &ws.CreatedAt, &ws.UpdatedAt, &ws.Role); err != nil {
+			return nil, err
+		}
+		ws.CreatedBy = createdBy.UUID
+		workspaces = append(workspaces, ws)
+	}
+	return workspaces, rows.Err()
+}
+
+func (r *repo) Rename(ctx context.Context, id uuid.UUID, name string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE workspaces SET name = $2, updated_at = NOW() WHERE id = $1`, id, name)
+	if err != nil {
+		return err
+	}
+	return expectRow(res, domain.ErrWorkspaceNotFound)
+}
+
+func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
+	// Los miembros e invitaciones se eliminan en cascada
+	res, err := r.db.ExecContext(ctx, `DELETE FROM workspaces WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return expectRow(res, domain.ErrWorkspaceNotFound)
+}
+
+func (r *repo) CountIdeas(ctx context.Context, id uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM ideation_ideas WHERE workspace_id = $1`, id).Scan(&n)
+	return n, err
+}
+
+func (r *repo) GetMemberRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error) {
+	var role string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2
+	`, workspaceID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrMemberNotFound
+	}
+	return role, err
+}
+
+func (r *repo) ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]domain.Member, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.workspace_id, m.user_id, u.username, u.email, m.role, m.created_at
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY m.created_at
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []domain.Member{}
+	for rows.Next() {
+		var m domain.Member
+		if err := rows.Scan(&m.WorkspaceID, &m.UserID, &m.Username, &m.Email, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (r *repo) UpdateMemberRole(ctx context.Context, workspaceID, userID uuid.UUID, role string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2
+	`, workspaceID, userID, role)
+	if err != nil {
+		return err
+	}
+	return expectRow(res, domain.ErrMemberNotFound)
+}
+
+func (r *repo) RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2
+	`, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	return expectRow(res, domain.ErrMemberNotFound)
+}
+
+func (r *repo) CountOwners(ctx context.Context, workspaceID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1 AND role = $2
+	`, workspaceID, domain.RoleOwner).Scan(&n)
+	return n, err
+}
+
+func (r *repo) CreateInvitation(ctx context.Context, inv *domain.Invitation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO workspace_invitations (id, workspace_id, email, role, token, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, inv.ID, inv.WorkspaceID, inv.Email, inv.Role, inv.Token, inv.InvitedBy, inv.ExpiresAt, inv.CreatedAt)
+	return err
+}
+
+func (r *repo) ListPendingInvitations(ctx context.Context, workspaceID uuid.UUID) ([]domain.Invitation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+invitationColumns+`
+		FROM workspace_invitations
+		WHERE workspace_id = $1 AND accepted_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []domain.Invitation{}
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *inv)
+	}
+	return invitations, rows.Err()
+}
+
+func (r *repo) GetInvitationByToken(ctx context.Context, token string) (*domain.Invitation, error) {
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx, `
+		SELECT `+invitationColumns+` FROM workspace_invitations WHERE token = $1
+	`, token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvitationNotFound
+	}
+	return inv, err
+}
+
+func (r *repo) DeleteInvitation(ctx context.Context, workspaceID, invitationID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM workspace_invitations WHERE id = $1 AND workspace_id = $2
+	`, invitationID, workspaceID)
+	if err != nil {
+		return err
+	}
+	return expectRow(res, domain.ErrInvitationNotFound)
+}
+
+func (r *repo) AcceptInvitation(ctx context.Context, inv *domain.Invitation, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// accepted_at IS NULL evita que dos requests concurrentes usen la misma invitación
+	res, err := tx.ExecContext(ctx, `
+		UPDATE workspace_invitations SET accepted_at = NOW() WHERE id = $1 AND accepted_at IS NULL
+	`, inv.ID)
+	if err != nil {
+		return err
+	}
+	if err := expectRow(res, domain.ErrInvitationAccepted); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id, user_id) DO NOTHING
+	`, inv.WorkspaceID, userID, inv.Role); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInvitation(row rowScanner) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	var acceptedAt sql.NullTime
+	if err := row.Scan(&inv.ID, &inv.WorkspaceID, &inv.Email, &inv.Role, &inv.Token, &inv.InvitedBy, &inv.ExpiresAt, &acceptedAt, &inv.CreatedAt); err != nil {
+		return nil, err
+	}
+	if acceptedAt.Valid {
+		t := acceptedAt.Time
+		inv.AcceptedAt = &t
+	}
+	return &inv, nil
+}
+
+func expectRow(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Member roles inside a workspace
+const (
+	RoleOwner  = "owner"  // manages members, invitations and the workspace itself
+	RoleEditor = "editor" // creates and edits projects
+	RoleViewer = "viewer" // read-only access to projects
+)
+
+// PersonalWorkspaceName is the name given to the workspace every user gets by default
+const PersonalWorkspaceName = "Personal"
+
+var (
+	ErrWorkspaceNotFound    = errors.New("workspace not found")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrForbidden            = errors.New("only workspace owners can do this")
+	ErrInvalidName          = errors.New("workspace name is required (max 100 chars)")
+	ErrInvalidRole          = errors.New("invalid role")
+	ErrInvalidEmail         = errors.New("invalid email")
+	ErrLastOwner            = errors.New("a workspace needs at least one owner")
+	ErrPersonalWorkspace    = errors.New("personal workspaces cannot be deleted or shared")
+	ErrWorkspaceNotEmpty    = errors.New("workspace still has ideas")
+	ErrAlreadyMember        = errors.New("user is already a member")
+	ErrInvitationExpired    = errors.New("invitation expired")
+	ErrInvitationAccepted   = errors.New("invitation already accepted")
+	ErrInvitationForAnother = errors.New("invitation was sent to a different email")
+)
+
+// Workspace groups ideas shared by its members
+type Workspace struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Personal  bool      `json:"personal"`
+	CreatedBy uuid.UUID `json:"created_by"`     // uuid.Nil once the creator deleted their account
+	Role      string    `json:"role,omitempty"` // role of the requesting user
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Member is a user with a role in a workspace
+type Member struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+// Invitation lets someone join a workspace by email
+type Invitation struct {
+	ID          uuid.UUID  `json:"id"`
+	WorkspaceID uuid.UUID  `json:"workspace_id"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Token       string     `json:"-"`
+	InvitedBy   uuid.UUID  `json:"invited_by"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// User is the subset of a user account the workspace module needs
+type User struct {
+	ID       uuid.UUID
+	Username string
+	Email    string
+}
+
+// IsValidRole reports whether role is a known member role
+func IsValidRole(role string) bool {
+	return role == RoleOwner || role == RoleEditor || role == RoleViewer
+}
+
+// IsExpired reports whether the invitation can no longer be accepted
+func (i *Invitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
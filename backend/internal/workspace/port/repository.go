@@ -0,0 +1,44 @@
+package port
+
+import (
+	"context"
+
+	"github.com/dark/idea-forge/internal/workspace/domain"
+	"github.com/google/uuid"
+)
+
+// WorkspaceRepository defines persistence for workspaces, members and invitations
+type WorkspaceRepository interface {
+	// Create stores the workspace and adds its creator as owner
+	Create(ctx context.Context, ws *domain.Workspace) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Workspace, error)
+	GetPersonal(ctx context.Context, userID uuid.UUID) (*domain.Workspace, error)
+	// ListForUser returns the workspaces the user belongs to, with the user's role
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Workspace, error)
+	Rename(ctx context.Context, id uuid.UUID, name string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CountIdeas(ctx context.Context, id uuid.UUID) (int, error)
+
+	GetMemberRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error)
+	ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]domain.Member, error)
+	UpdateMemberRole(ctx context.Context, workspaceID, userID uuid.UUID, role string) error
+	RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	CountOwners(ctx context.Context, workspaceID uuid.UUID) (int, error)
+
+	CreateInvitation(ctx context.Context, inv *domain.Invitation) error
+	ListPendingInvitations(ctx context.Context, workspaceID uuid.UUID) ([]domain.Invitation, error)
+	GetInvitationByToken(ctx context.Context, token string) (*domain.Invitation, error)
+	DeleteInvitation(ctx context.Context, workspaceID, invitationID uuid.UUID) error
+	// AcceptInvitation marks the invitation as accepted and adds the user as member
+	AcceptInvitation(ctx context.Context, inv *domain.Invitation, userID uuid.UUID) error
+}
+
+// UserDirectory looks up user accounts
+type UserDirectory interface {
+	GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error)
+}
+
+// InvitationSender emails workspace invitations
+type InvitationSender interface {
+	SendWorkspaceInvitation(ctx context.Context, email, inviterName, workspaceName, acceptLink string) error
+}
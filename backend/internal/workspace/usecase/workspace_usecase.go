@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dark/idea-forge/internal/workspace/domain"
+	"github.com/dark/idea-forge/internal/workspace/port"
+)
+
+const (
+	maxNameLength  = 100
+	invitationTTL  = 7 * 24 * time.Hour
+	acceptLinkPath = "/workspaces/invitations/accept?token="
+)
+
+// WorkspaceUsecase manages workspaces, their members and invitations
+type WorkspaceUsecase struct {
+	repo        port.WorkspaceRepository
+	users       port.UserDirectory
+	sender      port.InvitationSender
+	frontendURL string
+}
+
+// NewWorkspaceUsecase creates a new workspace use case
+func NewWorkspaceUsecase(repo port.WorkspaceRepository, users port.UserDirectory, sender port.InvitationSender, frontendURL string) *WorkspaceUsecase {
+	return &WorkspaceUsecase{repo: repo, users: users, sender: sender, frontendURL: frontendURL}
+}
+
+// Create creates a shared workspace owned by userID
+func (uc *WorkspaceUsecase) Create(ctx context.Context, userID uuid.UUID, name string) (*domain.Workspace, error) {
+	name, err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := &domain.Workspace{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedBy: userID,
+		Role:      domain.RoleOwner,
+	}
+	if err := uc.repo.Create(ctx, ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+// List returns the workspaces the user belongs to, creating the personal one if missing
+func (uc *WorkspaceUsecase) List(ctx context.Context, userID uuid.UUID) ([]domain.Workspace, error) {
+	if _, err := uc.PersonalWorkspaceID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListForUser(ctx, userID)
+}
+
+// Get returns a workspace the user belongs to
+func (uc *WorkspaceUsecase) Get(ctx context.Context, userID, workspaceID uuid.UUID) (*domain.Workspace, error) {
+	role, err := uc.memberRole(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := uc.repo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	ws.Role = role
+	return ws, nil
+}
+
+// Rename changes the name of a workspace (owners only)
+func (uc *WorkspaceUsecase) Rename(ctx context.Context, userID, workspaceID uuid.UUID, name string) (*domain.Workspace, error) {
+	name, err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requireOwner(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Rename(ctx, workspaceID, name); err != nil {
+		return nil, err
+	}
+	return uc.Get(ctx, userID, workspaceID)
+}
+
+// Delete removes an empty shared workspace (owners only)
+func (uc *WorkspaceUsecase) Delete(ctx context.Context, userID, workspaceID uuid.UUID) error {
+	if err := uc.requireOwner(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+	ws, err := uc.repo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if ws.Personal {
+		return domain.ErrPersonalWorkspace
+	}
+	ideas, err := uc.repo.CountIdeas(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if ideas > 0 {
+		return domain.ErrWorkspaceNotEmpty
+	}
+	return uc.repo.Delete(ctx, workspaceID)
+}
+
+// ListMembers lists the members of a workspace the user belongs to
+func (uc *WorkspaceUsecase) ListMembers(ctx context.Context, userID, workspaceID uuid.UUID) ([]domain.Member, error) {
+	if _, err := uc.memberRole(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListMembers(ctx, workspaceID)
+}
+
+// ChangeMemberRole changes the role of a member (owners only)
+func (uc *WorkspaceUsecase) ChangeMemberRole(ctx context.Context, userID, workspaceID, memberID uuid.UUID, role string) error {
+	if !domain.IsValidRole(role) {
+		return domain.ErrInvalidRole
+	}
+	if err := uc.requireOwner(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+	current, err := uc.repo.GetMemberRole(ctx, workspaceID, memberID)
+	if err != nil {
+		return err
+	}
+	if current == domain.RoleOwner && role != domain.RoleOwner {
+		if err := uc.ensureAnotherOwner(ctx, workspaceID); err != nil {
+			return err
+		}
+	}
+	return uc.repo.UpdateMemberRole(ctx, workspaceID, memberID, role)
+}
+
+// RemoveMember removes a member. Owners can remove anyone; any member can leave.
+func (uc *WorkspaceUsecase) RemoveMember(ctx context.Context, userID, workspaceID, memberID uuid.UUID) error {
+	if memberID != userID {
+		if err := uc.requireOwner(ctx, workspaceID, userID); err != nil {
+			return err
+		}
+	}
+	role, err := uc.repo.GetMemberRole(ctx, workspaceID, memberID)
+	if err != nil {
+		return err
+	}
+	if role == domain.RoleOwner {
+		if err := uc.ensureAnotherOwner(ctx, workspaceID); err != nil {
+			return err
+		}
+	}
+	return uc.repo.RemoveMember(ctx, workspaceID, memberID)
+}
+
+// Invite emails an invitation to join the workspace (owners only)
+func (uc *WorkspaceUsecase) Invite(ctx context.Context, userID, workspaceID uuid.UUID, email, role string) (*domain.Invitation, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, domain.ErrInvalidEmail
+	}
+	if role == "" {
+		role = domain.RoleEditor
+	}
+	if !domain.IsValidRole(role) {
+		return nil, domain.ErrInvalidRole
+	}
+	if err := uc.requireOwner(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	ws, err := uc.repo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if ws.Personal {
+		return nil, domain.ErrPersonalWorkspace
+	}
+	inviter, err := uc.users.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &domain.Invitation{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		Email:       email,
+		Role:        role,
+		Token:       uuid.New().String(),
+		InvitedBy:   userID,
+		ExpiresAt:   time.Now().Add(invitationTTL),
+		CreatedAt:   time.Now(),
+	}
+	if err := uc.repo.CreateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	link := uc.frontendURL + acceptLinkPath + inv.Token
+	if err := uc.sender.SendWorkspaceInvitation(ctx, email, inviter.Username, ws.Name, link); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// ListInvitations lists pending invitations of a workspace (owners only)
+func (uc *WorkspaceUsecase) ListInvitations(ctx context.Context, userID, workspaceID uuid.UUID) ([]domain.Invitation, error) {
+	if err := uc.requireOwner(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListPendingInvitations(ctx, workspaceID)
+}
+
+// RevokeInvitation deletes a pending invitation (owners only)
+func (uc *WorkspaceUsecase) RevokeInvitation(ctx context.Context, userID, workspaceID, invitationID uuid.UUID) error {
+	if err := uc.requireOwner(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+	return uc.repo.DeleteInvitation(ctx, workspaceID, invitationID)
+}
+
+// AcceptInvitation joins the user to the workspace of the invitation.
+// The invitation must have been sent to the user's email.
+func (uc *WorkspaceUsecase) AcceptInvitation(ctx context.Context, userID uuid.UUID, token string) (*domain.Workspace, error) {
+	inv, err := uc.repo.GetInvitationByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if inv.AcceptedAt != nil {
+		return nil, domain.ErrInvitationAccepted
+	}
+	if inv.IsExpired() {
+		return nil, domain.ErrInvitationExpired
+	}
+
+	user, err := uc.users.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, inv.Email) {
+		return nil, domain.ErrInvitationForAnother
+	}
+
+	if _, err := uc.repo.GetMemberRole(ctx, inv.WorkspaceID, userID); err == nil {
+		return nil, domain.ErrAlreadyMember
+	} else if !errors.Is(err, domain.ErrMemberNotFound) {
+		return nil, err
+	}
+
+	if err := uc.repo.AcceptInvitation(ctx, inv, userID); err != nil {
+		return nil, err
+	}
+	return uc.Get(ctx, userID, inv.WorkspaceID)
+}
+
+// PersonalWorkspaceID returns the personal workspace of the user, creating it on first use
+func (uc *WorkspaceUsecase) PersonalWorkspaceID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	ws, err := uc.repo.GetPersonal(ctx, userID)
+	if err == nil {
+		return ws.ID, nil
+	}
+	if !errors.Is(err, domain.ErrWorkspaceNotFound) {
+		return uuid.Nil, err
+	}
+
+	ws = &domain.Workspace{
+		ID:        uuid.New(),
+		Name:      domain.PersonalWorkspaceName,
+		Personal:  true,
+		CreatedBy: userID,
+	}
+	if err := uc.repo.Create(ctx, ws); err != nil {
+		return uuid.Nil, err
+	}
+	return ws.ID, nil
+}
+
+// memberRole returns the role of the user, hiding workspaces they do not belong to
+func (uc *WorkspaceUsecase) memberRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error) {
+	role, err := uc.repo.GetMemberRole(ctx, workspaceID, userID)
+	if errors.Is(err, domain.ErrMemberNotFound) {
+		return "", domain.ErrWorkspaceNotFound
+	}
+	return role, err
+}
+
+func (uc *WorkspaceUsecase) requireOwner(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	role, err := uc.memberRole(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if role != domain.RoleOwner {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func (uc *WorkspaceUsecase) ensureAnotherOwner(ctx context.Context, workspaceID uuid.UUID) error {
+	owners, err := uc.repo.CountOwners(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOwner
+	}
+	return nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return "", domain.ErrInvalidName
+	}
+	return name, nil
+}
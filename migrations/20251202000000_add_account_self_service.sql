@@ -0,0 +1,16 @@
+-- +goose Up
+-- Tabla de solicitudes de cambio de email (el código se envía a la nueva dirección)
+CREATE TABLE email_change_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email VARCHAR(255) NOT NULL,
+    code VARCHAR(6) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_email_change_user_id ON email_change_requests(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS email_change_requests;
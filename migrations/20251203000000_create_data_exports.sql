@@ -0,0 +1,16 @@
+-- +goose Up
+-- Archivos de exportación de datos personales (descarga con token de tiempo limitado)
+CREATE TABLE data_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(255) UNIQUE NOT NULL,
+    archive BYTEA NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_data_exports_token ON data_exports(token);
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS data_exports;
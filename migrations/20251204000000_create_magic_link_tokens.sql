@@ -0,0 +1,16 @@
+-- +goose Up
+-- Tabla de magic links (inicio de sesión sin contraseña, un solo uso)
+CREATE TABLE magic_link_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(255) UNIQUE NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_magic_link_token ON magic_link_tokens(token);
+CREATE INDEX idx_magic_link_user_id ON magic_link_tokens(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS magic_link_tokens;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Logins OIDC en curso: state, nonce y verificador PKCE (se eliminan al usarse)
+CREATE TABLE oidc_auth_states (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    state VARCHAR(255) UNIQUE NOT NULL,
+    provider VARCHAR(100) NOT NULL,
+    nonce VARCHAR(255) NOT NULL,
+    code_verifier VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+-- Identidades externas vinculadas a usuarios (provider + subject del ID token)
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(100) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS oidc_auth_states;
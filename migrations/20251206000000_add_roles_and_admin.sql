@@ -0,0 +1,33 @@
+-- +goose Up
+-- Roles de usuario (user, admin)
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user';
+CREATE INDEX idx_users_role ON users(role);
+
+-- Registro de auditoría de acciones de administradores
+CREATE TABLE admin_audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    target_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    details JSONB,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_admin_audit_created_at ON admin_audit_log(created_at DESC);
+CREATE INDEX idx_admin_audit_target ON admin_audit_log(target_user_id);
+
+-- Llamadas a la IA (Genkit) atribuidas a cada usuario
+CREATE TABLE ai_usage_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endpoint VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_ai_usage_user_created ON ai_usage_events(user_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS ai_usage_events;
+DROP TABLE IF EXISTS admin_audit_log;
+DROP INDEX IF EXISTS idx_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,62 @@
+-- +goose Up
+-- Workspaces (organizaciones) que agrupan ideas compartidas por sus miembros
+CREATE TABLE workspaces (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    personal BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+-- Un único workspace personal por usuario
+CREATE UNIQUE INDEX idx_workspaces_personal ON workspaces(created_by) WHERE personal;
+
+-- Miembros con su rol (owner, editor, viewer)
+CREATE TABLE workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner','editor','viewer')),
+    created_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user ON workspace_members(user_id);
+
+-- Invitaciones por email, aceptadas con token
+CREATE TABLE workspace_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner','editor','viewer')),
+    token VARCHAR(255) UNIQUE NOT NULL,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_workspace_invitations_workspace ON workspace_invitations(workspace_id);
+
+-- Las ideas pertenecen a un workspace
+ALTER TABLE ideation_ideas ADD COLUMN workspace_id UUID REFERENCES workspaces(id);
+CREATE INDEX idx_ideas_workspace_id ON ideation_ideas(workspace_id);
+
+-- Workspace personal para cada usuario existente, con sus ideas
+INSERT INTO workspaces (name, personal, created_by)
+SELECT 'Personal', TRUE, id FROM users;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT id, created_by, 'owner' FROM workspaces WHERE personal;
+
+UPDATE ideation_ideas i
+SET workspace_id = w.id
+FROM workspaces w
+WHERE w.personal AND w.created_by = i.user_id;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ideas_workspace_id;
+ALTER TABLE ideation_ideas DROP COLUMN IF EXISTS workspace_id;
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
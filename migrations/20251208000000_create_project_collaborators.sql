@@ -0,0 +1,15 @@
+-- +goose Up
+-- Accesos a un proyecto puntual (idea, plan, arquitectura y módulos) fuera del workspace
+CREATE TABLE project_collaborators (
+    idea_id UUID NOT NULL REFERENCES ideation_ideas(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('editor','viewer')),
+    added_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (idea_id, user_id)
+);
+
+CREATE INDEX idx_project_collaborators_user ON project_collaborators(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS project_collaborators;
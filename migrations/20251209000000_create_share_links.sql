@@ -0,0 +1,17 @@
+-- +goose Up
+-- Links públicos de solo lectura a un proyecto (firmados, revocables, con expiración y contraseña opcionales)
+CREATE TABLE share_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    idea_id UUID NOT NULL REFERENCES ideation_ideas(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    password_hash VARCHAR(255),
+    include_chats BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_share_links_idea ON share_links(idea_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS share_links;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Hilos de comentarios anclados a una sección (stage.section) y opcionalmente a un rango de caracteres
+CREATE TABLE comment_threads (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    idea_id UUID NOT NULL REFERENCES ideation_ideas(id) ON DELETE CASCADE,
+    anchor VARCHAR(100) NOT NULL,
+    range_start INTEGER,
+    range_end INTEGER,
+    quoted_text TEXT NOT NULL DEFAULT '',
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    outdated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_comment_threads_idea_anchor ON comment_threads(idea_id, anchor);
+
+-- Comentarios de un hilo (el primero abre el hilo, el resto son respuestas)
+CREATE TABLE comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    thread_id UUID NOT NULL REFERENCES comment_threads(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_comments_thread ON comments(thread_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS comment_threads;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- Outbox de eventos de dominio: cada cambio de un proyecto registra sus eventos en la
+-- misma transacción y el dispatcher los reparte (tiempo real, webhooks). Las filas
+-- despachadas se conservan como historial del proyecto, incluso si se elimina.
+CREATE TABLE outbox_events (
+    seq BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE,
+    event_type VARCHAR(100) NOT NULL,
+    idea_id UUID NOT NULL,
+    actor_id UUID,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    pending_subscribers JSONB,
+    last_error TEXT NOT NULL DEFAULT '',
+    dispatched_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_outbox_events_idea ON outbox_events(idea_id, seq);
+
+-- Los webhooks consultan si un evento ya generó entregas antes de encolarlo
+CREATE INDEX idx_webhook_deliveries_event ON webhook_deliveries(event_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Origen de cada evento del outbox: user, agent, system o la fuente de una propagación (p. ej. global_chat)
+ALTER TABLE outbox_events ADD COLUMN source VARCHAR(50) NOT NULL DEFAULT 'system';
+
+-- Historial de cambios de cada proyecto (quién o qué cambió qué y cuándo), derivado de los eventos de dominio
+CREATE TABLE project_activity (
+    id UUID PRIMARY KEY,
+    idea_id UUID NOT NULL REFERENCES ideation_ideas(id) ON DELETE CASCADE,
+    action VARCHAR(100) NOT NULL,
+    stage VARCHAR(50) NOT NULL,
+    field VARCHAR(255) NOT NULL DEFAULT '',
+    summary TEXT NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    actor_id UUID,
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_project_activity_idea ON project_activity(idea_id, occurred_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS project_activity;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS source;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Preferencias de notificación por usuario (los defaults deben coincidir con domain.DefaultPreferences)
+CREATE TABLE notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    comment_mentions VARCHAR(20) NOT NULL DEFAULT 'instant',   -- instant, digest, off
+    collaborator_added VARCHAR(20) NOT NULL DEFAULT 'instant', -- instant, digest, off
+    stale_stages BOOLEAN NOT NULL DEFAULT TRUE,
+    stale_modules BOOLEAN NOT NULL DEFAULT TRUE,
+    stale_after_days INTEGER NOT NULL DEFAULT 7,
+    digest VARCHAR(20) NOT NULL DEFAULT 'weekly',              -- daily, weekly, off
+    last_digest_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Notificaciones en espera del próximo resumen
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    idea_id UUID REFERENCES ideation_ideas(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    link TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_preferences;
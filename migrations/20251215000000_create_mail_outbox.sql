@@ -0,0 +1,36 @@
+-- +goose Up
+-- Emails encolados: se renderizan al encolarse y un worker los envía con reintentos.
+-- status: pending, sent o dead (agotó los intentos o el servidor los rechazó)
+CREATE TABLE mail_outbox (
+    id UUID PRIMARY KEY,
+    to_address VARCHAR(320) NOT NULL,
+    template VARCHAR(100) NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    subject TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_mail_outbox_due ON mail_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_mail_outbox_dead ON mail_outbox(created_at) WHERE status = 'dead';
+
+-- Idioma de los emails de cada usuario
+ALTER TABLE users ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT 'es';
+
+-- Las notificaciones en espera guardan sus datos para renderizarlas en el idioma del destinatario
+ALTER TABLE notifications ADD COLUMN data JSONB NOT NULL DEFAULT '{}';
+UPDATE notifications SET data = jsonb_build_object('Text', text);
+ALTER TABLE notifications DROP COLUMN text;
+
+-- +goose Down
+ALTER TABLE notifications ADD COLUMN text TEXT NOT NULL DEFAULT '';
+UPDATE notifications SET text = COALESCE(data->>'Text', data->>'Project', '');
+ALTER TABLE notifications DROP COLUMN data;
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
+DROP TABLE IF EXISTS mail_outbox;
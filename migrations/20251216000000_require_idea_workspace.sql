@@ -0,0 +1,42 @@
+-- +goose Up
+-- Toda idea pertenece a un workspace: se completan las que quedaron sin él
+
+-- Workspace personal para los usuarios que aún no lo tienen
+INSERT INTO workspaces (name, personal, created_by)
+SELECT 'Personal', TRUE, u.id FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM workspaces w WHERE w.personal AND w.created_by = u.id);
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT id, created_by, 'owner' FROM workspaces WHERE personal AND created_by IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+UPDATE ideation_ideas i
+SET workspace_id = w.id
+FROM workspaces w
+WHERE i.workspace_id IS NULL AND w.personal AND w.created_by = i.user_id;
+
+-- Las ideas sin autor (anteriores a la autenticación) pasan a un workspace compartido de los admins
+WITH orphaned AS (
+    INSERT INTO workspaces (name, personal)
+    SELECT 'Ideas sin propietario', FALSE
+    WHERE EXISTS (SELECT 1 FROM ideation_ideas WHERE workspace_id IS NULL)
+    RETURNING id
+), owners AS (
+    INSERT INTO workspace_members (workspace_id, user_id, role)
+    SELECT o.id, u.id, 'owner' FROM orphaned o, users u WHERE u.role = 'admin'
+)
+UPDATE ideation_ideas SET workspace_id = (SELECT id FROM orphaned) WHERE workspace_id IS NULL;
+
+-- Un workspace con ideas no se puede borrar (hay que moverlas o borrarlas antes)
+ALTER TABLE ideation_ideas
+    DROP CONSTRAINT ideation_ideas_workspace_id_fkey,
+    ADD CONSTRAINT ideation_ideas_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE RESTRICT,
+    ALTER COLUMN workspace_id SET NOT NULL;
+
+-- +goose Down
+ALTER TABLE ideation_ideas
+    ALTER COLUMN workspace_id DROP NOT NULL,
+    DROP CONSTRAINT ideation_ideas_workspace_id_fkey,
+    ADD CONSTRAINT ideation_ideas_workspace_id_fkey
+        FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
@@ -0,0 +1,15 @@
+-- +goose Up
+-- El link de descarga de la exportación sirve una sola vez: al descargarla se borra el archivo.
+-- Las exportaciones vencidas se eliminan periódicamente.
+ALTER TABLE data_exports
+    ADD COLUMN downloaded_at TIMESTAMPTZ,
+    ALTER COLUMN archive DROP NOT NULL;
+
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_data_exports_expires_at;
+DELETE FROM data_exports WHERE archive IS NULL;
+ALTER TABLE data_exports
+    ALTER COLUMN archive SET NOT NULL,
+    DROP COLUMN IF EXISTS downloaded_at;
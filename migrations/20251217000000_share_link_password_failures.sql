@@ -0,0 +1,15 @@
+-- +goose Up
+-- Intentos fallidos de contraseña en links compartidos, para limitar la fuerza bruta por link y por IP.
+-- Se guardan en la base para que el límite valga entre todas las instancias de la API.
+CREATE TABLE share_link_password_failures (
+    id BIGSERIAL PRIMARY KEY,
+    link_id UUID NOT NULL REFERENCES share_links(id) ON DELETE CASCADE,
+    ip VARCHAR(45) NOT NULL,
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_share_link_password_failures_link ON share_link_password_failures(link_id, failed_at);
+CREATE INDEX idx_share_link_password_failures_ip ON share_link_password_failures(ip, failed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS share_link_password_failures;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Intentos de confirmación de cada solicitud de cambio de email, para limitar la fuerza bruta del código.
+-- La solicitud se borra al agotarlos y hay que pedir un código nuevo.
+ALTER TABLE email_change_requests ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE email_change_requests DROP COLUMN IF EXISTS attempts;
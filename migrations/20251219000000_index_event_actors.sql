@@ -0,0 +1,8 @@
+-- +goose Up
+-- Al eliminar una cuenta se quita su actor_id de los eventos y de la actividad
+CREATE INDEX idx_outbox_events_actor ON outbox_events(actor_id) WHERE actor_id IS NOT NULL;
+CREATE INDEX idx_project_activity_actor ON project_activity(actor_id) WHERE actor_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_project_activity_actor;
+DROP INDEX IF EXISTS idx_outbox_events_actor;
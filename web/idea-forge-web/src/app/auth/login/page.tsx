@@ -1,6 +1,6 @@
 "use client";
 
-import { useState, Suspense } from "react";
+import { useState, useEffect, Suspense } from "react";
 import { useRouter, useSearchParams } from "next/navigation";
 import Link from "next/link";
 import { useForm } from "react-hook-form";
@@ -11,9 +11,9 @@ import { Input } from "@/components/ui/input";
 import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from "@/components/ui/card";
 import { Label } from "@/components/ui/label";
 import { toast } from "sonner";
-import { login } from "@/lib/api";
+import { login, getOIDCProviders, startOIDCLogin } from "@/lib/api";
 import { setAuthToken, setUser } from "@/lib/auth";
-import { Loader2, LogIn } from "lucide-react";
+import { Loader2, LogIn, Building2 } from "lucide-react";
 
 const loginSchema = z.object({
   email_or_username: z.string().min(3, "Mínimo 3 caracteres"),
@@ -29,6 +29,22 @@ function LoginContent() {
   const [isLoading, setIsLoading] = useState(false);
   const [needsVerification, setNeedsVerification] = useState(false);
   const [userIdForVerification, setUserIdForVerification] = useState("");
+  const [oidcProviders, setOidcProviders] = useState<string[]>([]);
+
+  useEffect(() => {
+    getOIDCProviders()
+      .then((data) => setOidcProviders(data.providers || []))
+      .catch(() => setOidcProviders([]));
+  }, []);
+
+  const handleOIDCLogin = async (provider: string) => {
+    try {
+      const { authorization_url } = await startOIDCLogin(provider);
+      window.location.href = authorization_url;
+    } catch (error: any) {
+      toast.error(error.response?.data?.error || "Error al contactar al proveedor");
+    }
+  };
 
   const {
     register: registerField,
@@ -142,6 +158,20 @@ function LoginContent() {
               )}
             </Button>
 
+            {oidcProviders.map((provider) => (
+              <Button
+                key={provider}
+                type="button"
+                variant="outline"
+                className="w-full"
+                onClick={() => handleOIDCLogin(provider)}
+                disabled={isLoading}
+              >
+                <Building2 className="mr-2 h-4 w-4" />
+                Continuar con {provider}
+              </Button>
+            ))}
+
             <div className="text-sm text-center text-muted-foreground">
               ¿No tienes cuenta?{" "}
               <Link href="/auth/register" className="text-primary hover:underline font-medium">
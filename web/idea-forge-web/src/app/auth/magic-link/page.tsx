@@ -0,0 +1,179 @@
+"use client";
+
+import { useState, useEffect, useRef, Suspense } from "react";
+import { useRouter, useSearchParams } from "next/navigation";
+import Link from "next/link";
+import { useForm } from "react-hook-form";
+import { zodResolver } from "@hookform/resolvers/zod";
+import * as z from "zod";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from "@/components/ui/card";
+import { Label } from "@/components/ui/label";
+import { toast } from "sonner";
+import { requestMagicLink, consumeMagicLink } from "@/lib/api";
+import { setAuthToken, setUser } from "@/lib/auth";
+import { Loader2, Mail, ArrowLeft, Wand2 } from "lucide-react";
+
+const magicLinkSchema = z.object({
+  email: z.string().email("Email inválido"),
+});
+
+type MagicLinkForm = z.infer<typeof magicLinkSchema>;
+
+function MagicLinkContent() {
+  const router = useRouter();
+  const searchParams = useSearchParams();
+  const [isLoading, setIsLoading] = useState(false);
+  const [emailSent, setEmailSent] = useState(false);
+  const consumed = useRef(false);
+
+  const token = searchParams.get("token") || "";
+
+  useEffect(() => {
+    // El link es de un solo uso: evitar el doble consumo en desarrollo (StrictMode)
+    if (!token || consumed.current) return;
+    consumed.current = true;
+
+    consumeMagicLink({ token })
+      .then((response) => {
+        setAuthToken(response.token);
+        setUser(response.user);
+        toast.success("¡Bienvenido de vuelta!");
+        router.push("/");
+      })
+      .catch((error: any) => {
+        toast.error(error.response?.data?.error || "El link es inválido o expiró");
+        router.push("/auth/magic-link");
+      });
+  }, [token, router]);
+
+  const {
+    register: registerField,
+    handleSubmit,
+    formState: { errors },
+    getValues,
+  } = useForm<MagicLinkForm>({
+    resolver: zodResolver(magicLinkSchema),
+  });
+
+  const onSubmit = async (data: MagicLinkForm) => {
+    setIsLoading(true);
+    try {
+      await requestMagicLink(data);
+      setEmailSent(true);
+      toast.success("Revisa tu email para continuar");
+    } catch (error: any) {
+      toast.error("Error al procesar la solicitud");
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  if (token) {
+    return (
+      <div className="min-h-screen flex items-center justify-center bg-background p-4">
+        <Card className="w-full max-w-md">
+          <CardHeader className="space-y-1 text-center">
+            <Loader2 className="h-16 w-16 text-primary mx-auto mb-4 animate-spin" />
+            <CardTitle className="text-2xl font-bold">Iniciando sesión...</CardTitle>
+          </CardHeader>
+        </Card>
+      </div>
+    );
+  }
+
+  if (emailSent) {
+    return (
+      <div className="min-h-screen flex items-center justify-center bg-background p-4">
+        <Card className="w-full max-w-md">
+          <CardHeader className="space-y-1 text-center">
+            <Mail className="h-16 w-16 text-primary mx-auto mb-4" />
+            <CardTitle className="text-2xl font-bold">Revisa tu Email</CardTitle>
+            <CardDescription>
+              Si existe una cuenta con el email <span className="font-medium">{getValues("email")}</span>,
+              recibirás un link para iniciar sesión.
+            </CardDescription>
+          </CardHeader>
+          <CardContent>
+            <div className="space-y-4">
+              <div className="p-4 bg-muted rounded-md">
+                <p className="text-sm text-center">
+                  El link expira en <span className="font-semibold">15 minutos</span> y solo puede usarse una vez
+                </p>
+              </div>
+
+              <Link href="/auth/login" className="block">
+                <Button variant="outline" className="w-full">
+                  <ArrowLeft className="mr-2 h-4 w-4" />
+                  Volver al inicio de sesión
+                </Button>
+              </Link>
+            </div>
+          </CardContent>
+        </Card>
+      </div>
+    );
+  }
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-background p-4">
+      <Card className="w-full max-w-md">
+        <CardHeader className="space-y-1 text-center">
+          <Wand2 className="h-16 w-16 text-primary mx-auto mb-4" />
+          <CardTitle className="text-2xl font-bold">Acceso sin Contraseña</CardTitle>
+          <CardDescription>
+            Te enviaremos un link para iniciar sesión
+          </CardDescription>
+        </CardHeader>
+        <form onSubmit={handleSubmit(onSubmit)}>
+          <CardContent className="space-y-4">
+            <div className="space-y-2">
+              <Label htmlFor="email">Email</Label>
+              <Input
+                id="email"
+                type="email"
+                placeholder="tu@email.com"
+                {...registerField("email")}
+                disabled={isLoading}
+              />
+              {errors.email && (
+                <p className="text-sm text-destructive">{errors.email.message}</p>
+              )}
+            </div>
+          </CardContent>
+
+          <CardFooter className="flex flex-col space-y-4">
+            <Button type="submit" className="w-full" disabled={isLoading}>
+              {isLoading ? (
+                <>
+                  <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+                  Enviando...
+                </>
+              ) : (
+                <>
+                  <Mail className="mr-2 h-4 w-4" />
+                  Enviar Link
+                </>
+              )}
+            </Button>
+
+            <div className="text-sm text-center text-muted-foreground">
+              <Link href="/auth/login" className="text-primary hover:underline">
+                Volver al inicio de sesión
+              </Link>
+            </div>
+          </CardFooter>
+        </form>
+      </Card>
+    </div>
+  );
+}
+
+export default function MagicLinkPage() {
+  return (
+    <Suspense fallback={<div className="min-h-screen flex items-center justify-center"><Loader2 className="h-8 w-8 animate-spin" /></div>}>
+      <MagicLinkContent />
+    </Suspense>
+  );
+}
@@ -0,0 +1,62 @@
+"use client";
+
+import { useEffect, useRef, Suspense } from "react";
+import { useRouter, useSearchParams } from "next/navigation";
+import { Card, CardHeader, CardTitle } from "@/components/ui/card";
+import { toast } from "sonner";
+import { completeOIDCLogin } from "@/lib/api";
+import { setAuthToken, setUser } from "@/lib/auth";
+import { Loader2 } from "lucide-react";
+
+function OIDCCallbackContent() {
+  const router = useRouter();
+  const searchParams = useSearchParams();
+  const completed = useRef(false);
+
+  useEffect(() => {
+    // El state es de un solo uso: evitar el doble canje en desarrollo (StrictMode)
+    if (completed.current) return;
+    completed.current = true;
+
+    const code = searchParams.get("code") || "";
+    const state = searchParams.get("state") || "";
+    const providerError = searchParams.get("error");
+
+    if (providerError || !code || !state) {
+      toast.error(searchParams.get("error_description") || "El proveedor canceló el inicio de sesión");
+      router.push("/auth/login");
+      return;
+    }
+
+    completeOIDCLogin({ code, state })
+      .then((response) => {
+        setAuthToken(response.token);
+        setUser(response.user);
+        toast.success("¡Bienvenido!");
+        router.push("/");
+      })
+      .catch((error: any) => {
+        toast.error(error.response?.data?.error || "Error al iniciar sesión");
+        router.push("/auth/login");
+      });
+  }, [searchParams, router]);
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-background p-4">
+      <Card className="w-full max-w-md">
+        <CardHeader className="space-y-1 text-center">
+          <Loader2 className="h-16 w-16 text-primary mx-auto mb-4 animate-spin" />
+          <CardTitle className="text-2xl font-bold">Iniciando sesión...</CardTitle>
+        </CardHeader>
+      </Card>
+    </div>
+  );
+}
+
+export default function OIDCCallbackPage() {
+  return (
+    <Suspense fallback={<div className="min-h-screen flex items-center justify-center"><Loader2 className="h-8 w-8 animate-spin" /></div>}>
+      <OIDCCallbackContent />
+    </Suspense>
+  );
+}
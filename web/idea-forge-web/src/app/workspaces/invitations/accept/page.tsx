@@ -0,0 +1,83 @@
+"use client";
+
+import { useEffect, useRef, useState, Suspense } from "react";
+import { useRouter, useSearchParams } from "next/navigation";
+import Link from "next/link";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+import { toast } from "sonner";
+import { acceptWorkspaceInvitation } from "@/lib/api";
+import { Loader2, Users, XCircle } from "lucide-react";
+
+function AcceptInvitationContent() {
+  const router = useRouter();
+  const searchParams = useSearchParams();
+  const [error, setError] = useState("");
+  const accepted = useRef(false);
+
+  const token = searchParams.get("token") || "";
+
+  useEffect(() => {
+    // La invitación es de un solo uso: evitar el doble envío en desarrollo (StrictMode)
+    if (accepted.current) return;
+    accepted.current = true;
+
+    if (!token) {
+      setError("El link de invitación no es válido");
+      return;
+    }
+
+    acceptWorkspaceInvitation(token)
+      .then((workspace) => {
+        toast.success(`Te uniste a ${workspace.name}`);
+        router.push("/");
+      })
+      .catch((error: any) => {
+        const message = typeof error.response?.data === "string" ? error.response.data.trim() : "";
+        setError(message || "La invitación es inválida o expiró");
+      });
+  }, [token, router]);
+
+  if (error) {
+    return (
+      <div className="min-h-screen flex items-center justify-center bg-background p-4">
+        <Card className="w-full max-w-md">
+          <CardHeader className="space-y-1 text-center">
+            <XCircle className="h-16 w-16 text-destructive mx-auto mb-4" />
+            <CardTitle className="text-2xl font-bold">No se pudo aceptar la invitación</CardTitle>
+            <CardDescription>{error}</CardDescription>
+          </CardHeader>
+          <CardContent>
+            <Link href="/" className="block">
+              <Button variant="outline" className="w-full">
+                Ir al inicio
+              </Button>
+            </Link>
+          </CardContent>
+        </Card>
+      </div>
+    );
+  }
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-background p-4">
+      <Card className="w-full max-w-md">
+        <CardHeader className="space-y-1 text-center">
+          <Users className="h-16 w-16 text-primary mx-auto mb-4" />
+          <CardTitle className="text-2xl font-bold">Uniéndote al workspace...</CardTitle>
+          <CardDescription>
+            <Loader2 className="h-5 w-5 animate-spin mx-auto" />
+          </CardDescription>
+        </CardHeader>
+      </Card>
+    </div>
+  );
+}
+
+export default function AcceptInvitationPage() {
+  return (
+    <Suspense fallback={<div className="min-h-screen flex items-center justify-center"><Loader2 className="h-8 w-8 animate-spin" /></div>}>
+      <AcceptInvitationContent />
+    </Suspense>
+  );
+}
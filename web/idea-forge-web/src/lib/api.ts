@@ -64,8 +64,8 @@ api.interceptors.response.use(
 export const getIdea = (id: string) =>
   api.get(`/ideation/ideas/${id}`).then((r) => r.data);
 
-export const listIdeas = () =>
-  api.get(`/ideation/ideas`).then((r) => r.data);
+export const listIdeas = (workspaceId?: string) =>
+  api.get(`/ideation/ideas`, { params: workspaceId ? { workspace_id: workspaceId } : undefined }).then((r) => r.data);
 
 export const getMessages = (id: string) =>
   api.get(`/ideation/ideas/${id}/messages`).then((r) => r.data);
@@ -80,8 +80,35 @@ export const createIdea = (payload: {
   scope: string;
   validate_competition?: boolean;
   validate_monetization?: boolean;
+  workspace_id?: string;
 }) => api.post(`/ideation/ideas`, payload).then((r) => r.data);
 
+export const importIdeasCsv = (file: File, options: {
+  workspaceId?: string;
+  dryRun?: boolean;
+  improve?: boolean;
+  columns?: Record<string, string>;
+} = {}) => {
+  const form = new FormData();
+  form.append('file', file);
+  const params: Record<string, string> = {};
+  if (options.workspaceId) params.workspace_id = options.workspaceId;
+  if (options.dryRun) params.dry_run = 'true';
+  if (options.improve) params.improve = 'true';
+  if (options.columns) {
+    params.columns = Object.entries(options.columns).map(([field, header]) => `${field}:${header}`).join(',');
+  }
+  return api.post(`/ideation/ideas/import-csv`, form, { params }).then((r) => r.data);
+};
+
+export const createIdeaFromDocument = (file: File, workspaceId?: string) => {
+  const form = new FormData();
+  form.append('file', file);
+  return api.post(`/ideation/ideas/from-document`, form, {
+    params: workspaceId ? { workspace_id: workspaceId } : undefined,
+  }).then((r) => r.data);
+};
+
 export const updateIdea = (id: string, payload: {
   title?: string;
   objective?: string;
@@ -147,12 +174,66 @@ export const getArchitectureMessages = (id: string) =>
 export const postArchitectureChat = (architectureId: string, message: string) =>
   api.post(`/architecture/agent/chat`, { architecture_id: architectureId, message }).then((r) => r.data);
 
+// Descarga el esqueleto del repositorio (zip); sin plantilla se elige según el stack
+export const downloadScaffold = (architectureId: string, template?: string): Promise<Blob> =>
+  api.post(`/architecture/${architectureId}/scaffold`, null, {
+    params: template ? { template } : undefined,
+    responseType: 'blob',
+  }).then((r) => r.data);
+
+export type SchemaIssue = {
+  severity: 'error' | 'warning';
+  kind: string;
+  line?: number;
+  table?: string;
+  message: string;
+};
+
+export type SchemaReport = {
+  valid: boolean;
+  errors: number;
+  warnings: number;
+  tables: {
+    name: string;
+    line: number;
+    columns: { name: string; type: string; not_null: boolean }[];
+    primary_key?: string[];
+    foreign_keys?: { columns: string[]; ref_table: string; ref_columns: string[] }[];
+  }[];
+  issues: SchemaIssue[];
+};
+
+export const validateSchema = (architectureId: string): Promise<SchemaReport> =>
+  api.get(`/architecture/${architectureId}/schema`).then((r) => r.data);
+
+export const downloadSchemaMigrations = (architectureId: string): Promise<Blob> =>
+  api.get(`/architecture/${architectureId}/schema/migrations`, { responseType: 'blob' }).then((r) => r.data);
+
+export const repairSchema = (architectureId: string): Promise<{
+  applied: boolean;
+  reply?: string;
+  before: SchemaReport;
+  after?: SchemaReport;
+}> => api.post(`/architecture/${architectureId}/schema/repair`).then((r) => r.data);
+
+export type DiagramKind = 'er' | 'components' | 'modules' | 'flow';
+
+export const getDiagramKinds = (architectureId: string): Promise<Record<DiagramKind, ('mermaid' | 'plantuml')[]>> =>
+  api.get(`/architecture/${architectureId}/diagrams`).then((r) => r.data);
+
+export const getDiagram = (architectureId: string, kind: DiagramKind, format?: 'mermaid' | 'plantuml'): Promise<string> =>
+  api.get(`/architecture/${architectureId}/diagrams/${kind}`, { params: { format }, responseType: 'text' }).then((r) => r.data);
+
+export const exportC4 = (architectureId: string, mode: 'deterministic' | 'agent' = 'deterministic'): Promise<string> =>
+  api.get(`/architecture/${architectureId}/c4`, { params: { mode }, responseType: 'text' }).then((r) => r.data);
+
 // Auth API
 export const register = (payload: {
   username: string;
   email: string;
   password: string;
   confirm_password: string;
+  locale?: 'es' | 'en';
 }) => api.post(`/auth/register`, payload).then((r) => r.data);
 
 export const verifyEmail = (payload: {
@@ -179,6 +260,26 @@ export const resetPassword = (payload: {
   confirm_password: string;
 }) => api.post(`/auth/reset-password`, payload).then((r) => r.data);
 
+export const requestMagicLink = (payload: {
+  email: string;
+}) => api.post(`/auth/magic-link`, payload).then((r) => r.data);
+
+export const consumeMagicLink = (payload: {
+  token: string;
+}) => api.post(`/auth/magic-link/consume`, payload).then((r) => r.data);
+
+export const getOIDCProviders = () =>
+  api.get(`/auth/oidc/providers`).then((r) => r.data);
+
+// El state queda ligado al navegador con una cookie HttpOnly: ambas llamadas deben enviarla
+export const startOIDCLogin = (provider: string) =>
+  api.get(`/auth/oidc/${provider}/authorize`, { withCredentials: true }).then((r) => r.data);
+
+export const completeOIDCLogin = (payload: {
+  state: string;
+  code: string;
+}) => api.post(`/auth/oidc/callback`, payload, { withCredentials: true }).then((r) => r.data);
+
 export const getMe = () => api.get(`/auth/me`).then((r) => r.data);
 
 // Propagation API - para propagar cambios entre módulos (bypass bloqueo)
@@ -201,6 +302,9 @@ export const propagateToIdeation = (id: string, payload: {
 export const getModulesByArchitectureId = (architectureId: string) =>
   api.get(`/dev-modules/by-architecture/${architectureId}`).then((r) => r.data);
 
+export const exportModules = (architectureId: string, format: 'github' | 'jira' | 'csv' = 'github'): Promise<Blob> =>
+  api.get(`/dev-modules/by-architecture/${architectureId}/export`, { params: { format }, responseType: 'blob' }).then((r) => r.data);
+
 export const getModule = (id: string) =>
   api.get(`/dev-modules/${id}`).then((r) => r.data);
 
@@ -226,3 +330,189 @@ export const getGlobalChatMessages = (ideaId: string) =>
 
 export const postGlobalChat = (ideaId: string, message: string) =>
   api.post(`/global-chat`, { idea_id: ideaId, message }).then((r) => r.data);
+
+// Workspaces API
+export const listWorkspaces = () =>
+  api.get(`/workspaces`).then((r) => r.data);
+
+export const createWorkspace = (name: string) =>
+  api.post(`/workspaces`, { name }).then((r) => r.data);
+
+export const getWorkspaceMembers = (id: string) =>
+  api.get(`/workspaces/${id}/members`).then((r) => r.data);
+
+export const inviteToWorkspace = (id: string, payload: {
+  email: string;
+  role: "owner" | "editor" | "viewer";
+}) => api.post(`/workspaces/${id}/invitations`, payload).then((r) => r.data);
+
+export const acceptWorkspaceInvitation = (token: string) =>
+  api.post(`/workspaces/invitations/accept`, { token }).then((r) => r.data);
+
+// Project export API: Markdown specification (md), PDF imprimible (pdf) o un zip por etapa con los chats (zip)
+export const exportProject = (ideaId: string, format: 'md' | 'pdf' | 'zip' = 'md'): Promise<Blob> =>
+  api.get(`/projects/${ideaId}/export`, { params: { format }, responseType: 'blob' }).then((r) => r.data);
+
+// Importa un project.json o el zip exportado; devuelve el proyecto creado con IDs nuevos
+export const importProject = (bundle: Blob, workspaceId?: string) =>
+  api.post(`/projects/import`, bundle, {
+    params: workspaceId ? { workspace_id: workspaceId } : undefined,
+    headers: { 'Content-Type': bundle.type || 'application/octet-stream' },
+  }).then((r) => r.data);
+
+// Project collaborators API
+export const getCollaborators = (ideaId: string) =>
+  api.get(`/projects/${ideaId}/collaborators`).then((r) => r.data);
+
+export const addCollaborator = (ideaId: string, payload: {
+  email: string;
+  role: "viewer" | "editor";
+}) => api.post(`/projects/${ideaId}/collaborators`, payload).then((r) => r.data);
+
+export const removeCollaborator = (ideaId: string, userId: string) =>
+  api.delete(`/projects/${ideaId}/collaborators/${userId}`);
+
+// Share links API
+export const getShareLinks = (ideaId: string) =>
+  api.get(`/projects/${ideaId}/share-links`).then((r) => r.data);
+
+export const createShareLink = (ideaId: string, payload: {
+  expires_at?: string;
+  password?: string;
+  include_chats?: boolean;
+}) => api.post(`/projects/${ideaId}/share-links`, payload).then((r) => r.data);
+
+export const revokeShareLink = (ideaId: string, linkId: string) =>
+  api.delete(`/projects/${ideaId}/share-links/${linkId}`);
+
+// Comments
+export const getComments = (ideaId: string, params?: { anchor?: string; resolved?: boolean }) =>
+  api.get(`/projects/${ideaId}/comments`, { params }).then((r) => r.data);
+
+export const createCommentThread = (ideaId: string, payload: {
+  anchor: string;
+  range_start?: number;
+  range_end?: number;
+  body: string;
+}) => api.post(`/projects/${ideaId}/comments`, payload).then((r) => r.data);
+
+export const replyToThread = (ideaId: string, threadId: string, body: string) =>
+  api.post(`/projects/${ideaId}/comments/${threadId}/replies`, { body }).then((r) => r.data);
+
+export const deleteComment = (ideaId: string, threadId: string, commentId: string) =>
+  api.delete(`/projects/${ideaId}/comments/${threadId}/replies/${commentId}`);
+
+export const resolveThread = (ideaId: string, threadId: string) =>
+  api.post(`/projects/${ideaId}/comments/${threadId}/resolve`).then((r) => r.data);
+
+export const unresolveThread = (ideaId: string, threadId: string) =>
+  api.post(`/projects/${ideaId}/comments/${threadId}/unresolve`).then((r) => r.data);
+
+// Activity
+export const getProjectActivity = (ideaId: string, params?: {
+  stage?: string;
+  field?: string;
+  action?: string;
+  source?: string;
+  actor_id?: string;
+  since?: string;
+  until?: string;
+  limit?: number;
+  offset?: number;
+}) => api.get(`/projects/${ideaId}/activity`, { params }).then((r) => r.data);
+
+// Notifications
+export type NotificationMode = 'instant' | 'digest' | 'off';
+
+export interface NotificationPreferences {
+  comment_mentions: NotificationMode;
+  collaborator_added: NotificationMode;
+  stale_stages: boolean;
+  stale_modules: boolean;
+  stale_after_days: number;
+  digest: 'daily' | 'weekly' | 'off';
+  last_digest_at?: string;
+}
+
+export const getNotificationPreferences = (): Promise<NotificationPreferences> =>
+  api.get(`/notifications/preferences`).then((r) => r.data);
+
+export const updateNotificationPreferences = (payload: Partial<Omit<NotificationPreferences, 'last_digest_at'>>): Promise<NotificationPreferences> =>
+  api.put(`/notifications/preferences`, payload).then((r) => r.data);
+
+// Webhooks
+export const getWebhookEvents = () =>
+  api.get(`/webhooks/events`).then((r) => r.data);
+
+export const getWebhooks = (workspaceId?: string) =>
+  api.get(`/webhooks`, { params: workspaceId ? { workspace_id: workspaceId } : undefined }).then((r) => r.data);
+
+export const createWebhook = (payload: { url: string; events?: string[]; workspace_id?: string }) =>
+  api.post(`/webhooks`, payload).then((r) => r.data);
+
+export const updateWebhook = (webhookId: string, payload: { url?: string; events?: string[]; active?: boolean }) =>
+  api.put(`/webhooks/${webhookId}`, payload).then((r) => r.data);
+
+export const deleteWebhook = (webhookId: string) =>
+  api.delete(`/webhooks/${webhookId}`);
+
+export const pingWebhook = (webhookId: string) =>
+  api.post(`/webhooks/${webhookId}/ping`).then((r) => r.data);
+
+export const getWebhookDeliveries = (webhookId: string, params?: { status?: string; limit?: number }) =>
+  api.get(`/webhooks/${webhookId}/deliveries`, { params }).then((r) => r.data);
+
+export const getWebhookDelivery = (webhookId: string, deliveryId: string) =>
+  api.get(`/webhooks/${webhookId}/deliveries/${deliveryId}`).then((r) => r.data);
+
+export const redeliverWebhook = (webhookId: string, deliveryId: string) =>
+  api.post(`/webhooks/${webhookId}/deliveries/${deliveryId}/redeliver`).then((r) => r.data);
+
+// Real-time project events (SSE). EventSource no permite el header Authorization,
+// por eso el stream se lee con fetch. Devuelve una función para cerrar la conexión.
+export type ProjectEvent = {
+  id: string;
+  type: string;
+  idea_id: string;
+  data?: any;
+  truncated?: boolean;
+  at: string;
+};
+
+export const subscribeToProject = (ideaId: string, onEvent: (event: ProjectEvent) => void) => {
+  const controller = new AbortController();
+
+  const connect = async () => {
+    while (!controller.signal.aborted) {
+      try {
+        const res = await fetch(`${process.env.NEXT_PUBLIC_API_BASE}/projects/${ideaId}/events`, {
+          headers: { Authorization: `Bearer ${Cookies.get('auth_token') ?? ''}` },
+          signal: controller.signal,
+        });
+        if (!res.ok || !res.body) throw new Error(`HTTP ${res.status}`);
+
+        const reader = res.body.pipeThrough(new TextDecoderStream()).getReader();
+        let buffer = "";
+        for (;;) {
+          const { value, done } = await reader.read();
+          if (done) break;
+          buffer += value;
+          const chunks = buffer.split("\n\n");
+          buffer = chunks.pop() ?? "";
+          for (const chunk of chunks) {
+            const data = chunk.split("\n").find((line) => line.startsWith("data: "));
+            if (data) onEvent(JSON.parse(data.slice(6)));
+          }
+        }
+      } catch (error) {
+        if (controller.signal.aborted) return;
+        console.error("❌ Project events stream error:", error);
+      }
+      // Reintentar tras un corte de conexión
+      await new Promise((resolve) => setTimeout(resolve, 3000));
+    }
+  };
+
+  connect();
+  return () => controller.abort();
+};
@@ -8,6 +8,7 @@ export interface User {
   username: string;
   email: string;
   status: string;
+  role: string;
   created_at: string;
   updated_at: string;
 }